@@ -0,0 +1,840 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Condition types of a Release in addition to the common Ready and Synced.
+// The ChartResolved, ValuesComposed, Released, Tested and Healthy conditions
+// report the stages of a Release in the order they are reached.
+const (
+	// TypeChartResolved indicates whether the chart of a Release was pulled
+	// and loaded.
+	TypeChartResolved xpv1.ConditionType = "ChartResolved"
+
+	// TypeValuesComposed indicates whether the values of a Release were
+	// composed from its values, valuesFrom and set.
+	TypeValuesComposed xpv1.ConditionType = "ValuesComposed"
+
+	// TypeReleased indicates whether the last revision of the release of a
+	// Release was deployed.
+	TypeReleased xpv1.ConditionType = "Released"
+
+	// TypeTested indicates whether the test hooks of the chart of a Release
+	// passed the last time they were run.
+	TypeTested xpv1.ConditionType = "Tested"
+
+	// TypeValidated indicates whether the rendered manifests of a Release
+	// passed a server-side dry-run against the target cluster.
+	TypeValidated xpv1.ConditionType = "Validated"
+
+	// TypePermitted indicates whether the provider may create and patch the
+	// rendered resources of a Release on the target cluster.
+	TypePermitted xpv1.ConditionType = "Permitted"
+
+	// TypeLinted indicates whether the chart of a Release passed linting with
+	// the composed values.
+	TypeLinted xpv1.ConditionType = "Linted"
+
+	// TypePolicyCompliant indicates whether the rendered manifests of a
+	// Release comply with the policies of the provider.
+	TypePolicyCompliant xpv1.ConditionType = "PolicyCompliant"
+
+	// TypeConflictFree indicates whether the rendered resources of a Release
+	// conflict with existing resources that are not part of the release.
+	TypeConflictFree xpv1.ConditionType = "ConflictFree"
+
+	// TypeDeletionProtected indicates that the deletion of a Release is
+	// blocked by its deletion protection.
+	TypeDeletionProtected xpv1.ConditionType = "DeletionProtected"
+
+	// TypeMigrated indicates how the previously deployed release was handled
+	// when the release of a Release moved.
+	TypeMigrated xpv1.ConditionType = "Migrated"
+
+	// TypeHealthy indicates whether the deployed resources of a Release are
+	// healthy.
+	TypeHealthy xpv1.ConditionType = "Healthy"
+
+	// TypeReadinessChecks indicates whether the readiness checks of a Release
+	// pass.
+	TypeReadinessChecks xpv1.ConditionType = "ReadinessChecks"
+
+	// TypeCRDsEstablished indicates whether the CRDs installed by a Release
+	// are established.
+	TypeCRDsEstablished xpv1.ConditionType = "CRDsEstablished"
+
+	// TypeUpgradePending indicates whether an upgrade of a Release is
+	// deferred until its next upgrade window.
+	TypeUpgradePending xpv1.ConditionType = "UpgradePending"
+
+	// TypeSuspended indicates whether changes to the release of a Release
+	// are suspended.
+	TypeSuspended xpv1.ConditionType = "Suspended"
+
+	// TypePendingApproval indicates whether an upgrade of a Release waits
+	// for approval.
+	TypePendingApproval xpv1.ConditionType = "PendingApproval"
+
+	// TypeCanaryVerified indicates whether the canary release of an upgrade
+	// of a Release was verified.
+	TypeCanaryVerified xpv1.ConditionType = "CanaryVerified"
+
+	// TypeHibernated indicates whether the release of a Release is
+	// uninstalled by its hibernation schedule.
+	TypeHibernated xpv1.ConditionType = "Hibernated"
+
+	// TypeConnectionDetails indicates whether all objects of the connection
+	// details of a Release exist.
+	TypeConnectionDetails xpv1.ConditionType = "ConnectionDetails"
+
+	// TypeChartAllowed indicates whether the chart of a Release is allowed
+	// by the chart policy of the provider.
+	TypeChartAllowed xpv1.ConditionType = "ChartAllowed"
+
+	// TypeExpired indicates whether a Release whose TTL has passed is
+	// deleted.
+	TypeExpired xpv1.ConditionType = "Expired"
+)
+
+// Reasons the chart of a Release is or is not resolved.
+const (
+	ReasonChartResolved         xpv1.ConditionReason = "ChartResolved"
+	ReasonChartResolutionFailed xpv1.ConditionReason = "ChartResolutionFailed"
+)
+
+// Reasons the values of a Release are or are not composed.
+const (
+	ReasonValuesComposed          xpv1.ConditionReason = "ValuesComposed"
+	ReasonValuesCompositionFailed xpv1.ConditionReason = "ValuesCompositionFailed"
+)
+
+// Reasons the release of a Release is or is not deployed.
+const (
+	ReasonReleaseDeployed xpv1.ConditionReason = "ReleaseDeployed"
+	ReasonReleasePending  xpv1.ConditionReason = "ReleasePending"
+	ReasonReleaseFailed   xpv1.ConditionReason = "ReleaseFailed"
+	ReasonInstallFailed   xpv1.ConditionReason = "InstallFailed"
+	ReasonUpgradeFailed   xpv1.ConditionReason = "UpgradeFailed"
+	ReasonRollbackFailed  xpv1.ConditionReason = "RollbackFailed"
+)
+
+// Reasons the tests of a Release did or did not pass.
+const (
+	ReasonTestsPassed xpv1.ConditionReason = "TestsPassed"
+	ReasonTestsFailed xpv1.ConditionReason = "TestsFailed"
+	ReasonTestsNotRun xpv1.ConditionReason = "TestsNotRun"
+)
+
+// Reasons a Release is or is not validated.
+const (
+	ReasonDryRunSucceeded xpv1.ConditionReason = "DryRunSucceeded"
+	ReasonDryRunFailed    xpv1.ConditionReason = "DryRunFailed"
+)
+
+// Reasons the provider may or may not deploy the resources of a Release.
+const (
+	ReasonPermissionsGranted xpv1.ConditionReason = "PermissionsGranted"
+	ReasonPermissionsMissing xpv1.ConditionReason = "PermissionsMissing"
+)
+
+// Reasons a Release is or is not linted.
+const (
+	ReasonLintSucceeded xpv1.ConditionReason = "LintSucceeded"
+	ReasonLintFailed    xpv1.ConditionReason = "LintFailed"
+)
+
+// Reasons a Release does or does not comply with its policies.
+const (
+	ReasonPolicyPassed   xpv1.ConditionReason = "PolicyPassed"
+	ReasonPolicyViolated xpv1.ConditionReason = "PolicyViolated"
+)
+
+// Reasons a Release does or does not conflict with existing resources.
+const (
+	ReasonNoConflicts       xpv1.ConditionReason = "NoConflicts"
+	ReasonResourcesAdopted  xpv1.ConditionReason = "ResourcesAdopted"
+	ReasonResourcesReplaced xpv1.ConditionReason = "ResourcesReplaced"
+	ReasonConflictDetected  xpv1.ConditionReason = "ConflictDetected"
+)
+
+// Reasons the previously deployed release was or was not migrated.
+const (
+	ReasonPreviousReleaseUninstalled xpv1.ConditionReason = "PreviousReleaseUninstalled"
+	ReasonPreviousReleaseKept        xpv1.ConditionReason = "PreviousReleaseKept"
+	ReasonMigrationForbidden         xpv1.ConditionReason = "MigrationForbidden"
+)
+
+// Reasons the deployed resources of a Release are or are not healthy.
+const (
+	ReasonResourcesHealthy   xpv1.ConditionReason = "ResourcesHealthy"
+	ReasonResourcesUnhealthy xpv1.ConditionReason = "ResourcesUnhealthy"
+)
+
+// Reasons the readiness checks of a Release do or do not pass.
+const (
+	ReasonReadinessChecksPassed xpv1.ConditionReason = "ReadinessChecksPassed"
+	ReasonReadinessChecksFailed xpv1.ConditionReason = "ReadinessChecksFailed"
+)
+
+// Reasons the CRDs of a Release are or are not established.
+const (
+	ReasonCRDsEstablished    xpv1.ConditionReason = "CRDsEstablished"
+	ReasonCRDsNotEstablished xpv1.ConditionReason = "CRDsNotEstablished"
+)
+
+// Reasons an upgrade of a Release is or is not pending.
+const (
+	ReasonOutsideUpgradeWindow xpv1.ConditionReason = "OutsideUpgradeWindow"
+	ReasonNoUpgradePending     xpv1.ConditionReason = "NoUpgradePending"
+)
+
+// Reasons changes to the release of a Release are or are not suspended.
+const (
+	ReasonSuspended xpv1.ConditionReason = "Suspended"
+	ReasonResumed   xpv1.ConditionReason = "Resumed"
+)
+
+// Reasons an upgrade of a Release does or does not wait for approval.
+const (
+	ReasonAwaitingApproval xpv1.ConditionReason = "AwaitingApproval"
+	ReasonApproved         xpv1.ConditionReason = "Approved"
+)
+
+// Reasons the canary release of an upgrade was or was not verified.
+const (
+	ReasonCanaryVerifying xpv1.ConditionReason = "CanaryVerifying"
+	ReasonCanaryPromoted  xpv1.ConditionReason = "CanaryPromoted"
+)
+
+// Reasons the release of a Release is or is not hibernated.
+const (
+	ReasonHibernating xpv1.ConditionReason = "Hibernating"
+	ReasonAwake       xpv1.ConditionReason = "Awake"
+)
+
+// Reasons the connection details of a Release are or are not published.
+const (
+	ReasonConnectionDetailsPublished xpv1.ConditionReason = "ConnectionDetailsPublished"
+	ReasonConnectionDetailsPending   xpv1.ConditionReason = "ConnectionDetailsPending"
+)
+
+// Reasons the deletion of a Release is blocked.
+const (
+	ReasonDeletionProtectionEnabled xpv1.ConditionReason = "DeletionProtectionEnabled"
+)
+
+// Reasons the chart of a Release is or is not allowed.
+const (
+	ReasonChartAllowed xpv1.ConditionReason = "ChartAllowed"
+	ReasonChartDenied  xpv1.ConditionReason = "ChartDenied"
+)
+
+// Reasons an expired Release is not deleted.
+const (
+	ReasonExpiryBlocked xpv1.ConditionReason = "ExpiryBlocked"
+)
+
+// ChartResolved returns a condition indicating that the supplied version of
+// the supplied chart was pulled and loaded.
+func ChartResolved(name, version string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeChartResolved,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonChartResolved,
+		Message:            name + "-" + version,
+	}
+}
+
+// ChartResolutionFailed returns a condition indicating that the chart could
+// not be pulled or loaded.
+func ChartResolutionFailed(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeChartResolved,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonChartResolutionFailed,
+		Message:            err.Error(),
+	}
+}
+
+// ValuesComposed returns a condition indicating that the values were
+// composed.
+func ValuesComposed() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeValuesComposed,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonValuesComposed,
+	}
+}
+
+// ValuesCompositionFailed returns a condition indicating that the values
+// could not be composed, e.g. because a referenced secret is missing.
+func ValuesCompositionFailed(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeValuesComposed,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonValuesCompositionFailed,
+		Message:            err.Error(),
+	}
+}
+
+// ReleaseDeployed returns a condition indicating that the supplied revision
+// of the release is deployed.
+func ReleaseDeployed(revision int) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeReleased,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonReleaseDeployed,
+		Message:            fmt.Sprintf("revision %d is deployed", revision),
+	}
+}
+
+// ReleasePending returns a condition indicating that the supplied revision of
+// the release is in the supplied pending state.
+func ReleasePending(revision int, state string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeReleased,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonReleasePending,
+		Message:            fmt.Sprintf("revision %d is %s", revision, state),
+	}
+}
+
+// ReleaseFailed returns a condition indicating that the supplied revision of
+// the release failed. The description of the revision is reported in the
+// message.
+func ReleaseFailed(revision int, description string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeReleased,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonReleaseFailed,
+		Message:            fmt.Sprintf("revision %d failed: %s", revision, description),
+	}
+}
+
+// InstallFailed returns a condition indicating that the release could not be
+// installed.
+func InstallFailed(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeReleased,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonInstallFailed,
+		Message:            err.Error(),
+	}
+}
+
+// UpgradeFailed returns a condition indicating that the release could not be
+// upgraded.
+func UpgradeFailed(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeReleased,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonUpgradeFailed,
+		Message:            err.Error(),
+	}
+}
+
+// RollbackFailed returns a condition indicating that a failed revision of the
+// release could not be rolled back.
+func RollbackFailed(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeReleased,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRollbackFailed,
+		Message:            err.Error(),
+	}
+}
+
+// TestsPassed returns a condition indicating that all test hooks of the
+// release passed.
+func TestsPassed() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeTested,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonTestsPassed,
+	}
+}
+
+// TestsFailed returns a condition indicating that the supplied test hooks of
+// the release failed.
+func TestsFailed(failed []string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeTested,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonTestsFailed,
+		Message:            strings.Join(failed, ", "),
+	}
+}
+
+// TestsNotRun returns a condition indicating that the test hooks of the
+// release were not run for its current revision.
+func TestsNotRun() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeTested,
+		Status:             corev1.ConditionUnknown,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonTestsNotRun,
+	}
+}
+
+// DryRunSucceeded returns a condition indicating that the rendered manifests
+// were accepted by the target cluster in a server-side dry-run.
+func DryRunSucceeded() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeValidated,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDryRunSucceeded,
+	}
+}
+
+// DryRunFailed returns a condition indicating that the target cluster rejected
+// the rendered manifests in a server-side dry-run.
+func DryRunFailed(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeValidated,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDryRunFailed,
+		Message:            err.Error(),
+	}
+}
+
+// PermissionsGranted returns a condition indicating that the provider may
+// deploy the rendered resources on the target cluster.
+func PermissionsGranted() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypePermitted,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPermissionsGranted,
+	}
+}
+
+// PermissionsMissing returns a condition indicating that the provider lacks
+// the supplied permissions to deploy the rendered resources.
+func PermissionsMissing(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypePermitted,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPermissionsMissing,
+		Message:            err.Error(),
+	}
+}
+
+// LintSucceeded returns a condition indicating that the chart passed linting.
+// Warnings, if any, are reported in the message.
+func LintSucceeded(warnings string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeLinted,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonLintSucceeded,
+		Message:            warnings,
+	}
+}
+
+// LintFailed returns a condition indicating that the chart failed linting.
+func LintFailed(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeLinted,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonLintFailed,
+		Message:            err.Error(),
+	}
+}
+
+// PolicyCompliant returns a condition indicating that the rendered manifests
+// comply with all policies.
+func PolicyCompliant() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypePolicyCompliant,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPolicyPassed,
+	}
+}
+
+// PolicyViolated returns a condition indicating that the rendered manifests
+// violate at least one policy.
+func PolicyViolated(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypePolicyCompliant,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPolicyViolated,
+		Message:            err.Error(),
+	}
+}
+
+// NoConflicts returns a condition indicating that the rendered resources do
+// not conflict with existing resources.
+func NoConflicts() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeConflictFree,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNoConflicts,
+	}
+}
+
+// ConflictsResolved returns a condition indicating that the supplied
+// conflicting resources were adopted or replaced, depending on the reason.
+func ConflictsResolved(r xpv1.ConditionReason, resources []string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeConflictFree,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             r,
+		Message:            strings.Join(resources, ", "),
+	}
+}
+
+// ConflictDetected returns a condition indicating that rendered resources
+// conflict with existing resources.
+func ConflictDetected(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeConflictFree,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonConflictDetected,
+		Message:            err.Error(),
+	}
+}
+
+// DeletionBlocked returns a condition indicating that the Release is not
+// uninstalled because deletion protection is enabled.
+func DeletionBlocked() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDeletionProtected,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDeletionProtectionEnabled,
+		Message:            "deletion is blocked until spec.forProvider.deletionProtection is unset",
+	}
+}
+
+// PreviousReleaseUninstalled returns a condition indicating that the
+// previously deployed release was uninstalled.
+func PreviousReleaseUninstalled(previous string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeMigrated,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPreviousReleaseUninstalled,
+		Message:            "uninstalled previous release " + previous,
+	}
+}
+
+// PreviousReleaseKept returns a condition indicating that the previously
+// deployed release was left in place and is no longer managed.
+func PreviousReleaseKept(previous string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeMigrated,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPreviousReleaseKept,
+		Message:            "previous release " + previous + " is no longer managed",
+	}
+}
+
+// MigrationForbidden returns a condition indicating that the release is not
+// moved because its policy forbids it.
+func MigrationForbidden(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeMigrated,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonMigrationForbidden,
+		Message:            err.Error(),
+	}
+}
+
+// ResourcesHealthy returns a condition indicating that all deployed resources
+// are healthy.
+func ResourcesHealthy() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonResourcesHealthy,
+	}
+}
+
+// ResourcesUnhealthy returns a condition indicating that the supplied deployed
+// resources are not healthy.
+func ResourcesUnhealthy(resources []string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonResourcesUnhealthy,
+		Message:            strings.Join(resources, "; "),
+	}
+}
+
+// ReadinessChecksPassed returns a condition indicating that all readiness
+// checks pass.
+func ReadinessChecksPassed() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeReadinessChecks,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonReadinessChecksPassed,
+	}
+}
+
+// ReadinessChecksFailed returns a condition indicating that the supplied
+// readiness checks do not pass.
+func ReadinessChecksFailed(failed []string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeReadinessChecks,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonReadinessChecksFailed,
+		Message:            strings.Join(failed, "; "),
+	}
+}
+
+// CRDsEstablished returns a condition indicating that all CRDs installed by
+// the release are established.
+func CRDsEstablished() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeCRDsEstablished,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonCRDsEstablished,
+	}
+}
+
+// CRDsNotEstablished returns a condition indicating that the supplied CRDs
+// are not established yet.
+func CRDsNotEstablished(crds []string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeCRDsEstablished,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonCRDsNotEstablished,
+		Message:            strings.Join(crds, ", "),
+	}
+}
+
+// UpgradeDeferred returns a condition indicating that an upgrade is deferred
+// until the supplied time the next upgrade window opens. A zero time means no
+// window opens within a year.
+func UpgradeDeferred(next time.Time) xpv1.Condition {
+	msg := "no upgrade window opens within a year"
+	if !next.IsZero() {
+		msg = "upgrade is deferred until the upgrade window opening at " + next.UTC().Format(time.RFC3339)
+	}
+	return xpv1.Condition{
+		Type:               TypeUpgradePending,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonOutsideUpgradeWindow,
+		Message:            msg,
+	}
+}
+
+// NoUpgradePending returns a condition indicating that no upgrade is
+// deferred.
+func NoUpgradePending() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeUpgradePending,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNoUpgradePending,
+	}
+}
+
+// Suspended returns a condition indicating that installs, upgrades and
+// uninstalls of the release are suspended.
+func Suspended() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeSuspended,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonSuspended,
+		Message:            "changes to the release are suspended until spec.suspend is unset",
+	}
+}
+
+// Resumed returns a condition indicating that changes to the release are no
+// longer suspended.
+func Resumed() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeSuspended,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonResumed,
+	}
+}
+
+// AwaitingApproval returns a condition indicating that the upgrade to the
+// supplied revision with the supplied diff digest waits for approval.
+func AwaitingApproval(revision int, digest string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypePendingApproval,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonAwaitingApproval,
+		Message:            fmt.Sprintf("upgrade to revision %d waits for approval of diff %s", revision, digest),
+	}
+}
+
+// Approved returns a condition indicating that the upgrade with the supplied
+// diff digest was approved.
+func Approved(digest string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypePendingApproval,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonApproved,
+		Message:            "approved diff " + digest,
+	}
+}
+
+// CanaryVerifying returns a condition indicating that the supplied canary
+// release is verified. Unhealthy resources of the canary release are listed
+// in the message.
+func CanaryVerifying(canary string, unhealthy []string) xpv1.Condition {
+	msg := "waiting for canary release " + canary
+	if len(unhealthy) > 0 {
+		msg += " to become healthy: " + strings.Join(unhealthy, ", ")
+	}
+	return xpv1.Condition{
+		Type:               TypeCanaryVerified,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonCanaryVerifying,
+		Message:            msg,
+	}
+}
+
+// CanaryPromoted returns a condition indicating that the canary release was
+// verified and the upgrade was applied to the release.
+func CanaryPromoted() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeCanaryVerified,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonCanaryPromoted,
+	}
+}
+
+// Hibernating returns a condition indicating that the release is uninstalled
+// by its hibernation schedule.
+func Hibernating() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHibernated,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonHibernating,
+	}
+}
+
+// Awake returns a condition indicating that the release is installed
+// according to its hibernation schedule.
+func Awake() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHibernated,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonAwake,
+	}
+}
+
+// ConnectionDetailsPublished returns a condition indicating that all objects
+// of the connection details of a Release exist.
+func ConnectionDetailsPublished() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeConnectionDetails,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonConnectionDetailsPublished,
+	}
+}
+
+// ConnectionDetailsPending returns a condition indicating that the supplied
+// objects of the connection details of a Release do not exist yet.
+func ConnectionDetailsPending(missing []string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeConnectionDetails,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonConnectionDetailsPending,
+		Message:            "waiting for " + strings.Join(missing, ", "),
+	}
+}
+
+// ChartAllowed returns a condition indicating that the chart of a Release is
+// allowed by the chart policy of the provider.
+func ChartAllowed() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeChartAllowed,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonChartAllowed,
+	}
+}
+
+// ChartDenied returns a condition indicating that the chart of a Release is
+// denied by the chart policy of the provider. The Release is not deployed
+// until either its chart or the policy changes.
+func ChartDenied(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeChartAllowed,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonChartDenied,
+		Message:            err.Error(),
+	}
+}
+
+// ExpiryBlocked returns a condition indicating that the TTL of a Release has
+// passed, but the Release is not deleted for the supplied reason.
+func ExpiryBlocked(why string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeExpired,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonExpiryBlocked,
+		Message:            "TTL has passed, but the release is not deleted because " + why,
+	}
+}
@@ -45,6 +45,24 @@ var (
 	ReleaseGroupVersionKind = SchemeGroupVersion.WithKind(ReleaseKind)
 )
 
+// ReleaseSet type metadata.
+var (
+	ReleaseSetKind             = reflect.TypeOf(ReleaseSet{}).Name()
+	ReleaseSetGroupKind        = schema.GroupKind{Group: Group, Kind: ReleaseSetKind}.String()
+	ReleaseSetKindAPIVersion   = ReleaseSetKind + "." + SchemeGroupVersion.String()
+	ReleaseSetGroupVersionKind = SchemeGroupVersion.WithKind(ReleaseSetKind)
+)
+
+// ReleaseClass type metadata.
+var (
+	ReleaseClassKind             = reflect.TypeOf(ReleaseClass{}).Name()
+	ReleaseClassGroupKind        = schema.GroupKind{Group: Group, Kind: ReleaseClassKind}.String()
+	ReleaseClassKindAPIVersion   = ReleaseClassKind + "." + SchemeGroupVersion.String()
+	ReleaseClassGroupVersionKind = SchemeGroupVersion.WithKind(ReleaseClassKind)
+)
+
 func init() {
 	SchemeBuilder.Register(&Release{}, &ReleaseList{})
+	SchemeBuilder.Register(&ReleaseSet{}, &ReleaseSetList{})
+	SchemeBuilder.Register(&ReleaseClass{}, &ReleaseClassList{})
 }
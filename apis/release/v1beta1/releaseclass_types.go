@@ -0,0 +1,112 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ReleaseClassDefaults are used for fields that are not set on a Release of
+// the class. Boolean fields are enabled if they are enabled on either the
+// class or the Release.
+type ReleaseClassDefaults struct {
+	// Wait for releases to become ready.
+	// +optional
+	Wait bool `json:"wait,omitempty"`
+	// WaitTimeout of releases.
+	// +optional
+	WaitTimeout *metav1.Duration `json:"waitTimeout,omitempty"`
+	// MaxHistory is the maximum number of revisions kept per release.
+	// +optional
+	MaxHistory *int32 `json:"maxHistory,omitempty"`
+	// SkipCRDs skips installation of CRDs.
+	// +optional
+	SkipCRDs bool `json:"skipCRDs,omitempty"`
+	// SkipCreateNamespace won't create the namespaces of releases.
+	// +optional
+	SkipCreateNamespace bool `json:"skipCreateNamespace,omitempty"`
+	// NamespaceMetadata is added to namespaces created for releases.
+	// +optional
+	NamespaceMetadata *NamespaceMetadata `json:"namespaceMetadata,omitempty"`
+	// ReleaseNameChangePolicy of releases.
+	// +optional
+	// +kubebuilder:validation:Enum=Keep;Recreate
+	ReleaseNameChangePolicy ReleaseNameChangePolicy `json:"releaseNameChangePolicy,omitempty"`
+	// NamespaceChangePolicy of releases.
+	// +optional
+	// +kubebuilder:validation:Enum=Forbid;Recreate;Orphan
+	NamespaceChangePolicy NamespaceChangePolicy `json:"namespaceChangePolicy,omitempty"`
+	// ServerSideDryRun validates releases before they are installed or
+	// upgraded.
+	// +optional
+	ServerSideDryRun bool `json:"serverSideDryRun,omitempty"`
+	// RBACPreflight checks the permissions of the provider before releases
+	// are installed or upgraded.
+	// +optional
+	RBACPreflight bool `json:"rbacPreflight,omitempty"`
+	// Lint charts before releases are installed or upgraded.
+	// +optional
+	Lint bool `json:"lint,omitempty"`
+	// DriftPolicy of releases.
+	// +optional
+	// +kubebuilder:validation:Enum=Ignore;Detect;Correct
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+	// ConflictPolicy of releases.
+	// +optional
+	// +kubebuilder:validation:Enum=Fail;Adopt;Force
+	ConflictPolicy ConflictPolicy `json:"conflictPolicy,omitempty"`
+}
+
+// A ReleaseClassSpec defines the defaults and guardrails of a ReleaseClass.
+type ReleaseClassSpec struct {
+	// Defaults for Releases of the class.
+	// +optional
+	Defaults ReleaseClassDefaults `json:"defaults,omitempty"`
+	// AllowedRepositories are the chart repositories Releases of the class
+	// may install charts from, including the chart URLs and repositories
+	// below them. Scheme and host must match exactly, paths by whole
+	// segments. All repositories are allowed if none are set.
+	// +optional
+	AllowedRepositories []string `json:"allowedRepositories,omitempty"`
+	// AllowedNamespaces are the namespaces Releases of the class may be
+	// installed into. Shell file name patterns like "team-*" are supported.
+	// All namespaces are allowed if none are set.
+	// +optional
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A ReleaseClass carries defaults and guardrails for the Releases that refer
+// to it by name.
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,helm}
+type ReleaseClass struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ReleaseClassSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// ReleaseClassList contains a list of ReleaseClass
+type ReleaseClassList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ReleaseClass `json:"items"`
+}
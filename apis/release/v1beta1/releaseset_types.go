@@ -0,0 +1,130 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// Labels added to the Releases of a ReleaseSet.
+const (
+	// LabelKeyReleaseSet is the name of the ReleaseSet of a Release.
+	LabelKeyReleaseSet = "helm.crossplane.io/release-set"
+	// LabelKeyProviderConfig is the name of the ProviderConfig a Release of
+	// a ReleaseSet uses.
+	LabelKeyProviderConfig = "helm.crossplane.io/provider-config"
+)
+
+// ReleaseTemplateMetadata is added to every Release of a ReleaseSet.
+type ReleaseTemplateMetadata struct {
+	// Labels of the Releases.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations of the Releases.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// A ReleaseTemplate is used to create the Releases of a ReleaseSet.
+type ReleaseTemplate struct {
+	// Metadata of the Releases.
+	// +optional
+	Metadata ReleaseTemplateMetadata `json:"metadata,omitempty"`
+	// Spec of the Releases. The ProviderConfig reference is set for every
+	// selected ProviderConfig.
+	Spec ReleaseSpec `json:"spec"`
+}
+
+// A ReleaseSetOverride customizes the Release for one ProviderConfig.
+type ReleaseSetOverride struct {
+	// ProviderConfigName is the name of the ProviderConfig the override
+	// applies to.
+	ProviderConfigName string `json:"providerConfigName"`
+	// Values are merged into the values of the template.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Values runtime.RawExtension `json:"values,omitempty"`
+	// Set are appended to the set values of the template.
+	// +optional
+	Set []SetVal `json:"set,omitempty"`
+}
+
+// A ReleaseSetSpec defines the desired state of a ReleaseSet.
+type ReleaseSetSpec struct {
+	// ProviderConfigSelector selects the ProviderConfigs, i.e. the clusters,
+	// a Release is created for.
+	ProviderConfigSelector metav1.LabelSelector `json:"providerConfigSelector"`
+	// Template of the Releases.
+	Template ReleaseTemplate `json:"template"`
+	// Overrides customize the Releases of selected ProviderConfigs.
+	// +optional
+	Overrides []ReleaseSetOverride `json:"overrides,omitempty"`
+}
+
+// ReleaseSetMember is the observed state of a Release of a ReleaseSet.
+type ReleaseSetMember struct {
+	// ProviderConfigName is the name of the ProviderConfig of the Release.
+	ProviderConfigName string `json:"providerConfigName"`
+	// ReleaseName is the name of the Release.
+	ReleaseName string `json:"releaseName"`
+	// Ready is true if the Release is ready.
+	Ready bool `json:"ready"`
+	// Message of the Ready condition of the Release, if it is not ready.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// A ReleaseSetStatus represents the observed state of a ReleaseSet.
+type ReleaseSetStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+	// Desired is the number of selected ProviderConfigs.
+	Desired int32 `json:"desired,omitempty"`
+	// Ready is the number of ready Releases.
+	Ready int32 `json:"ready,omitempty"`
+	// Releases of the ReleaseSet.
+	Releases []ReleaseSetMember `json:"releases,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A ReleaseSet creates a Release for every selected ProviderConfig.
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="DESIRED",type="integer",JSONPath=".status.desired"
+// +kubebuilder:printcolumn:name="READY",type="integer",JSONPath=".status.ready"
+// +kubebuilder:printcolumn:name="CHART",type="string",JSONPath=".spec.template.spec.forProvider.chart.name"
+// +kubebuilder:printcolumn:name="VERSION",type="string",JSONPath=".spec.template.spec.forProvider.chart.version"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,helm}
+type ReleaseSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ReleaseSetSpec   `json:"spec"`
+	Status ReleaseSetStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ReleaseSetList contains a list of ReleaseSet
+type ReleaseSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ReleaseSet `json:"items"`
+}
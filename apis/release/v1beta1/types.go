@@ -23,8 +23,37 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	helmv1beta1 "github.com/crossplane-contrib/provider-helm/apis/v1beta1"
 )
 
+// AnnotationKeyAdopt marks a Release that adopts an existing Helm release of
+// the same name, e.g. one installed by the Helm CLI. The chart name, version
+// and repository of an adopted release are late initialized from the
+// existing release if they are not set. Its values are not, because they may
+// contain secrets. The repository is only known for releases that were
+// installed or upgraded by the provider.
+const AnnotationKeyAdopt = "helm.crossplane.io/adopt"
+
+// AnnotationKeyApprovedDiff approves the upgrade of a Release that requires
+// approval with the supplied diff digest, like spec.forProvider.approvedDiff.
+const AnnotationKeyApprovedDiff = "helm.crossplane.io/approved-diff"
+
+// AnnotationKeyDebug enables the verbose logging of the Helm actions of a
+// Release if set to "true". Helm logs are written to the provider log at info
+// level, along with the name and namespace of the release.
+const AnnotationKeyDebug = "helm.crossplane.io/debug"
+
+// AnnotationKeyChartRepository records the repository of the chart of a
+// release in the annotations of the chart, which Helm stores with the
+// release.
+const AnnotationKeyChartRepository = "helm.crossplane.io/chart-repository"
+
+// AnnotationKeyCommonMetadata records the keys of the common labels and
+// annotations that were applied to a resource of a release, so that keys
+// removed from spec.forProvider.commonMetadata are removed by an upgrade.
+const AnnotationKeyCommonMetadata = "helm.crossplane.io/common-metadata"
+
 // A ChartSpec defines the chart spec for a Release
 type ChartSpec struct {
 	// Repository: Helm repository URL, required if ChartSpec.URL not set
@@ -35,6 +64,11 @@ type ChartSpec struct {
 	Version string `json:"version,omitempty"`
 	// URL to chart package (typically .tgz), optional and overrides others fields in the spec
 	URL string `json:"url,omitempty"`
+	// SourceType selects the chart fetcher registered with the provider that
+	// fetches the chart, e.g. from an internal artifact store. Charts are
+	// pulled from Helm repositories, OCI registries and URLs if not set.
+	// +optional
+	SourceType string `json:"sourceType,omitempty"`
 	// PullSecretRef is reference to the secret containing credentials to helm repository
 	PullSecretRef xpv1.SecretReference `json:"pullSecretRef,omitempty"`
 }
@@ -56,6 +90,25 @@ type DataKeySelector struct {
 type ValueFromSource struct {
 	ConfigMapKeyRef *DataKeySelector `json:"configMapKeyRef,omitempty"`
 	SecretKeyRef    *DataKeySelector `json:"secretKeyRef,omitempty"`
+	// Plugin reads the value from a value source plugin of the provider.
+	// +optional
+	Plugin *PluginValueSource `json:"plugin,omitempty"`
+}
+
+// PluginValueSource reads values from a value source plugin, e.g. one that
+// reads them from an internal configuration service.
+type PluginValueSource struct {
+	// Name of the plugin, as registered with the --value-source-plugin flag
+	// of the provider.
+	Name string `json:"name"`
+	// Config of the source passed to the plugin, e.g. the path of the values
+	// in a configuration service.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Config runtime.RawExtension `json:"config,omitempty"`
+	// Optional ignores values the plugin reports as missing.
+	// +optional
+	Optional bool `json:"optional,omitempty"`
 }
 
 // SetVal represents a "set" value override in a Release
@@ -73,24 +126,564 @@ type ValuesSpec struct {
 	Set        []SetVal             `json:"set,omitempty"`
 }
 
+// KustomizePatchTarget selects the rendered resources a patch applies to.
+type KustomizePatchTarget struct {
+	Group     string `json:"group,omitempty"`
+	Version   string `json:"version,omitempty"`
+	Kind      string `json:"kind,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	// LabelSelector is a label selector expression matched against the
+	// labels of the rendered resources.
+	LabelSelector string `json:"labelSelector,omitempty"`
+	// AnnotationSelector is a label selector expression matched against the
+	// annotations of the rendered resources.
+	AnnotationSelector string `json:"annotationSelector,omitempty"`
+}
+
+// KustomizePatch is an inline strategic merge or JSON 6902 patch.
+type KustomizePatch struct {
+	// Patch is the content of the patch. Both strategic merge and JSON 6902
+	// patches are supported.
+	Patch string `json:"patch"`
+	// Target selects the rendered resources the patch applies to. Required
+	// for JSON 6902 patches.
+	Target *KustomizePatchTarget `json:"target,omitempty"`
+}
+
+// KustomizePostRender describes kustomize patches applied to the rendered manifests.
+type KustomizePostRender struct {
+	// Patches applied to the rendered manifests.
+	Patches []KustomizePatch `json:"patches,omitempty"`
+}
+
+// PostRenderWebhook describes an HTTP or gRPC endpoint that post-renders
+// manifests. The rendered manifests are POSTed to HTTP endpoints as a
+// multi-document YAML stream and the response body replaces them. gRPC
+// endpoints serve the PostRenderService of postrender.proto.
+type PostRenderWebhook struct {
+	// URL of the post-render endpoint. The manifests are POSTed to http://
+	// and https:// URLs, and sent to the PostRenderService of grpc:// and
+	// grpcs:// URLs, e.g. grpcs://renderer.example.org:9443.
+	URL string `json:"url"`
+	// TLSSecretRef is a reference to a secret containing the TLS settings used
+	// to connect to the endpoint. The "ca.crt" key is used to verify the
+	// server, "tls.crt" and "tls.key" are used as client certificate. All keys
+	// are optional.
+	// +optional
+	TLSSecretRef *xpv1.SecretReference `json:"tlsSecretRef,omitempty"`
+	// BearerTokenSecretRef is a reference to a secret key containing a token
+	// sent in the Authorization header of each request.
+	// +optional
+	BearerTokenSecretRef *xpv1.SecretKeySelector `json:"bearerTokenSecretRef,omitempty"`
+	// Timeout of a single post-render request. Defaults to 30s.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// PostRender defines how the manifests rendered by Helm are modified before
+// they are installed or upgraded.
+type PostRender struct {
+	// Kustomize patches applied to the rendered manifests. They are applied
+	// after any patches loaded via PatchesFrom.
+	Kustomize *KustomizePostRender `json:"kustomize,omitempty"`
+	// Webhook post-renders the manifests using an HTTP or gRPC endpoint. It
+	// runs after all kustomize patches were applied.
+	Webhook *PostRenderWebhook `json:"webhook,omitempty"`
+}
+
+// ManifestOutputKind is the kind of object a manifest is published to.
+type ManifestOutputKind string
+
+// Kinds of objects a manifest can be published to.
+const (
+	ManifestOutputKindConfigMap ManifestOutputKind = "ConfigMap"
+	ManifestOutputKindSecret    ManifestOutputKind = "Secret"
+)
+
+// ManifestOutputCluster is the cluster a manifest is published to.
+type ManifestOutputCluster string
+
+// Clusters a manifest can be published to.
+const (
+	// ManifestOutputClusterLocal is the cluster the provider runs in.
+	ManifestOutputClusterLocal ManifestOutputCluster = "Local"
+	// ManifestOutputClusterTarget is the cluster the release is deployed to.
+	ManifestOutputClusterTarget ManifestOutputCluster = "Target"
+)
+
+// ManifestCompression is how a published manifest is compressed.
+type ManifestCompression string
+
+// Compressions of published manifests.
+const (
+	// ManifestCompressionNone publishes the manifest as is.
+	ManifestCompressionNone ManifestCompression = "None"
+	// ManifestCompressionGzip publishes the gzip compressed manifest, as
+	// binary data of a ConfigMap.
+	ManifestCompressionGzip ManifestCompression = "Gzip"
+)
+
+// ManifestOutput configures a ConfigMap or Secret the manifest of a Release
+// is published to.
+type ManifestOutput struct {
+	// Kind of the object the manifest is written to.
+	// +kubebuilder:validation:Enum=ConfigMap;Secret
+	Kind ManifestOutputKind `json:"kind"`
+	// Name of the object the manifest is written to.
+	Name string `json:"name"`
+	// Namespace of the object the manifest is written to.
+	Namespace string `json:"namespace"`
+	// Key the manifest is written to. Defaults to "manifest".
+	// +optional
+	Key string `json:"key,omitempty"`
+	// Cluster the object is written to. Defaults to Local.
+	// +optional
+	// +kubebuilder:validation:Enum=Local;Target
+	Cluster ManifestOutputCluster `json:"cluster,omitempty"`
+	// Compression of the manifest, so that the manifests of large charts
+	// fit into the object. Values read from manifests the provider published
+	// compressed are decompressed transparently, up to 16 MiB. Defaults to
+	// None.
+	// +optional
+	// +kubebuilder:validation:Enum=None;Gzip
+	Compression ManifestCompression `json:"compression,omitempty"`
+}
+
+// StatusLimits bound the details of the manifest of a Release that are
+// reported in its status, so that Releases of large charts stay small. The
+// full manifest may be published using a ManifestOutput.
+type StatusLimits struct {
+	// MaxResources is the maximum number of resources reported in the
+	// inventory. Zero omits the inventory. Defaults to 250.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxResources *int32 `json:"maxResources,omitempty"`
+	// MaxNotesLength is the maximum length in bytes of the release notes
+	// reported. Zero omits the notes. Defaults to 4096.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxNotesLength *int32 `json:"maxNotesLength,omitempty"`
+}
+
+// IgnoreDifferences selects fields of deployed resources that are ignored
+// when comparing them against the live state of the target cluster, e.g.
+// replicas managed by a HorizontalPodAutoscaler.
+type IgnoreDifferences struct {
+	// Group of the resources, empty for the core group.
+	// +optional
+	Group string `json:"group,omitempty"`
+	// Kind of the resources.
+	Kind string `json:"kind"`
+	// Name of the resource. All resources of the kind if not set.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Namespace of the resources. Resources in all namespaces if not set.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// JSONPointers to the ignored fields, e.g. /spec/replicas.
+	JSONPointers []string `json:"jsonPointers"`
+}
+
+// DriftPolicy determines how drift of deployed resources from the release
+// manifest is handled.
+type DriftPolicy string
+
+// Drift policies.
+const (
+	// DriftPolicyIgnore does not compare deployed resources with their live
+	// state.
+	DriftPolicyIgnore DriftPolicy = "Ignore"
+	// DriftPolicyDetect reports drifted resources in the status.
+	DriftPolicyDetect DriftPolicy = "Detect"
+	// DriftPolicyCorrect reports drifted resources and reverts the drift by
+	// upgrading the release.
+	DriftPolicyCorrect DriftPolicy = "Correct"
+)
+
+// ConflictPolicy determines how rendered resources that already exist but are
+// not part of the release are handled.
+type ConflictPolicy string
+
+// Conflict policies.
+const (
+	// ConflictPolicyFail fails the install or upgrade.
+	ConflictPolicyFail ConflictPolicy = "Fail"
+	// ConflictPolicyAdopt adds the Helm ownership metadata of the release to
+	// the existing resources, so that they are imported into the release.
+	ConflictPolicyAdopt ConflictPolicy = "Adopt"
+	// ConflictPolicyForce deletes the existing resources, so that they are
+	// recreated by the release.
+	ConflictPolicyForce ConflictPolicy = "Force"
+)
+
+// UninstallPolicy determines what happens to a release when its Release is
+// deleted.
+type UninstallPolicy string
+
+// Uninstall policies.
+const (
+	// UninstallPolicyUninstall uninstalls the release, deleting its
+	// resources.
+	UninstallPolicyUninstall UninstallPolicy = "Uninstall"
+	// UninstallPolicyKeepResources deletes the Helm release record but
+	// leaves the deployed resources in place. The canary release of an
+	// upgrade in progress is still uninstalled.
+	UninstallPolicyKeepResources UninstallPolicy = "KeepResources"
+)
+
+// UpgradeStrategyType determines how a release is upgraded.
+type UpgradeStrategyType string
+
+// Upgrade strategy types.
+const (
+	// UpgradeStrategyDirect upgrades the release in place.
+	UpgradeStrategyDirect UpgradeStrategyType = "Direct"
+	// UpgradeStrategyCanary verifies an upgrade with a canary release
+	// before it is applied to the release.
+	UpgradeStrategyCanary UpgradeStrategyType = "Canary"
+)
+
+// A CanaryStrategy configures the canary release of a Canary upgrade.
+type CanaryStrategy struct {
+	// Suffix of the name of the canary release. Defaults to "-canary".
+	// +optional
+	Suffix string `json:"suffix,omitempty"`
+	// Values are merged into the values of the canary release, e.g. to
+	// reduce its replicas.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Values runtime.RawExtension `json:"values,omitempty"`
+}
+
+// An UpgradeStrategy determines how a release is upgraded.
+type UpgradeStrategy struct {
+	// Type of the strategy. A Canary upgrade first installs the new chart
+	// version and values as a parallel canary release, waits for its
+	// resources to become healthy, then upgrades the release and uninstalls
+	// the canary release. Charts must derive the names of their resources
+	// from the release name to be upgraded with a canary. Defaults to
+	// Direct.
+	// +optional
+	// +kubebuilder:validation:Enum=Direct;Canary
+	Type UpgradeStrategyType `json:"type,omitempty"`
+	// Canary configures the canary release of Canary upgrades.
+	// +optional
+	Canary *CanaryStrategy `json:"canary,omitempty"`
+}
+
+// CanaryStatus is the observed state of the canary release of an upgrade.
+type CanaryStatus struct {
+	// ReleaseName is the name of the canary release.
+	ReleaseName string `json:"releaseName"`
+	// Digest of the chart, values and patches of the canary release.
+	Digest string `json:"digest"`
+	// Unhealthy resources of the canary release.
+	// +optional
+	Unhealthy []string `json:"unhealthy,omitempty"`
+}
+
+// A FailureTolerance suppresses transient failures to observe a release.
+type FailureTolerance struct {
+	// Threshold is the number of consecutive failures to observe the release
+	// that are tolerated before they are reported.
+	Threshold int32 `json:"threshold"`
+	// GracePeriod limits how long failures are tolerated since the first
+	// one, regardless of the threshold.
+	// +optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+}
+
+// A ManagementAction is an action the provider may take on the release of a
+// Release.
+// +kubebuilder:validation:Enum=Observe;Create;Update;Delete;LateInitialize;*
+type ManagementAction string
+
+// Management actions.
+const (
+	// ManagementActionObserve observes the release and publishes its status
+	// and connection details.
+	ManagementActionObserve ManagementAction = "Observe"
+	// ManagementActionCreate installs the release.
+	ManagementActionCreate ManagementAction = "Create"
+	// ManagementActionUpdate upgrades, rolls back and migrates the release
+	// and corrects drift of its resources.
+	ManagementActionUpdate ManagementAction = "Update"
+	// ManagementActionDelete uninstalls the release when the Release is
+	// deleted.
+	ManagementActionDelete ManagementAction = "Delete"
+	// ManagementActionLateInitialize fills unset fields of the Release from
+	// the release and the chart.
+	ManagementActionLateInitialize ManagementAction = "LateInitialize"
+	// ManagementActionAll allows all actions.
+	ManagementActionAll ManagementAction = "*"
+)
+
+// ReleaseNameChangePolicy determines what happens to a deployed release when
+// the release name of its Release changes.
+type ReleaseNameChangePolicy string
+
+// Release name change policies.
+const (
+	// ReleaseNameChangePolicyKeep keeps the previous release and warns that
+	// it is no longer managed.
+	ReleaseNameChangePolicyKeep ReleaseNameChangePolicy = "Keep"
+	// ReleaseNameChangePolicyRecreate uninstalls the previous release before
+	// the release is installed under its new name.
+	ReleaseNameChangePolicyRecreate ReleaseNameChangePolicy = "Recreate"
+)
+
+// NamespaceChangePolicy determines what happens to a deployed release when
+// the namespace of its Release changes.
+type NamespaceChangePolicy string
+
+// Namespace change policies.
+const (
+	// NamespaceChangePolicyForbid refuses to move the release until the
+	// namespace is changed back.
+	NamespaceChangePolicyForbid NamespaceChangePolicy = "Forbid"
+	// NamespaceChangePolicyRecreate uninstalls the release from the previous
+	// namespace before it is installed into the new one.
+	NamespaceChangePolicyRecreate NamespaceChangePolicy = "Recreate"
+	// NamespaceChangePolicyOrphan leaves the release in the previous
+	// namespace in place and installs the release into the new one.
+	NamespaceChangePolicyOrphan NamespaceChangePolicy = "Orphan"
+)
+
+// CommonMetadata is added to every resource rendered for a Release.
+type CommonMetadata struct {
+	// Labels added to every rendered resource.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations added to every rendered resource.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ArgoCD configures the metadata that Argo CD relies on to tell the
+// resources of a Release apart from those of its Applications.
+type ArgoCD struct {
+	// Application that Argo CD tracks the rendered resources as part of,
+	// using its label tracking method. Resources are not tracked if not set.
+	// +optional
+	Application string `json:"application,omitempty"`
+	// InstanceLabelKey is the label Argo CD is configured to track the
+	// resources of Applications by. Defaults to app.kubernetes.io/instance,
+	// which many charts set themselves.
+	// +optional
+	InstanceLabelKey string `json:"instanceLabelKey,omitempty"`
+	// Exclude annotates the rendered resources so that Argo CD neither
+	// prunes them nor reports them as out of sync, e.g. when they are
+	// deployed into a namespace managed by an Application.
+	// +optional
+	Exclude bool `json:"exclude,omitempty"`
+}
+
+// NamespaceMetadata is added to the namespace created for a Release.
+type NamespaceMetadata struct {
+	// Labels of the namespace.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations of the namespace.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
 // ReleaseParameters are the configurable fields of a Release.
 type ReleaseParameters struct {
 	Chart ChartSpec `json:"chart"`
 	// Namespace to install the release into.
 	Namespace string `json:"namespace"`
 	// SkipCreateNamespace won't create the namespace for the release. This requires the namespace to already exist.
-	SkipCreateNamespace bool `json:"skipCreateNamespace,omitempty"`
+	SkipCreateNamespace *bool `json:"skipCreateNamespace,omitempty"`
+	// NamespaceMetadata is added to the namespace when it is created for the
+	// release, e.g. pod security or sidecar injection labels. It is not
+	// applied to namespaces that already exist.
+	// +optional
+	NamespaceMetadata *NamespaceMetadata `json:"namespaceMetadata,omitempty"`
+	// DeleteNamespaceOnUninstall deletes the namespace when the release is
+	// uninstalled. Only namespaces created for the release are deleted, and
+	// only if no other releases or resources remain in them.
+	// +optional
+	DeleteNamespaceOnUninstall bool `json:"deleteNamespaceOnUninstall,omitempty"`
 	// Wait for the release to become ready.
-	Wait bool `json:"wait,omitempty"`
+	Wait *bool `json:"wait,omitempty"`
+	// HealthCheck gates the Ready condition of the Release on the health of
+	// the deployed resources, e.g. Deployments being available and Jobs being
+	// completed. Contrary to wait, health is assessed on every observation
+	// and reported in the Healthy condition.
+	// +optional
+	HealthCheck bool `json:"healthCheck,omitempty"`
+	// WaitForCRDs gates the Ready condition of the Release on the CRDs
+	// installed by the chart being established, so that custom resources of
+	// these CRDs can be created once the Release is ready. The outcome is
+	// reported in the CRDsEstablished condition.
+	// +optional
+	WaitForCRDs bool `json:"waitForCRDs,omitempty"`
+	// ReadinessChecks gate the Ready condition of the Release on application
+	// specific signals on the target cluster, e.g. a status field of a
+	// custom resource created by the chart. The outcome is reported in the
+	// ReadinessChecks condition.
+	// +optional
+	ReadinessChecks []ReadinessCheck `json:"readinessChecks,omitempty"`
+	// DependsOn are resources in the control plane, by default other
+	// Releases, whose Ready condition must be True before the release is
+	// installed or upgraded. Depending on resources of other providers
+	// requires granting the provider access to them.
+	// +optional
+	DependsOn []Dependency `json:"dependsOn,omitempty"`
+	// UpgradeWindows are the periods in which upgrades of the release are
+	// applied. Changes detected outside of the windows are deferred and
+	// reported as a pending upgrade. Installs and rollbacks of failed
+	// releases are not deferred. Upgrades are applied at any time if no
+	// windows are set.
+	// +optional
+	UpgradeWindows []UpgradeWindow `json:"upgradeWindows,omitempty"`
+	// UpgradeStrategy determines how the release is upgraded.
+	// +optional
+	UpgradeStrategy *UpgradeStrategy `json:"upgradeStrategy,omitempty"`
+	// Hibernation uninstalls the release on a schedule and reinstalls it
+	// later with the same chart version and values. The chart version is
+	// pinned when the release is installed.
+	// +optional
+	Hibernation *Hibernation `json:"hibernation,omitempty"`
+	// RequireApproval holds upgrades of the release until they are approved.
+	// A pending upgrade is reported in the PendingApproval condition with
+	// the digest of the proposed chart, values and patches, which is
+	// approved by setting ApprovedDiff or the
+	// helm.crossplane.io/approved-diff annotation to it.
+	// +optional
+	RequireApproval bool `json:"requireApproval,omitempty"`
+	// ApprovedDiff is the digest of the approved upgrade.
+	// +optional
+	ApprovedDiff string `json:"approvedDiff,omitempty"`
 	// WaitTimeout is the duration Helm will wait for the release to become
 	// ready. Only applies if wait is also set. Defaults to 5m.
 	WaitTimeout *metav1.Duration `json:"waitTimeout,omitempty"`
 	// PatchesFrom describe patches to be applied to the rendered manifests.
 	PatchesFrom []ValueFromSource `json:"patchesFrom,omitempty"`
+	// PostRender describes inline modifications of the rendered manifests.
+	PostRender *PostRender `json:"postRender,omitempty"`
+	// CommonMetadata is added to every resource rendered for the release,
+	// after any patches were applied.
+	CommonMetadata *CommonMetadata `json:"commonMetadata,omitempty"`
+	// ArgoCD adds the metadata Argo CD relies on to every resource rendered
+	// for the release, so that clusters co-managed by Argo CD don't fight
+	// over them. It is added along with CommonMetadata.
+	// +optional
+	ArgoCD *ArgoCD `json:"argoCD,omitempty"`
 	// ValuesSpec defines the Helm value overrides spec for a Release.
 	ValuesSpec `json:",inline"`
 	// SkipCRDs skips installation of CRDs for the release.
-	SkipCRDs bool `json:"skipCRDs,omitempty"`
+	SkipCRDs *bool `json:"skipCRDs,omitempty"`
+	// MaxHistory is the maximum number of revisions kept for the release.
+	// Defaults to 20.
+	// +optional
+	MaxHistory *int32 `json:"maxHistory,omitempty"`
+	// ReleaseClassName is the name of the ReleaseClass providing defaults
+	// and guardrails for the release.
+	// +optional
+	ReleaseClassName string `json:"releaseClassName,omitempty"`
+	// RenderOnly renders the chart without installing it. The rendered
+	// manifests are published to the connection secret under the "manifest"
+	// key. Switching an installed release to render only leaves it in place.
+	RenderOnly bool `json:"renderOnly,omitempty"`
+	// ServerSideDryRun validates the rendered manifests with a server-side
+	// dry-run against the target cluster before installing or upgrading.
+	// Rejected manifests fail the operation and are reported in the
+	// Validated condition.
+	ServerSideDryRun *bool `json:"serverSideDryRun,omitempty"`
+	// RBACPreflight checks that the provider may get, create and patch
+	// every rendered resource on the target cluster before installing or
+	// upgrading, so that missing permissions fail the operation before any
+	// resource is applied. They are reported in the Permitted condition.
+	// +optional
+	RBACPreflight *bool `json:"rbacPreflight,omitempty"`
+	// ServiceAccountName of a ServiceAccount in the release namespace on
+	// the target cluster that all operations on the release are performed
+	// as, so that its RBAC constrains what the release may deploy. It may
+	// not be set if the ProviderConfig impersonates a user, so that Releases
+	// can't escape the impersonation.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	// KubeConfigSecretRef overrides the credentials of the ProviderConfig
+	// with the kubeconfig in the referenced Secret key, e.g. for a one-off
+	// deployment to a cluster that has no ProviderConfig. It may only be set
+	// if the ProviderConfig allows Releases to supply kubeconfigs. The
+	// identity of the ProviderConfig is not used, so that its tokens are not
+	// sent to the server the kubeconfig names, but its impersonation and
+	// connection settings still apply.
+	// +optional
+	KubeConfigSecretRef *xpv1.SecretKeySelector `json:"kubeConfigSecretRef,omitempty"`
+	// Lint lints the chart with the composed values before installing or
+	// upgrading. Lint errors fail the operation, both errors and warnings are
+	// reported in the Linted condition.
+	Lint *bool `json:"lint,omitempty"`
+	// ManifestOutput publishes the deployed manifest, or the rendered one in
+	// render only mode, to a ConfigMap or Secret. The object is deleted with
+	// the Release, and objects of other Releases are not overwritten.
+	ManifestOutput *ManifestOutput `json:"manifestOutput,omitempty"`
+	// StatusLimits bound the details of the manifest reported in the status
+	// of the Release.
+	// +optional
+	StatusLimits *StatusLimits `json:"statusLimits,omitempty"`
+	// IgnoreDifferences are fields of deployed resources that may be mutated
+	// on the target cluster, e.g. by other controllers, without the release
+	// being considered out of sync. They are ignored when detecting drift of
+	// the deployed resources, including changes observed by drift watches.
+	IgnoreDifferences []IgnoreDifferences `json:"ignoreDifferences,omitempty"`
+	// DriftPolicy determines whether the live state of deployed resources is
+	// compared with the release manifest, and whether drift is reverted.
+	// Only fields set in the manifest are compared. Defaults to Ignore.
+	// +optional
+	// +kubebuilder:validation:Enum=Ignore;Detect;Correct
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+	// WatchDrift watches the deployed resources on the target cluster, so
+	// that their drift is observed as soon as they change rather than at
+	// the next poll. Only resources labelled app.kubernetes.io/managed-by:
+	// Helm, as charts conventionally do, are watched. The provider must be
+	// allowed to list and watch them. Has no effect if the DriftPolicy is
+	// Ignore.
+	// +optional
+	WatchDrift bool `json:"watchDrift,omitempty"`
+	// ConflictPolicy determines how rendered resources that already exist on
+	// the target cluster but are not part of the release are handled. The
+	// outcome is reported in the ConflictFree condition. Existing resources
+	// are not checked if no policy is set, in which case Helm fails to
+	// install or upgrade the release.
+	// +optional
+	// +kubebuilder:validation:Enum=Fail;Adopt;Force
+	ConflictPolicy ConflictPolicy `json:"conflictPolicy,omitempty"`
+	// UninstallPolicy determines what happens to the release when the
+	// Release is deleted. KeepResources removes the Helm release record but
+	// leaves the deployed resources in place, e.g. to hand them off to
+	// another tool. Defaults to Uninstall. Note that the Orphan deletion
+	// policy leaves both the release record and its resources in place.
+	// +optional
+	// +kubebuilder:validation:Enum=Uninstall;KeepResources
+	UninstallPolicy UninstallPolicy `json:"uninstallPolicy,omitempty"`
+	// DeletionProtection blocks the deletion of the Release while set. The
+	// release is neither uninstalled nor forgotten and the Release is kept
+	// until the flag is removed.
+	// +optional
+	DeletionProtection bool `json:"deletionProtection,omitempty"`
+	// ReleaseNameChangePolicy determines what happens to the deployed release
+	// when the release name, i.e. the external name of the Release, changes.
+	// Keep leaves the previous release in place and warns that it is no
+	// longer managed, Recreate uninstalls it before installing the release
+	// under its new name. Defaults to Keep.
+	// +optional
+	// +kubebuilder:validation:Enum=Keep;Recreate
+	ReleaseNameChangePolicy ReleaseNameChangePolicy `json:"releaseNameChangePolicy,omitempty"`
+	// NamespaceChangePolicy determines what happens to the deployed release
+	// when the namespace changes. Forbid refuses to move the release,
+	// Recreate uninstalls it from the previous namespace and Orphan leaves it
+	// there unmanaged before the release is installed into the new
+	// namespace. The outcome is reported in the Migrated condition. Defaults
+	// to Forbid.
+	// +optional
+	// +kubebuilder:validation:Enum=Forbid;Recreate;Orphan
+	NamespaceChangePolicy NamespaceChangePolicy `json:"namespaceChangePolicy,omitempty"`
+	// Notifications about events of the release, in addition to those
+	// configured by its ProviderConfig.
+	// +optional
+	Notifications []helmv1beta1.NotificationSink `json:"notifications,omitempty"`
 }
 
 // ReleaseObservation are the observable fields of a Release.
@@ -98,6 +691,89 @@ type ReleaseObservation struct {
 	State              release.Status `json:"state,omitempty"`
 	ReleaseDescription string         `json:"releaseDescription,omitempty"`
 	Revision           int            `json:"revision,omitempty"`
+	// FirstDeployed is when the first revision of the release was deployed.
+	// +optional
+	FirstDeployed *metav1.Time `json:"firstDeployed,omitempty"`
+	// LastDeployed is when the current revision of the release was
+	// deployed.
+	// +optional
+	LastDeployed *metav1.Time `json:"lastDeployed,omitempty"`
+	// ChartVersion is the version of the chart of the current revision.
+	// +optional
+	ChartVersion string `json:"chartVersion,omitempty"`
+	// AppVersion is the version of the application packaged by the chart of
+	// the current revision.
+	// +optional
+	AppVersion string `json:"appVersion,omitempty"`
+	// Notes are the rendered NOTES.txt of the chart of the current
+	// revision.
+	// +optional
+	Notes string `json:"notes,omitempty"`
+	// Drifted are deployed resources whose live state differs from the
+	// release manifest.
+	Drifted []string `json:"drifted,omitempty"`
+	// KeptResources are resources that were left in place on uninstall
+	// because of their helm.sh/resource-policy annotation.
+	KeptResources []string `json:"keptResources,omitempty"`
+	// Resources deployed by the current revision of the release.
+	Resources []ResourceRef `json:"resources,omitempty"`
+	// Images are the container images referenced by the resources deployed
+	// by the current revision of the release.
+	Images []string `json:"images,omitempty"`
+	// History lists the most recent revisions of the release, newest
+	// first.
+	// +optional
+	History []ReleaseRevision `json:"history,omitempty"`
+	// Truncated reports the details of the manifest that were omitted
+	// because of the status limits of the Release.
+	// +optional
+	Truncated *StatusTruncation `json:"truncated,omitempty"`
+}
+
+// StatusTruncation reports the details of the manifest of a Release that
+// were omitted from its status.
+type StatusTruncation struct {
+	// OmittedResources is the number of resources omitted from the
+	// inventory.
+	// +optional
+	OmittedResources int `json:"omittedResources,omitempty"`
+	// NotesTruncated is true if the release notes were truncated.
+	// +optional
+	NotesTruncated bool `json:"notesTruncated,omitempty"`
+	// ManifestRef is the ConfigMap or Secret the full manifest is published
+	// to, if any.
+	// +optional
+	ManifestRef *ManifestOutput `json:"manifestRef,omitempty"`
+}
+
+// A ReleaseRevision is a revision of a release.
+type ReleaseRevision struct {
+	// Revision number.
+	Revision int `json:"revision"`
+	// ChartVersion is the version of the chart of the revision.
+	// +optional
+	ChartVersion string `json:"chartVersion,omitempty"`
+	// AppVersion is the version of the application packaged by the chart of
+	// the revision.
+	// +optional
+	AppVersion string `json:"appVersion,omitempty"`
+	// Status of the revision, e.g. deployed or superseded.
+	// +optional
+	Status release.Status `json:"status,omitempty"`
+	// Description of the revision, e.g. Upgrade complete.
+	// +optional
+	Description string `json:"description,omitempty"`
+	// Deployed is when the revision was deployed.
+	// +optional
+	Deployed *metav1.Time `json:"deployed,omitempty"`
+}
+
+// A ResourceRef identifies a resource deployed by a release.
+type ResourceRef struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
 }
 
 // A ReleaseSpec defines the desired state of a Release.
@@ -105,8 +781,144 @@ type ReleaseSpec struct {
 	xpv1.ResourceSpec `json:",inline"`
 	ConnectionDetails []ConnectionDetail `json:"connectionDetails,omitempty"`
 	ForProvider       ReleaseParameters  `json:"forProvider"`
+	// ConnectionDetailTemplates render connection details from the values
+	// extracted by ConnectionDetails, e.g. to build a connection string.
+	// +optional
+	ConnectionDetailTemplates []ConnectionDetailTemplate `json:"connectionDetailTemplates,omitempty"`
+	// ConnectionDetailsTimeout is how long after the release was deployed
+	// the objects of ConnectionDetails may be missing, e.g. because an
+	// operator installed by the chart creates them asynchronously. Until
+	// then the Release is not ready; afterwards missing objects are an
+	// error. Defaults to 5 minutes.
+	// +optional
+	ConnectionDetailsTimeout *metav1.Duration `json:"connectionDetailsTimeout,omitempty"`
+	// PublishConnectionDetailsTo publishes the connection details to a
+	// secret store, e.g. Vault, in addition to the connection secret
+	// configured by WriteConnectionSecretToReference.
+	// +optional
+	PublishConnectionDetailsTo *PublishConnectionDetailsTo `json:"publishConnectionDetailsTo,omitempty"`
+	// AdditionalConnectionSecrets are Secrets that subsets of the connection
+	// details are written to in addition to the connection secret
+	// configured by WriteConnectionSecretToReference, e.g. to share the
+	// credentials of an application with the namespace of its team.
+	// +optional
+	AdditionalConnectionSecrets []ConnectionSecret `json:"additionalConnectionSecrets,omitempty"`
 	// RollbackRetriesLimit is max number of attempts to retry Helm deployment by rolling back the release.
 	RollbackRetriesLimit *int32 `json:"rollbackLimit,omitempty"`
+	// ManagementPolicies are the actions the provider may take on the
+	// release. The release is always observed. For example, ["Observe"]
+	// reports on a release without ever changing it, and
+	// ["Observe", "Create", "Update"] leaves the release in place when the
+	// Release is deleted. Defaults to ["*"], which allows all actions.
+	// +optional
+	ManagementPolicies []ManagementAction `json:"managementPolicies,omitempty"`
+	// ObserveOnly maps the Release to an existing release, whose status and
+	// connection details are published without ever installing, upgrading
+	// or uninstalling it. The chart and values of the Release are not
+	// compared with the release. Takes precedence over ManagementPolicies.
+	// +optional
+	ObserveOnly bool `json:"observeOnly,omitempty"`
+	// Suspend halts installs, upgrades and uninstalls of the release while
+	// its status is still observed. Deleting a suspended Release is blocked
+	// until it is resumed.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+	// TTL after which the Release uninstalls and deletes itself, counting
+	// from its creation. Useful for ephemeral environments. Releases that
+	// are suspended, protected from deletion or whose management policies
+	// don't allow deleting are not deleted; the Expired condition reports
+	// why.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+	// PollInterval at which the release is observed. Defaults to the poll
+	// interval of the provider.
+	// +optional
+	PollInterval *metav1.Duration `json:"pollInterval,omitempty"`
+	// FailureTolerance suppresses transient failures to observe the release,
+	// like API server hiccups. Tolerated failures neither change the
+	// conditions of the Release nor emit events, and the release is
+	// observed again shortly. Failures are reported immediately if not set.
+	// +optional
+	FailureTolerance *FailureTolerance `json:"failureTolerance,omitempty"`
+}
+
+// DiffSummary summarizes the changes of the last upgrade of a Release.
+// Resources are identified by their kind, namespace and name.
+type DiffSummary struct {
+	// FromRevision is the revision that was upgraded.
+	FromRevision int `json:"fromRevision"`
+	// Added resources.
+	Added []string `json:"added,omitempty"`
+	// Changed resources.
+	Changed []string `json:"changed,omitempty"`
+	// Removed resources.
+	Removed []string `json:"removed,omitempty"`
+}
+
+// A DeployAttempt is an install or upgrade of the release of a Release.
+type DeployAttempt struct {
+	// ChartVersion is the version of the chart the attempt targeted.
+	// +optional
+	ChartVersion string `json:"chartVersion,omitempty"`
+	// Digest of the chart, values and patches the attempt targeted.
+	// +optional
+	Digest string `json:"digest,omitempty"`
+	// Revision of the release created by the attempt. Not set if the
+	// attempt failed before a revision was created.
+	// +optional
+	Revision int `json:"revision,omitempty"`
+	// Time of the attempt.
+	Time metav1.Time `json:"time"`
+}
+
+// An AuditOperation is a change the provider made to the release of a
+// Release.
+type AuditOperation string
+
+// Audited operations.
+const (
+	AuditOperationInstall   AuditOperation = "Install"
+	AuditOperationUpgrade   AuditOperation = "Upgrade"
+	AuditOperationRollback  AuditOperation = "Rollback"
+	AuditOperationUninstall AuditOperation = "Uninstall"
+)
+
+// An AuditOutcome is the outcome of an audited operation.
+type AuditOutcome string
+
+// Outcomes of audited operations.
+const (
+	AuditOutcomeSucceeded AuditOutcome = "Succeeded"
+	AuditOutcomeFailed    AuditOutcome = "Failed"
+)
+
+// An AuditEntry records a change the provider made to the release of a
+// Release.
+type AuditEntry struct {
+	// Time of the operation.
+	Time metav1.Time `json:"time"`
+	// Operation the provider performed.
+	Operation AuditOperation `json:"operation"`
+	// Outcome of the operation.
+	Outcome AuditOutcome `json:"outcome"`
+	// Generation of the Release the operation was performed for. The
+	// change of the Release that led to the operation, and who made it,
+	// can be found in the audit log of the API server.
+	// +optional
+	Generation int64 `json:"generation,omitempty"`
+	// Revision of the release the operation created or, for rollbacks and
+	// uninstalls, acted on.
+	// +optional
+	Revision int `json:"revision,omitempty"`
+	// ChartVersion is the version of the chart of the revision.
+	// +optional
+	ChartVersion string `json:"chartVersion,omitempty"`
+	// Digest of the chart, values and patches of installs and upgrades.
+	// +optional
+	Digest string `json:"digest,omitempty"`
+	// Message reports why the operation failed.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // A ReleaseStatus represents the observed state of a Release.
@@ -116,12 +928,262 @@ type ReleaseStatus struct {
 	PatchesSha          string             `json:"patchesSha,omitempty"`
 	Failed              int32              `json:"failed,omitempty"`
 	Synced              bool               `json:"synced,omitempty"`
+	// ObservedGeneration is the generation of the Release that was last
+	// observed successfully. The status reflects the latest spec if it
+	// equals the generation of the Release.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// ReleaseName is the name of the deployed release.
+	ReleaseName string `json:"releaseName,omitempty"`
+	// ReleaseNamespace is the namespace of the deployed release.
+	ReleaseNamespace string `json:"releaseNamespace,omitempty"`
+	// LastDiff summarizes the changes of the last upgrade.
+	LastDiff *DiffSummary `json:"lastDiff,omitempty"`
+	// PendingUpgrade is true if an upgrade was deferred until the next
+	// upgrade window.
+	PendingUpgrade bool `json:"pendingUpgrade,omitempty"`
+	// Hibernated is true if the release is uninstalled by its hibernation
+	// schedule.
+	Hibernated bool `json:"hibernated,omitempty"`
+	// Canary is the canary release of an upgrade in progress.
+	Canary *CanaryStatus `json:"canary,omitempty"`
+	// SyncedDigest is the digest of the chart, values and patches of the
+	// last successful install or upgrade.
+	SyncedDigest string `json:"syncedDigest,omitempty"`
+	// SyncedRevision is the revision of the last successful install or
+	// upgrade.
+	SyncedRevision int `json:"syncedRevision,omitempty"`
+	// LastAttemptedRevision is the last install or upgrade of the release,
+	// whether or not it succeeded.
+	// +optional
+	LastAttemptedRevision *DeployAttempt `json:"lastAttemptedRevision,omitempty"`
+	// LastDeployedRevision is the last install or upgrade of the release
+	// that succeeded. The release still runs it if a later attempt failed,
+	// unless the failed revision was not rolled back.
+	// +optional
+	LastDeployedRevision *DeployAttempt `json:"lastDeployedRevision,omitempty"`
+	// AuditLog lists the most recent installs, upgrades, rollbacks and
+	// uninstalls of the release, oldest first.
+	// +optional
+	AuditLog []AuditEntry `json:"auditLog,omitempty"`
+	// ConsecutiveFailures is the number of tolerated consecutive failures to
+	// observe the release.
+	ConsecutiveFailures int32 `json:"consecutiveFailures,omitempty"`
+	// FailingSince is the time of the first of the consecutive failures.
+	FailingSince *metav1.Time `json:"failingSince,omitempty"`
+	// ExpiresAt is the time the Release deletes itself if a TTL is set.
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+}
+
+// ReadinessCheck is an application specific signal on the target cluster
+// that must be met before a Release becomes ready.
+type ReadinessCheck struct {
+	// Object whose state is checked. If set, FieldPath selects the field
+	// whose value is checked. The namespace defaults to the namespace of the
+	// release.
+	v1.ObjectReference `json:",inline"`
+	// Value the field at FieldPath must have. Any non-empty value is
+	// accepted if not set.
+	// +optional
+	Value string `json:"value,omitempty"`
+	// ConditionType of a status condition of the object that must be True.
+	// +optional
+	ConditionType string `json:"conditionType,omitempty"`
+}
+
+// An UpgradeWindow is a recurring period in which upgrades may be applied.
+type UpgradeWindow struct {
+	// Schedule is a cron expression with the five fields minute, hour, day
+	// of month, month and day of week, at which the window opens. Fields
+	// support "*", values, ranges, lists and steps like "*/15".
+	Schedule string `json:"schedule"`
+	// Duration the window stays open.
+	Duration metav1.Duration `json:"duration"`
+	// TimeZone of the schedule, like "Europe/Berlin". Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// A Hibernation uninstalls a release on a schedule and reinstalls it later,
+// e.g. outside of working hours.
+type Hibernation struct {
+	// Hibernate is a cron expression with five fields at which the release
+	// is uninstalled, like "0 20 * * 1-5".
+	Hibernate string `json:"hibernate"`
+	// WakeUp is a cron expression with five fields at which the release is
+	// reinstalled, like "0 7 * * 1-5".
+	WakeUp string `json:"wakeUp"`
+	// TimeZone of the schedules, like "Europe/Berlin". Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// A Dependency references a resource that must be ready before a Release is
+// installed or upgraded.
+type Dependency struct {
+	// APIVersion of the resource. Defaults to the API version of Release.
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+	// Kind of the resource. Defaults to Release.
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// Name of the resource.
+	Name string `json:"name"`
+	// Namespace of the resource, if it is namespaced.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
 }
 
 // ConnectionDetail todo
 type ConnectionDetail struct {
 	v1.ObjectReference    `json:",inline"`
 	ToConnectionSecretKey string `json:"toConnectionSecretKey,omitempty"`
+	// JSONPath expression evaluated against the referenced object, e.g.
+	// {.status.loadBalancer.ingress[0].ip}. Supports filters and ranges
+	// over lists, whose results are joined by spaces. Takes precedence
+	// over FieldPath.
+	// +optional
+	JSONPath string `json:"jsonPath,omitempty"`
+	// NotesRegex extracts the value from the rendered NOTES.txt of the
+	// release rather than from an object, e.g. URL: (\S+). The first
+	// capture group of the regular expression, or the whole match if it has
+	// none, is published. Combine with ConnectionDetailTemplates to build
+	// values from several matches.
+	// +optional
+	NotesRegex string `json:"notesRegex,omitempty"`
+	// AllKeys propagates all keys of the referenced Secret to the
+	// connection secret. FieldPath, JSONPath and ToConnectionSecretKey are
+	// ignored.
+	// +optional
+	AllKeys bool `json:"allKeys,omitempty"`
+	// KeyPrefix is prepended to the keys propagated by AllKeys.
+	// +optional
+	KeyPrefix string `json:"keyPrefix,omitempty"`
+	// SkipPartOfReleaseCheck reads the value from an object that is not
+	// annotated as part of the release, e.g. one created by an operator or
+	// job that the chart deployed.
+	// +optional
+	SkipPartOfReleaseCheck bool `json:"skipPartOfReleaseCheck,omitempty"`
+	// Encoding determines how the extracted value is transformed before it
+	// is published. Auto decodes the data of Secrets and the binaryData of
+	// ConfigMaps, which are base64 encoded. None publishes the value as is.
+	// Base64Decode decodes base64 encoded values of other fields, e.g. the
+	// caBundle of a webhook configuration. Base64Encode encodes the value.
+	// Defaults to Auto.
+	// +optional
+	// +kubebuilder:validation:Enum=Auto;None;Base64Decode;Base64Encode
+	Encoding ValueEncoding `json:"encoding,omitempty"`
+	// Transforms are applied in order to the value after it was encoded,
+	// e.g. to trim a trailing newline or to extract the host of a URL.
+	// Applied to each key propagated by AllKeys.
+	// +optional
+	Transforms []ValueTransform `json:"transforms,omitempty"`
+}
+
+// ValueEncoding determines how the value of a connection detail is
+// transformed before it is published.
+type ValueEncoding string
+
+// Value encodings.
+const (
+	// ValueEncodingAuto decodes the base64 encoded data of Secrets and
+	// binaryData of ConfigMaps.
+	ValueEncodingAuto ValueEncoding = "Auto"
+	// ValueEncodingNone publishes values as is.
+	ValueEncodingNone ValueEncoding = "None"
+	// ValueEncodingBase64Decode decodes base64 encoded values.
+	ValueEncodingBase64Decode ValueEncoding = "Base64Decode"
+	// ValueEncodingBase64Encode encodes values as base64.
+	ValueEncodingBase64Encode ValueEncoding = "Base64Encode"
+)
+
+// A ValueTransformType is a type of transformation of the value of a
+// connection detail.
+type ValueTransformType string
+
+// Value transformation types.
+const (
+	// ValueTransformTrim removes leading and trailing white space.
+	ValueTransformTrim ValueTransformType = "Trim"
+	// ValueTransformToLower converts all letters to lower case.
+	ValueTransformToLower ValueTransformType = "ToLower"
+	// ValueTransformBase64Encode encodes the value as base64.
+	ValueTransformBase64Encode ValueTransformType = "Base64Encode"
+	// ValueTransformBase64Decode decodes a base64 encoded value.
+	ValueTransformBase64Decode ValueTransformType = "Base64Decode"
+	// ValueTransformRegex replaces the value by a match of a regular
+	// expression.
+	ValueTransformRegex ValueTransformType = "Regex"
+)
+
+// A ValueTransform transforms the value of a connection detail.
+type ValueTransform struct {
+	// Type of the transformation.
+	// +kubebuilder:validation:Enum=Trim;ToLower;Base64Encode;Base64Decode;Regex
+	Type ValueTransformType `json:"type"`
+	// Regex whose first capture group, or whole match if it has none,
+	// replaces the value, e.g. ^https?://([^/:]+). A value that does not
+	// match is an error. Required by Regex transformations.
+	// +optional
+	Regex string `json:"regex,omitempty"`
+}
+
+// ConnectionSecretMetadata is added to a connection secret.
+type ConnectionSecretMetadata struct {
+	// Labels of the connection secret.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations of the connection secret.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Type of the connection secret.
+	// +optional
+	Type *v1.SecretType `json:"type,omitempty"`
+}
+
+// PublishConnectionDetailsTo configures the secret store the connection
+// details of a Release are published to.
+type PublishConnectionDetailsTo struct {
+	// Name of the connection secret in the store. It is a single segment of
+	// the path of the secrets of Vault and Plugin stores.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9]([-_.a-zA-Z0-9]*[a-zA-Z0-9])?$`
+	Name string `json:"name"`
+	// Metadata of the connection secret. Only supported by Kubernetes
+	// stores.
+	// +optional
+	Metadata *ConnectionSecretMetadata `json:"metadata,omitempty"`
+	// SecretStoreConfigRef references the StoreConfig of the secret store.
+	// +optional
+	// +kubebuilder:default={"name": "default"}
+	SecretStoreConfigRef *xpv1.Reference `json:"configRef,omitempty"`
+}
+
+// A ConnectionSecret is a Secret that a subset of the connection details of
+// a Release is written to.
+type ConnectionSecret struct {
+	// Name of the Secret.
+	Name string `json:"name"`
+	// Namespace of the Secret.
+	Namespace string `json:"namespace"`
+	// Keys of the connection details written to the Secret. All connection
+	// details are written if not set.
+	// +optional
+	Keys []string `json:"keys,omitempty"`
+	// Metadata of the Secret.
+	// +optional
+	Metadata *ConnectionSecretMetadata `json:"metadata,omitempty"`
+}
+
+// A ConnectionDetailTemplate renders a connection detail from other
+// connection details.
+type ConnectionDetailTemplate struct {
+	// ToConnectionSecretKey is the key of the rendered connection detail.
+	ToConnectionSecretKey string `json:"toConnectionSecretKey"`
+	// Template is a Go template rendered with the connection details
+	// extracted so far, by key. For example,
+	// postgres://{{ .username }}:{{ urlquery .password }}@{{ .host }}:{{ .port }}/{{ .database }}.
+	// Keys that are not valid identifiers can be accessed with
+	// {{ index . "db-password" }}. Referencing a missing key is an error.
+	Template string `json:"template"`
 }
 
 // +kubebuilder:object:root=true
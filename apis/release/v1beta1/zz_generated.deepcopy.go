@@ -21,10 +21,80 @@ limitations under the License.
 package v1beta1
 
 import (
+	apisv1beta1 "github.com/crossplane-contrib/provider-helm/apis/v1beta1"
+	commonv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCD) DeepCopyInto(out *ArgoCD) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCD.
+func (in *ArgoCD) DeepCopy() *ArgoCD {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCD)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditEntry) DeepCopyInto(out *AuditEntry) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditEntry.
+func (in *AuditEntry) DeepCopy() *AuditEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryStatus) DeepCopyInto(out *CanaryStatus) {
+	*out = *in
+	if in.Unhealthy != nil {
+		in, out := &in.Unhealthy, &out.Unhealthy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStatus.
+func (in *CanaryStatus) DeepCopy() *CanaryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryStrategy) DeepCopyInto(out *CanaryStrategy) {
+	*out = *in
+	in.Values.DeepCopyInto(&out.Values)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStrategy.
+func (in *CanaryStrategy) DeepCopy() *CanaryStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChartSpec) DeepCopyInto(out *ChartSpec) {
 	*out = *in
@@ -36,60 +106,882 @@ func (in *ChartSpec) DeepCopy() *ChartSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ChartSpec)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ConnectionDetail) DeepCopyInto(out *ConnectionDetail) {
-	*out = *in
-	out.ObjectReference = in.ObjectReference
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionDetail.
-func (in *ConnectionDetail) DeepCopy() *ConnectionDetail {
-	if in == nil {
-		return nil
+	out := new(ChartSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommonMetadata) DeepCopyInto(out *CommonMetadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommonMetadata.
+func (in *CommonMetadata) DeepCopy() *CommonMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(CommonMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionDetail) DeepCopyInto(out *ConnectionDetail) {
+	*out = *in
+	out.ObjectReference = in.ObjectReference
+	if in.Transforms != nil {
+		in, out := &in.Transforms, &out.Transforms
+		*out = make([]ValueTransform, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionDetail.
+func (in *ConnectionDetail) DeepCopy() *ConnectionDetail {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionDetail)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionDetailTemplate) DeepCopyInto(out *ConnectionDetailTemplate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionDetailTemplate.
+func (in *ConnectionDetailTemplate) DeepCopy() *ConnectionDetailTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionDetailTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionSecret) DeepCopyInto(out *ConnectionSecret) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(ConnectionSecretMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionSecret.
+func (in *ConnectionSecret) DeepCopy() *ConnectionSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionSecretMetadata) DeepCopyInto(out *ConnectionSecretMetadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(corev1.SecretType)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionSecretMetadata.
+func (in *ConnectionSecretMetadata) DeepCopy() *ConnectionSecretMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionSecretMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataKeySelector) DeepCopyInto(out *DataKeySelector) {
+	*out = *in
+	out.NamespacedName = in.NamespacedName
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataKeySelector.
+func (in *DataKeySelector) DeepCopy() *DataKeySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(DataKeySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Dependency) DeepCopyInto(out *Dependency) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Dependency.
+func (in *Dependency) DeepCopy() *Dependency {
+	if in == nil {
+		return nil
+	}
+	out := new(Dependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeployAttempt) DeepCopyInto(out *DeployAttempt) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeployAttempt.
+func (in *DeployAttempt) DeepCopy() *DeployAttempt {
+	if in == nil {
+		return nil
+	}
+	out := new(DeployAttempt)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiffSummary) DeepCopyInto(out *DiffSummary) {
+	*out = *in
+	if in.Added != nil {
+		in, out := &in.Added, &out.Added
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Changed != nil {
+		in, out := &in.Changed, &out.Changed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Removed != nil {
+		in, out := &in.Removed, &out.Removed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiffSummary.
+func (in *DiffSummary) DeepCopy() *DiffSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(DiffSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailureTolerance) DeepCopyInto(out *FailureTolerance) {
+	*out = *in
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailureTolerance.
+func (in *FailureTolerance) DeepCopy() *FailureTolerance {
+	if in == nil {
+		return nil
+	}
+	out := new(FailureTolerance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Hibernation) DeepCopyInto(out *Hibernation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Hibernation.
+func (in *Hibernation) DeepCopy() *Hibernation {
+	if in == nil {
+		return nil
+	}
+	out := new(Hibernation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IgnoreDifferences) DeepCopyInto(out *IgnoreDifferences) {
+	*out = *in
+	if in.JSONPointers != nil {
+		in, out := &in.JSONPointers, &out.JSONPointers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IgnoreDifferences.
+func (in *IgnoreDifferences) DeepCopy() *IgnoreDifferences {
+	if in == nil {
+		return nil
+	}
+	out := new(IgnoreDifferences)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KustomizePatch) DeepCopyInto(out *KustomizePatch) {
+	*out = *in
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(KustomizePatchTarget)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KustomizePatch.
+func (in *KustomizePatch) DeepCopy() *KustomizePatch {
+	if in == nil {
+		return nil
+	}
+	out := new(KustomizePatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KustomizePatchTarget) DeepCopyInto(out *KustomizePatchTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KustomizePatchTarget.
+func (in *KustomizePatchTarget) DeepCopy() *KustomizePatchTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(KustomizePatchTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KustomizePostRender) DeepCopyInto(out *KustomizePostRender) {
+	*out = *in
+	if in.Patches != nil {
+		in, out := &in.Patches, &out.Patches
+		*out = make([]KustomizePatch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KustomizePostRender.
+func (in *KustomizePostRender) DeepCopy() *KustomizePostRender {
+	if in == nil {
+		return nil
+	}
+	out := new(KustomizePostRender)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManifestOutput) DeepCopyInto(out *ManifestOutput) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestOutput.
+func (in *ManifestOutput) DeepCopy() *ManifestOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(ManifestOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceMetadata) DeepCopyInto(out *NamespaceMetadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceMetadata.
+func (in *NamespaceMetadata) DeepCopy() *NamespaceMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacedName) DeepCopyInto(out *NamespacedName) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedName.
+func (in *NamespacedName) DeepCopy() *NamespacedName {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacedName)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PluginValueSource) DeepCopyInto(out *PluginValueSource) {
+	*out = *in
+	in.Config.DeepCopyInto(&out.Config)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginValueSource.
+func (in *PluginValueSource) DeepCopy() *PluginValueSource {
+	if in == nil {
+		return nil
+	}
+	out := new(PluginValueSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostRender) DeepCopyInto(out *PostRender) {
+	*out = *in
+	if in.Kustomize != nil {
+		in, out := &in.Kustomize, &out.Kustomize
+		*out = new(KustomizePostRender)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(PostRenderWebhook)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostRender.
+func (in *PostRender) DeepCopy() *PostRender {
+	if in == nil {
+		return nil
+	}
+	out := new(PostRender)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostRenderWebhook) DeepCopyInto(out *PostRenderWebhook) {
+	*out = *in
+	if in.TLSSecretRef != nil {
+		in, out := &in.TLSSecretRef, &out.TLSSecretRef
+		*out = new(commonv1.SecretReference)
+		**out = **in
+	}
+	if in.BearerTokenSecretRef != nil {
+		in, out := &in.BearerTokenSecretRef, &out.BearerTokenSecretRef
+		*out = new(commonv1.SecretKeySelector)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostRenderWebhook.
+func (in *PostRenderWebhook) DeepCopy() *PostRenderWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(PostRenderWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublishConnectionDetailsTo) DeepCopyInto(out *PublishConnectionDetailsTo) {
+	*out = *in
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(ConnectionSecretMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretStoreConfigRef != nil {
+		in, out := &in.SecretStoreConfigRef, &out.SecretStoreConfigRef
+		*out = new(commonv1.Reference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublishConnectionDetailsTo.
+func (in *PublishConnectionDetailsTo) DeepCopy() *PublishConnectionDetailsTo {
+	if in == nil {
+		return nil
+	}
+	out := new(PublishConnectionDetailsTo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadinessCheck) DeepCopyInto(out *ReadinessCheck) {
+	*out = *in
+	out.ObjectReference = in.ObjectReference
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadinessCheck.
+func (in *ReadinessCheck) DeepCopy() *ReadinessCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadinessCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Release) DeepCopyInto(out *Release) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Release.
+func (in *Release) DeepCopy() *Release {
+	if in == nil {
+		return nil
+	}
+	out := new(Release)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Release) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReleaseClass) DeepCopyInto(out *ReleaseClass) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseClass.
+func (in *ReleaseClass) DeepCopy() *ReleaseClass {
+	if in == nil {
+		return nil
+	}
+	out := new(ReleaseClass)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ReleaseClass) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReleaseClassDefaults) DeepCopyInto(out *ReleaseClassDefaults) {
+	*out = *in
+	if in.WaitTimeout != nil {
+		in, out := &in.WaitTimeout, &out.WaitTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxHistory != nil {
+		in, out := &in.MaxHistory, &out.MaxHistory
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NamespaceMetadata != nil {
+		in, out := &in.NamespaceMetadata, &out.NamespaceMetadata
+		*out = new(NamespaceMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseClassDefaults.
+func (in *ReleaseClassDefaults) DeepCopy() *ReleaseClassDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(ReleaseClassDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReleaseClassList) DeepCopyInto(out *ReleaseClassList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ReleaseClass, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseClassList.
+func (in *ReleaseClassList) DeepCopy() *ReleaseClassList {
+	if in == nil {
+		return nil
+	}
+	out := new(ReleaseClassList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ReleaseClassList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReleaseClassSpec) DeepCopyInto(out *ReleaseClassSpec) {
+	*out = *in
+	in.Defaults.DeepCopyInto(&out.Defaults)
+	if in.AllowedRepositories != nil {
+		in, out := &in.AllowedRepositories, &out.AllowedRepositories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseClassSpec.
+func (in *ReleaseClassSpec) DeepCopy() *ReleaseClassSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReleaseClassSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReleaseList) DeepCopyInto(out *ReleaseList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Release, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseList.
+func (in *ReleaseList) DeepCopy() *ReleaseList {
+	if in == nil {
+		return nil
+	}
+	out := new(ReleaseList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ReleaseList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReleaseObservation) DeepCopyInto(out *ReleaseObservation) {
+	*out = *in
+	if in.FirstDeployed != nil {
+		in, out := &in.FirstDeployed, &out.FirstDeployed
+		*out = (*in).DeepCopy()
+	}
+	if in.LastDeployed != nil {
+		in, out := &in.LastDeployed, &out.LastDeployed
+		*out = (*in).DeepCopy()
+	}
+	if in.Drifted != nil {
+		in, out := &in.Drifted, &out.Drifted
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.KeptResources != nil {
+		in, out := &in.KeptResources, &out.KeptResources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ResourceRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]ReleaseRevision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Truncated != nil {
+		in, out := &in.Truncated, &out.Truncated
+		*out = new(StatusTruncation)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseObservation.
+func (in *ReleaseObservation) DeepCopy() *ReleaseObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(ReleaseObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReleaseParameters) DeepCopyInto(out *ReleaseParameters) {
+	*out = *in
+	out.Chart = in.Chart
+	if in.SkipCreateNamespace != nil {
+		in, out := &in.SkipCreateNamespace, &out.SkipCreateNamespace
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NamespaceMetadata != nil {
+		in, out := &in.NamespaceMetadata, &out.NamespaceMetadata
+		*out = new(NamespaceMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Wait != nil {
+		in, out := &in.Wait, &out.Wait
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ReadinessChecks != nil {
+		in, out := &in.ReadinessChecks, &out.ReadinessChecks
+		*out = make([]ReadinessCheck, len(*in))
+		copy(*out, *in)
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]Dependency, len(*in))
+		copy(*out, *in)
+	}
+	if in.UpgradeWindows != nil {
+		in, out := &in.UpgradeWindows, &out.UpgradeWindows
+		*out = make([]UpgradeWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.UpgradeStrategy != nil {
+		in, out := &in.UpgradeStrategy, &out.UpgradeStrategy
+		*out = new(UpgradeStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hibernation != nil {
+		in, out := &in.Hibernation, &out.Hibernation
+		*out = new(Hibernation)
+		**out = **in
+	}
+	if in.WaitTimeout != nil {
+		in, out := &in.WaitTimeout, &out.WaitTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.PatchesFrom != nil {
+		in, out := &in.PatchesFrom, &out.PatchesFrom
+		*out = make([]ValueFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PostRender != nil {
+		in, out := &in.PostRender, &out.PostRender
+		*out = new(PostRender)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CommonMetadata != nil {
+		in, out := &in.CommonMetadata, &out.CommonMetadata
+		*out = new(CommonMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ArgoCD != nil {
+		in, out := &in.ArgoCD, &out.ArgoCD
+		*out = new(ArgoCD)
+		**out = **in
+	}
+	in.ValuesSpec.DeepCopyInto(&out.ValuesSpec)
+	if in.SkipCRDs != nil {
+		in, out := &in.SkipCRDs, &out.SkipCRDs
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxHistory != nil {
+		in, out := &in.MaxHistory, &out.MaxHistory
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ServerSideDryRun != nil {
+		in, out := &in.ServerSideDryRun, &out.ServerSideDryRun
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RBACPreflight != nil {
+		in, out := &in.RBACPreflight, &out.RBACPreflight
+		*out = new(bool)
+		**out = **in
+	}
+	if in.KubeConfigSecretRef != nil {
+		in, out := &in.KubeConfigSecretRef, &out.KubeConfigSecretRef
+		*out = new(commonv1.SecretKeySelector)
+		**out = **in
+	}
+	if in.Lint != nil {
+		in, out := &in.Lint, &out.Lint
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ManifestOutput != nil {
+		in, out := &in.ManifestOutput, &out.ManifestOutput
+		*out = new(ManifestOutput)
+		**out = **in
+	}
+	if in.StatusLimits != nil {
+		in, out := &in.StatusLimits, &out.StatusLimits
+		*out = new(StatusLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IgnoreDifferences != nil {
+		in, out := &in.IgnoreDifferences, &out.IgnoreDifferences
+		*out = make([]IgnoreDifferences, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = make([]apisv1beta1.NotificationSink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	out := new(ConnectionDetail)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataKeySelector) DeepCopyInto(out *DataKeySelector) {
-	*out = *in
-	out.NamespacedName = in.NamespacedName
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataKeySelector.
-func (in *DataKeySelector) DeepCopy() *DataKeySelector {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseParameters.
+func (in *ReleaseParameters) DeepCopy() *ReleaseParameters {
 	if in == nil {
 		return nil
 	}
-	out := new(DataKeySelector)
+	out := new(ReleaseParameters)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NamespacedName) DeepCopyInto(out *NamespacedName) {
+func (in *ReleaseRevision) DeepCopyInto(out *ReleaseRevision) {
 	*out = *in
+	if in.Deployed != nil {
+		in, out := &in.Deployed, &out.Deployed
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedName.
-func (in *NamespacedName) DeepCopy() *NamespacedName {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseRevision.
+func (in *ReleaseRevision) DeepCopy() *ReleaseRevision {
 	if in == nil {
 		return nil
 	}
-	out := new(NamespacedName)
+	out := new(ReleaseRevision)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Release) DeepCopyInto(out *Release) {
+func (in *ReleaseSet) DeepCopyInto(out *ReleaseSet) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -97,18 +989,18 @@ func (in *Release) DeepCopyInto(out *Release) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Release.
-func (in *Release) DeepCopy() *Release {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseSet.
+func (in *ReleaseSet) DeepCopy() *ReleaseSet {
 	if in == nil {
 		return nil
 	}
-	out := new(Release)
+	out := new(ReleaseSet)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Release) DeepCopyObject() runtime.Object {
+func (in *ReleaseSet) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -116,31 +1008,31 @@ func (in *Release) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReleaseList) DeepCopyInto(out *ReleaseList) {
+func (in *ReleaseSetList) DeepCopyInto(out *ReleaseSetList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Release, len(*in))
+		*out = make([]ReleaseSet, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseList.
-func (in *ReleaseList) DeepCopy() *ReleaseList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseSetList.
+func (in *ReleaseSetList) DeepCopy() *ReleaseSetList {
 	if in == nil {
 		return nil
 	}
-	out := new(ReleaseList)
+	out := new(ReleaseSetList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ReleaseList) DeepCopyObject() runtime.Object {
+func (in *ReleaseSetList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -148,45 +1040,84 @@ func (in *ReleaseList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReleaseObservation) DeepCopyInto(out *ReleaseObservation) {
+func (in *ReleaseSetMember) DeepCopyInto(out *ReleaseSetMember) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseObservation.
-func (in *ReleaseObservation) DeepCopy() *ReleaseObservation {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseSetMember.
+func (in *ReleaseSetMember) DeepCopy() *ReleaseSetMember {
 	if in == nil {
 		return nil
 	}
-	out := new(ReleaseObservation)
+	out := new(ReleaseSetMember)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReleaseParameters) DeepCopyInto(out *ReleaseParameters) {
+func (in *ReleaseSetOverride) DeepCopyInto(out *ReleaseSetOverride) {
 	*out = *in
-	out.Chart = in.Chart
-	if in.WaitTimeout != nil {
-		in, out := &in.WaitTimeout, &out.WaitTimeout
-		*out = new(v1.Duration)
-		**out = **in
+	in.Values.DeepCopyInto(&out.Values)
+	if in.Set != nil {
+		in, out := &in.Set, &out.Set
+		*out = make([]SetVal, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.PatchesFrom != nil {
-		in, out := &in.PatchesFrom, &out.PatchesFrom
-		*out = make([]ValueFromSource, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseSetOverride.
+func (in *ReleaseSetOverride) DeepCopy() *ReleaseSetOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ReleaseSetOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReleaseSetSpec) DeepCopyInto(out *ReleaseSetSpec) {
+	*out = *in
+	in.ProviderConfigSelector.DeepCopyInto(&out.ProviderConfigSelector)
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]ReleaseSetOverride, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	in.ValuesSpec.DeepCopyInto(&out.ValuesSpec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseParameters.
-func (in *ReleaseParameters) DeepCopy() *ReleaseParameters {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseSetSpec.
+func (in *ReleaseSetSpec) DeepCopy() *ReleaseSetSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ReleaseParameters)
+	out := new(ReleaseSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReleaseSetStatus) DeepCopyInto(out *ReleaseSetStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	if in.Releases != nil {
+		in, out := &in.Releases, &out.Releases
+		*out = make([]ReleaseSetMember, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseSetStatus.
+func (in *ReleaseSetStatus) DeepCopy() *ReleaseSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReleaseSetStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -198,14 +1129,58 @@ func (in *ReleaseSpec) DeepCopyInto(out *ReleaseSpec) {
 	if in.ConnectionDetails != nil {
 		in, out := &in.ConnectionDetails, &out.ConnectionDetails
 		*out = make([]ConnectionDetail, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ConnectionDetailTemplates != nil {
+		in, out := &in.ConnectionDetailTemplates, &out.ConnectionDetailTemplates
+		*out = make([]ConnectionDetailTemplate, len(*in))
 		copy(*out, *in)
 	}
+	if in.ConnectionDetailsTimeout != nil {
+		in, out := &in.ConnectionDetailsTimeout, &out.ConnectionDetailsTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.PublishConnectionDetailsTo != nil {
+		in, out := &in.PublishConnectionDetailsTo, &out.PublishConnectionDetailsTo
+		*out = new(PublishConnectionDetailsTo)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalConnectionSecrets != nil {
+		in, out := &in.AdditionalConnectionSecrets, &out.AdditionalConnectionSecrets
+		*out = make([]ConnectionSecret, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
 	if in.RollbackRetriesLimit != nil {
 		in, out := &in.RollbackRetriesLimit, &out.RollbackRetriesLimit
 		*out = new(int32)
 		**out = **in
 	}
+	if in.ManagementPolicies != nil {
+		in, out := &in.ManagementPolicies, &out.ManagementPolicies
+		*out = make([]ManagementAction, len(*in))
+		copy(*out, *in)
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.PollInterval != nil {
+		in, out := &in.PollInterval, &out.PollInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.FailureTolerance != nil {
+		in, out := &in.FailureTolerance, &out.FailureTolerance
+		*out = new(FailureTolerance)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseSpec.
@@ -222,7 +1197,42 @@ func (in *ReleaseSpec) DeepCopy() *ReleaseSpec {
 func (in *ReleaseStatus) DeepCopyInto(out *ReleaseStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
-	out.AtProvider = in.AtProvider
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+	if in.LastDiff != nil {
+		in, out := &in.LastDiff, &out.LastDiff
+		*out = new(DiffSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailingSince != nil {
+		in, out := &in.FailingSince, &out.FailingSince
+		*out = (*in).DeepCopy()
+	}
+	if in.LastAttemptedRevision != nil {
+		in, out := &in.LastAttemptedRevision, &out.LastAttemptedRevision
+		*out = new(DeployAttempt)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastDeployedRevision != nil {
+		in, out := &in.LastDeployedRevision, &out.LastDeployedRevision
+		*out = new(DeployAttempt)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuditLog != nil {
+		in, out := &in.AuditLog, &out.AuditLog
+		*out = make([]AuditEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseStatus.
@@ -235,6 +1245,67 @@ func (in *ReleaseStatus) DeepCopy() *ReleaseStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReleaseTemplate) DeepCopyInto(out *ReleaseTemplate) {
+	*out = *in
+	in.Metadata.DeepCopyInto(&out.Metadata)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseTemplate.
+func (in *ReleaseTemplate) DeepCopy() *ReleaseTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ReleaseTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReleaseTemplateMetadata) DeepCopyInto(out *ReleaseTemplateMetadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseTemplateMetadata.
+func (in *ReleaseTemplateMetadata) DeepCopy() *ReleaseTemplateMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(ReleaseTemplateMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceRef) DeepCopyInto(out *ResourceRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRef.
+func (in *ResourceRef) DeepCopy() *ResourceRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SetVal) DeepCopyInto(out *SetVal) {
 	*out = *in
@@ -255,6 +1326,87 @@ func (in *SetVal) DeepCopy() *SetVal {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatusLimits) DeepCopyInto(out *StatusLimits) {
+	*out = *in
+	if in.MaxResources != nil {
+		in, out := &in.MaxResources, &out.MaxResources
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxNotesLength != nil {
+		in, out := &in.MaxNotesLength, &out.MaxNotesLength
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatusLimits.
+func (in *StatusLimits) DeepCopy() *StatusLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(StatusLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatusTruncation) DeepCopyInto(out *StatusTruncation) {
+	*out = *in
+	if in.ManifestRef != nil {
+		in, out := &in.ManifestRef, &out.ManifestRef
+		*out = new(ManifestOutput)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatusTruncation.
+func (in *StatusTruncation) DeepCopy() *StatusTruncation {
+	if in == nil {
+		return nil
+	}
+	out := new(StatusTruncation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeStrategy) DeepCopyInto(out *UpgradeStrategy) {
+	*out = *in
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeStrategy.
+func (in *UpgradeStrategy) DeepCopy() *UpgradeStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeWindow) DeepCopyInto(out *UpgradeWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeWindow.
+func (in *UpgradeWindow) DeepCopy() *UpgradeWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ValueFromSource) DeepCopyInto(out *ValueFromSource) {
 	*out = *in
@@ -268,6 +1420,11 @@ func (in *ValueFromSource) DeepCopyInto(out *ValueFromSource) {
 		*out = new(DataKeySelector)
 		**out = **in
 	}
+	if in.Plugin != nil {
+		in, out := &in.Plugin, &out.Plugin
+		*out = new(PluginValueSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValueFromSource.
@@ -280,6 +1437,21 @@ func (in *ValueFromSource) DeepCopy() *ValueFromSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValueTransform) DeepCopyInto(out *ValueTransform) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValueTransform.
+func (in *ValueTransform) DeepCopy() *ValueTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(ValueTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ValuesSpec) DeepCopyInto(out *ValuesSpec) {
 	*out = *in
@@ -0,0 +1,56 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TypeHealthy indicates whether the target cluster of a ProviderConfig is
+// reachable using its credentials.
+const TypeHealthy xpv1.ConditionType = "Healthy"
+
+// Reasons the target cluster of a ProviderConfig is or is not healthy.
+const (
+	ReasonClusterReachable   xpv1.ConditionReason = "ClusterReachable"
+	ReasonClusterUnreachable xpv1.ConditionReason = "ClusterUnreachable"
+)
+
+// ClusterReachable returns a condition indicating that the target cluster of
+// a ProviderConfig is reachable.
+func ClusterReachable() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonClusterReachable,
+	}
+}
+
+// ClusterUnreachable returns a condition indicating that the target cluster
+// of a ProviderConfig could not be reached for the supplied reason.
+func ClusterUnreachable(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonClusterUnreachable,
+		Message:            err.Error(),
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// A SecretStoreType is a type of secret store.
+type SecretStoreType string
+
+// Secret store types.
+const (
+	// SecretStoreKubernetes stores connection details in Secrets of the
+	// cluster the provider runs in.
+	SecretStoreKubernetes SecretStoreType = "Kubernetes"
+	// SecretStoreVault stores connection details in the KV secrets engine
+	// of a HashiCorp Vault server.
+	SecretStoreVault SecretStoreType = "Vault"
+	// SecretStorePlugin stores connection details through an external
+	// secret store plugin of Crossplane, such as ess-plugin-vault.
+	SecretStorePlugin SecretStoreType = "Plugin"
+)
+
+// A VaultKVVersion is a version of the Vault KV secrets engine.
+type VaultKVVersion string
+
+// Vault KV secrets engine versions.
+const (
+	VaultKVVersionV1 VaultKVVersion = "v1"
+	VaultKVVersionV2 VaultKVVersion = "v2"
+)
+
+// VaultCredentials are read from a source such as a Secret.
+type VaultCredentials struct {
+	// Source of the credentials.
+	// +kubebuilder:validation:Enum=None;Secret;Environment;Filesystem
+	Source xpv1.CredentialsSource `json:"source"`
+
+	xpv1.CommonCredentialSelectors `json:",inline"`
+}
+
+// VaultConfig configures a Vault secret store.
+type VaultConfig struct {
+	// Server is the address of the Vault server, e.g.
+	// https://vault.example.org:8200.
+	Server string `json:"server"`
+
+	// MountPath of the KV secrets engine, e.g. secret.
+	MountPath string `json:"mountPath"`
+
+	// Version of the KV secrets engine. Defaults to v2.
+	// +optional
+	// +kubebuilder:validation:Enum=v1;v2
+	Version VaultKVVersion `json:"version,omitempty"`
+
+	// CABundle used to verify the certificate of the Vault server. The
+	// system CAs are used if not set.
+	// +optional
+	CABundle *VaultCredentials `json:"caBundle,omitempty"`
+
+	// Token used to authenticate to the Vault server.
+	Token VaultCredentials `json:"token"`
+}
+
+// A PluginConfigReference references the config of an external secret store
+// plugin, e.g. a VaultConfig of ess-plugin-vault.
+type PluginConfigReference struct {
+	// APIVersion of the config.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the config.
+	Kind string `json:"kind"`
+
+	// Name of the config.
+	Name string `json:"name"`
+}
+
+// PluginStoreConfig configures an external secret store plugin of
+// Crossplane. The provider authenticates to it with the TLS certificates in
+// the directory of its --ess-tls-cert-dir flag.
+type PluginStoreConfig struct {
+	// Endpoint of the plugin, e.g. ess-plugin-vault.crossplane-system:4040.
+	Endpoint string `json:"endpoint"`
+
+	// ConfigRef references the config the plugin stores secrets with.
+	ConfigRef PluginConfigReference `json:"configRef"`
+}
+
+// A StoreConfigSpec defines the desired state of a StoreConfig.
+type StoreConfigSpec struct {
+	// Type of the secret store. Defaults to Kubernetes.
+	// +optional
+	// +kubebuilder:validation:Enum=Kubernetes;Vault;Plugin
+	Type SecretStoreType `json:"type,omitempty"`
+
+	// DefaultScope of the connection secrets of Releases. It is the
+	// namespace of the Secrets of Kubernetes stores, and the path that
+	// secrets of Vault and Plugin stores are written below.
+	DefaultScope string `json:"defaultScope"`
+
+	// Vault configures the Vault secret store. Required if the type is
+	// Vault.
+	// +optional
+	Vault *VaultConfig `json:"vault,omitempty"`
+
+	// Plugin configures the external secret store plugin. Required if the
+	// type is Plugin.
+	// +optional
+	Plugin *PluginStoreConfig `json:"plugin,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A StoreConfig configures a secret store that the connection details of
+// Releases can be published to. Stores of type Plugin publish through the
+// external secret store plugins of Crossplane, such as ess-plugin-vault. The
+// secrets of Vault and Plugin stores record the UID of the Release that owns
+// them, and are neither overwritten nor deleted by other Releases.
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="TYPE",type="string",JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="DEFAULT-SCOPE",type="string",JSONPath=".spec.defaultScope"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,store,helm}
+type StoreConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec StoreConfigSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// StoreConfigList contains a list of StoreConfig.
+type StoreConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StoreConfig `json:"items"`
+}
@@ -28,20 +28,291 @@ type ProviderConfigSpec struct {
 	// kubeconfig file. Use InjectedIdentity for in-cluster config.
 	Credentials ProviderCredentials `json:"credentials"`
 
+	// TranslateExecPlugins replaces well-known exec credential plugins of
+	// the kubeconfig, i.e. the AWS CLI, aws-iam-authenticator,
+	// gke-gcloud-auth-plugin and kubelogin, with native token sources that
+	// use the identity of the provider. The tokens are sent to whatever
+	// server the kubeconfig names, so only enable it for kubeconfigs from
+	// trusted sources. Kubeconfigs of Releases are never translated.
+	// +optional
+	TranslateExecPlugins bool `json:"translateExecPlugins,omitempty"`
+
+	// AllowReleaseKubeconfigs allows the Releases using this ProviderConfig
+	// to override its credentials with a kubeconfig of their own, i.e. to
+	// deploy to any cluster they have a kubeconfig for.
+	// +optional
+	AllowReleaseKubeconfigs bool `json:"allowReleaseKubeconfigs,omitempty"`
+
 	// Identity used to authenticate to the Kubernetes API. The identity
 	// credentials can be used to supplement kubeconfig 'credentials', for
 	// example by configuring a bearer token source such as OAuth.
 	// +optional
 	Identity *Identity `json:"identity,omitempty"`
+
+	// Cluster to connect to if the credentials source is None, e.g. if the
+	// identity supplies all credentials.
+	// +optional
+	Cluster *Cluster `json:"cluster,omitempty"`
+
+	// Impersonate performs all operations against the Kubernetes API as the
+	// supplied user, so that RBAC of the target cluster constrains what may
+	// be deployed using this ProviderConfig.
+	// +optional
+	Impersonate *Impersonation `json:"impersonate,omitempty"`
+
+	// BoundToken configures short-lived tokens requested from the
+	// TokenRequest API for the InjectedIdentity credentials source, rather
+	// than using the mounted ServiceAccount token of the provider. The
+	// provider must be allowed to create tokens for its ServiceAccount.
+	// +optional
+	BoundToken *BoundToken `json:"boundToken,omitempty"`
+
+	// Connection tunes the clients of the target cluster, e.g. for slow or
+	// rate-limited API servers. Unset fields default to the flags of the
+	// provider.
+	// +optional
+	Connection *Connection `json:"connection,omitempty"`
+
+	// DefaultFor selects the Releases that use this ProviderConfig if they
+	// reference the default ProviderConfig, e.g. all Releases into the
+	// namespaces of a tenant. The reference of a selected Release is updated
+	// to this ProviderConfig once.
+	// +optional
+	DefaultFor *DefaultFor `json:"defaultFor,omitempty"`
+
+	// MaxConcurrentReconciles is the maximum number of Releases using this
+	// ProviderConfig that are reconciled at once, so that a slow or
+	// unreachable target cluster can't occupy all workers of the provider.
+	// Defaults to the flag of the provider.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrentReconciles *int32 `json:"maxConcurrentReconciles,omitempty"`
+
+	// Notifications about events of the Releases using this ProviderConfig,
+	// in addition to those configured by the Releases themselves.
+	// +optional
+	Notifications []NotificationSink `json:"notifications,omitempty"`
+
+	// TargetNamespaces restricts the namespaces the Releases using this
+	// ProviderConfig may install into, e.g. so that the Releases of a
+	// tenant can't install into kube-system or the namespaces of other
+	// tenants. Releases may install into all namespaces if not set.
+	// +optional
+	TargetNamespaces *TargetNamespaces `json:"targetNamespaces,omitempty"`
+}
+
+// TargetNamespaces restricts the namespaces Releases install resources into.
+// Releases must satisfy all of the restrictions that are set.
+type TargetNamespaces struct {
+	// Allowed namespaces. Shell file name patterns like "team-*" are
+	// supported.
+	// +optional
+	Allowed []string `json:"allowed,omitempty"`
+
+	// ClaimNamespaceOnly only allows Releases to install into the namespace
+	// of the claim they were composed for, as recorded in their
+	// crossplane.io/claim-namespace label. Releases that were not composed
+	// for a claim may not install into any namespace. Anyone who may create
+	// Releases may set the label, so it only restricts tenants that create
+	// Releases through claims alone.
+	// +optional
+	ClaimNamespaceOnly bool `json:"claimNamespaceOnly,omitempty"`
+
+	// AllowClusterScoped allows Releases to install cluster-scoped
+	// resources, like CustomResourceDefinitions and ClusterRoles, which are
+	// in no namespace.
+	// +optional
+	AllowClusterScoped bool `json:"allowClusterScoped,omitempty"`
+}
+
+// DefaultFor selects Releases. Releases are selected if they match all of
+// the criteria that are set.
+type DefaultFor struct {
+	// Namespaces the Releases install into. Shell file name patterns like
+	// "team-*" are supported.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// ReleaseSelector selects Releases by their labels.
+	// +optional
+	ReleaseSelector *metav1.LabelSelector `json:"releaseSelector,omitempty"`
+}
+
+// A NotificationSinkType determines the default payload of notifications.
+type NotificationSinkType string
+
+// Types of notification sinks.
+const (
+	// NotificationSinkGeneric receives the event as a JSON object with the
+	// fields release, releaseName, namespace, type, reason and message.
+	NotificationSinkGeneric NotificationSinkType = "Generic"
+	// NotificationSinkSlack receives the event as the text of a message,
+	// e.g. a Slack incoming webhook.
+	NotificationSinkSlack NotificationSinkType = "Slack"
+)
+
+// A NotificationEvent is an event of a Release that is notified.
+// +kubebuilder:validation:Enum=Installed;InstallFailed;Upgraded;UpgradeFailed;RolledBack;RollbackFailed;UninstallFailed;TestsFailed;DriftDetected
+type NotificationEvent string
+
+// A NotificationSink is an HTTP endpoint that events of Releases are POSTed
+// to, so that failed deploys are noticed without watching events.
+type NotificationSink struct {
+	// Type of the sink, which determines the default payload.
+	// +optional
+	// +kubebuilder:validation:Enum=Generic;Slack
+	// +kubebuilder:default=Generic
+	Type NotificationSinkType `json:"type,omitempty"`
+
+	// URL notifications are POSTed to.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// URLSecretRef is a reference to a secret key containing the URL, for
+	// URLs that embed credentials like those of Slack incoming webhooks. It
+	// takes precedence over URL.
+	// +optional
+	URLSecretRef *xpv1.SecretKeySelector `json:"urlSecretRef,omitempty"`
+
+	// Events that are notified. Defaults to InstallFailed, UpgradeFailed,
+	// RollbackFailed and TestsFailed.
+	// +optional
+	Events []NotificationEvent `json:"events,omitempty"`
+
+	// Template of the payload, overriding the default payload of the type.
+	// It is a Go template executed with the fields Release, ReleaseName,
+	// Namespace, Type, Reason and Message of the event.
+	// +optional
+	Template string `json:"template,omitempty"`
+
+	// Headers sent with every notification. The Content-Type defaults to
+	// application/json.
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Timeout of a notification. Defaults to 10s.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// A Connection configures the clients of a target cluster.
+type Connection struct {
+	// QPS is the maximum sustained rate of requests to the API server.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	QPS *int32 `json:"qps,omitempty"`
+
+	// Burst is the maximum number of requests to the API server in excess
+	// of the QPS.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Burst *int32 `json:"burst,omitempty"`
+
+	// Timeout of requests to the API server, such as 30s. Zero means no
+	// timeout.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// ProxyURL of an HTTP, HTTPS or SOCKS5 proxy that requests to the API
+	// server are sent through, e.g. a Konnectivity or egress proxy. Charts
+	// are not downloaded through it.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^(http|https|socks5)://`
+	ProxyURL string `json:"proxyURL,omitempty"`
+
+	// TLS overrides the TLS settings of the credentials, e.g. for clusters
+	// fronted by internal load balancers with re-issued certificates.
+	// +optional
+	TLS *TLSOverrides `json:"tls,omitempty"`
+}
+
+// TLSOverrides of a target cluster.
+type TLSOverrides struct {
+	// CABundle is a PEM encoded bundle of certificate authorities that are
+	// trusted in addition to those of the credentials. It replaces the system
+	// certificate authorities if the credentials specify none.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+
+	// ServerName used to verify the certificate of the API server, if it
+	// differs from the host the provider connects to.
+	// +optional
+	ServerName string `json:"serverName,omitempty"`
+
+	// InsecureSkipVerify disables verification of the certificate of the
+	// API server. Connections are susceptible to man-in-the-middle attacks;
+	// use only for testing.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// A BoundToken configures ServiceAccount tokens requested from the
+// TokenRequest API.
+type BoundToken struct {
+	// Audiences of the token. Defaults to the audience of the API server.
+	// +optional
+	Audiences []string `json:"audiences,omitempty"`
+
+	// ExpirationSeconds is the requested lifetime of the token. Tokens are
+	// refreshed before they expire. Defaults to one hour.
+	// +optional
+	// +kubebuilder:validation:Minimum=600
+	ExpirationSeconds *int64 `json:"expirationSeconds,omitempty"`
+}
+
+// An Impersonation is a user the provider acts as.
+type Impersonation struct {
+	// Username to impersonate.
+	Username string `json:"username"`
+
+	// Groups to impersonate.
+	// +optional
+	Groups []string `json:"groups,omitempty"`
+
+	// UID to impersonate.
+	// +optional
+	UID string `json:"uid,omitempty"`
+}
+
+// A Cluster is the API server endpoint of a Kubernetes cluster.
+type Cluster struct {
+	// Server is the URL of the API server.
+	Server string `json:"server"`
+
+	// CertificateAuthorityData is the PEM encoded certificate authority of
+	// the API server.
+	// +optional
+	CertificateAuthorityData []byte `json:"certificateAuthorityData,omitempty"`
 }
 
+// CredentialsSourceClusterAPI reads the kubeconfig Secret that Cluster API
+// maintains for a Cluster.
+const CredentialsSourceClusterAPI xpv1.CredentialsSource = "ClusterAPI"
+
 // ProviderCredentials required to authenticate.
 type ProviderCredentials struct {
-	// Source of the provider credentials.
-	// +kubebuilder:validation:Enum=None;Secret;InjectedIdentity;Environment;Filesystem
+	// Source of the provider credentials. The ClusterAPI source is only
+	// supported for the credentials of a ProviderConfig, not its identity.
+	// +kubebuilder:validation:Enum=None;Secret;InjectedIdentity;Environment;Filesystem;ClusterAPI
 	Source xpv1.CredentialsSource `json:"source"`
 
 	xpv1.CommonCredentialSelectors `json:",inline"`
+
+	// ClusterAPIRef references the Cluster API Cluster whose kubeconfig is
+	// used if the source is ClusterAPI. The kubeconfig is read from the
+	// <name>-kubeconfig Secret on every reconcile, so rotated kubeconfigs
+	// are picked up automatically.
+	// +optional
+	ClusterAPIRef *ClusterAPIReference `json:"clusterAPIRef,omitempty"`
+}
+
+// A ClusterAPIReference references a Cluster API Cluster.
+type ClusterAPIReference struct {
+	// Name of the Cluster.
+	Name string `json:"name"`
+
+	// Namespace of the Cluster.
+	Namespace string `json:"namespace"`
 }
 
 // IdentityType used to authenticate to the Kubernetes API.
@@ -50,20 +321,144 @@ type IdentityType string
 // Supported identity types.
 const (
 	IdentityTypeGoogleApplicationCredentials = "GoogleApplicationCredentials"
+	IdentityTypeGoogleWorkloadIdentity       = "GoogleWorkloadIdentity"
+	IdentityTypeAWSWebIdentity               = "AWSWebIdentity"
+	IdentityTypeAzureADIdentity              = "AzureADIdentity"
+	IdentityTypeOIDCTokenExchange            = "OIDCTokenExchange"
 )
 
 // Identity used to authenticate.
 type Identity struct {
-	// Type of identity.
-	// +kubebuilder:validation:Enum=GoogleApplicationCredentials
+	// Type of identity. GoogleWorkloadIdentity, AWSWebIdentity and
+	// AzureADIdentity require the InjectedIdentity source, i.e. GKE workload
+	// identity, IAM roles for service accounts or an Azure managed or
+	// workload identity configured for the provider. OIDCTokenExchange
+	// requires the InjectedIdentity source too, and exchanges the
+	// ServiceAccount token of the provider.
+	// +kubebuilder:validation:Enum=GoogleApplicationCredentials;GoogleWorkloadIdentity;AWSWebIdentity;AzureADIdentity;OIDCTokenExchange
 	Type IdentityType `json:"type"`
 
 	ProviderCredentials `json:",inline"`
+
+	// AWS configures the AWSWebIdentity identity. EKS tokens are signed
+	// with AWS Signature Version 4 by the provider itself, not by the AWS
+	// SDK or aws-iam-authenticator.
+	// +optional
+	AWS *AWSIdentity `json:"aws,omitempty"`
+
+	// Azure configures the AzureADIdentity identity.
+	// +optional
+	Azure *AzureIdentity `json:"azure,omitempty"`
+
+	// OIDC configures the OIDCTokenExchange identity.
+	// +optional
+	OIDC *OIDCIdentity `json:"oidc,omitempty"`
+}
+
+// AWSIdentity configures EKS tokens generated for the IAM role of the
+// provider.
+type AWSIdentity struct {
+	// ClusterName is the name of the EKS cluster.
+	ClusterName string `json:"clusterName"`
+
+	// Region of the EKS cluster. Defaults to the region of the provider.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// RoleARN of an IAM role that is assumed with the role of the provider,
+	// e.g. a role in the account of the EKS cluster.
+	// +optional
+	RoleARN string `json:"roleARN,omitempty"`
+}
+
+// AzureIdentity configures AAD tokens fetched for the Azure managed identity
+// or workload identity of the provider.
+type AzureIdentity struct {
+	// ClientID of the managed identity, or of the application federated
+	// with the workload identity. Defaults to the AZURE_CLIENT_ID of the
+	// provider.
+	// +optional
+	ClientID string `json:"clientID,omitempty"`
+
+	// TenantID of the application federated with the workload identity.
+	// Defaults to the AZURE_TENANT_ID of the provider.
+	// +optional
+	TenantID string `json:"tenantID,omitempty"`
+
+	// ServerID is the application ID of the AAD server of the AKS cluster.
+	// Defaults to the AKS AAD server used by AKS-managed AAD.
+	// +optional
+	ServerID string `json:"serverID,omitempty"`
+}
+
+// OIDCIdentity configures the OAuth 2.0 token exchange of the ServiceAccount
+// token of the provider for a token the target cluster trusts.
+type OIDCIdentity struct {
+	// TokenURL of the security token service.
+	TokenURL string `json:"tokenURL"`
+
+	// Audience of the exchanged token, e.g. the client ID the OIDC
+	// authenticator of the target cluster is configured with.
+	// +optional
+	Audience string `json:"audience,omitempty"`
+
+	// Scopes of the exchanged token.
+	// +optional
+	Scopes []string `json:"scopes,omitempty"`
+
+	// ClientID the provider authenticates as, if required by the security
+	// token service.
+	// +optional
+	ClientID string `json:"clientID,omitempty"`
+
+	// RequestedTokenType of the exchanged token, e.g.
+	// urn:ietf:params:oauth:token-type:id_token. Defaults to an access
+	// token.
+	// +optional
+	RequestedTokenType string `json:"requestedTokenType,omitempty"`
+
+	// TokenFile the ServiceAccount token is read from, e.g. a projected
+	// token with the audience of the security token service. Defaults to
+	// the mounted ServiceAccount token of the provider.
+	// +optional
+	TokenFile string `json:"tokenFile,omitempty"`
 }
 
 // A ProviderConfigStatus defines the status of a Provider.
 type ProviderConfigStatus struct {
 	xpv1.ProviderConfigStatus `json:",inline"`
+
+	// LastCheckedTime is when the target cluster was last probed.
+	// +optional
+	LastCheckedTime *metav1.Time `json:"lastCheckedTime,omitempty"`
+
+	// Operations summarizes the recent operations of Releases using this
+	// provider configuration.
+	// +optional
+	Operations *OperationStatistics `json:"operations,omitempty"`
+}
+
+// OperationStatistics summarize the recent install, upgrade and uninstall
+// operations of the Releases using a provider configuration.
+type OperationStatistics struct {
+	// LastSucceededTime is when an operation last succeeded.
+	// +optional
+	LastSucceededTime *metav1.Time `json:"lastSucceededTime,omitempty"`
+
+	// LastFailedTime is when an operation last failed.
+	// +optional
+	LastFailedTime *metav1.Time `json:"lastFailedTime,omitempty"`
+
+	// RecentOperations is the number of recent operations the failure rate
+	// is computed from.
+	RecentOperations int32 `json:"recentOperations"`
+
+	// RecentFailures is the number of recent operations that failed.
+	RecentFailures int32 `json:"recentFailures"`
+
+	// RecentFailurePercent is the percentage of recent operations that
+	// failed.
+	RecentFailurePercent int32 `json:"recentFailurePercent"`
 }
 
 // +kubebuilder:object:root=true
@@ -73,6 +468,9 @@ type ProviderConfigStatus struct {
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="SECRET-NAME",type="string",JSONPath=".spec.credentialsSecretRef.name",priority=1
+// +kubebuilder:printcolumn:name="HEALTHY",type="string",JSONPath=".status.conditions[?(@.type=='Healthy')].status"
+// +kubebuilder:printcolumn:name="USERS",type="integer",JSONPath=".status.users"
+// +kubebuilder:printcolumn:name="FAILURE-RATE",type="integer",JSONPath=".status.operations.recentFailurePercent",priority=1
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,provider,helm}
 type ProviderConfig struct {
 	metav1.TypeMeta   `json:",inline"`
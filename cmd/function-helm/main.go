@@ -0,0 +1,48 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"gopkg.in/alecthomas/kingpin.v2"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/crossplane-contrib/provider-helm/pkg/function"
+)
+
+func main() {
+	var (
+		app   = kingpin.New(filepath.Base(os.Args[0]), "Composition function rendering Helm charts into composed resources. Reads a FunctionIO from stdin and writes it to stdout.").DefaultEnvars()
+		debug = app.Flag("debug", "Run with debug logging.").Short('d').Bool()
+	)
+	kingpin.MustParse(app.Parse(os.Args[1:]))
+
+	// Logs go to stderr, stdout is reserved for the FunctionIO.
+	log := logging.NewLogrLogger(zap.New(zap.UseDevMode(*debug), zap.WriteTo(os.Stderr)).WithName("function-helm"))
+
+	in, err := ioutil.ReadAll(os.Stdin)
+	kingpin.FatalIfError(err, "Cannot read FunctionIO")
+	out, err := function.New(function.NewHelmRenderer(log)).Run(context.Background(), in)
+	kingpin.FatalIfError(err, "Cannot run function")
+	_, err = os.Stdout.Write(out)
+	kingpin.FatalIfError(err, "Cannot write FunctionIO")
+}
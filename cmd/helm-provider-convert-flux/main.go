@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"gopkg.in/alecthomas/kingpin.v2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane-contrib/provider-helm/pkg/flux"
+)
+
+func main() {
+	var (
+		app   = kingpin.New(filepath.Base(os.Args[0]), "Convert Flux HelmReleases into Releases, resolving their charts from the HelmRepositories in the same files. The Releases are written to stdout, warnings about settings that could not be converted to stderr.").DefaultEnvars()
+		files = app.Arg("file", "YAML files of the HelmReleases and HelmRepositories.").Required().ExistingFiles()
+		pc    = app.Flag("provider-config", "ProviderConfig of the converted Releases.").String()
+	)
+	kingpin.MustParse(app.Parse(os.Args[1:]))
+
+	kingpin.FatalIfError(convertFlux(os.Stdout, os.Stderr, *files, flux.Options{ProviderConfigName: *pc}), "Cannot convert Flux HelmReleases")
+}
+
+// convertFlux writes the Releases converted from the Flux objects in the
+// supplied files to w, and the warnings of the conversion to warn.
+func convertFlux(w, warn io.Writer, files []string, o flux.Options) error {
+	var objs []unstructured.Unstructured
+	for _, f := range files {
+		r, err := os.Open(f) // nolint:gosec
+		if err != nil {
+			return err
+		}
+		fo, err := flux.Decode(r)
+		_ = r.Close()
+		if err != nil {
+			return errors.Wrap(err, f)
+		}
+		objs = append(objs, fo...)
+	}
+
+	rels, warnings, err := flux.Convert(objs, o)
+	if err != nil {
+		return err
+	}
+	for _, msg := range warnings {
+		if _, err := fmt.Fprintln(warn, "Warning:", msg); err != nil {
+			return err
+		}
+	}
+	for i := range rels {
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&rels[i])
+		if err != nil {
+			return err
+		}
+		// Leave out the empty status and creation timestamp.
+		delete(u, "status")
+		unstructured.RemoveNestedField(u, "metadata", "creationTimestamp")
+		b, err := yaml.Marshal(u)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "---\n%s", b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane-contrib/provider-helm/pkg/importer"
+)
+
+func main() {
+	var (
+		app          = kingpin.New(filepath.Base(os.Args[0]), "Generate Releases adopting the Helm releases of a cluster. The Releases are written to stdout, warnings about what must be completed or reviewed before they are applied to stderr.").DefaultEnvars()
+		kubeconfig   = app.Flag("kubeconfig", "Kubeconfig of the cluster whose releases are imported. Defaults to the kubeconfig kubectl uses.").String()
+		kubeContext  = app.Flag("context", "Context of the kubeconfig to use.").String()
+		namespace    = app.Flag("namespace", "Namespace whose releases are imported. Releases of all namespaces are imported if not set.").Short('n').String()
+		pc           = app.Flag("provider-config", "ProviderConfig of the imported Releases.").String()
+		repositories = app.Flag("repository", "Repository of a chart, as chart=URL. May be repeated.").StringMap()
+	)
+	kingpin.MustParse(app.Parse(os.Args[1:]))
+
+	rels, err := list(*kubeconfig, *kubeContext, *namespace)
+	kingpin.FatalIfError(err, "Cannot list Helm releases")
+	kingpin.FatalIfError(write(os.Stdout, os.Stderr, rels, importer.Options{ProviderConfigName: *pc, Repositories: *repositories}), "Cannot import Helm releases")
+}
+
+// list returns all revisions of the Helm releases of the supplied namespace,
+// or of all namespaces if it is empty, from the Secrets Helm stores them in.
+func list(kubeconfig, context, namespace string) ([]*release.Release, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = kubeconfig
+	cfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: context}).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	cs, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return driver.NewSecrets(cs.CoreV1().Secrets(namespace)).List(func(*release.Release) bool { return true })
+}
+
+// write writes the Releases adopting the supplied Helm releases to w, and the
+// warnings of the import to warn.
+func write(w, warn io.Writer, rels []*release.Release, o importer.Options) error {
+	crs, warnings, err := importer.Import(rels, o)
+	if err != nil {
+		return err
+	}
+	for _, msg := range warnings {
+		if _, err := fmt.Fprintln(warn, "Warning:", msg); err != nil {
+			return err
+		}
+	}
+	for i := range crs {
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&crs[i])
+		if err != nil {
+			return err
+		}
+		// Leave out the empty status and creation timestamp.
+		delete(u, "status")
+		unstructured.RemoveNestedField(u, "metadata", "creationTimestamp")
+		b, err := yaml.Marshal(u)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "---\n%s", b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kubectl-crossplane_helm is a kubectl plugin, invoked as kubectl
+// crossplane-helm, that previews Releases using the code of the provider.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/pkg/errors"
+	"gopkg.in/alecthomas/kingpin.v2"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane-contrib/provider-helm/apis"
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+	"github.com/crossplane-contrib/provider-helm/pkg/controller/release"
+)
+
+const errFmtNotRelease = "%s is not a %s"
+
+func main() {
+	var (
+		app           = kingpin.New(filepath.Base(os.Args[0]), "Crossplane Helm provider plugin for kubectl.").DefaultEnvars()
+		debug         = app.Flag("debug", "Run with debug logging.").Short('d').Bool()
+		kubeconfig    = app.Flag("kubeconfig", "Kubeconfig of the Crossplane cluster the values, patches and ReleaseClasses of the Release are read from. Defaults to the kubeconfig kubectl uses.").String()
+		kubeContext   = app.Flag("context", "Context of the kubeconfig to use.").String()
+		preview       = app.Command("preview", "Render a Release exactly like the provider does, without deploying it. The rendered manifest is written to stdout.")
+		previewFile   = preview.Arg("file", "YAML file of the Release, or - to read it from stdin.").Required().String()
+		targetConfig  = preview.Flag("target-kubeconfig", "Kubeconfig of the target cluster of the Release. If set, the differences to the deployed revision of the release are written instead of the manifest.").String()
+		targetContext = preview.Flag("target-context", "Context of the target kubeconfig to use.").String()
+	)
+	if kingpin.MustParse(app.Parse(os.Args[1:])) != preview.FullCommand() {
+		return
+	}
+
+	zl := zap.New(zap.UseDevMode(*debug), zap.WriteTo(os.Stderr))
+	log := logging.NewLogrLogger(zl.WithName("kubectl-crossplane-helm"))
+
+	cr, err := readRelease(*previewFile)
+	kingpin.FatalIfError(err, "Cannot read Release")
+
+	cfg, err := restConfig(*kubeconfig, *kubeContext)
+	kingpin.FatalIfError(err, "Cannot load kubeconfig")
+	s := runtime.NewScheme()
+	kingpin.FatalIfError(clientgoscheme.AddToScheme(s), "Cannot add Kubernetes APIs to scheme")
+	kingpin.FatalIfError(apis.AddToScheme(s), "Cannot add Helm APIs to scheme")
+	kube, err := client.New(cfg, client.Options{Scheme: s})
+	kingpin.FatalIfError(err, "Cannot create Kubernetes client")
+
+	var target *rest.Config
+	if *targetConfig != "" {
+		target, err = restConfig(*targetConfig, *targetContext)
+		kingpin.FatalIfError(err, "Cannot load target kubeconfig")
+	}
+
+	pv, err := release.NewPreviewer(kube, log).Preview(context.Background(), cr, target)
+	kingpin.FatalIfError(err, "Cannot preview Release")
+	kingpin.FatalIfError(write(os.Stdout, pv, target != nil), "Cannot write preview")
+}
+
+func readRelease(file string) (*v1beta1.Release, error) {
+	var b []byte
+	var err error
+	if file == "-" {
+		b, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		b, err = ioutil.ReadFile(filepath.Clean(file))
+	}
+	if err != nil {
+		return nil, err
+	}
+	cr := &v1beta1.Release{}
+	if err := yaml.Unmarshal(b, cr); err != nil {
+		return nil, err
+	}
+	if cr.GetObjectKind().GroupVersionKind() != v1beta1.ReleaseGroupVersionKind {
+		return nil, errors.Errorf(errFmtNotRelease, file, v1beta1.ReleaseGroupVersionKind)
+	}
+	return cr, nil
+}
+
+func restConfig(kubeconfig, context string) (*rest.Config, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = kubeconfig
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: context}).ClientConfig()
+}
+
+// write writes the manifest of the supplied preview to w, or its differences
+// to the deployed revision if diff is true.
+func write(w io.Writer, pv *release.Preview, diff bool) error {
+	if !diff {
+		_, err := io.WriteString(w, pv.Manifest)
+		return err
+	}
+	if pv.FromRevision == 0 {
+		_, err := fmt.Fprintf(w, "Release is not deployed, it would install:\n%s", pv.Manifest)
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "Upgrading from revision %d: %d added, %d changed, %d removed\n", pv.FromRevision, len(pv.Added), len(pv.Changed), len(pv.Removed)); err != nil {
+		return err
+	}
+	for _, s := range []struct {
+		sign string
+		keys []string
+	}{{"+", pv.Added}, {"~", pv.Changed}, {"-", pv.Removed}} {
+		for _, k := range s.keys {
+			if _, err := fmt.Fprintln(w, s.sign, k); err != nil {
+				return err
+			}
+		}
+	}
+	if pv.Diff == "" {
+		return nil
+	}
+	_, err := fmt.Fprintf(w, "\n%s", pv.Diff)
+	return err
+}
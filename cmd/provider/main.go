@@ -17,17 +17,29 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	// Embed the time zone database for upgrade windows.
+	_ "time/tzdata"
 
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"gopkg.in/alecthomas/kingpin.v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	rtcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	"github.com/crossplane-contrib/provider-helm/apis"
+	helmv1beta1 "github.com/crossplane-contrib/provider-helm/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-helm/pkg/clients/valuesource"
 	"github.com/crossplane-contrib/provider-helm/pkg/controller"
+	"github.com/crossplane-contrib/provider-helm/pkg/controller/release"
+	"github.com/crossplane-contrib/provider-helm/pkg/tracing"
 )
 
 func main() {
@@ -35,6 +47,35 @@ func main() {
 		app            = kingpin.New(filepath.Base(os.Args[0]), "Helm support for Crossplane.").DefaultEnvars()
 		debug          = app.Flag("debug", "Run with debug logging.").Short('d').Bool()
 		syncPeriod     = app.Flag("sync", "Controller manager sync period such as 300ms, 1.5h, or 2h45m").Short('s').Default("1h").Duration()
+		pollInterval   = app.Flag("poll", "Default interval at which Releases are observed, such as 1m or 1h.").Default("10m").Duration()
+		execPlugins    = app.Flag("allow-exec-plugin", "Command of an exec credential plugin that kubeconfigs may use. The AWS CLI, aws-iam-authenticator, gke-gcloud-auth-plugin and kubelogin are replaced with native token sources and need not be allowed.").Strings()
+		cacheTTL       = app.Flag("client-cache-ttl", "How long clients of a target cluster are reused before they are rebuilt.").Default("10m").Duration()
+		cacheSize      = app.Flag("client-cache-size", "Maximum number of target cluster clients that are cached.").Default("100").Int()
+		targetQPS      = app.Flag("target-qps", "Maximum sustained rate of requests to target cluster API servers, unless a ProviderConfig specifies its own.").Default("5").Int32()
+		targetBurst    = app.Flag("target-burst", "Maximum burst of requests to target cluster API servers, unless a ProviderConfig specifies its own.").Default("10").Int32()
+		targetTimeout  = app.Flag("target-timeout", "Timeout of requests to target cluster API servers, unless a ProviderConfig specifies its own. Zero means no timeout.").Default("0").Duration()
+		healthInterval = app.Flag("health-probe-interval", "Interval at which the target clusters of ProviderConfigs are probed.").Default("5m").Duration()
+		helmDebug      = app.Flag("helm-debug", "Log the verbose output of the Helm actions of all Releases. The output of a single Release is logged if it is annotated with helm.crossplane.io/debug: \"true\".").Bool()
+		storageCache   = app.Flag("helm-storage-cache", "Read the release records of target clusters from a cache that watches them rather than from their API servers on every reconcile. The provider must be allowed to list and watch Secrets across the target clusters.").Bool()
+		poolSize       = app.Flag("max-reconciles-per-provider-config", "Maximum number of Releases of a ProviderConfig that are reconciled at once, so that a slow target cluster can't occupy all workers, unless the ProviderConfig specifies its own. Zero means unlimited.").Default("0").Int()
+		maxRate        = app.Flag("max-reconcile-rate", "Maximum rate per second at which resources are reconciled, across all controllers.").Default("10").Int()
+		maxReconciles  = app.Flag("max-concurrent-reconciles", "Maximum number of Releases that are reconciled at once.").Default("10").Int()
+		maxSetRecs     = app.Flag("max-concurrent-releaseset-reconciles", "Maximum number of ReleaseSets that are reconciled at once.").Default("1").Int()
+		backoffBase    = app.Flag("requeue-backoff-base", "Delay before a resource whose reconcile failed is reconciled again. The delay doubles with every consecutive failure.").Default("1s").Duration()
+		backoffMax     = app.Flag("requeue-backoff-max", "Maximum delay before a resource whose reconcile failed is reconciled again.").Default("1m").Duration()
+		requeueJitter  = app.Flag("requeue-jitter", "Maximum fraction of the poll interval of a Release that is added to it at random, such as 0.1, so that Releases created at once are not observed at once.").Default("0").Float64()
+		chartPulls     = app.Flag("max-parallel-chart-pulls", "Maximum number of charts that are pulled at once. Concurrent pulls of the same chart are always deduplicated. Zero means unlimited.").Default("5").Int()
+		valuePlugins   = app.Flag("value-source-plugin", "Value source plugin serving the plugin API on a unix socket, as name=socket, such as cmdb=/plugins/cmdb.sock. Releases read values from it by name. May be repeated.").StringMap()
+		essCertDir     = app.Flag("ess-tls-cert-dir", "Directory of the CA certificate (ca.crt), client certificate (tls.crt) and key (tls.key) that the provider authenticates to external secret store plugins with.").Envar("ESS_TLS_CERTS_DIR").String()
+		allowRepos     = app.Flag("chart-policy-allow-repository", "Repository charts may be pulled from, such as https://charts.example.org/stable. Includes the chart URLs and repositories below it. All are allowed if not set. May be repeated.").Strings()
+		denyRepos      = app.Flag("chart-policy-deny-repository", "Repository charts may not be pulled from, including the chart URLs and repositories below it. Takes precedence over allowed repositories. May be repeated.").Strings()
+		allowCharts    = app.Flag("chart-policy-allow-chart", "Glob pattern of the names of the charts Releases may deploy, such as ingress-*. All are allowed if not set. May be repeated.").Strings()
+		denyCharts     = app.Flag("chart-policy-deny-chart", "Glob pattern of the names of the charts Releases may not deploy. Takes precedence over allowed charts. May be repeated.").Strings()
+		allowVersions  = app.Flag("chart-policy-allow-versions", "Semver range the versions of charts must be in, as pattern=range, such as 'ingress-*=>=4.0.0 <5.0.0'. May be repeated.").StringMap()
+		policyURL      = app.Flag("policy-server-url", "URL of an Open Policy Agent server that the rendered manifests of all Releases are evaluated against before they are installed or upgraded, such as http://opa.opa-system:8181. Its policies are managed by its operators, not by Releases. Manifests are not evaluated if not set.").String()
+		policyQuery    = app.Flag("policy-query", "Path of the rule of the policy server that reports violations as a set of messages.").Default("helm/deny").String()
+		otlpEndpoint   = app.Flag("otlp-endpoint", "Address of an OTLP/HTTP endpoint that traces of reconciles are exported to, such as otel-collector:4318. Traces are not exported if not set.").String()
+		otlpInsecure   = app.Flag("otlp-insecure", "Export traces to the OTLP endpoint without TLS.").Bool()
 		leaderElection = app.Flag("leader-election", "Use leader election for the conroller manager.").Short('l').Default("false").OverrideDefaultFromEnvar("LEADER_ELECTION").Bool()
 	)
 	kingpin.MustParse(app.Parse(os.Args[1:]))
@@ -48,7 +89,7 @@ func main() {
 		ctrl.SetLogger(zl)
 	}
 
-	log.Debug("Starting", "sync-period", syncPeriod.String())
+	log.Debug("Starting", "sync-period", syncPeriod.String(), "poll-interval", pollInterval.String())
 
 	cfg, err := ctrl.GetConfig()
 	kingpin.FatalIfError(err, "Cannot get API server rest config")
@@ -57,10 +98,73 @@ func main() {
 		LeaderElection:   *leaderElection,
 		LeaderElectionID: "crossplane-leader-election-provider-helm",
 		SyncPeriod:       syncPeriod,
+		// Secrets are read straight from the API server rather than
+		// caching every Secret of the cluster. Changes to them are watched
+		// as metadata only.
+		ClientDisableCacheFor: []client.Object{&corev1.Secret{}},
 	})
 	kingpin.FatalIfError(err, "Cannot create controller manager")
 
+	stopTracing := func(context.Context) error { return nil }
+	if *otlpEndpoint != "" {
+		stopTracing, err = tracing.Setup(context.Background(), *otlpEndpoint, *otlpInsecure)
+		kingpin.FatalIfError(err, "Cannot set up tracing")
+	}
+
 	kingpin.FatalIfError(apis.AddToScheme(mgr.GetScheme()), "Cannot add Helm APIs to scheme")
-	kingpin.FatalIfError(controller.Setup(mgr, log), "Cannot setup Helm controllers")
-	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
+	var ps *release.PolicyServer
+	if *policyURL != "" {
+		ps = &release.PolicyServer{URL: *policyURL, Query: *policyQuery}
+	}
+	plugins, err := valuesource.DialPlugins(*valuePlugins)
+	kingpin.FatalIfError(err, "Cannot dial value source plugins")
+	defer plugins.Close()
+	// All controllers share the maximum reconcile rate of the provider.
+	rl := ratelimiter.NewDefaultProviderRateLimiter(*maxRate)
+	kingpin.FatalIfError(controller.Setup(mgr, log, controller.Options{
+		Release: release.Options{
+			PollInterval:    *pollInterval,
+			ExecPlugins:     *execPlugins,
+			ClientCacheTTL:  *cacheTTL,
+			ClientCacheSize: *cacheSize,
+			Connection: helmv1beta1.Connection{
+				QPS:     targetQPS,
+				Burst:   targetBurst,
+				Timeout: &metav1.Duration{Duration: *targetTimeout},
+			},
+			HealthProbeInterval:            *healthInterval,
+			HelmDebug:                      *helmDebug,
+			HelmStorageCache:               *storageCache,
+			MaxReconcilesPerProviderConfig: *poolSize,
+			Controller: rtcontroller.Options{
+				MaxConcurrentReconciles: *maxReconciles,
+				RateLimiter:             controller.NewRateLimiter(rl, *backoffBase, *backoffMax),
+			},
+			RequeueJitter:         *requeueJitter,
+			MaxParallelChartPulls: *chartPulls,
+			ValueSourcePlugins:    plugins,
+			ESSTLSCertDir:         *essCertDir,
+			ChartPolicy: &release.ChartPolicy{
+				AllowedRepositories: *allowRepos,
+				DeniedRepositories:  *denyRepos,
+				AllowedCharts:       *allowCharts,
+				DeniedCharts:        *denyCharts,
+				AllowedVersions:     *allowVersions,
+			},
+			PolicyServer: ps,
+		},
+		ReleaseSet: rtcontroller.Options{
+			MaxConcurrentReconciles: *maxSetRecs,
+			RateLimiter:             controller.NewRateLimiter(rl, *backoffBase, *backoffMax),
+		},
+		ProviderConfig: rtcontroller.Options{
+			RateLimiter: controller.NewRateLimiter(rl, *backoffBase, *backoffMax),
+		},
+	}), "Cannot setup Helm controllers")
+	err = mgr.Start(ctrl.SetupSignalHandler())
+	// Export the spans of the last reconciles before exiting.
+	if serr := stopTracing(context.Background()); serr != nil {
+		log.Info("Cannot flush traces", "error", serr)
+	}
+	kingpin.FatalIfError(err, "Cannot start controller manager")
 }
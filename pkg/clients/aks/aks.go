@@ -0,0 +1,234 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package aks contains utilities for authenticating to AKS clusters.
+package aks
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	envClientID      = "AZURE_CLIENT_ID"
+	envTenantID      = "AZURE_TENANT_ID"
+	envTokenFile     = "AZURE_FEDERATED_TOKEN_FILE"
+	envAuthorityHost = "AZURE_AUTHORITY_HOST"
+
+	defaultAuthorityHost = "https://login.microsoftonline.com/"
+	imdsEndpoint         = "http://169.254.169.254/metadata/identity/oauth2/token"
+	imdsAPIVersion       = "2018-02-01"
+
+	// DefaultServerID is the application ID of the Azure Kubernetes Service
+	// AAD server, i.e. the audience of AAD tokens for AKS clusters.
+	DefaultServerID = "6dae42f8-4368-4678-94ff-3960e28e3630"
+
+	clientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+	// Access tokens are replaced this long before they expire, so that
+	// requests in flight never carry an expired token.
+	tokenLeeway = 5 * time.Minute
+
+	aadTimeout = 30 * time.Second
+)
+
+const (
+	errNoTenant       = "no tenant configured for workload identity, " + envTenantID + " must be set"
+	errNoClientID     = "no client ID configured for workload identity, " + envClientID + " must be set"
+	errReadTokenFile  = "cannot read federated token file"
+	errFetchToken     = "cannot fetch AAD access token"
+	errAADStatus      = "unexpected status %d from AAD"
+	errAADError       = "%s: %s"
+	errDecodeResponse = "cannot decode AAD response"
+)
+
+// Config of the AAD tokens.
+type Config struct {
+	// ClientID of the managed identity or of the application federated
+	// with the workload identity. Defaults to AZURE_CLIENT_ID.
+	ClientID string
+	// TenantID of the application federated with the workload identity.
+	// Defaults to AZURE_TENANT_ID.
+	TenantID string
+	// ServerID is the application ID of the AAD server of the AKS cluster.
+	// Defaults to DefaultServerID.
+	ServerID string
+}
+
+// WrapRESTConfig configures the supplied REST config to use AAD tokens of
+// the identity of the provider. Azure workload identity is used if a
+// federated token file is configured, otherwise the managed identity of the
+// node the provider runs on.
+func WrapRESTConfig(rc *rest.Config, cfg Config) error {
+	ts, err := newTokenSource(cfg, os.Getenv)
+	if err != nil {
+		return err
+	}
+
+	// ReuseTokenSource caches tokens until they are about to expire.
+	src := oauth2.ReuseTokenSource(nil, ts)
+	rc.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &oauth2.Transport{Source: src, Base: rt}
+	})
+
+	return nil
+}
+
+// A tokenSource fetches AAD access tokens for the AKS AAD server.
+type tokenSource struct {
+	cfg       Config
+	tokenFile string
+	authority string
+	imds      string
+	readFile  func(string) ([]byte, error)
+	client    *http.Client
+	now       func() time.Time
+}
+
+func newTokenSource(cfg Config, env func(string) string) (*tokenSource, error) {
+	if cfg.ClientID == "" {
+		cfg.ClientID = env(envClientID)
+	}
+	if cfg.TenantID == "" {
+		cfg.TenantID = env(envTenantID)
+	}
+	if cfg.ServerID == "" {
+		cfg.ServerID = DefaultServerID
+	}
+	s := &tokenSource{
+		cfg:       cfg,
+		tokenFile: env(envTokenFile),
+		authority: env(envAuthorityHost),
+		imds:      imdsEndpoint,
+		readFile:  ioutil.ReadFile,
+		client:    &http.Client{Timeout: aadTimeout},
+		now:       time.Now,
+	}
+	if s.authority == "" {
+		s.authority = defaultAuthorityHost
+	}
+	if s.tokenFile == "" {
+		return s, nil
+	}
+	if cfg.TenantID == "" {
+		return nil, errors.New(errNoTenant)
+	}
+	if cfg.ClientID == "" {
+		return nil, errors.New(errNoClientID)
+	}
+	return s, nil
+}
+
+// Token returns a new AAD access token. Its expiry is brought forward by the
+// token leeway so that it is refreshed early.
+func (s *tokenSource) Token() (*oauth2.Token, error) {
+	var req *http.Request
+	var err error
+	if s.tokenFile != "" {
+		req, err = s.workloadIdentityRequest()
+	} else {
+		req, err = s.managedIdentityRequest()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, errFetchToken)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	var t struct {
+		AccessToken      string      `json:"access_token"`
+		TokenType        string      `json:"token_type"`
+		ExpiresIn        json.Number `json:"expires_in"`
+		Error            string      `json:"error"`
+		ErrorDescription string      `json:"error_description"`
+	}
+	derr := json.NewDecoder(resp.Body).Decode(&t)
+	if resp.StatusCode != http.StatusOK {
+		if derr != nil || t.Error == "" {
+			return nil, errors.Errorf(errAADStatus, resp.StatusCode)
+		}
+		return nil, errors.Errorf(errAADError, t.Error, t.ErrorDescription)
+	}
+	if derr != nil {
+		return nil, errors.Wrap(derr, errDecodeResponse)
+	}
+	expiresIn, err := t.ExpiresIn.Int64()
+	if err != nil {
+		return nil, errors.Wrap(err, errDecodeResponse)
+	}
+
+	lifetime := time.Duration(expiresIn)*time.Second - tokenLeeway
+	if lifetime < 0 {
+		lifetime = 0
+	}
+	return &oauth2.Token{
+		AccessToken: t.AccessToken,
+		TokenType:   t.TokenType,
+		Expiry:      now.Add(lifetime),
+	}, nil
+}
+
+// workloadIdentityRequest exchanges the federated token of the provider for
+// an AAD token using the client credentials flow.
+func (s *tokenSource) workloadIdentityRequest() (*http.Request, error) {
+	assertion, err := s.readFile(s.tokenFile)
+	if err != nil {
+		return nil, errors.Wrap(err, errReadTokenFile)
+	}
+	form := url.Values{
+		"grant_type":            {"client_credentials"},
+		"client_id":             {s.cfg.ClientID},
+		"client_assertion_type": {clientAssertionType},
+		"client_assertion":      {strings.TrimSpace(string(assertion))},
+		"scope":                 {s.cfg.ServerID + "/.default"},
+	}
+	u := strings.TrimSuffix(s.authority, "/") + "/" + s.cfg.TenantID + "/oauth2/v2.0/token"
+	req, err := http.NewRequest(http.MethodPost, u, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, errors.Wrap(err, errFetchToken)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req, nil
+}
+
+// managedIdentityRequest fetches an AAD token of the managed identity from
+// the instance metadata service.
+func (s *tokenSource) managedIdentityRequest() (*http.Request, error) {
+	q := url.Values{"api-version": {imdsAPIVersion}, "resource": {s.cfg.ServerID}}
+	if s.cfg.ClientID != "" {
+		q.Set("client_id", s.cfg.ClientID)
+	}
+	req, err := http.NewRequest(http.MethodGet, s.imds+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, errFetchToken)
+	}
+	req.Header.Set("Metadata", "true")
+	return req, nil
+}
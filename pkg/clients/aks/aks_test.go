@@ -0,0 +1,134 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/imds":
+			if r.Header.Get("Metadata") != "true" || r.URL.Query().Get("resource") != DefaultServerID {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			// IMDS encodes numbers as strings.
+			_, _ = w.Write([]byte(`{"access_token":"mi-` + r.URL.Query().Get("client_id") + `","expires_in":"3600","token_type":"Bearer"}`))
+		case "/tenant/oauth2/v2.0/token":
+			_ = r.ParseForm()
+			if r.PostForm.Get("client_assertion") != "federated" || r.PostForm.Get("scope") != DefaultServerID+"/.default" {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte(`{"error":"invalid_client","error_description":"bad assertion"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token":"wi-` + r.PostForm.Get("client_id") + `","expires_in":3600,"token_type":"Bearer"}`))
+		}
+	}))
+	defer srv.Close()
+
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	type want struct {
+		token string
+		err   error
+	}
+
+	cases := map[string]struct {
+		cfg       Config
+		env       map[string]string
+		assertion string
+		want      want
+	}{
+		"ManagedIdentity": {
+			cfg: Config{ClientID: "client"},
+			env: map[string]string{},
+			want: want{
+				token: "mi-client",
+			},
+		},
+		"WorkloadIdentity": {
+			env:       map[string]string{envTokenFile: "/token", envTenantID: "tenant", envClientID: "client", envAuthorityHost: srv.URL},
+			assertion: "federated\n",
+			want: want{
+				token: "wi-client",
+			},
+		},
+		"WorkloadIdentityDenied": {
+			env:       map[string]string{envTokenFile: "/token", envTenantID: "tenant", envClientID: "client", envAuthorityHost: srv.URL},
+			assertion: "wrong",
+			want: want{
+				err: errors.Errorf(errAADError, "invalid_client", "bad assertion"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, err := newTokenSource(tc.cfg, func(k string) string { return tc.env[k] })
+			if err != nil {
+				t.Fatalf("newTokenSource(...): %s", err)
+			}
+			s.imds = srv.URL + "/imds"
+			s.now = func() time.Time { return now }
+			s.readFile = func(string) ([]byte, error) { return []byte(tc.assertion), nil }
+
+			tok, err := s.Token()
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("Token(): -want error, +got error: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.token, tok.AccessToken); diff != "" {
+				t.Errorf("Token(): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(now.Add(time.Hour-tokenLeeway), tok.Expiry); diff != "" {
+				t.Errorf("Token(): -want expiry, +got expiry:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewTokenSource(t *testing.T) {
+	cases := map[string]struct {
+		env map[string]string
+		err error
+	}{
+		"ManagedIdentity":  {env: map[string]string{}},
+		"WorkloadIdentity": {env: map[string]string{envTokenFile: "/token", envTenantID: "tenant", envClientID: "client"}},
+		"NoTenant":         {env: map[string]string{envTokenFile: "/token", envClientID: "client"}, err: errors.New(errNoTenant)},
+		"NoClientID":       {env: map[string]string{envTokenFile: "/token", envTenantID: "tenant"}, err: errors.New(errNoClientID)},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := newTokenSource(Config{}, func(k string) string { return tc.env[k] })
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("newTokenSource(...): -want error, +got error: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	saNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+	saPrefix        = "system:serviceaccount:"
+
+	// Bound tokens are replaced once this fraction of their lifetime has
+	// passed, like kubelet does for projected tokens.
+	boundTokenRefreshRatio = 0.8
+
+	tokenRequestTimeout = 30 * time.Second
+)
+
+const (
+	errReadNamespace      = "cannot read namespace of the provider"
+	errReadInClusterToken = "cannot read the mounted ServiceAccount token of the provider"
+	errParseInClusterSub  = "cannot determine the ServiceAccount of the provider from its mounted token"
+	errNewCoreClient      = "cannot create Kubernetes core client"
+	errRequestToken       = "cannot request bound ServiceAccount token"
+)
+
+var (
+	boundTokensMu sync.Mutex
+	boundTokens   = map[string]oauth2.TokenSource{}
+)
+
+// WrapRESTConfigWithBoundToken configures the supplied in-cluster REST config
+// to use short-lived ServiceAccount tokens of the provider requested from the
+// TokenRequest API, rather than its mounted token. Tokens are refreshed
+// before they expire. The provider must be allowed to create tokens for its
+// own ServiceAccount.
+func WrapRESTConfigWithBoundToken(rc *rest.Config, audiences []string, expirationSeconds *int64) error {
+	key := strings.Join(audiences, ",") + "/"
+	if expirationSeconds != nil {
+		key += strconv.FormatInt(*expirationSeconds, 10)
+	}
+
+	boundTokensMu.Lock()
+	defer boundTokensMu.Unlock()
+
+	src, ok := boundTokens[key]
+	if !ok {
+		ts, err := newBoundTokenSource(rest.CopyConfig(rc), audiences, expirationSeconds)
+		if err != nil {
+			return err
+		}
+		// The token source is shared by all clients using the same
+		// audiences and expiry, so that tokens are not requested on every
+		// reconcile.
+		src = oauth2.ReuseTokenSource(nil, ts)
+		boundTokens[key] = src
+	}
+
+	rc.BearerToken = ""
+	rc.BearerTokenFile = ""
+	rc.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &oauth2.Transport{Source: src, Base: rt}
+	})
+	return nil
+}
+
+// A boundTokenSource requests ServiceAccount tokens from the TokenRequest
+// API.
+type boundTokenSource struct {
+	namespace         string
+	name              string
+	audiences         []string
+	expirationSeconds *int64
+	create            func(ctx context.Context, namespace, name string, tr *authenticationv1.TokenRequest) (*authenticationv1.TokenRequest, error)
+	now               func() time.Time
+}
+
+func newBoundTokenSource(rc *rest.Config, audiences []string, expirationSeconds *int64) (*boundTokenSource, error) {
+	ns, err := ioutil.ReadFile(saNamespaceFile)
+	if err != nil {
+		return nil, errors.Wrap(err, errReadNamespace)
+	}
+	tok, err := ioutil.ReadFile(rc.BearerTokenFile)
+	if err != nil {
+		return nil, errors.Wrap(err, errReadInClusterToken)
+	}
+	name, err := serviceAccountName(string(tok))
+	if err != nil {
+		return nil, err
+	}
+	c, err := corev1.NewForConfig(rc)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewCoreClient)
+	}
+	return &boundTokenSource{
+		namespace:         strings.TrimSpace(string(ns)),
+		name:              name,
+		audiences:         audiences,
+		expirationSeconds: expirationSeconds,
+		create: func(ctx context.Context, namespace, name string, tr *authenticationv1.TokenRequest) (*authenticationv1.TokenRequest, error) {
+			return c.ServiceAccounts(namespace).CreateToken(ctx, name, tr, metav1.CreateOptions{})
+		},
+		now: time.Now,
+	}, nil
+}
+
+// serviceAccountName returns the name of the ServiceAccount the supplied
+// token was issued for. The token is not verified.
+func serviceAccountName(token string) (string, error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return "", errors.New(errParseInClusterSub)
+	}
+	b, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", errors.Wrap(err, errParseInClusterSub)
+	}
+	var claims struct {
+		Subject string `json:"sub"`
+	}
+	if err := json.Unmarshal(b, &claims); err != nil {
+		return "", errors.Wrap(err, errParseInClusterSub)
+	}
+	// The subject is system:serviceaccount:<namespace>:<name>.
+	s := strings.Split(strings.TrimPrefix(claims.Subject, saPrefix), ":")
+	if !strings.HasPrefix(claims.Subject, saPrefix) || len(s) != 2 {
+		return "", errors.New(errParseInClusterSub)
+	}
+	return s[1], nil
+}
+
+// Token returns a new bound ServiceAccount token. Its expiry is brought
+// forward so that it is refreshed early.
+func (s *boundTokenSource) Token() (*oauth2.Token, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), tokenRequestTimeout)
+	defer cancel()
+
+	now := s.now()
+	tr, err := s.create(ctx, s.namespace, s.name, &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			Audiences:         s.audiences,
+			ExpirationSeconds: s.expirationSeconds,
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errRequestToken)
+	}
+
+	lifetime := tr.Status.ExpirationTimestamp.Sub(now)
+	return &oauth2.Token{
+		AccessToken: tr.Status.Token,
+		Expiry:      now.Add(time.Duration(float64(lifetime) * boundTokenRefreshRatio)),
+	}, nil
+}
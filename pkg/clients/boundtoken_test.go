@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func jwt(claims string) string {
+	return "e30." + base64.RawURLEncoding.EncodeToString([]byte(claims)) + ".sig"
+}
+
+func TestServiceAccountName(t *testing.T) {
+	type want struct {
+		name string
+		err  error
+	}
+
+	cases := map[string]struct {
+		token string
+		want  want
+	}{
+		"ServiceAccount": {
+			token: jwt(`{"sub":"system:serviceaccount:crossplane-system:provider-helm-1234"}`) + "\n",
+			want:  want{name: "provider-helm-1234"},
+		},
+		"NotServiceAccount": {
+			token: jwt(`{"sub":"admin"}`),
+			want:  want{err: errors.New(errParseInClusterSub)},
+		},
+		"NotJWT": {
+			token: "legacy",
+			want:  want{err: errors.New(errParseInClusterSub)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := serviceAccountName(tc.token)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("serviceAccountName(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.name, got); diff != "" {
+				t.Errorf("serviceAccountName(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBoundTokenSource(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	exp := int64(3600)
+
+	s := &boundTokenSource{
+		namespace:         "crossplane-system",
+		name:              "provider-helm",
+		audiences:         []string{"target"},
+		expirationSeconds: &exp,
+		now:               func() time.Time { return now },
+		create: func(_ context.Context, namespace, name string, tr *authenticationv1.TokenRequest) (*authenticationv1.TokenRequest, error) {
+			if namespace != "crossplane-system" || name != "provider-helm" || tr.Spec.Audiences[0] != "target" || *tr.Spec.ExpirationSeconds != exp {
+				return nil, errBoom
+			}
+			tr.Status = authenticationv1.TokenRequestStatus{
+				Token:               "bound",
+				ExpirationTimestamp: metav1.NewTime(now.Add(time.Hour)),
+			}
+			return tr, nil
+		},
+	}
+
+	tok, err := s.Token()
+	if err != nil {
+		t.Fatalf("Token(): %s", err)
+	}
+	if diff := cmp.Diff("bound", tok.AccessToken); diff != "" {
+		t.Errorf("Token(): -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(now.Add(48*time.Minute), tok.Expiry); diff != "" {
+		t.Errorf("Token(): -want expiry, +got expiry:\n%s", diff)
+	}
+}
+
+var errBoom = errors.New("boom")
@@ -18,6 +18,7 @@ package clients
 
 import (
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
@@ -25,18 +26,29 @@ import (
 )
 
 // NewRESTConfig returns a REST config given a secret with connection information.
-func NewRESTConfig(kubeconfig []byte) (*rest.Config, error) {
+// Well-known exec credential plugins are replaced with native token sources
+// if translateExec is true, other plugins are only executed if their command
+// is allowed.
+func NewRESTConfig(kubeconfig []byte, translateExec bool, allowedExecPlugins ...string) (*rest.Config, error) {
 	ac, err := clientcmd.Load(kubeconfig)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to load kubeconfig")
 	}
-	return restConfigFromAPIConfig(ac)
+	rc, err := restConfigFromAPIConfig(ac)
+	if err != nil {
+		return nil, err
+	}
+	if err := resolveExecProvider(rc, translateExec, allowedExecPlugins); err != nil {
+		return nil, err
+	}
+	return rc, nil
 }
 
 // NewKubeClient returns a kubernetes client given a secret with connection
-// information.
-func NewKubeClient(config *rest.Config) (client.Client, error) {
-	kc, err := client.New(config, client.Options{})
+// information. The supplied REST mapper, if any, is used to map kinds to
+// resources; otherwise the API of the cluster is discovered eagerly.
+func NewKubeClient(config *rest.Config, mapper meta.RESTMapper) (client.Client, error) {
+	kc, err := client.New(config, client.Options{Mapper: mapper})
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot create Kubernetes client")
 	}
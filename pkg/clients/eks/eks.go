@@ -0,0 +1,252 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package eks contains utilities for authenticating to EKS clusters.
+package eks
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	envRoleARN     = "AWS_ROLE_ARN"
+	envTokenFile   = "AWS_WEB_IDENTITY_TOKEN_FILE"
+	envSessionName = "AWS_ROLE_SESSION_NAME"
+	envRegion      = "AWS_REGION"
+	envDefRegion   = "AWS_DEFAULT_REGION"
+
+	defaultSessionName = "provider-helm"
+	stsVersion         = "2011-06-15"
+
+	tokenPrefix     = "k8s-aws-v1."
+	clusterIDHeader = "x-k8s-aws-id"
+
+	// EKS accepts a token for 15 minutes after it was signed. Tokens are
+	// replaced a minute early.
+	tokenLifetime = 14 * time.Minute
+	presignExpiry = 60 * time.Second
+
+	// Role credentials are renewed this long before they expire.
+	credentialsLeeway = 5 * time.Minute
+
+	stsTimeout = 30 * time.Second
+)
+
+const (
+	errNoWebIdentity    = "no web identity configured for the provider, " + envRoleARN + " and " + envTokenFile + " must be set"
+	errNoRegion         = "no region configured"
+	errReadTokenFile    = "cannot read web identity token file"
+	errAssumeWebRole    = "cannot assume role with web identity"
+	errAssumeRole       = "cannot assume role %s"
+	errSTSStatus        = "unexpected status %d from STS"
+	errSTSError         = "%s: %s"
+	errDecodeSTSReponse = "cannot decode STS response"
+)
+
+// Config of the EKS tokens.
+type Config struct {
+	// ClusterName is the name of the EKS cluster.
+	ClusterName string
+	// Region of the EKS cluster. Defaults to the region of the provider.
+	Region string
+	// RoleARN of a role that is assumed with the web identity role.
+	RoleARN string
+}
+
+// WrapRESTConfig configures the supplied REST config to use EKS tokens
+// generated for the IAM role of the web identity of the provider, i.e. IAM
+// roles for service accounts.
+func WrapRESTConfig(rc *rest.Config, cfg Config) error {
+	ts, err := newTokenSource(cfg, os.Getenv)
+	if err != nil {
+		return err
+	}
+
+	// ReuseTokenSource caches tokens until they expire.
+	src := oauth2.ReuseTokenSource(nil, ts)
+	rc.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &oauth2.Transport{Source: src, Base: rt}
+	})
+
+	return nil
+}
+
+// A tokenSource generates EKS tokens, i.e. presigned STS GetCallerIdentity
+// requests. It is not safe for concurrent use.
+type tokenSource struct {
+	cfg      Config
+	region   string
+	endpoint string
+	env      func(string) string
+	readFile func(string) ([]byte, error)
+	client   *http.Client
+	now      func() time.Time
+
+	creds *credentials
+}
+
+func newTokenSource(cfg Config, env func(string) string) (*tokenSource, error) {
+	region := cfg.Region
+	if region == "" {
+		region = env(envRegion)
+	}
+	if region == "" {
+		region = env(envDefRegion)
+	}
+	if region == "" {
+		return nil, errors.New(errNoRegion)
+	}
+	return &tokenSource{
+		cfg:      cfg,
+		region:   region,
+		endpoint: stsEndpoint(region),
+		env:      env,
+		readFile: ioutil.ReadFile,
+		client:   &http.Client{Timeout: stsTimeout},
+		now:      time.Now,
+	}, nil
+}
+
+func stsEndpoint(region string) string {
+	if strings.HasPrefix(region, "cn-") {
+		return "https://sts." + region + ".amazonaws.com.cn"
+	}
+	return "https://sts." + region + ".amazonaws.com"
+}
+
+// Token returns a new EKS token.
+func (s *tokenSource) Token() (*oauth2.Token, error) {
+	c, err := s.credentials()
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	q := url.Values{"Action": {"GetCallerIdentity"}, "Version": {stsVersion}}
+	u, err := presign(*c, s.region, "sts", s.endpoint, q, map[string]string{clusterIDHeader: s.cfg.ClusterName}, now, presignExpiry)
+	if err != nil {
+		return nil, err
+	}
+	return &oauth2.Token{
+		AccessToken: tokenPrefix + base64.RawURLEncoding.EncodeToString([]byte(u)),
+		Expiry:      now.Add(tokenLifetime),
+	}, nil
+}
+
+// credentials returns the credentials of the role, assuming it again if
+// the cached credentials are about to expire.
+func (s *tokenSource) credentials() (*credentials, error) {
+	if s.creds != nil && s.now().Before(s.creds.Expiration.Add(-credentialsLeeway)) {
+		return s.creds, nil
+	}
+
+	arn, file := s.env(envRoleARN), s.env(envTokenFile)
+	if arn == "" || file == "" {
+		return nil, errors.New(errNoWebIdentity)
+	}
+	tok, err := s.readFile(file)
+	if err != nil {
+		return nil, errors.Wrap(err, errReadTokenFile)
+	}
+	session := s.env(envSessionName)
+	if session == "" {
+		session = defaultSessionName
+	}
+
+	q := url.Values{
+		"Action":           {"AssumeRoleWithWebIdentity"},
+		"Version":          {stsVersion},
+		"RoleArn":          {arn},
+		"RoleSessionName":  {session},
+		"WebIdentityToken": {strings.TrimSpace(string(tok))},
+	}
+	c, err := s.call(s.endpoint + "/?" + q.Encode())
+	if err != nil {
+		return nil, errors.Wrap(err, errAssumeWebRole)
+	}
+
+	if s.cfg.RoleARN != "" {
+		q := url.Values{
+			"Action":          {"AssumeRole"},
+			"Version":         {stsVersion},
+			"RoleArn":         {s.cfg.RoleARN},
+			"RoleSessionName": {session},
+		}
+		u, err := presign(*c, s.region, "sts", s.endpoint, q, nil, s.now(), presignExpiry)
+		if err != nil {
+			return nil, errors.Wrapf(err, errAssumeRole, s.cfg.RoleARN)
+		}
+		if c, err = s.call(u); err != nil {
+			return nil, errors.Wrapf(err, errAssumeRole, s.cfg.RoleARN)
+		}
+	}
+
+	s.creds = c
+	return c, nil
+}
+
+// credentials are temporary AWS credentials returned by STS.
+type credentials struct {
+	AccessKeyID     string `xml:"AccessKeyId"`
+	SecretAccessKey string
+	SessionToken    string
+	Expiration      time.Time
+}
+
+// call sends the supplied STS request and returns the credentials of its
+// response.
+func (s *tokenSource) call(u string) (*credentials, error) {
+	resp, err := s.client.Get(u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error struct {
+				Code    string
+				Message string
+			}
+		}
+		if err := xml.NewDecoder(resp.Body).Decode(&e); err != nil || e.Error.Code == "" {
+			return nil, errors.Errorf(errSTSStatus, resp.StatusCode)
+		}
+		return nil, errors.Errorf(errSTSError, e.Error.Code, e.Error.Message)
+	}
+
+	// The credentials are wrapped in an element named after the action,
+	// e.g. AssumeRoleResult.
+	var r struct {
+		Result struct {
+			Credentials credentials
+		} `xml:",any"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, errors.Wrap(err, errDecodeSTSReponse)
+	}
+	return &r.Result.Credentials, nil
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eks
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+const stsResponse = `<%[1]sResponse><%[1]sResult><Credentials>
+<AccessKeyId>%[2]s</AccessKeyId><SecretAccessKey>secret</SecretAccessKey>
+<SessionToken>session-%[2]s</SessionToken><Expiration>2021-01-01T01:00:00Z</Expiration>
+</Credentials></%[1]sResult></%[1]sResponse>`
+
+const stsErrorResponse = `<ErrorResponse><Error><Code>AccessDenied</Code><Message>denied</Message></Error></ErrorResponse>`
+
+func TestToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch q.Get("Action") {
+		case "AssumeRoleWithWebIdentity":
+			if q.Get("WebIdentityToken") != "web-token" {
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte(stsErrorResponse))
+				return
+			}
+			_, _ = fmt.Fprintf(w, stsResponse, "AssumeRoleWithWebIdentity", "irsa")
+		case "AssumeRole":
+			if q.Get("X-Amz-Security-Token") != "session-irsa" || q.Get("X-Amz-Signature") == "" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = fmt.Fprintf(w, stsResponse, "AssumeRole", "cross")
+		}
+	}))
+	defer srv.Close()
+
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	env := map[string]string{envRoleARN: "arn:aws:iam::123456789012:role/irsa", envTokenFile: "/token", envRegion: "us-west-2"}
+
+	type want struct {
+		accessKey string
+		err       error
+	}
+
+	cases := map[string]struct {
+		cfg   Config
+		env   map[string]string
+		token string
+		want  want
+	}{
+		"NoWebIdentity": {
+			cfg: Config{ClusterName: "eks", Region: "us-west-2"},
+			env: map[string]string{},
+			want: want{
+				err: errors.New(errNoWebIdentity),
+			},
+		},
+		"AssumeWebRoleDenied": {
+			cfg:   Config{ClusterName: "eks"},
+			env:   env,
+			token: "wrong",
+			want: want{
+				err: errors.Wrap(errors.Errorf(errSTSError, "AccessDenied", "denied"), errAssumeWebRole),
+			},
+		},
+		"WebIdentityRole": {
+			cfg:   Config{ClusterName: "eks"},
+			env:   env,
+			token: "web-token\n",
+			want: want{
+				accessKey: "irsa",
+			},
+		},
+		"CrossAccountRole": {
+			cfg:   Config{ClusterName: "eks", RoleARN: "arn:aws:iam::210987654321:role/cross"},
+			env:   env,
+			token: "web-token",
+			want: want{
+				accessKey: "cross",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, err := newTokenSource(tc.cfg, func(k string) string { return tc.env[k] })
+			if err != nil {
+				t.Fatalf("newTokenSource(...): %s", err)
+			}
+			s.endpoint = srv.URL
+			s.now = func() time.Time { return now }
+			s.readFile = func(string) ([]byte, error) { return []byte(tc.token), nil }
+
+			tok, err := s.Token()
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("Token(): -want error, +got error: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+
+			if diff := cmp.Diff(now.Add(tokenLifetime), tok.Expiry); diff != "" {
+				t.Errorf("Token(): -want expiry, +got expiry: %s", diff)
+			}
+			if !strings.HasPrefix(tok.AccessToken, tokenPrefix) {
+				t.Fatalf("Token(): token %q does not have prefix %q", tok.AccessToken, tokenPrefix)
+			}
+			b, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(tok.AccessToken, tokenPrefix))
+			if err != nil {
+				t.Fatalf("Token(): cannot decode token: %s", err)
+			}
+			u, err := url.Parse(string(b))
+			if err != nil {
+				t.Fatalf("Token(): cannot parse presigned URL: %s", err)
+			}
+			q := u.Query()
+			want := map[string]string{
+				"Action":               "GetCallerIdentity",
+				"X-Amz-Credential":     tc.want.accessKey + "/20210101/us-west-2/sts/aws4_request",
+				"X-Amz-Security-Token": "session-" + tc.want.accessKey,
+				"X-Amz-SignedHeaders":  "host;" + clusterIDHeader,
+				"X-Amz-Expires":        "60",
+			}
+			got := map[string]string{}
+			for k := range want {
+				got[k] = q.Get(k)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Token(): -want query, +got query: %s", diff)
+			}
+		})
+	}
+}
+
+func TestNewTokenSourceRegion(t *testing.T) {
+	cases := map[string]struct {
+		cfg  Config
+		env  map[string]string
+		want string
+		err  error
+	}{
+		"Config":        {cfg: Config{Region: "eu-west-1"}, env: map[string]string{envRegion: "us-west-2"}, want: "eu-west-1"},
+		"Environment":   {env: map[string]string{envRegion: "us-west-2", envDefRegion: "us-east-1"}, want: "us-west-2"},
+		"DefaultRegion": {env: map[string]string{envDefRegion: "us-east-1"}, want: "us-east-1"},
+		"NoRegion":      {env: map[string]string{}, err: errors.New(errNoRegion)},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, err := newTokenSource(tc.cfg, func(k string) string { return tc.env[k] })
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("newTokenSource(...): -want error, +got error: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want, s.region); diff != "" {
+				t.Errorf("newTokenSource(...): -want region, +got region: %s", diff)
+			}
+		})
+	}
+}
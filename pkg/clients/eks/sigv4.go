@@ -0,0 +1,115 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	sigV4Algorithm = "AWS4-HMAC-SHA256"
+	sigV4Request   = "aws4_request"
+	amzDateFormat  = "20060102T150405Z"
+	amzDayFormat   = "20060102"
+
+	// emptyPayloadHash is the hex encoded SHA-256 of an empty body.
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	errParseEndpoint = "cannot parse endpoint"
+)
+
+// presign returns the supplied GET request to the endpoint as URL signed
+// with AWS Signature Version 4, including the supplied headers in the
+// signature.
+func presign(c credentials, region, service, endpoint string, q url.Values, headers map[string]string, now time.Time, expires time.Duration) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", errors.Wrap(err, errParseEndpoint)
+	}
+	if u.Path == "" {
+		u.Path = "/"
+	}
+
+	h := map[string]string{"host": u.Host}
+	for k, v := range headers {
+		h[strings.ToLower(k)] = strings.TrimSpace(v)
+	}
+	names := make([]string, 0, len(h))
+	for k := range h {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	ch := &strings.Builder{}
+	for _, k := range names {
+		ch.WriteString(k + ":" + h[k] + "\n")
+	}
+	signed := strings.Join(names, ";")
+
+	now = now.UTC()
+	scope := strings.Join([]string{now.Format(amzDayFormat), region, service, sigV4Request}, "/")
+
+	sq := url.Values{}
+	for k, v := range q {
+		sq[k] = v
+	}
+	sq.Set("X-Amz-Algorithm", sigV4Algorithm)
+	sq.Set("X-Amz-Credential", c.AccessKeyID+"/"+scope)
+	sq.Set("X-Amz-Date", now.Format(amzDateFormat))
+	sq.Set("X-Amz-Expires", strconv.Itoa(int(expires.Seconds())))
+	sq.Set("X-Amz-SignedHeaders", signed)
+	if c.SessionToken != "" {
+		sq.Set("X-Amz-Security-Token", c.SessionToken)
+	}
+	query := canonicalQuery(sq)
+
+	creq := strings.Join([]string{"GET", u.EscapedPath(), query, ch.String(), signed, emptyPayloadHash}, "\n")
+	sts := strings.Join([]string{sigV4Algorithm, now.Format(amzDateFormat), scope, hexSHA256(creq)}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.SecretAccessKey), now.Format(amzDayFormat))
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, sigV4Request)
+
+	u.RawQuery = query + "&X-Amz-Signature=" + hex.EncodeToString(hmacSHA256(key, sts))
+	return u.String(), nil
+}
+
+// canonicalQuery encodes the supplied query sorted by key, escaping spaces
+// as %20 rather than +.
+func canonicalQuery(q url.Values) string {
+	return strings.ReplaceAll(q.Encode(), "+", "%20")
+}
+
+func hexSHA256(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data)) //nolint:errcheck
+	return h.Sum(nil)
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ess contains a client of the external secret store plugins of
+// Crossplane, such as ess-plugin-vault. Plugins serve the gRPC service
+// described in ess.proto, authenticated with mutual TLS.
+package ess
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative ess.proto
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// Files of the certificates that clients of plugins authenticate with, in
+// the same layout as those of Crossplane.
+const (
+	CAFile   = "ca.crt"
+	CertFile = "tls.crt"
+	KeyFile  = "tls.key"
+)
+
+const (
+	errNoCertDir     = "no TLS certificate directory is configured for external secret store plugins"
+	errReadCA        = "cannot read CA certificate of external secret store plugins"
+	errParseCA       = "cannot parse CA certificate of external secret store plugins"
+	errLoadClientKey = "cannot load client certificate of external secret store plugins"
+	errDial          = "cannot dial external secret store plugin"
+)
+
+// Dial returns a connection to the plugin serving the supplied endpoint. The
+// client authenticates with the certificate and key in the supplied
+// directory, and verifies the plugin with its CA certificate. The connection
+// is established lazily, so the plugin need not be running yet.
+func Dial(endpoint, certDir string) (*grpc.ClientConn, error) {
+	if certDir == "" {
+		return nil, errors.New(errNoCertDir)
+	}
+	ca, err := ioutil.ReadFile(filepath.Join(certDir, CAFile)) // nolint:gosec
+	if err != nil {
+		return nil, errors.Wrap(err, errReadCA)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.New(errParseCA)
+	}
+	cert, err := tls.LoadX509KeyPair(filepath.Join(certDir, CertFile), filepath.Join(certDir, KeyFile))
+	if err != nil {
+		return nil, errors.Wrap(err, errLoadClientKey)
+	}
+	creds := credentials.NewTLS(&tls.Config{
+		RootCAs:      pool,
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	})
+	conn, err := grpc.Dial(endpoint, grpc.WithTransportCredentials(creds))
+	return conn, errors.Wrap(err, errDial)
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ess
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestDial(t *testing.T) {
+	invalidCA, err := ioutil.TempDir("", "ess")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(invalidCA) // nolint:errcheck
+	if err := ioutil.WriteFile(filepath.Join(invalidCA, CAFile), []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]struct {
+		reason  string
+		certDir string
+		want    error
+	}{
+		"NoCertDir": {
+			reason: "Plugins should not be dialed without TLS certificates.",
+			want:   errors.New(errNoCertDir),
+		},
+		"InvalidCA": {
+			reason:  "A CA certificate that can't be parsed should be an error.",
+			certDir: invalidCA,
+			want:    errors.New(errParseCA),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Dial("ess-plugin-vault:4040", tc.certDir)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDial(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/crossplane-contrib/provider-helm/pkg/clients/aks"
+	"github.com/crossplane-contrib/provider-helm/pkg/clients/eks"
+	"github.com/crossplane-contrib/provider-helm/pkg/clients/gke"
+)
+
+const (
+	errExecNotAllowed = "exec credential plugin %q is neither supported natively nor allowed, see --allow-exec-plugin"
+	errTranslateExec  = "cannot use native token source for exec credential plugin %q"
+)
+
+// resolveExecProvider replaces the exec credential plugin of the supplied
+// REST config with a native token source if translation is enabled and the
+// plugin is a well-known one, i.e. the AWS CLI, aws-iam-authenticator,
+// gke-gcloud-auth-plugin or kubelogin. The native token sources use the
+// identity of the provider, and send its tokens to whatever server the
+// REST config names. Other plugins are only executed if their command is
+// allowed.
+func resolveExecProvider(rc *rest.Config, translate bool, allowed []string) error {
+	e := rc.ExecProvider
+	if e == nil {
+		return nil
+	}
+
+	var wrap func() error
+	switch cmd := filepath.Base(e.Command); {
+	case !translate:
+		// Well-known plugins are treated like any other.
+	case cmd == "aws" && hasArgs(e.Args, "eks", "get-token"):
+		cfg := eks.Config{
+			ClusterName: flagValue(e.Args, "--cluster-name"),
+			Region:      flagValue(e.Args, "--region"),
+			RoleARN:     flagValue(e.Args, "--role-arn"),
+		}
+		if cfg.Region == "" {
+			cfg.Region = envValue(e.Env, "AWS_REGION", "AWS_DEFAULT_REGION")
+		}
+		wrap = func() error { return eks.WrapRESTConfig(rc, cfg) }
+	case cmd == "aws-iam-authenticator" && hasArgs(e.Args, "token"):
+		cfg := eks.Config{
+			ClusterName: flagValue(e.Args, "-i", "--cluster-id"),
+			RoleARN:     flagValue(e.Args, "-r", "--role"),
+			Region:      envValue(e.Env, "AWS_REGION", "AWS_DEFAULT_REGION"),
+		}
+		wrap = func() error { return eks.WrapRESTConfig(rc, cfg) }
+	case cmd == "gke-gcloud-auth-plugin":
+		wrap = func() error { return gke.WrapRESTConfigWithWorkloadIdentity(rc, gke.DefaultScopes...) }
+	case cmd == "kubelogin" && hasArgs(e.Args, "get-token") && nonInteractiveLogin(e.Args):
+		cfg := aks.Config{
+			ServerID: flagValue(e.Args, "--server-id"),
+			ClientID: flagValue(e.Args, "--client-id"),
+			TenantID: flagValue(e.Args, "-t", "--tenant-id"),
+		}
+		wrap = func() error { return aks.WrapRESTConfig(rc, cfg) }
+	}
+
+	if wrap != nil {
+		rc.ExecProvider = nil
+		return errors.Wrapf(wrap(), errTranslateExec, e.Command)
+	}
+
+	for _, a := range allowed {
+		// Commands must match exactly, i.e. allowing a command by name
+		// does not allow it at an arbitrary path.
+		if a == e.Command {
+			return nil
+		}
+	}
+	return errors.Errorf(errExecNotAllowed, e.Command)
+}
+
+// nonInteractiveLogin returns true if the supplied kubelogin arguments use
+// a login mode that is backed by the identity of the provider.
+func nonInteractiveLogin(args []string) bool {
+	switch flagValue(args, "-l", "--login") {
+	case "msi", "workloadidentity":
+		return true
+	}
+	return false
+}
+
+// hasArgs returns true if the supplied arguments contain all of the supplied
+// positional arguments.
+func hasArgs(args []string, want ...string) bool {
+	for _, w := range want {
+		found := false
+		for _, a := range args {
+			if a == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// flagValue returns the value of the first of the supplied flags that is
+// set in the supplied arguments, either as '--flag value' or '--flag=value'.
+func flagValue(args []string, flags ...string) string {
+	for _, f := range flags {
+		for i, a := range args {
+			if a == f && i+1 < len(args) {
+				return args[i+1]
+			}
+			if strings.HasPrefix(a, f+"=") {
+				return strings.TrimPrefix(a, f+"=")
+			}
+		}
+	}
+	return ""
+}
+
+// envValue returns the value of the first of the supplied environment
+// variables that is set for the exec credential plugin.
+func envValue(env []api.ExecEnvVar, names ...string) string {
+	for _, n := range names {
+		for _, e := range env {
+			if e.Name == n && e.Value != "" {
+				return e.Value
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestResolveExecProvider(t *testing.T) {
+	type want struct {
+		native bool
+		err    error
+	}
+
+	cases := map[string]struct {
+		exec      *api.ExecConfig
+		translate bool
+		allowed   []string
+		want      want
+	}{
+		"NoExecProvider": {},
+		"AWSCLI": {
+			exec:      &api.ExecConfig{Command: "aws", Args: []string{"--region", "us-west-2", "eks", "get-token", "--cluster-name", "eks"}},
+			translate: true,
+			want:      want{native: true},
+		},
+		"AWSIAMAuthenticator": {
+			exec: &api.ExecConfig{
+				Command: "aws-iam-authenticator",
+				Args:    []string{"token", "-i", "eks"},
+				Env:     []api.ExecEnvVar{{Name: "AWS_REGION", Value: "us-west-2"}},
+			},
+			translate: true,
+			want:      want{native: true},
+		},
+		"GKEAuthPlugin": {
+			exec:      &api.ExecConfig{Command: "/usr/lib/google-cloud-sdk/bin/gke-gcloud-auth-plugin"},
+			translate: true,
+			want:      want{native: true},
+		},
+		"KubeloginMSI": {
+			exec:      &api.ExecConfig{Command: "kubelogin", Args: []string{"get-token", "--login=msi", "--server-id", "server"}},
+			translate: true,
+			want:      want{native: true},
+		},
+		"KubeloginDeviceCode": {
+			exec:      &api.ExecConfig{Command: "kubelogin", Args: []string{"get-token", "--login", "devicecode"}},
+			translate: true,
+			want:      want{err: errors.Errorf(errExecNotAllowed, "kubelogin")},
+		},
+		"NotTranslated": {
+			exec: &api.ExecConfig{Command: "aws", Args: []string{"--region", "us-west-2", "eks", "get-token", "--cluster-name", "eks"}},
+			want: want{err: errors.Errorf(errExecNotAllowed, "aws")},
+		},
+		"NotTranslatedButAllowed": {
+			exec:    &api.ExecConfig{Command: "gke-gcloud-auth-plugin"},
+			allowed: []string{"gke-gcloud-auth-plugin"},
+		},
+		"Allowed": {
+			exec:    &api.ExecConfig{Command: "vault-k8s-token"},
+			allowed: []string{"vault-k8s-token"},
+		},
+		"AllowedByNameOnly": {
+			exec:    &api.ExecConfig{Command: "/tmp/vault-k8s-token"},
+			allowed: []string{"vault-k8s-token"},
+			want:    want{err: errors.Errorf(errExecNotAllowed, "/tmp/vault-k8s-token")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rc := &rest.Config{ExecProvider: tc.exec}
+			err := resolveExecProvider(rc, tc.translate, tc.allowed)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("resolveExecProvider(...): -want error, +got error: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if got := rc.ExecProvider == nil && rc.WrapTransport != nil; got != tc.want.native {
+				t.Errorf("resolveExecProvider(...): want native token source %t, got %t", tc.want.native, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gke
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	envMetadataHost     = "GCE_METADATA_HOST"
+	defaultMetadataHost = "metadata.google.internal"
+	metadataTokenPath   = "/computeMetadata/v1/instance/service-accounts/default/token"
+	metadataFlavor      = "Metadata-Flavor"
+
+	// Access tokens are replaced this long before they expire, so that
+	// requests in flight never carry an expired token.
+	tokenLeeway = 5 * time.Minute
+
+	metadataTimeout = 10 * time.Second
+)
+
+const (
+	errFetchToken     = "cannot fetch access token from the metadata server"
+	errMetadataStatus = "unexpected status %d from the metadata server"
+	errDecodeToken    = "cannot decode access token from the metadata server"
+)
+
+// WrapRESTConfigWithWorkloadIdentity configures the supplied REST config to
+// use OAuth2 bearer tokens of the workload identity of the provider, i.e. the
+// Google service account its Kubernetes service account is bound to.
+func WrapRESTConfigWithWorkloadIdentity(rc *rest.Config, scopes ...string) error {
+	host := os.Getenv(envMetadataHost)
+	if host == "" {
+		host = defaultMetadataHost
+	}
+	ts := &metadataTokenSource{
+		url:    "http://" + host + metadataTokenPath + "?" + url.Values{"scopes": {strings.Join(scopes, ",")}}.Encode(),
+		client: &http.Client{Timeout: metadataTimeout},
+	}
+
+	// ReuseTokenSource caches tokens until they are about to expire.
+	src := oauth2.ReuseTokenSource(nil, ts)
+	rc.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &oauth2.Transport{Source: src, Base: rt}
+	})
+
+	return nil
+}
+
+// A metadataTokenSource fetches access tokens from the GKE metadata server.
+type metadataTokenSource struct {
+	url    string
+	client *http.Client
+}
+
+// Token returns a new access token. Its expiry is brought forward by the
+// token leeway so that it is refreshed early.
+func (s *metadataTokenSource) Token() (*oauth2.Token, error) {
+	req, err := http.NewRequest(http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, errFetchToken)
+	}
+	req.Header.Set(metadataFlavor, "Google")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, errFetchToken)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf(errMetadataStatus, resp.StatusCode)
+	}
+
+	var t struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+		TokenType   string `json:"token_type"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return nil, errors.Wrap(err, errDecodeToken)
+	}
+
+	lifetime := time.Duration(t.ExpiresIn)*time.Second - tokenLeeway
+	if lifetime < 0 {
+		lifetime = 0
+	}
+	return &oauth2.Token{
+		AccessToken: t.AccessToken,
+		TokenType:   t.TokenType,
+		Expiry:      time.Now().Add(lifetime),
+	}, nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gke
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestMetadataTokenSource(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(metadataFlavor) != "Google" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Query().Get("scopes") != "a,b" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"tok","expires_in":3600,"token_type":"Bearer"}`))
+	}))
+	defer srv.Close()
+
+	type want struct {
+		token string
+		err   error
+	}
+
+	cases := map[string]struct {
+		url  string
+		want want
+	}{
+		"Success": {
+			url: srv.URL + metadataTokenPath + "?scopes=a%2Cb",
+			want: want{
+				token: "tok",
+			},
+		},
+		"UnexpectedStatus": {
+			url: srv.URL + metadataTokenPath + "?scopes=c",
+			want: want{
+				err: errors.Errorf(errMetadataStatus, http.StatusBadRequest),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &metadataTokenSource{url: tc.url, client: srv.Client()}
+			tok, err := s.Token()
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("Token(): -want error, +got error: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.token, tok.AccessToken); diff != "" {
+				t.Errorf("Token(): -want, +got:\n%s", diff)
+			}
+			if max := time.Now().Add(time.Hour - tokenLeeway); tok.Expiry.After(max) {
+				t.Errorf("Token(): expiry %s is later than %s", tok.Expiry, max)
+			}
+		})
+	}
+}
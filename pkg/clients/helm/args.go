@@ -1,6 +1,11 @@
 package helm
 
-import "time"
+import (
+	"time"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
 
 // Args stores common options that can be passed to a Helm client on initialization
 type Args struct {
@@ -12,4 +17,26 @@ type Args struct {
 	Timeout time.Duration
 	// SkipCRDs skips CRDs creation during Helm release install or upgrade.
 	SkipCRDs bool
+	// CommonLabels are added to every rendered resource.
+	CommonLabels map[string]string
+	// CommonAnnotations are added to every rendered resource.
+	CommonAnnotations map[string]string
+	// MaxHistory is the maximum number of revisions kept on upgrade.
+	MaxHistory int
+	// PostRenderWebhook configures an HTTP endpoint that post-renders the
+	// manifests after all patches were applied.
+	PostRenderWebhook *WebhookConfig
+	// ClientGetter is shared with other Helm clients of the same cluster and
+	// namespace. A new one is created if not set.
+	ClientGetter genericclioptions.RESTClientGetter
+	// Debug logs the verbose output of Helm actions at info rather than
+	// debug level.
+	Debug bool
+	// StorageSecrets is the Secrets client release records are read and
+	// written through, e.g. one that reads from a StorageCache. A client of
+	// the API server is used if not set.
+	StorageSecrets corev1client.SecretInterface
+	// Capabilities caches the capabilities of the cluster across Helm
+	// clients. They are discovered on every install and upgrade if not set.
+	Capabilities *CapabilitiesCache
 }
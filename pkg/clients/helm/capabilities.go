@@ -0,0 +1,105 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/release"
+	"k8s.io/client-go/discovery"
+)
+
+// capabilitiesTTL is how long the capabilities of a cluster are cached.
+const capabilitiesTTL = 5 * time.Minute
+
+const (
+	errGetServerVersion = "could not get server version from Kubernetes"
+	errGetAPIVersions   = "could not get apiVersions from Kubernetes"
+)
+
+// A CapabilitiesCache caches the capabilities of a target cluster, i.e. its
+// version and API versions, which Helm would otherwise discover anew for
+// every install and upgrade. It may be shared by the Helm clients of a
+// cluster.
+type CapabilitiesCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	caps    *chartutil.Capabilities
+	fetched time.Time
+}
+
+// NewCapabilitiesCache returns an empty CapabilitiesCache.
+func NewCapabilitiesCache() *CapabilitiesCache {
+	return &CapabilitiesCache{ttl: capabilitiesTTL, now: time.Now}
+}
+
+// Get returns the cached capabilities, discovering them through the supplied
+// discovery client if none are cached or they expired.
+func (c *CapabilitiesCache) Get(dc discovery.CachedDiscoveryInterface) (*chartutil.Capabilities, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.caps != nil && c.now().Sub(c.fetched) < c.ttl {
+		return c.caps, nil
+	}
+
+	// Like Helm, discover the latest capabilities rather than those the
+	// discovery client may have cached.
+	dc.Invalidate()
+	v, err := dc.ServerVersion()
+	if err != nil {
+		return nil, errors.Wrap(err, errGetServerVersion)
+	}
+	// Like Helm, tolerate orphaned API services; the API versions of all
+	// other groups are still discovered.
+	av, err := action.GetVersionSet(dc)
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return nil, errors.Wrap(err, errGetAPIVersions)
+	}
+
+	c.caps = &chartutil.Capabilities{
+		APIVersions: av,
+		KubeVersion: chartutil.KubeVersion{Version: v.GitVersion, Major: v.Major, Minor: v.Minor},
+	}
+	c.fetched = c.now()
+	return c.caps, nil
+}
+
+// Invalidate the cached capabilities, e.g. because CRDs were installed.
+func (c *CapabilitiesCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.caps = nil
+}
+
+// deploysCRDs returns whether the supplied chart or the manifest of the
+// supplied release, if any, contain CRDs, which change the capabilities of
+// the cluster.
+func deploysCRDs(ch *chart.Chart, rel *release.Release) bool {
+	if ch != nil && len(ch.CRDObjects()) > 0 {
+		return true
+	}
+	return rel != nil && strings.Contains(rel.Manifest, "kind: CustomResourceDefinition")
+}
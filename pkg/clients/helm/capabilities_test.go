@@ -0,0 +1,122 @@
+package helm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"helm.sh/helm/v3/pkg/chart"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/release"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery/cached/memory"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+func TestCapabilitiesCache(t *testing.T) {
+	fd := &fakediscovery.FakeDiscovery{
+		Fake: &k8stesting.Fake{Resources: []*metav1.APIResourceList{
+			{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{{Name: "deployments", Kind: "Deployment"}}},
+		}},
+		FakedServerVersion: &version.Info{GitVersion: "v1.21.2", Major: "1", Minor: "21"},
+	}
+	dc := memory.NewMemCacheClient(fd)
+
+	now := time.Now()
+	c := NewCapabilitiesCache()
+	c.now = func() time.Time { return now }
+
+	discoveries := func() int {
+		n := 0
+		for _, a := range fd.Actions() {
+			if a.GetResource().Resource == "version" {
+				n++
+			}
+		}
+		return n
+	}
+
+	for _, step := range []struct {
+		name  string
+		setup func()
+		want  int
+	}{
+		{name: "Discovered", want: 1},
+		{name: "Cached", want: 1},
+		{name: "Invalidated", setup: c.Invalidate, want: 2},
+		{name: "Expired", setup: func() { now = now.Add(capabilitiesTTL) }, want: 3},
+	} {
+		if step.setup != nil {
+			step.setup()
+		}
+		caps, err := c.Get(dc)
+		if err != nil {
+			t.Fatalf("%s: Get(...): unexpected error: %s", step.name, err)
+		}
+		if diff := cmp.Diff("v1.21.2", caps.KubeVersion.Version); diff != "" {
+			t.Errorf("%s: Get(...): -want version, +got version: %s", step.name, diff)
+		}
+		if !caps.APIVersions.Has("apps/v1") {
+			t.Errorf("%s: Get(...): want apps/v1 API version", step.name)
+		}
+		if diff := cmp.Diff(step.want, discoveries()); diff != "" {
+			t.Errorf("%s: Get(...): -want discoveries, +got discoveries: %s", step.name, diff)
+		}
+	}
+}
+
+func TestDeploysCRDs(t *testing.T) {
+	cases := map[string]struct {
+		ch   *chart.Chart
+		rel  *release.Release
+		want bool
+	}{
+		"ChartCRDs": {
+			ch:   &chart.Chart{Files: []*chart.File{{Name: "crds/crontab.yaml"}}},
+			want: true,
+		},
+		"TemplatedCRDs": {
+			ch:   &chart.Chart{},
+			rel:  &release.Release{Manifest: "apiVersion: apiextensions.k8s.io/v1\nkind: CustomResourceDefinition\n"},
+			want: true,
+		},
+		"NoCRDs": {
+			ch:  &chart.Chart{},
+			rel: &release.Release{Manifest: "apiVersion: v1\nkind: ConfigMap\n"},
+		},
+		"NoRelease": {
+			ch: &chart.Chart{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, deploysCRDs(tc.ch, tc.rel)); diff != "" {
+				t.Errorf("deploysCRDs(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func TestTemplateKeepsActionConfig(t *testing.T) {
+	rc := &rest.Config{Host: "https://127.0.0.1:1"}
+	c, err := NewClient(logging.NewNopLogger(), rc, func(a *Args) { a.Namespace = "default" })
+	if err != nil {
+		t.Fatalf("NewClient(...): unexpected error: %s", err)
+	}
+	hc := c.(*client)
+	ch := &chart.Chart{
+		Metadata:  &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "test", Version: "1.0.0"},
+		Templates: []*chart.File{{Name: "templates/cm.yaml", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: test\n")}},
+	}
+	if _, err := hc.Template("test", ch, map[string]interface{}{}, nil); err != nil {
+		t.Fatalf("Template(...): unexpected error: %s", err)
+	}
+	if _, ok := hc.actionConfig.KubeClient.(*kubefake.PrintingKubeClient); ok {
+		t.Errorf("Template(...): replaced the Kubernetes client of the install and upgrade actions")
+	}
+}
@@ -23,7 +23,9 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/pkg/errors"
@@ -31,7 +33,12 @@ import (
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
 	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/postrender"
 	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/repo"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
 	"k8s.io/client-go/rest"
 	ktype "sigs.k8s.io/kustomize/api/types"
 
@@ -50,6 +57,9 @@ const (
 	errFailedToLoadChart               = "failed to load chart"
 	errUnexpectedDirContentTmpl        = "expected 1 .tgz chart file, got [%s]"
 	errFailedToParseURL                = "failed to parse URL"
+	errFailedToGetReleaseHistory       = "failed to get release history"
+	errFailedToDeleteReleaseRecord     = "failed to delete release record"
+	errFailedToGetDiscoveryClient      = "failed to get discovery client"
 )
 
 // Client is the interface to interact with Helm
@@ -57,8 +67,12 @@ type Client interface {
 	GetLastRelease(release string) (*release.Release, error)
 	Install(release string, chart *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (*release.Release, error)
 	Upgrade(release string, chart *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (*release.Release, error)
+	Template(release string, chart *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (*release.Release, error)
+	Lint(chart *chart.Chart, vals map[string]interface{}) (*LintResult, error)
 	Rollback(release string) error
 	Uninstall(release string) error
+	Forget(release string) error
+	History(release string) ([]*release.Release, error)
 	PullAndLoadChart(spec *v1beta1.ChartSpec, creds *RepoCreds) (*chart.Chart, error)
 }
 
@@ -67,9 +81,16 @@ type client struct {
 	pullClient      *action.Pull
 	getClient       *action.Get
 	installClient   *action.Install
+	templateClient  *action.Install
 	upgradeClient   *action.Upgrade
 	rollbackClient  *action.Rollback
 	uninstallClient *action.Uninstall
+	storage         *storage.Storage
+	actionConfig    *action.Configuration
+	capabilities    *CapabilitiesCache
+	metadataRender  *MetadataRender
+	webhookRender   *WebhookRender
+	maxHistory      int
 }
 
 // ArgsApplier defines helm client arguments helper
@@ -83,15 +104,27 @@ func NewClient(log logging.Logger, restConfig *rest.Config, argAppliers ...ArgsA
 		apply(args)
 	}
 
-	rg := newRESTClientGetter(restConfig, args.Namespace)
+	rg := args.ClientGetter
+	if rg == nil {
+		rg = newRESTClientGetter(restConfig, args.Namespace)
+	}
 
 	actionConfig := new(action.Configuration)
 	// Always store helm state in the same cluster/namespace where chart is deployed
 	if err := actionConfig.Init(rg, args.Namespace, helmDriverSecret, func(format string, v ...interface{}) {
-		log.Debug(fmt.Sprintf(format, v))
+		if args.Debug {
+			log.Info(fmt.Sprintf(format, v...))
+			return
+		}
+		log.Debug(fmt.Sprintf(format, v...))
 	}); err != nil {
 		return nil, err
 	}
+	if args.StorageSecrets != nil {
+		d := driver.NewSecrets(args.StorageSecrets)
+		d.Log = actionConfig.Log
+		actionConfig.Releases = storage.Init(d)
+	}
 
 	pc := action.NewPull()
 
@@ -113,6 +146,19 @@ func NewClient(log logging.Logger, restConfig *rest.Config, argAppliers ...ArgsA
 	ic.Timeout = args.Timeout
 	ic.SkipCRDs = args.SkipCRDs
 
+	// Rendering a template happens entirely on the client, similar to
+	// `helm template`, so that nothing is installed on the cluster. Helm
+	// replaces the Kubernetes client and storage of the configuration of a
+	// client-only install with fakes, so it must not share the configuration
+	// of the other actions.
+	templateConfig := *actionConfig
+	tc := action.NewInstall(&templateConfig)
+	tc.Namespace = args.Namespace
+	tc.DryRun = true
+	tc.ClientOnly = true
+	tc.Replace = true
+	tc.IncludeCRDs = !args.SkipCRDs
+
 	uc := action.NewUpgrade(actionConfig)
 	uc.Wait = args.Wait
 	uc.Timeout = args.Timeout
@@ -124,14 +170,39 @@ func NewClient(log logging.Logger, restConfig *rest.Config, argAppliers ...ArgsA
 	rb.Wait = args.Wait
 	rb.Timeout = args.Timeout
 
+	var wr *WebhookRender
+	if args.PostRenderWebhook != nil {
+		var err error
+		if wr, err = NewWebhookRender(*args.PostRenderWebhook); err != nil {
+			return nil, errors.Wrap(err, errFailedToBuildWebhookRender)
+		}
+	}
+
+	var mr *MetadataRender
+	if len(args.CommonLabels) > 0 || len(args.CommonAnnotations) > 0 {
+		mr = &MetadataRender{labels: args.CommonLabels, annotations: args.CommonAnnotations}
+	}
+
+	mh := args.MaxHistory
+	if mh == 0 {
+		mh = releaseMaxHistory
+	}
+
 	return &client{
 		log:             log,
 		pullClient:      pc,
 		getClient:       gc,
 		installClient:   ic,
+		templateClient:  tc,
 		upgradeClient:   uc,
 		rollbackClient:  rb,
 		uninstallClient: uic,
+		storage:         actionConfig.Releases,
+		actionConfig:    actionConfig,
+		capabilities:    args.Capabilities,
+		metadataRender:  mr,
+		webhookRender:   wr,
+		maxHistory:      mh,
 	}, nil
 }
 
@@ -163,7 +234,7 @@ func (hc *client) pullLatestChartVersion(spec *v1beta1.ChartSpec, creds *RepoCre
 	}()
 
 	if err := hc.pullChart(spec, creds, tmpDir); err != nil {
-		return "", nil
+		return "", err
 	}
 
 	chartFileName, err := getChartFileName(tmpDir)
@@ -173,12 +244,64 @@ func (hc *client) pullLatestChartVersion(spec *v1beta1.ChartSpec, creds *RepoCre
 
 	chartFilePath := filepath.Join(chartCache, chartFileName)
 	if err := os.Rename(filepath.Join(tmpDir, chartFileName), chartFilePath); err != nil {
-		return "", nil
+		return "", err
 	}
 	return chartFilePath, nil
 }
 
 func (hc *client) pullChart(spec *v1beta1.ChartSpec, creds *RepoCreds, chartDir string) error {
+	start := time.Now()
+	err := hc.pull(spec, creds, chartDir)
+	observeChartPull(spec, start, err)
+	return errors.Wrap(err, errFailedToPullChart)
+}
+
+// pull fetches charts of a source type using its fetcher, streams charts
+// served over HTTP to disk and has Helm pull all others.
+func (hc *client) pull(spec *v1beta1.ChartSpec, creds *RepoCreds, chartDir string) error {
+	if spec.SourceType != "" {
+		return hc.fetch(spec, creds, chartDir)
+	}
+	chartURL := spec.URL
+	if spec.URL == "" {
+		if u, err := url.Parse(spec.Repository); err != nil || !streamable(u) {
+			return hc.helmPull(spec, creds, chartDir)
+		}
+		// Resolving the chart URL downloads the index of the repository.
+		var err error
+		pc := hc.pullClient
+		chartURL, err = repo.FindChartInAuthAndTLSAndPassRepoURL(spec.Repository, creds.Username, creds.Password, spec.Name, spec.Version, pc.CertFile, pc.KeyFile, pc.CaFile, pc.InsecureSkipTLSverify, false, getter.All(pc.Settings))
+		if err != nil {
+			return err
+		}
+	}
+	u, err := url.Parse(chartURL)
+	if err != nil {
+		return errors.Wrap(err, errFailedToParseURL)
+	}
+	if !streamable(u) {
+		return hc.helmPull(spec, creds, chartDir)
+	}
+	// Like Helm, credentials of a repository are only sent along to charts
+	// it serves from its own host.
+	if spec.URL == "" {
+		if ru, err := url.Parse(spec.Repository); err != nil || ru.Host != u.Host {
+			creds = nil
+		}
+	}
+	c, err := downloadClient(hc.pullClient, u)
+	if err != nil {
+		return err
+	}
+	p, err := downloadChart(c, u, creds, chartDir)
+	if err != nil {
+		return err
+	}
+	hc.log.Debug("Downloaded chart", "url", u.Redacted(), "path", p)
+	return nil
+}
+
+func (hc *client) helmPull(spec *v1beta1.ChartSpec, creds *RepoCreds, chartDir string) error {
 	pc := hc.pullClient
 
 	chartRef := spec.URL
@@ -195,23 +318,27 @@ func (hc *client) pullChart(spec *v1beta1.ChartSpec, creds *RepoCreds, chartDir
 
 	o, err := pc.Run(chartRef)
 	hc.log.Debug(o)
-	if err != nil {
-		return errors.Wrap(err, errFailedToPullChart)
-	}
-	return nil
+	return err
 }
 
 func (hc *client) PullAndLoadChart(spec *v1beta1.ChartSpec, creds *RepoCreds) (*chart.Chart, error) {
 	var chartFilePath string
 	var err error
-	if spec.URL == "" && spec.Version == "" {
-		chartFilePath, err = hc.pullLatestChartVersion(spec, creds)
+	if spec.Version == "" && (spec.URL == "" || spec.SourceType != "") {
+		// Pulling a chart without a version downloads the index of its
+		// repository to resolve the latest version. Fetchers resolve it
+		// themselves.
+		chartFilePath, err = chartPulls.do(pullKey(spec, "", creds), func() (string, error) {
+			p, err := hc.pullLatestChartVersion(spec, creds)
+			chartIndexRefreshes.WithLabelValues(result(err)).Inc()
+			return p, err
+		})
 		if err != nil {
 			return nil, err
 		}
 	} else {
 		filename := fmt.Sprintf("%s-%s.tgz", spec.Name, spec.Version)
-		if spec.URL != "" {
+		if spec.URL != "" && spec.SourceType == "" {
 			u, err := url.Parse(spec.URL)
 			if err != nil {
 				return nil, errors.Wrap(err, errFailedToParseURL)
@@ -220,12 +347,23 @@ func (hc *client) PullAndLoadChart(spec *v1beta1.ChartSpec, creds *RepoCreds) (*
 		}
 		chartFilePath = filepath.Join(chartCache, filename)
 
-		if _, err := os.Stat(chartFilePath); os.IsNotExist(err) {
-			if err = hc.pullChart(spec, creds, chartCache); err != nil {
+		_, err := os.Stat(chartFilePath)
+		if os.IsNotExist(err) {
+			chartCacheLookups.WithLabelValues(resultMiss).Inc()
+			_, err = chartPulls.do(pullKey(spec, spec.Version, creds), func() (string, error) {
+				// A pull that just completed may have cached the chart.
+				if _, err := os.Stat(chartFilePath); err == nil {
+					return chartFilePath, nil
+				}
+				return chartFilePath, hc.pullChart(spec, creds, chartCache)
+			})
+			if err != nil {
 				return nil, err
 			}
 		} else if err != nil {
 			return nil, errors.Wrap(err, errFailedToCheckIfLocalChartExists)
+		} else {
+			chartCacheLookups.WithLabelValues(resultHit).Inc()
 		}
 	}
 
@@ -242,30 +380,91 @@ func (hc *client) GetLastRelease(release string) (*release.Release, error) {
 
 func (hc *client) Install(release string, chart *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (*release.Release, error) {
 	hc.installClient.ReleaseName = release
+	hc.installClient.PostRenderer = hc.postRenderer(patches)
 
-	if len(patches) > 0 {
-		hc.installClient.PostRenderer = &KustomizationRender{
-			patches: patches,
-			logger:  hc.log,
-		}
+	if err := hc.useCachedCapabilities(); err != nil {
+		return nil, err
 	}
+	r, err := hc.installClient.Run(chart, vals)
+	hc.invalidateDiscovery(chart, r)
+	return r, err
+}
 
-	return hc.installClient.Run(chart, vals)
+func (hc *client) Template(release string, chart *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (*release.Release, error) {
+	hc.templateClient.ReleaseName = release
+	hc.templateClient.PostRenderer = hc.postRenderer(patches)
+
+	return hc.templateClient.Run(chart, vals)
 }
 
 func (hc *client) Upgrade(release string, chart *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (*release.Release, error) {
 	// Reset values so that source of truth for desired state is always the CR itself
 	hc.upgradeClient.ResetValues = true
-	hc.upgradeClient.MaxHistory = releaseMaxHistory
+	hc.upgradeClient.MaxHistory = hc.maxHistory
+	hc.upgradeClient.PostRenderer = hc.postRenderer(patches)
+
+	if err := hc.useCachedCapabilities(); err != nil {
+		return nil, err
+	}
+	r, err := hc.upgradeClient.Run(release, chart, vals)
+	hc.invalidateDiscovery(chart, r)
+	return r, err
+}
+
+// useCachedCapabilities has installs and upgrades use the cached capabilities
+// of the cluster, if they are cached, rather than discovering them.
+func (hc *client) useCachedCapabilities() error {
+	if hc.capabilities == nil {
+		return nil
+	}
+	dc, err := hc.actionConfig.RESTClientGetter.ToDiscoveryClient()
+	if err != nil {
+		return errors.Wrap(err, errFailedToGetDiscoveryClient)
+	}
+	caps, err := hc.capabilities.Get(dc)
+	if err != nil {
+		return err
+	}
+	hc.actionConfig.Capabilities = caps
+	return nil
+}
+
+// invalidateDiscovery invalidates the cached capabilities and REST mappings
+// of the cluster if the supplied install or upgrade may have changed them.
+func (hc *client) invalidateDiscovery(ch *chart.Chart, rel *release.Release) {
+	if !deploysCRDs(ch, rel) {
+		return
+	}
+	if hc.capabilities != nil {
+		hc.capabilities.Invalidate()
+	}
+	if i, ok := hc.actionConfig.RESTClientGetter.(interface{ Invalidate() }); ok {
+		i.Invalidate()
+	}
+}
 
+// postRenderer returns the post renderer for an install or upgrade. Kustomize
+// patches run first, followed by common metadata and finally the post-render
+// webhook if one is set.
+func (hc *client) postRenderer(patches []ktype.Patch) postrender.PostRenderer {
+	var pr []postrender.PostRenderer
 	if len(patches) > 0 {
-		hc.upgradeClient.PostRenderer = &KustomizationRender{
+		pr = append(pr, &KustomizationRender{
 			patches: patches,
 			logger:  hc.log,
-		}
+		})
+	}
+	if hc.metadataRender != nil {
+		pr = append(pr, hc.metadataRender)
 	}
+	if hc.webhookRender != nil {
+		pr = append(pr, hc.webhookRender)
+	}
+	return newPostRenderer(pr...)
+}
 
-	return hc.upgradeClient.Run(release, chart, vals)
+func (hc *client) Lint(chart *chart.Chart, vals map[string]interface{}) (*LintResult, error) {
+	return lint(chart, hc.installClient.Namespace, vals)
 }
 
 func (hc *client) Rollback(release string) error {
@@ -276,3 +475,35 @@ func (hc *client) Uninstall(release string) error {
 	_, err := hc.uninstallClient.Run(release)
 	return err
 }
+
+// Forget deletes all revisions of a release from the Helm storage but leaves
+// the deployed resources in place.
+func (hc *client) Forget(release string) error {
+	h, err := hc.storage.History(release)
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, errFailedToGetReleaseHistory)
+	}
+	for _, r := range h {
+		if _, err := hc.storage.Delete(r.Name, r.Version); err != nil && !errors.Is(err, driver.ErrReleaseNotFound) {
+			return errors.Wrap(err, errFailedToDeleteReleaseRecord)
+		}
+	}
+	return nil
+}
+
+// History returns all revisions of a release, oldest first. No revisions are
+// returned if the release does not exist.
+func (hc *client) History(release string) ([]*release.Release, error) {
+	h, err := hc.storage.History(release)
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToGetReleaseHistory)
+	}
+	sort.Slice(h, func(i, j int) bool { return h[i].Version < h[j].Version })
+	return h, nil
+}
@@ -0,0 +1,44 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+func TestForget(t *testing.T) {
+	s := storage.Init(driver.NewMemory())
+	for _, r := range []*release.Release{
+		{Name: "test", Version: 1, Info: &release.Info{Status: release.StatusSuperseded}},
+		{Name: "test", Version: 2, Info: &release.Info{Status: release.StatusDeployed}},
+		{Name: "other", Version: 1, Info: &release.Info{Status: release.StatusDeployed}},
+	} {
+		if err := s.Create(r); err != nil {
+			t.Fatalf("Create(...): unexpected error: %s", err)
+		}
+	}
+	hc := &client{storage: s}
+
+	if err := hc.Forget("test"); err != nil {
+		t.Fatalf("Forget(...): unexpected error: %s", err)
+	}
+	// Forgetting an unknown release is not an error.
+	if err := hc.Forget("test"); err != nil {
+		t.Fatalf("Forget(...): unexpected error: %s", err)
+	}
+
+	all, err := s.ListReleases()
+	if err != nil {
+		t.Fatalf("ListReleases(...): unexpected error: %s", err)
+	}
+	names := make([]string, 0, len(all))
+	for _, r := range all {
+		names = append(names, r.Name)
+	}
+	if diff := cmp.Diff([]string{"other"}, names); diff != "" {
+		t.Errorf("Forget(...): -want remaining releases, +got remaining releases: %s", diff)
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/action"
+)
+
+const (
+	errFailedToDownloadChart = "failed to download chart"
+	errUnexpectedStatusTmpl  = "unexpected status %q downloading chart"
+	errChartURLHasNoFile     = "chart URL does not name a file"
+	errReadCAFile            = "failed to read CA file of chart repository"
+	errParseCAFile           = "failed to parse CA file of chart repository"
+	errLoadClientCert        = "failed to load client certificate of chart repository"
+)
+
+// chartDownloadTimeout bounds the time to download a chart archive, so that
+// a stalled repository cannot block a reconcile indefinitely.
+const chartDownloadTimeout = 5 * time.Minute
+
+// streamable returns whether the chart archive at the supplied URL can be
+// streamed to disk by downloadChart. Other charts, e.g. from OCI
+// registries, are pulled by Helm, which buffers them in memory.
+func streamable(u *url.URL) bool {
+	return u.Scheme == "http" || u.Scheme == "https"
+}
+
+// downloadClient returns an HTTP client to download the chart archive at the
+// supplied URL. Like the HTTP getter of Helm, it honours the proxy of the
+// environment and the TLS settings of the supplied pull action.
+func downloadClient(pc *action.Pull, u *url.URL) (*http.Client, error) {
+	t := &http.Transport{
+		DisableCompression: true,
+		Proxy:              http.ProxyFromEnvironment,
+	}
+	if (pc.CertFile != "" && pc.KeyFile != "") || pc.CaFile != "" || pc.InsecureSkipTLSverify {
+		cfg := &tls.Config{
+			ServerName:         u.Hostname(),
+			InsecureSkipVerify: pc.InsecureSkipTLSverify, // nolint:gosec
+		}
+		if pc.CertFile != "" && pc.KeyFile != "" {
+			cert, err := tls.LoadX509KeyPair(pc.CertFile, pc.KeyFile)
+			if err != nil {
+				return nil, errors.Wrap(err, errLoadClientCert)
+			}
+			cfg.Certificates = []tls.Certificate{cert}
+		}
+		if pc.CaFile != "" {
+			ca, err := ioutil.ReadFile(pc.CaFile)
+			if err != nil {
+				return nil, errors.Wrap(err, errReadCAFile)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(ca) {
+				return nil, errors.New(errParseCAFile)
+			}
+			cfg.RootCAs = pool
+		}
+		t.TLSClientConfig = cfg
+	}
+	return &http.Client{Transport: t, Timeout: chartDownloadTimeout}, nil
+}
+
+// downloadChart streams the chart archive at the supplied URL into a file of
+// the supplied directory, named after the last element of the path of the
+// URL, and returns the path of the file. The archive is written to a
+// temporary file that is renamed once it is complete, so that a failed
+// download never leaves a partial archive in the chart cache. The supplied
+// credentials, if any, are sent using basic auth.
+func downloadChart(hc *http.Client, u *url.URL, creds *RepoCreds, dir string) (string, error) {
+	name := path.Base(u.Path)
+	if name == "." || name == "/" {
+		return "", errors.New(errChartURLHasNoFile)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", errors.Wrap(err, errFailedToDownloadChart)
+	}
+	if creds != nil && (creds.Username != "" || creds.Password != "") {
+		req.SetBasicAuth(creds.Username, creds.Password)
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, errFailedToDownloadChart)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf(errUnexpectedStatusTmpl, resp.Status)
+	}
+
+	f, err := ioutil.TempFile(dir, ".download-")
+	if err != nil {
+		return "", errors.Wrap(err, errFailedToDownloadChart)
+	}
+	tmp := f.Name()
+	_, err = io.Copy(f, resp.Body)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return "", errors.Wrap(err, errFailedToDownloadChart)
+	}
+
+	p := filepath.Join(dir, name)
+	if err := os.Rename(tmp, p); err != nil {
+		_ = os.Remove(tmp)
+		return "", errors.Wrap(err, errFailedToDownloadChart)
+	}
+	return p, nil
+}
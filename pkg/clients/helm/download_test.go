@@ -0,0 +1,167 @@
+package helm
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"helm.sh/helm/v3/pkg/action"
+)
+
+func TestDownloadChart(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if u, p, ok := r.BasicAuth(); ok && (u != "admin" || p != "secret") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/charts/wordpress-1.0.0.tgz" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("archive"))
+	}))
+	defer srv.Close()
+
+	type want struct {
+		file  string
+		err   bool
+		files []string
+	}
+	cases := map[string]struct {
+		path  string
+		creds *RepoCreds
+		want  want
+	}{
+		"Downloaded": {
+			path: "/charts/wordpress-1.0.0.tgz",
+			want: want{file: "wordpress-1.0.0.tgz", files: []string{"wordpress-1.0.0.tgz"}},
+		},
+		"DownloadedWithCredentials": {
+			path:  "/charts/wordpress-1.0.0.tgz",
+			creds: &RepoCreds{Username: "admin", Password: "secret"},
+			want:  want{file: "wordpress-1.0.0.tgz", files: []string{"wordpress-1.0.0.tgz"}},
+		},
+		"Unauthorized": {
+			path:  "/charts/wordpress-1.0.0.tgz",
+			creds: &RepoCreds{Username: "admin", Password: "wrong"},
+			want:  want{err: true, files: []string{}},
+		},
+		"NotFound": {
+			path: "/charts/mysql-1.0.0.tgz",
+			want: want{err: true, files: []string{}},
+		},
+		"NoFile": {
+			path: "/",
+			want: want{err: true, files: []string{}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "charts")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir) // nolint:errcheck
+
+			u, _ := url.Parse(srv.URL + tc.path)
+			p, err := downloadChart(srv.Client(), u, tc.creds, dir)
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("downloadChart(...): -want error, +got error: %s (%v)", diff, err)
+			}
+			if tc.want.file != "" {
+				if diff := cmp.Diff(filepath.Join(dir, tc.want.file), p); diff != "" {
+					t.Errorf("downloadChart(...): -want path, +got path: %s", diff)
+				}
+				b, _ := ioutil.ReadFile(p)
+				if diff := cmp.Diff("archive", string(b)); diff != "" {
+					t.Errorf("downloadChart(...): -want content, +got content: %s", diff)
+				}
+			}
+			fs, _ := ioutil.ReadDir(dir)
+			got := []string{}
+			for _, f := range fs {
+				got = append(got, f.Name())
+			}
+			if diff := cmp.Diff(tc.want.files, got); diff != "" {
+				t.Errorf("downloadChart(...): -want files, +got files: %s", diff)
+			}
+		})
+	}
+}
+
+func TestDownloadClient(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("archive"))
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "certs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // nolint:errcheck
+	ca := filepath.Join(dir, "ca.crt")
+	if err := ioutil.WriteFile(ca, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	invalid := filepath.Join(dir, "invalid.crt")
+	if err := ioutil.WriteFile(invalid, []byte("invalid"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	type want struct {
+		clientErr bool
+		getErr    bool
+	}
+	cases := map[string]struct {
+		pull *action.Pull
+		want want
+	}{
+		"UntrustedServer": {
+			pull: &action.Pull{},
+			want: want{getErr: true},
+		},
+		"TrustedCA": {
+			pull: &action.Pull{ChartPathOptions: action.ChartPathOptions{CaFile: ca}},
+		},
+		"InsecureSkipVerify": {
+			pull: &action.Pull{ChartPathOptions: action.ChartPathOptions{InsecureSkipTLSverify: true}},
+		},
+		"InvalidCA": {
+			pull: &action.Pull{ChartPathOptions: action.ChartPathOptions{CaFile: invalid}},
+			want: want{clientErr: true},
+		},
+		"MissingClientCert": {
+			pull: &action.Pull{ChartPathOptions: action.ChartPathOptions{CertFile: filepath.Join(dir, "tls.crt"), KeyFile: filepath.Join(dir, "tls.key")}},
+			want: want{clientErr: true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			u, _ := url.Parse(srv.URL + "/charts/wordpress-1.0.0.tgz")
+			c, err := downloadClient(tc.pull, u)
+			if diff := cmp.Diff(tc.want.clientErr, err != nil); diff != "" {
+				t.Fatalf("downloadClient(...): -want error, +got error: %s (%v)", diff, err)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(chartDownloadTimeout, c.Timeout); diff != "" {
+				t.Errorf("downloadClient(...): -want timeout, +got timeout: %s", diff)
+			}
+			resp, err := c.Get(u.String())
+			if err == nil {
+				resp.Body.Close() // nolint:errcheck
+			}
+			if diff := cmp.Diff(tc.want.getErr, err != nil); diff != "" {
+				t.Errorf("c.Get(...): -want error, +got error: %s (%v)", diff, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	errFmtUnknownSourceType = "no chart fetcher is registered for source type %q"
+)
+
+// A ChartFetcher fetches the charts of a source type, e.g. from an internal
+// artifact store. Fetched charts are cached in the chart cache like those
+// pulled from repositories.
+type ChartFetcher interface {
+	// Fetch writes the archive of the supplied chart into the supplied
+	// directory and returns its path. Like Helm, it must name the archive
+	// <name>-<version>.tgz. Charts without a version are expected to be
+	// fetched at their latest version.
+	Fetch(spec *v1beta1.ChartSpec, creds *RepoCreds, dir string) (string, error)
+}
+
+// A ChartFetcherFn is a function that satisfies the ChartFetcher interface.
+type ChartFetcherFn func(spec *v1beta1.ChartSpec, creds *RepoCreds, dir string) (string, error)
+
+// Fetch the supplied chart into the supplied directory.
+func (fn ChartFetcherFn) Fetch(spec *v1beta1.ChartSpec, creds *RepoCreds, dir string) (string, error) {
+	return fn(spec, creds, dir)
+}
+
+var (
+	fetchersMu sync.RWMutex
+	fetchers   = map[string]ChartFetcher{}
+)
+
+// RegisterChartFetcher registers the supplied fetcher for charts of the
+// supplied source type, i.e. of their spec.sourceType. It is intended to be
+// called from the init function of the package implementing the fetcher,
+// and panics if a fetcher is already registered for the source type.
+func RegisterChartFetcher(sourceType string, f ChartFetcher) {
+	fetchersMu.Lock()
+	defer fetchersMu.Unlock()
+	if sourceType == "" || f == nil {
+		panic("helm: chart fetcher and source type must be set")
+	}
+	if _, ok := fetchers[sourceType]; ok {
+		panic("helm: chart fetcher registered twice for source type " + sourceType)
+	}
+	fetchers[sourceType] = f
+}
+
+// chartFetcher returns the fetcher registered for the supplied source type.
+func chartFetcher(sourceType string) (ChartFetcher, error) {
+	fetchersMu.RLock()
+	defer fetchersMu.RUnlock()
+	f, ok := fetchers[sourceType]
+	if !ok {
+		return nil, errors.Errorf(errFmtUnknownSourceType, sourceType)
+	}
+	return f, nil
+}
+
+// fetch fetches the supplied chart using the fetcher of its source type.
+func (hc *client) fetch(spec *v1beta1.ChartSpec, creds *RepoCreds, chartDir string) error {
+	f, err := chartFetcher(spec.SourceType)
+	if err != nil {
+		return err
+	}
+	p, err := f.Fetch(spec, creds, chartDir)
+	if err != nil {
+		return err
+	}
+	hc.log.Debug("Fetched chart", "sourceType", spec.SourceType, "path", p)
+	return nil
+}
@@ -0,0 +1,77 @@
+package helm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"k8s.io/client-go/rest"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+func TestPullAndLoadChartFetcher(t *testing.T) {
+	const sourceType = "test-artifact-store"
+
+	fetches := 0
+	RegisterChartFetcher(sourceType, ChartFetcherFn(func(spec *v1beta1.ChartSpec, _ *RepoCreds, dir string) (string, error) {
+		fetches++
+		v := spec.Version
+		if v == "" {
+			v = "2.0.0"
+		}
+		return chartutil.Save(&chart.Chart{
+			Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: spec.Name, Version: v},
+		}, dir)
+	}))
+	defer func() {
+		fetchersMu.Lock()
+		delete(fetchers, sourceType)
+		fetchersMu.Unlock()
+	}()
+
+	c, err := NewClient(logging.NewNopLogger(), &rest.Config{}, func(a *Args) { a.Namespace = "default" })
+	if err != nil {
+		t.Fatalf("NewClient(...): unexpected error: %s", err)
+	}
+
+	spec := &v1beta1.ChartSpec{SourceType: sourceType, Name: "fetcher-test-chart", Version: "1.0.0"}
+	defer os.Remove(filepath.Join(chartCache, "fetcher-test-chart-1.0.0.tgz")) // nolint:errcheck
+	for i := 0; i < 2; i++ {
+		ch, err := c.PullAndLoadChart(spec, &RepoCreds{})
+		if err != nil {
+			t.Fatalf("PullAndLoadChart(...): unexpected error: %s", err)
+		}
+		if diff := cmp.Diff("1.0.0", ch.Metadata.Version); diff != "" {
+			t.Errorf("PullAndLoadChart(...): -want version, +got version:\n%s", diff)
+		}
+	}
+	if diff := cmp.Diff(1, fetches); diff != "" {
+		t.Errorf("PullAndLoadChart(...): want cached charts not to be fetched again: -want fetches, +got fetches:\n%s", diff)
+	}
+
+	// Charts without a version are fetched at their latest version.
+	latest := &v1beta1.ChartSpec{SourceType: sourceType, Name: "fetcher-test-chart"}
+	defer os.Remove(filepath.Join(chartCache, "fetcher-test-chart-2.0.0.tgz")) // nolint:errcheck
+	ch, err := c.PullAndLoadChart(latest, &RepoCreds{})
+	if err != nil {
+		t.Fatalf("PullAndLoadChart(...): unexpected error: %s", err)
+	}
+	if diff := cmp.Diff("2.0.0", ch.Metadata.Version); diff != "" {
+		t.Errorf("PullAndLoadChart(...): -want version, +got version:\n%s", diff)
+	}
+
+	unknown := &v1beta1.ChartSpec{SourceType: "unknown", Name: "fetcher-test-chart", Version: "1.0.0-unknown"}
+	_, err = c.PullAndLoadChart(unknown, &RepoCreds{})
+	want := errors.Wrap(errors.Errorf(errFmtUnknownSourceType, "unknown"), errFailedToPullChart)
+	if diff := cmp.Diff(want, err, test.EquateErrors()); diff != "" {
+		t.Errorf("PullAndLoadChart(...): -want error, +got error:\n%s", diff)
+	}
+}
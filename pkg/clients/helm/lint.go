@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/lint/support"
+)
+
+const (
+	errFailedToSaveChartForLint = "failed to save chart for linting"
+)
+
+// LintResult is the outcome of linting a chart.
+type LintResult struct {
+	// Warnings will likely not prevent the chart from working.
+	Warnings []string
+	// Errors will likely prevent the chart from working.
+	Errors []string
+}
+
+// lint lints the supplied chart with the supplied values, similar to
+// `helm lint`.
+func lint(ch *chart.Chart, namespace string, vals map[string]interface{}) (*LintResult, error) {
+	dir, err := ioutil.TempDir("", "helm-lint")
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToSaveChartForLint)
+	}
+	defer os.RemoveAll(dir) // nolint:errcheck
+
+	if err := chartutil.SaveDir(ch, dir); err != nil {
+		return nil, errors.Wrap(err, errFailedToSaveChartForLint)
+	}
+
+	l := action.NewLint()
+	l.Namespace = namespace
+	lr := l.Run([]string{filepath.Join(dir, ch.Name())}, vals)
+
+	res := &LintResult{}
+	for _, m := range lr.Messages {
+		switch m.Severity {
+		case support.ErrorSev:
+			res.Errors = append(res.Errors, m.Error())
+		case support.WarningSev:
+			res.Warnings = append(res.Warnings, m.Error())
+		}
+	}
+	// Errors that are not reported as messages, e.g. the chart could not be
+	// loaded at all.
+	if lr.TotalChartsLinted == 0 {
+		for _, err := range lr.Errors {
+			res.Errors = append(res.Errors, err.Error())
+		}
+	}
+	return res, nil
+}
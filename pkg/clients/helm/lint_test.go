@@ -0,0 +1,54 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func testLintChart(template string) *chart.Chart {
+	return &chart.Chart{
+		Metadata: &chart.Metadata{
+			APIVersion: chart.APIVersionV2,
+			Name:       "lint-test",
+			Version:    "0.1.0",
+		},
+		Templates: []*chart.File{
+			{Name: "templates/cm.yaml", Data: []byte(template)},
+		},
+	}
+}
+
+func TestLint(t *testing.T) {
+	type want struct {
+		errors int
+	}
+
+	cases := map[string]struct {
+		chart *chart.Chart
+		vals  map[string]interface{}
+		want  want
+	}{
+		"Valid": {
+			chart: testLintChart("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Values.name }}\n"),
+			vals:  map[string]interface{}{"name": "cm"},
+		},
+		"TemplateError": {
+			chart: testLintChart("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Values.name\n"),
+			want:  want{errors: 1},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := lint(tc.chart, "default", tc.vals)
+			if err != nil {
+				t.Fatalf("lint(...): unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want.errors, len(got.Errors)); diff != "" {
+				t.Errorf("lint(...): -want errors, +got errors: %s\n%v", diff, got.Errors)
+			}
+		})
+	}
+}
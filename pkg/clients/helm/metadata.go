@@ -0,0 +1,93 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	errFailedToParseRenderedManifests = "failed to parse rendered manifests"
+	errFailedToSetCommonMetadata      = "failed to set common metadata"
+)
+
+// AppliedMetadata is the record of the keys of the common labels and
+// annotations applied to a resource, stored in its
+// helm.crossplane.io/common-metadata annotation.
+type AppliedMetadata struct {
+	Labels      []string `json:"labels,omitempty"`
+	Annotations []string `json:"annotations,omitempty"`
+}
+
+// MetadataRender implements the helm PostRenderer interface by adding a set of
+// labels and annotations to every rendered resource.
+type MetadataRender struct {
+	labels      map[string]string
+	annotations map[string]string
+}
+
+// Run adds the configured labels and annotations to the rendered manifests,
+// and records their keys.
+func (mr MetadataRender) Run(renderedManifests *bytes.Buffer) (*bytes.Buffer, error) {
+	nodes, err := kio.FromBytes(renderedManifests.Bytes())
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToParseRenderedManifests)
+	}
+	applied, err := json.Marshal(AppliedMetadata{Labels: sortedKeys(mr.labels), Annotations: sortedKeys(mr.annotations)})
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToSetCommonMetadata)
+	}
+
+	for _, n := range nodes {
+		for _, k := range sortedKeys(mr.labels) {
+			if err := n.PipeE(yaml.SetLabel(k, mr.labels[k])); err != nil {
+				return nil, errors.Wrap(err, errFailedToSetCommonMetadata)
+			}
+		}
+		for _, k := range sortedKeys(mr.annotations) {
+			if err := n.PipeE(yaml.SetAnnotation(k, mr.annotations[k])); err != nil {
+				return nil, errors.Wrap(err, errFailedToSetCommonMetadata)
+			}
+		}
+		if err := n.PipeE(yaml.SetAnnotation(v1beta1.AnnotationKeyCommonMetadata, string(applied))); err != nil {
+			return nil, errors.Wrap(err, errFailedToSetCommonMetadata)
+		}
+	}
+
+	s, err := kio.StringAll(nodes)
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToParseRenderedManifests)
+	}
+	return bytes.NewBufferString(s), nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
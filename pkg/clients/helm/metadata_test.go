@@ -0,0 +1,54 @@
+package helm
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMetadataRender(t *testing.T) {
+	type want struct {
+		result string
+		err    error
+	}
+
+	cases := map[string]struct {
+		mr   MetadataRender
+		base string
+		want want
+	}{
+		"LabelsAndAnnotations": {
+			mr: MetadataRender{
+				labels:      map[string]string{"team": "platform", "app": "nginx"},
+				annotations: map[string]string{"owner": "ops"},
+			},
+			base: testDeployment,
+			want: want{
+				result: "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: nginx-deployment\n  labels:\n    app: 'nginx'\n    team: 'platform'\n  annotations:\n    owner: 'ops'\n    helm.crossplane.io/common-metadata: '{\"labels\":[\"app\",\"team\"],\"annotations\":[\"owner\"]}'\nspec:\n  selector:\n    matchLabels:\n      app: nginx\n      env: dev\n  template:\n    metadata:\n      labels:\n        app: nginx\n        env: dev\n    spec:\n      containers:\n      - name: nginx\n        image: nginx:1.14.2\n        ports:\n        - containerPort: 80\n",
+			},
+		},
+		"MultipleDocuments": {
+			mr: MetadataRender{
+				labels: map[string]string{"team": "platform"},
+			},
+			base: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\n  labels:\n    team: other\n",
+			want: want{
+				result: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n  labels:\n    team: 'platform'\n  annotations:\n    helm.crossplane.io/common-metadata: '{\"labels\":[\"team\"]}'\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\n  labels:\n    team: platform\n  annotations:\n    helm.crossplane.io/common-metadata: '{\"labels\":[\"team\"]}'\n",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.mr.Run(bytes.NewBufferString(tc.base))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("Run(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got.String()); diff != "" {
+				t.Errorf("Run(...): -want result, +got result: %s", diff)
+			}
+		})
+	}
+}
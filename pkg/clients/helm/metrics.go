@@ -0,0 +1,104 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	metricsNamespace = "provider_helm"
+
+	resultSuccess = "success"
+	resultFailure = "failure"
+	resultHit     = "hit"
+	resultMiss    = "miss"
+
+	sourceRepository = "repository"
+	sourceURL        = "url"
+	sourceUnknown    = "unknown"
+)
+
+var (
+	chartPullDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "chart",
+		Name:      "pull_duration_seconds",
+		Help:      "Latency of chart downloads from repositories, URLs and chart fetchers.",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"source", "result"})
+
+	chartCacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "chart",
+		Name:      "cache_lookups_total",
+		Help:      "Lookups of charts of pinned versions in the local chart cache, by whether the chart was cached.",
+	}, []string{"result"})
+
+	chartIndexRefreshes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "chart",
+		Name:      "index_refreshes_total",
+		Help:      "Repository index downloads to resolve the latest version of charts without a pinned version.",
+	}, []string{"result"})
+
+	chartPullsDeduplicated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "chart",
+		Name:      "pulls_deduplicated_total",
+		Help:      "Chart pulls that waited for a concurrent pull of the same chart rather than downloading it again.",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(chartPullDuration, chartCacheLookups, chartIndexRefreshes, chartPullsDeduplicated)
+}
+
+// chartSource returns the kind of source the supplied chart is pulled from.
+// Charts of source types without a fetcher are not pulled from any source.
+func chartSource(spec *v1beta1.ChartSpec) string {
+	if spec.SourceType != "" {
+		if _, err := chartFetcher(spec.SourceType); err != nil {
+			return sourceUnknown
+		}
+		return spec.SourceType
+	}
+	if spec.URL != "" {
+		return sourceURL
+	}
+	return sourceRepository
+}
+
+// result returns the result label of an operation that returned the
+// supplied error.
+func result(err error) string {
+	if err != nil {
+		return resultFailure
+	}
+	return resultSuccess
+}
+
+// observeChartPull records the latency of a pull of the supplied chart that
+// started at the supplied time and returned the supplied error.
+func observeChartPull(spec *v1beta1.ChartSpec, start time.Time, err error) {
+	chartPullDuration.WithLabelValues(chartSource(spec), result(err)).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"bytes"
+
+	"helm.sh/helm/v3/pkg/postrender"
+)
+
+// chainRender runs a series of post renderers, feeding the output of each
+// one into the next.
+type chainRender []postrender.PostRenderer
+
+func (cr chainRender) Run(renderedManifests *bytes.Buffer) (*bytes.Buffer, error) {
+	var err error
+	m := renderedManifests
+	for _, r := range cr {
+		if m, err = r.Run(m); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// newPostRenderer returns a post renderer running all supplied renderers in
+// order, or nil if there is nothing to run.
+func newPostRenderer(renderers ...postrender.PostRenderer) postrender.PostRenderer {
+	switch len(renderers) {
+	case 0:
+		return nil
+	case 1:
+		return renderers[0]
+	default:
+		return chainRender(renderers)
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"strings"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+// DefaultMaxParallelChartPulls is the number of charts that are pulled at
+// once unless SetMaxParallelChartPulls specifies otherwise.
+const DefaultMaxParallelChartPulls = 5
+
+// chartPulls are the chart pulls of all Helm clients, which share the chart
+// cache.
+var chartPulls = newPullGroup(DefaultMaxParallelChartPulls)
+
+// SetMaxParallelChartPulls sets how many charts are pulled at once. Zero
+// means unlimited. It must be called before any chart is pulled.
+func SetMaxParallelChartPulls(n int) {
+	chartPulls = newPullGroup(n)
+}
+
+// A pullGroup deduplicates concurrent pulls of the same chart and bounds the
+// number of charts that are pulled at once, so that the Releases of a chart
+// don't all download it at once, e.g. after the provider restarted.
+type pullGroup struct {
+	flights singleflight.Group
+	// slots holds a token for every pull in progress. Pulls are unbounded
+	// if nil.
+	slots chan struct{}
+}
+
+func newPullGroup(max int) *pullGroup {
+	g := &pullGroup{}
+	if max > 0 {
+		g.slots = make(chan struct{}, max)
+	}
+	return g
+}
+
+// do calls the supplied pull unless a pull of the same key is in progress,
+// in which case its result is returned once it completes.
+func (g *pullGroup) do(key string, pull func() (string, error)) (string, error) {
+	v, err, shared := g.flights.Do(key, func() (interface{}, error) {
+		if g.slots != nil {
+			g.slots <- struct{}{}
+			defer func() { <-g.slots }()
+		}
+		return pull()
+	})
+	if shared {
+		chartPullsDeduplicated.Inc()
+	}
+	p, _ := v.(string)
+	return p, err
+}
+
+// pullKey identifies the pulls of the supplied chart that may be
+// deduplicated. Pulls with different credentials are not, so that one
+// Release's wrong credentials can't fail the pulls of others.
+func pullKey(spec *v1beta1.ChartSpec, version string, creds *RepoCreds) string {
+	return strings.Join([]string{spec.SourceType, spec.Repository, spec.URL, spec.Name, version, creds.Username, creds.Password}, "\x00")
+}
@@ -0,0 +1,85 @@
+package helm
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+func TestPullGroupDeduplicates(t *testing.T) {
+	g := newPullGroup(DefaultMaxParallelChartPulls)
+	release := make(chan struct{})
+	var pulls int32
+
+	var wg sync.WaitGroup
+	paths := make([]string, 5)
+	for i := range paths {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			paths[i], _ = g.do("wordpress-1.0.0", func() (string, error) {
+				atomic.AddInt32(&pulls, 1)
+				<-release
+				return "/tmp/charts/wordpress-1.0.0.tgz", nil
+			})
+		}(i)
+	}
+	// Give all callers time to join the pull in progress.
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if diff := cmp.Diff(int32(1), pulls); diff != "" {
+		t.Errorf("do(...): -want pulls, +got pulls: %s", diff)
+	}
+	for _, p := range paths {
+		if diff := cmp.Diff("/tmp/charts/wordpress-1.0.0.tgz", p); diff != "" {
+			t.Errorf("do(...): -want path, +got path: %s", diff)
+		}
+	}
+}
+
+func TestPullGroupBounded(t *testing.T) {
+	max := 2
+	g := newPullGroup(max)
+	var inProgress, most int32
+
+	var wg sync.WaitGroup
+	for _, key := range []string{"a", "b", "c", "d", "e", "f"} {
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			_, _ = g.do(key, func() (string, error) {
+				n := atomic.AddInt32(&inProgress, 1)
+				for {
+					m := atomic.LoadInt32(&most)
+					if n <= m || atomic.CompareAndSwapInt32(&most, m, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				atomic.AddInt32(&inProgress, -1)
+				return key, nil
+			})
+		}(key)
+	}
+	wg.Wait()
+
+	if most > int32(max) {
+		t.Errorf("do(...): want at most %d pulls at once, got %d", max, most)
+	}
+}
+
+func TestPullKey(t *testing.T) {
+	spec := &v1beta1.ChartSpec{Repository: "https://charts.example.org", Name: "wordpress", Version: "1.0.0"}
+	a := pullKey(spec, spec.Version, &RepoCreds{Username: "admin", Password: "secret"})
+	b := pullKey(spec, spec.Version, &RepoCreds{Username: "admin", Password: "wrong"})
+	if a == b {
+		t.Errorf("pullKey(...): want pulls with different credentials to have different keys")
+	}
+}
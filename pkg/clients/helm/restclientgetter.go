@@ -17,17 +17,25 @@ limitations under the License.
 package helm
 
 import (
+	"sync"
+
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/discovery"
-	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/crossplane-contrib/provider-helm/pkg/clients"
 )
 
 type restClientGetter struct {
 	Namespace string
 	config    *rest.Config
+
+	once   sync.Once
+	mapper *clients.LazyRESTMapper
+	err    error
 }
 
 func newRESTClientGetter(config *rest.Config, namespace string) *restClientGetter {
@@ -37,34 +45,52 @@ func newRESTClientGetter(config *rest.Config, namespace string) *restClientGette
 	}
 }
 
+// NewRESTClientGetter returns a RESTClientGetter for the supplied namespace
+// that uses the supplied REST mapper and its discovery information, so that
+// the mapper may be shared by all clients of a cluster to avoid repeated
+// discovery. A mapper of its own is created on first use if none is
+// supplied.
+func NewRESTClientGetter(config *rest.Config, namespace string, mapper *clients.LazyRESTMapper) genericclioptions.RESTClientGetter {
+	g := newRESTClientGetter(config, namespace)
+	if mapper != nil {
+		g.once.Do(func() { g.mapper = mapper })
+	}
+	return g
+}
+
 func (c *restClientGetter) ToRESTConfig() (*rest.Config, error) {
 	return c.config, nil
 }
 
+func (c *restClientGetter) lazyRESTMapper() (*clients.LazyRESTMapper, error) {
+	c.once.Do(func() {
+		c.mapper, c.err = clients.NewLazyRESTMapper(c.config)
+	})
+	return c.mapper, c.err
+}
+
 func (c *restClientGetter) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
-	config, err := c.ToRESTConfig()
+	m, err := c.lazyRESTMapper()
 	if err != nil {
 		return nil, err
 	}
-
-	// The more groups you have, the more discovery requests you need to make.
-	// given 25 groups (our groups + a few custom conf) with one-ish version each, discovery needs to make 50 requests
-	// double it just so we don't end up here again for a while.  This config is only used for discovery.
-	config.Burst = 100
-
-	discoveryClient, _ := discovery.NewDiscoveryClientForConfig(config)
-	return memory.NewMemCacheClient(discoveryClient), nil
+	return m.Discovery(), nil
 }
 
 func (c *restClientGetter) ToRESTMapper() (meta.RESTMapper, error) {
-	discoveryClient, err := c.ToDiscoveryClient()
+	m, err := c.lazyRESTMapper()
 	if err != nil {
 		return nil, err
 	}
+	return restmapper.NewShortcutExpander(m, m.Discovery()), nil
+}
 
-	mapper := restmapper.NewDeferredDiscoveryRESTMapper(discoveryClient)
-	expander := restmapper.NewShortcutExpander(mapper, discoveryClient)
-	return expander, nil
+// Invalidate the discovery information of the getter, e.g. because CRDs
+// were installed.
+func (c *restClientGetter) Invalidate() {
+	if m, err := c.lazyRESTMapper(); err == nil {
+		m.Invalidate()
+	}
 }
 
 func (c *restClientGetter) ToRawKubeConfigLoader() clientcmd.ClientConfig {
@@ -0,0 +1,164 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+// storageLabelSelector selects the Secrets Helm stores release records in.
+const storageLabelSelector = "owner=helm"
+
+// storageCacheSyncTimeout is how long a StorageCache waits for its initial
+// list of release records.
+const storageCacheSyncTimeout = 30 * time.Second
+
+const (
+	errNewStorageClientset = "cannot create clientset for Helm storage cache"
+	errStorageCacheSync    = "cannot sync Helm storage cache"
+	errParseLabelSelector  = "cannot parse label selector"
+)
+
+// A StorageCache caches the Secrets that Helm stores the release records of
+// a target cluster in, in all namespaces. It requires permission to list and
+// watch Secrets across the target cluster.
+type StorageCache struct {
+	client  corev1client.CoreV1Interface
+	indexer cache.Indexer
+	lister  corev1listers.SecretLister
+	stop    chan struct{}
+}
+
+// NewStorageCache returns a StorageCache of the target cluster of the
+// supplied REST config, once it listed all release records.
+func NewStorageCache(rc *rest.Config) (*StorageCache, error) {
+	cs, err := kubernetes.NewForConfig(rc)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewStorageClientset)
+	}
+	f := informers.NewSharedInformerFactoryWithOptions(cs, 0, informers.WithTweakListOptions(func(o *metav1.ListOptions) {
+		o.LabelSelector = storageLabelSelector
+	}))
+	si := f.Core().V1().Secrets()
+	inf := si.Informer()
+
+	c := &StorageCache{
+		client:  cs.CoreV1(),
+		indexer: inf.GetIndexer(),
+		lister:  si.Lister(),
+		stop:    make(chan struct{}),
+	}
+	go inf.Run(c.stop)
+
+	ctx, cancel := context.WithTimeout(context.Background(), storageCacheSyncTimeout)
+	defer cancel()
+	if !cache.WaitForCacheSync(ctx.Done(), inf.HasSynced) {
+		c.Stop()
+		return nil, errors.New(errStorageCacheSync)
+	}
+	return c, nil
+}
+
+// Stop watching the release records.
+func (c *StorageCache) Stop() {
+	close(c.stop)
+}
+
+// Secrets returns a Secrets client of the supplied namespace that reads from
+// the cache. Writes are sent to the API server and added to the cache right
+// away, so that later reads of the same client observe them even before the
+// watch catches up.
+func (c *StorageCache) Secrets(namespace string) corev1client.SecretInterface {
+	return &cachedSecrets{
+		SecretInterface: c.client.Secrets(namespace),
+		namespace:       namespace,
+		indexer:         c.indexer,
+		lister:          c.lister,
+	}
+}
+
+// cachedSecrets implements the subset of a SecretInterface the Helm storage
+// driver uses on top of a cache.
+type cachedSecrets struct {
+	corev1client.SecretInterface
+	namespace string
+	indexer   cache.Indexer
+	lister    corev1listers.SecretLister
+}
+
+func (s *cachedSecrets) Get(_ context.Context, name string, _ metav1.GetOptions) (*corev1.Secret, error) {
+	o, err := s.lister.Secrets(s.namespace).Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return o.DeepCopy(), nil
+}
+
+func (s *cachedSecrets) List(_ context.Context, opts metav1.ListOptions) (*corev1.SecretList, error) {
+	sel, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseLabelSelector)
+	}
+	l, err := s.lister.Secrets(s.namespace).List(sel)
+	if err != nil {
+		return nil, err
+	}
+	out := &corev1.SecretList{Items: make([]corev1.Secret, 0, len(l))}
+	for _, o := range l {
+		out.Items = append(out.Items, *o.DeepCopy())
+	}
+	return out, nil
+}
+
+func (s *cachedSecrets) Create(ctx context.Context, secret *corev1.Secret, opts metav1.CreateOptions) (*corev1.Secret, error) {
+	o, err := s.SecretInterface.Create(ctx, secret, opts)
+	if err != nil {
+		return nil, err
+	}
+	_ = s.indexer.Add(o)
+	return o, nil
+}
+
+func (s *cachedSecrets) Update(ctx context.Context, secret *corev1.Secret, opts metav1.UpdateOptions) (*corev1.Secret, error) {
+	o, err := s.SecretInterface.Update(ctx, secret, opts)
+	if err != nil {
+		return nil, err
+	}
+	_ = s.indexer.Update(o)
+	return o, nil
+}
+
+func (s *cachedSecrets) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	err := s.SecretInterface.Delete(ctx, name, opts)
+	if err == nil || kerrors.IsNotFound(err) {
+		_ = s.indexer.Delete(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: s.namespace, Name: name}})
+	}
+	return err
+}
@@ -0,0 +1,94 @@
+package helm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newTestStorageCache(objs ...*corev1.Secret) (*StorageCache, *fake.Clientset) {
+	cs := fake.NewSimpleClientset()
+	idx := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, o := range objs {
+		_, _ = cs.CoreV1().Secrets(o.Namespace).Create(context.Background(), o, metav1.CreateOptions{})
+		_ = idx.Add(o)
+	}
+	return &StorageCache{client: cs.CoreV1(), indexer: idx, lister: corev1listers.NewSecretLister(idx)}, cs
+}
+
+func TestStorageCache(t *testing.T) {
+	c, cs := newTestStorageCache()
+	s := storage.Init(driver.NewSecrets(c.Secrets("wordpress")))
+
+	for _, r := range []*release.Release{
+		{Name: "wordpress", Namespace: "wordpress", Version: 1, Info: &release.Info{Status: release.StatusSuperseded}},
+		{Name: "wordpress", Namespace: "wordpress", Version: 2, Info: &release.Info{Status: release.StatusDeployed}},
+	} {
+		if err := s.Create(r); err != nil {
+			t.Fatalf("Create(...): unexpected error: %s", err)
+		}
+	}
+
+	// Reads are served by the cache, which saw the writes.
+	cs.ClearActions()
+	last, err := s.Last("wordpress")
+	if err != nil {
+		t.Fatalf("Last(...): unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(2, last.Version); diff != "" {
+		t.Errorf("Last(...): -want version, +got version:\n%s", diff)
+	}
+	if a := cs.Actions(); len(a) != 0 {
+		t.Errorf("Last(...): want no requests to the API server, got %v", a)
+	}
+
+	if _, err := s.Delete("wordpress", 2); err != nil {
+		t.Fatalf("Delete(...): unexpected error: %s", err)
+	}
+	h, err := s.History("wordpress")
+	if err != nil {
+		t.Fatalf("History(...): unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(1, len(h)); diff != "" {
+		t.Errorf("History(...): -want revisions, +got revisions:\n%s", diff)
+	}
+	if _, err := s.Get("wordpress", 2); err == nil {
+		t.Errorf("Get(...): want an error getting a deleted revision")
+	}
+}
+
+func TestStorageCacheList(t *testing.T) {
+	secret := func(ns, name, owner string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name, Labels: map[string]string{"owner": owner}}}
+	}
+	c, _ := newTestStorageCache(
+		secret("wordpress", "a", "helm"),
+		secret("wordpress", "b", "someone"),
+		secret("other", "c", "helm"),
+	)
+
+	l, err := c.Secrets("wordpress").List(context.Background(), metav1.ListOptions{LabelSelector: "owner=helm"})
+	if err != nil {
+		t.Fatalf("List(...): unexpected error: %s", err)
+	}
+	names := make([]string, 0, len(l.Items))
+	for _, s := range l.Items {
+		names = append(names, s.Name)
+	}
+	if diff := cmp.Diff([]string{"a"}, names); diff != "" {
+		t.Errorf("List(...): -want, +got:\n%s", diff)
+	}
+
+	if _, err := c.Secrets("wordpress").List(context.Background(), metav1.ListOptions{LabelSelector: "owner in ("}); err == nil {
+		t.Errorf("List(...): want an error for an invalid label selector")
+	}
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/crossplane-contrib/provider-helm/pkg/clients/postrender"
+)
+
+const (
+	defaultWebhookTimeout = 30 * time.Second
+	webhookContentType    = "application/x-yaml"
+	maxWebhookErrorBody   = 1024
+
+	// maxWebhookResponse limits the size of the manifests returned by a
+	// webhook.
+	maxWebhookResponse = 16 << 20
+)
+
+const (
+	errFailedToParseWebhookCA     = "failed to parse post-render webhook CA bundle"
+	errFailedToLoadWebhookCert    = "failed to load post-render webhook client certificate"
+	errFailedToCallWebhook        = "failed to call post-render webhook"
+	errFailedToReadWebhookResp    = "failed to read post-render webhook response"
+	errUnexpectedWebhookStatus    = "post-render webhook returned status %d: %s"
+	errEmptyWebhookResponse       = "post-render webhook returned an empty response"
+	errFailedToBuildWebhookCall   = "failed to build post-render webhook request"
+	errFailedToBuildWebhookRender = "failed to build post-render webhook client"
+	errFailedToParseWebhookURL    = "failed to parse post-render webhook URL"
+	errUnsupportedWebhookScheme   = "unsupported post-render webhook URL scheme %q"
+	errWebhookResponseTooLarge    = "post-render webhook response exceeds %d bytes"
+)
+
+// WebhookConfig configures a post-render webhook.
+type WebhookConfig struct {
+	// URL the rendered manifests are POSTed to. The manifests are sent to
+	// the PostRenderService of grpc:// and grpcs:// URLs instead.
+	URL string
+	// CAData is a PEM encoded CA bundle used to verify the server.
+	CAData []byte
+	// CertData and KeyData are a PEM encoded client certificate and key.
+	CertData []byte
+	KeyData  []byte
+	// BearerToken is sent in the Authorization header if set.
+	BearerToken string
+	// Timeout of a single request.
+	Timeout time.Duration
+}
+
+// WebhookRender implements the helm PostRenderer interface by delegating to
+// an HTTP or gRPC endpoint.
+type WebhookRender struct {
+	url    string
+	token  string
+	client *http.Client
+
+	// target and dialOpts of gRPC endpoints.
+	target   string
+	dialOpts []grpc.DialOption
+	timeout  time.Duration
+}
+
+// NewWebhookRender returns a WebhookRender for the supplied config.
+func NewWebhookRender(cfg WebhookConfig) (*WebhookRender, error) {
+	tc := &tls.Config{MinVersion: tls.VersionTLS12}
+	if len(cfg.CAData) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(cfg.CAData) {
+			return nil, errors.New(errFailedToParseWebhookCA)
+		}
+		tc.RootCAs = pool
+	}
+	if len(cfg.CertData) > 0 || len(cfg.KeyData) > 0 {
+		cert, err := tls.X509KeyPair(cfg.CertData, cfg.KeyData)
+		if err != nil {
+			return nil, errors.Wrap(err, errFailedToLoadWebhookCert)
+		}
+		tc.Certificates = []tls.Certificate{cert}
+	}
+
+	t := cfg.Timeout
+	if t == 0 {
+		t = defaultWebhookTimeout
+	}
+
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToParseWebhookURL)
+	}
+	switch u.Scheme {
+	case "http", "https":
+	case "grpc":
+		return &WebhookRender{token: cfg.BearerToken, target: u.Host, timeout: t, dialOpts: []grpc.DialOption{grpc.WithInsecure()}}, nil
+	case "grpcs":
+		return &WebhookRender{token: cfg.BearerToken, target: u.Host, timeout: t, dialOpts: []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tc))}}, nil
+	default:
+		return nil, errors.Errorf(errUnsupportedWebhookScheme, u.Scheme)
+	}
+
+	return &WebhookRender{
+		url:   cfg.URL,
+		token: cfg.BearerToken,
+		client: &http.Client{
+			Timeout:   t,
+			Transport: &http.Transport{TLSClientConfig: tc, Proxy: http.ProxyFromEnvironment},
+		},
+	}, nil
+}
+
+// Run sends the rendered manifests to the webhook and returns its response.
+func (wr *WebhookRender) Run(renderedManifests *bytes.Buffer) (*bytes.Buffer, error) {
+	if wr.target != "" {
+		return wr.runGRPC(renderedManifests)
+	}
+	req, err := http.NewRequest(http.MethodPost, wr.url, bytes.NewReader(renderedManifests.Bytes()))
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToBuildWebhookCall)
+	}
+	req.Header.Set("Content-Type", webhookContentType)
+	req.Header.Set("Accept", webhookContentType)
+	if wr.token != "" {
+		req.Header.Set("Authorization", "Bearer "+wr.token)
+	}
+
+	resp, err := wr.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToCallWebhook)
+	}
+	defer resp.Body.Close() // nolint:errcheck
+
+	b, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxWebhookResponse+1))
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToReadWebhookResp)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if len(b) > maxWebhookErrorBody {
+			b = b[:maxWebhookErrorBody]
+		}
+		return nil, errors.Errorf(errUnexpectedWebhookStatus, resp.StatusCode, string(b))
+	}
+	if len(b) > maxWebhookResponse {
+		return nil, errors.Errorf(errWebhookResponseTooLarge, maxWebhookResponse)
+	}
+	return webhookResponse(b)
+}
+
+// runGRPC sends the rendered manifests to the PostRenderService of the
+// webhook. A connection is dialed per call, because releases are rendered
+// rarely and each has its own webhook client.
+func (wr *WebhookRender) runGRPC(renderedManifests *bytes.Buffer) (*bytes.Buffer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), wr.timeout)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, wr.target, wr.dialOpts...)
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToCallWebhook)
+	}
+	defer conn.Close() // nolint:errcheck
+
+	if wr.token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+wr.token)
+	}
+	resp, err := postrender.NewPostRenderServiceClient(conn).PostRender(ctx,
+		&postrender.PostRenderRequest{Manifests: renderedManifests.Bytes()},
+		grpc.MaxCallRecvMsgSize(maxWebhookResponse))
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToCallWebhook)
+	}
+	return webhookResponse(resp.GetManifests())
+}
+
+func webhookResponse(b []byte) (*bytes.Buffer, error) {
+	if len(bytes.TrimSpace(b)) == 0 {
+		return nil, errors.New(errEmptyWebhookResponse)
+	}
+	return bytes.NewBuffer(b), nil
+}
@@ -0,0 +1,192 @@
+package helm
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/crossplane-contrib/provider-helm/pkg/clients/postrender"
+)
+
+const testToken = "s3cr3t"
+
+func TestWebhookRender(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+testToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte("unauthorized"))
+			return
+		}
+		b, _ := ioutil.ReadAll(r.Body)
+		if len(b) == 0 {
+			return
+		}
+		if string(b) == "large" {
+			_, _ = w.Write(bytes.Repeat([]byte("#"), maxWebhookResponse+1))
+			return
+		}
+		_, _ = w.Write(append([]byte("# mutated\n"), b...))
+	}))
+	defer srv.Close()
+
+	type want struct {
+		result string
+		err    error
+	}
+
+	cases := map[string]struct {
+		cfg  WebhookConfig
+		base string
+		want want
+	}{
+		"Success": {
+			cfg:  WebhookConfig{URL: srv.URL, BearerToken: testToken},
+			base: testDeployment,
+			want: want{
+				result: "# mutated\n" + testDeployment,
+			},
+		},
+		"Unauthorized": {
+			cfg:  WebhookConfig{URL: srv.URL},
+			base: testDeployment,
+			want: want{
+				err: errors.Errorf(errUnexpectedWebhookStatus, http.StatusUnauthorized, "unauthorized"),
+			},
+		},
+		"EmptyResponse": {
+			cfg:  WebhookConfig{URL: srv.URL, BearerToken: testToken},
+			base: "",
+			want: want{
+				err: errors.New(errEmptyWebhookResponse),
+			},
+		},
+		"ResponseTooLarge": {
+			cfg:  WebhookConfig{URL: srv.URL, BearerToken: testToken},
+			base: "large",
+			want: want{
+				err: errors.Errorf(errWebhookResponseTooLarge, maxWebhookResponse),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			wr, err := NewWebhookRender(tc.cfg)
+			if err != nil {
+				t.Fatalf("NewWebhookRender(...): %s", err)
+			}
+
+			got, gotErr := wr.Run(bytes.NewBufferString(tc.base))
+			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
+				t.Fatalf("Run(...): -want error, +got error: %s", diff)
+			}
+			gotResult := ""
+			if gotErr == nil {
+				gotResult = got.String()
+			}
+			if diff := cmp.Diff(tc.want.result, gotResult); diff != "" {
+				t.Errorf("Run(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+type testPostRenderServer struct {
+	postrender.UnimplementedPostRenderServiceServer
+}
+
+func (testPostRenderServer) PostRender(ctx context.Context, req *postrender.PostRenderRequest) (*postrender.PostRenderResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if a := md.Get("authorization"); len(a) != 1 || a[0] != "Bearer "+testToken {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+	if len(req.GetManifests()) == 0 {
+		return &postrender.PostRenderResponse{}, nil
+	}
+	return &postrender.PostRenderResponse{Manifests: append([]byte("# mutated\n"), req.GetManifests()...)}, nil
+}
+
+func TestWebhookRenderGRPC(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	postrender.RegisterPostRenderServiceServer(srv, testPostRenderServer{})
+	go srv.Serve(lis) // nolint:errcheck
+	defer srv.Stop()
+
+	type want struct {
+		result string
+		err    bool
+	}
+
+	cases := map[string]struct {
+		cfg  WebhookConfig
+		base string
+		want want
+	}{
+		"Success": {
+			cfg:  WebhookConfig{URL: "grpc://" + lis.Addr().String(), BearerToken: testToken},
+			base: testDeployment,
+			want: want{result: "# mutated\n" + testDeployment},
+		},
+		"Unauthorized": {
+			cfg:  WebhookConfig{URL: "grpc://" + lis.Addr().String()},
+			base: testDeployment,
+			want: want{err: true},
+		},
+		"EmptyResponse": {
+			cfg:  WebhookConfig{URL: "grpc://" + lis.Addr().String(), BearerToken: testToken},
+			base: "",
+			want: want{err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			wr, err := NewWebhookRender(tc.cfg)
+			if err != nil {
+				t.Fatalf("NewWebhookRender(...): %s", err)
+			}
+
+			got, gotErr := wr.Run(bytes.NewBufferString(tc.base))
+			if diff := cmp.Diff(tc.want.err, gotErr != nil); diff != "" {
+				t.Fatalf("Run(...): -want error, +got error: %s (%v)", diff, gotErr)
+			}
+			gotResult := ""
+			if gotErr == nil {
+				gotResult = got.String()
+			}
+			if diff := cmp.Diff(tc.want.result, gotResult); diff != "" {
+				t.Errorf("Run(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewWebhookRenderUnsupportedScheme(t *testing.T) {
+	_, err := NewWebhookRender(WebhookConfig{URL: "ftp://example.org"})
+	if diff := cmp.Diff(errors.Errorf(errUnsupportedWebhookScheme, "ftp"), err, test.EquateErrors()); diff != "" {
+		t.Errorf("NewWebhookRender(...): -want error, +got error: %s", diff)
+	}
+}
+
+func TestNewWebhookRenderInvalidCA(t *testing.T) {
+	_, err := NewWebhookRender(WebhookConfig{URL: "https://example.org", CAData: []byte("not a cert")})
+	if diff := cmp.Diff(errors.New(errFailedToParseWebhookCA), err, test.EquateErrors()); diff != "" {
+		t.Errorf("NewWebhookRender(...): -want error, +got error: %s", diff)
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"net/http"
+
+	"k8s.io/client-go/rest"
+)
+
+// headerImpersonateUID is not yet supported by the impersonation config of
+// the client-go version we use.
+const headerImpersonateUID = "Impersonate-Uid"
+
+// Impersonate configures the supplied REST config to act as the supplied
+// user, overriding any impersonation configured by its kubeconfig.
+func Impersonate(rc *rest.Config, username, uid string, groups []string) {
+	rc.Impersonate = rest.ImpersonationConfig{UserName: username, Groups: groups}
+	if uid == "" {
+		return
+	}
+	rc.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &uidRoundTripper{uid: uid, rt: rt}
+	})
+}
+
+// ImpersonateServiceAccount configures the supplied REST config to act as the
+// supplied ServiceAccount.
+func ImpersonateServiceAccount(rc *rest.Config, namespace, name string) {
+	Impersonate(rc, "system:serviceaccount:"+namespace+":"+name, "", []string{"system:serviceaccounts", "system:serviceaccounts:" + namespace})
+}
+
+type uidRoundTripper struct {
+	uid string
+	rt  http.RoundTripper
+}
+
+func (u *uidRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set(headerImpersonateUID, u.uid)
+	return u.rt.RoundTrip(req)
+}
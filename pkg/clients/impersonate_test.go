@@ -0,0 +1,74 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/client-go/rest"
+)
+
+func TestImpersonate(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header
+	}))
+	defer srv.Close()
+
+	rc := &rest.Config{
+		Host:        srv.URL,
+		Impersonate: rest.ImpersonationConfig{UserName: "kubeconfig-user"},
+	}
+	Impersonate(rc, "deployer", "1234", []string{"team-a", "team-b"})
+
+	rt, err := rest.TransportFor(rc)
+	if err != nil {
+		t.Fatalf("TransportFor(...): %s", err)
+	}
+	resp, err := (&http.Client{Transport: rt}).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get(...): %s", err)
+	}
+	_ = resp.Body.Close()
+
+	want := map[string][]string{
+		"Impersonate-User":  {"deployer"},
+		"Impersonate-Group": {"team-a", "team-b"},
+		"Impersonate-Uid":   {"1234"},
+	}
+	for k, v := range want {
+		if diff := cmp.Diff(v, got.Values(k)); diff != "" {
+			t.Errorf("Impersonate(...): header %s: -want, +got:\n%s", k, diff)
+		}
+	}
+}
+
+func TestImpersonateServiceAccount(t *testing.T) {
+	rc := &rest.Config{}
+	ImpersonateServiceAccount(rc, "team-a", "deployer")
+
+	want := rest.ImpersonationConfig{
+		UserName: "system:serviceaccount:team-a:deployer",
+		Groups:   []string{"system:serviceaccounts", "system:serviceaccounts:team-a"},
+	}
+	if diff := cmp.Diff(want, rc.Impersonate); diff != "" {
+		t.Errorf("ImpersonateServiceAccount(...): -want, +got:\n%s", diff)
+	}
+}
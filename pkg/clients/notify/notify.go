@@ -0,0 +1,176 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package notify contains a client for POSTing notifications about events of
+// releases to HTTP endpoints such as Slack incoming webhooks.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultTimeout   = 10 * time.Second
+	maxErrorBodySize = 1024
+)
+
+const (
+	errFailedToParseTemplate  = "failed to parse notification template"
+	errFailedToRenderTemplate = "failed to render notification template"
+	errFailedToEncodePayload  = "failed to encode notification"
+	errFailedToBuildRequest   = "failed to build notification request"
+	errFailedToCallEndpoint   = "failed to call notification endpoint"
+	errUnexpectedStatus       = "notification endpoint returned status %d: %s"
+	errFmtUnsupportedSinkType = "unsupported notification sink type %q"
+	errFailedToReadResponse   = "failed to read notification endpoint response"
+)
+
+// Types of sinks.
+const (
+	TypeGeneric = "Generic"
+	TypeSlack   = "Slack"
+)
+
+// A Notification about an event of a release.
+type Notification struct {
+	// Release is the name of the Release.
+	Release string `json:"release"`
+	// ReleaseName is the name of the Helm release.
+	ReleaseName string `json:"releaseName"`
+	// Namespace of the Helm release.
+	Namespace string `json:"namespace"`
+	// Type of the event, Normal or Warning.
+	Type string `json:"type"`
+	// Reason of the event, e.g. UpgradeFailed.
+	Reason string `json:"reason"`
+	// Message of the event.
+	Message string `json:"message"`
+}
+
+// Config of a Sink.
+type Config struct {
+	// Type of the sink, which determines the default payload. Defaults to
+	// TypeGeneric.
+	Type string
+	// URL notifications are POSTed to.
+	URL string
+	// Template of the payload, overriding the default payload of the type.
+	Template string
+	// Headers sent with every notification.
+	Headers map[string]string
+	// Timeout of a notification. Defaults to 10s.
+	Timeout time.Duration
+}
+
+// A Sink POSTs notifications to an HTTP endpoint.
+type Sink struct {
+	typ      string
+	url      string
+	template *template.Template
+	headers  map[string]string
+	client   *http.Client
+}
+
+// NewSink returns a Sink with the supplied config.
+func NewSink(cfg Config) (*Sink, error) {
+	s := &Sink{typ: cfg.Type, url: cfg.URL, headers: cfg.Headers, client: &http.Client{Timeout: cfg.Timeout}}
+	if s.typ == "" {
+		s.typ = TypeGeneric
+	}
+	if s.typ != TypeGeneric && s.typ != TypeSlack {
+		return nil, errors.Errorf(errFmtUnsupportedSinkType, s.typ)
+	}
+	if s.client.Timeout == 0 {
+		s.client.Timeout = defaultTimeout
+	}
+	if cfg.Template != "" {
+		t, err := template.New("notification").Option("missingkey=error").Parse(cfg.Template)
+		if err != nil {
+			return nil, errors.Wrap(err, errFailedToParseTemplate)
+		}
+		s.template = t
+	}
+	return s, nil
+}
+
+// Send POSTs the supplied notification to the endpoint of the sink.
+func (s *Sink) Send(ctx context.Context, n Notification) error {
+	body, err := s.payload(n)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, errFailedToBuildRequest)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, errFailedToCallEndpoint)
+	}
+	defer resp.Body.Close() // nolint:errcheck
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		b, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return errors.Wrap(err, errFailedToReadResponse)
+		}
+		if len(b) > maxErrorBodySize {
+			b = b[:maxErrorBodySize]
+		}
+		return errors.Errorf(errUnexpectedStatus, resp.StatusCode, string(b))
+	}
+	return nil
+}
+
+func (s *Sink) payload(n Notification) ([]byte, error) {
+	if s.template != nil {
+		buf := &bytes.Buffer{}
+		if err := s.template.Execute(buf, n); err != nil {
+			return nil, errors.Wrap(err, errFailedToRenderTemplate)
+		}
+		return buf.Bytes(), nil
+	}
+	var p interface{} = n
+	if s.typ == TypeSlack {
+		p = map[string]string{"text": Text(n)}
+	}
+	b, err := json.Marshal(p)
+	return b, errors.Wrap(err, errFailedToEncodePayload)
+}
+
+// Text returns a human readable text of the supplied notification, e.g.
+// "Release apps-web (release web in namespace apps): UpgradeFailed: ...".
+func Text(n Notification) string {
+	t := fmt.Sprintf("Release %s (release %s in namespace %s): %s", n.Release, n.ReleaseName, n.Namespace, n.Reason)
+	if n.Message != "" {
+		t += ": " + n.Message
+	}
+	return t
+}
@@ -0,0 +1,98 @@
+package notify
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestSend(t *testing.T) {
+	n := Notification{Release: "apps-web", ReleaseName: "web", Namespace: "apps", Type: "Warning", Reason: "UpgradeFailed", Message: "boom"}
+
+	type want struct {
+		body        string
+		contentType string
+		err         error
+	}
+	cases := map[string]struct {
+		cfg    Config
+		status int
+		want   want
+	}{
+		"Generic": {
+			cfg: Config{},
+			want: want{
+				body:        `{"release":"apps-web","releaseName":"web","namespace":"apps","type":"Warning","reason":"UpgradeFailed","message":"boom"}`,
+				contentType: "application/json",
+			},
+		},
+		"Slack": {
+			cfg: Config{Type: TypeSlack},
+			want: want{
+				body:        `{"text":"Release apps-web (release web in namespace apps): UpgradeFailed: boom"}`,
+				contentType: "application/json",
+			},
+		},
+		"Template": {
+			cfg: Config{Template: "{{ .ReleaseName }} {{ .Reason }}", Headers: map[string]string{"Content-Type": "text/plain"}},
+			want: want{
+				body:        "web UpgradeFailed",
+				contentType: "text/plain",
+			},
+		},
+		"ErrorStatus": {
+			cfg:    Config{},
+			status: http.StatusBadRequest,
+			want: want{
+				body:        `{"release":"apps-web","releaseName":"web","namespace":"apps","type":"Warning","reason":"UpgradeFailed","message":"boom"}`,
+				contentType: "application/json",
+				err:         errors.Errorf(errUnexpectedStatus, http.StatusBadRequest, "invalid"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var body, contentType string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := ioutil.ReadAll(r.Body)
+				body, contentType = string(b), r.Header.Get("Content-Type")
+				if tc.status != 0 {
+					w.WriteHeader(tc.status)
+					_, _ = w.Write([]byte("invalid"))
+				}
+			}))
+			defer srv.Close()
+
+			tc.cfg.URL = srv.URL
+			s, err := NewSink(tc.cfg)
+			if err != nil {
+				t.Fatalf("NewSink(...): %s", err)
+			}
+			err = s.Send(context.Background(), n)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Send(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.body, body); diff != "" {
+				t.Errorf("Send(...): -want body, +got body:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.contentType, contentType); diff != "" {
+				t.Errorf("Send(...): -want content type, +got content type:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewSink(t *testing.T) {
+	if _, err := NewSink(Config{Type: "Teams"}); err == nil {
+		t.Errorf("NewSink(...): expected error for unsupported type")
+	}
+	if _, err := NewSink(Config{Template: "{{ .Release "}); err == nil {
+		t.Errorf("NewSink(...): expected error for invalid template")
+	}
+}
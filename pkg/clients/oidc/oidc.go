@@ -0,0 +1,176 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package oidc contains utilities for authenticating to clusters using OAuth
+// 2.0 token exchange, e.g. against an OIDC issuer the clusters trust.
+package oidc
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// DefaultTokenFile is the ServiceAccount token mounted into the
+	// provider pod.
+	DefaultTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+	grantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+	tokenTypeJWT           = "urn:ietf:params:oauth:token-type:jwt"
+	tokenTypeAccessToken   = "urn:ietf:params:oauth:token-type:access_token"
+
+	// Exchanged tokens are replaced this long before they expire, so that
+	// requests in flight never carry an expired token.
+	tokenLeeway = 1 * time.Minute
+
+	// defaultTokenLifetime applies to exchanged tokens that don't state
+	// their lifetime.
+	defaultTokenLifetime = 5 * time.Minute
+
+	exchangeTimeout = 30 * time.Second
+)
+
+const (
+	errReadTokenFile  = "cannot read subject token file"
+	errExchangeToken  = "cannot exchange token"
+	errSTSStatus      = "unexpected status %d from the token endpoint"
+	errSTSError       = "%s: %s"
+	errDecodeResponse = "cannot decode token exchange response"
+	errNoAccessToken  = "token exchange response contains no access token"
+)
+
+// Config of the token exchange.
+type Config struct {
+	// TokenURL of the security token service.
+	TokenURL string
+	// Audience of the exchanged token, e.g. the client ID the target cluster
+	// trusts.
+	Audience string
+	// Scopes of the exchanged token.
+	Scopes []string
+	// ClientID the provider authenticates as, if required by the security
+	// token service.
+	ClientID string
+	// RequestedTokenType of the exchanged token. Defaults to an access token.
+	RequestedTokenType string
+	// TokenFile the subject token is read from. Defaults to the mounted
+	// ServiceAccount token of the provider.
+	TokenFile string
+}
+
+// WrapRESTConfig configures the supplied REST config to use bearer tokens
+// exchanged for the ServiceAccount token of the provider.
+func WrapRESTConfig(rc *rest.Config, cfg Config) error {
+	if cfg.TokenFile == "" {
+		cfg.TokenFile = DefaultTokenFile
+	}
+	if cfg.RequestedTokenType == "" {
+		cfg.RequestedTokenType = tokenTypeAccessToken
+	}
+	ts := &tokenSource{
+		cfg:      cfg,
+		readFile: ioutil.ReadFile,
+		client:   &http.Client{Timeout: exchangeTimeout},
+		now:      time.Now,
+	}
+
+	// ReuseTokenSource caches tokens until they are about to expire.
+	src := oauth2.ReuseTokenSource(nil, ts)
+	rc.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &oauth2.Transport{Source: src, Base: rt}
+	})
+
+	return nil
+}
+
+// A tokenSource exchanges the subject token for a token trusted by the
+// target cluster.
+type tokenSource struct {
+	cfg      Config
+	readFile func(string) ([]byte, error)
+	client   *http.Client
+	now      func() time.Time
+}
+
+// Token returns a newly exchanged token. Its expiry is brought forward by the
+// token leeway so that it is refreshed early.
+func (s *tokenSource) Token() (*oauth2.Token, error) {
+	subject, err := s.readFile(s.cfg.TokenFile)
+	if err != nil {
+		return nil, errors.Wrap(err, errReadTokenFile)
+	}
+
+	form := url.Values{
+		"grant_type":           {grantTypeTokenExchange},
+		"subject_token":        {strings.TrimSpace(string(subject))},
+		"subject_token_type":   {tokenTypeJWT},
+		"requested_token_type": {s.cfg.RequestedTokenType},
+	}
+	if s.cfg.Audience != "" {
+		form.Set("audience", s.cfg.Audience)
+	}
+	if len(s.cfg.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.cfg.Scopes, " "))
+	}
+	if s.cfg.ClientID != "" {
+		form.Set("client_id", s.cfg.ClientID)
+	}
+
+	now := s.now()
+	resp, err := s.client.PostForm(s.cfg.TokenURL, form)
+	if err != nil {
+		return nil, errors.Wrap(err, errExchangeToken)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	var t struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int64  `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	derr := json.NewDecoder(resp.Body).Decode(&t)
+	if resp.StatusCode != http.StatusOK {
+		if derr != nil || t.Error == "" {
+			return nil, errors.Errorf(errSTSStatus, resp.StatusCode)
+		}
+		return nil, errors.Errorf(errSTSError, t.Error, t.ErrorDescription)
+	}
+	if derr != nil {
+		return nil, errors.Wrap(derr, errDecodeResponse)
+	}
+	if t.AccessToken == "" {
+		return nil, errors.New(errNoAccessToken)
+	}
+
+	// Tokens of unknown lifetime are exchanged again periodically.
+	lifetime := defaultTokenLifetime
+	if t.ExpiresIn > 0 {
+		lifetime = time.Duration(t.ExpiresIn)*time.Second - tokenLeeway
+	}
+	if lifetime < 0 {
+		lifetime = 0
+	}
+	return &oauth2.Token{AccessToken: t.AccessToken, Expiry: now.Add(lifetime)}, nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oidc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		f := r.PostForm
+		if f.Get("grant_type") != grantTypeTokenExchange || f.Get("subject_token") != "sa-token" || f.Get("subject_token_type") != tokenTypeJWT {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant","error_description":"bad subject token"}`))
+			return
+		}
+		switch f.Get("audience") {
+		case "lifetime":
+			_, _ = w.Write([]byte(`{"access_token":"exchanged","issued_token_type":"urn:ietf:params:oauth:token-type:access_token","token_type":"N_A","expires_in":600}`))
+		default:
+			_, _ = w.Write([]byte(`{"access_token":"exchanged","token_type":"Bearer"}`))
+		}
+	}))
+	defer srv.Close()
+
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	type want struct {
+		expiry time.Time
+		err    error
+	}
+
+	cases := map[string]struct {
+		cfg     Config
+		subject string
+		want    want
+	}{
+		"Lifetime": {
+			cfg:     Config{TokenURL: srv.URL, Audience: "lifetime"},
+			subject: "sa-token\n",
+			want:    want{expiry: now.Add(10*time.Minute - tokenLeeway)},
+		},
+		"UnknownLifetime": {
+			cfg:     Config{TokenURL: srv.URL, Audience: "cluster"},
+			subject: "sa-token",
+			want:    want{expiry: now.Add(defaultTokenLifetime)},
+		},
+		"InvalidGrant": {
+			cfg:     Config{TokenURL: srv.URL},
+			subject: "wrong",
+			want:    want{err: errors.Errorf(errSTSError, "invalid_grant", "bad subject token")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &tokenSource{
+				cfg:      tc.cfg,
+				readFile: func(string) ([]byte, error) { return []byte(tc.subject), nil },
+				client:   srv.Client(),
+				now:      func() time.Time { return now },
+			}
+			tok, err := s.Token()
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("Token(): -want error, +got error: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff("exchanged", tok.AccessToken); diff != "" {
+				t.Errorf("Token(): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.expiry, tok.Expiry); diff != "" {
+				t.Errorf("Token(): -want expiry, +got expiry:\n%s", diff)
+			}
+		})
+	}
+}
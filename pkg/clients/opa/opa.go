@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package opa contains a client for evaluating Rego policies using the REST
+// API of an Open Policy Agent server.
+package opa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultTimeout   = 30 * time.Second
+	maxErrorBodySize = 1024
+)
+
+const (
+	errFailedToBuildRequest   = "failed to build policy server request"
+	errFailedToCallServer     = "failed to call policy server"
+	errFailedToReadResponse   = "failed to read policy server response"
+	errUnexpectedStatus       = "policy server returned status %d: %s"
+	errFailedToEncodeInput    = "failed to encode policy input"
+	errFailedToDecodeResponse = "failed to decode policy server response"
+)
+
+// A Client evaluates Rego policies using an Open Policy Agent server.
+type Client struct {
+	url    string
+	client *http.Client
+}
+
+// NewClient returns a Client for the Open Policy Agent server at the supplied
+// base URL.
+func NewClient(url string) *Client {
+	return &Client{
+		url:    strings.TrimSuffix(url, "/"),
+		client: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// Evaluate evaluates the rule at the supplied path, e.g. "helm/deny", against
+// the supplied input and returns the violations it reports. The rule is
+// expected to produce a set or list of messages; an undefined rule reports no
+// violations.
+func (c *Client) Evaluate(ctx context.Context, path string, input interface{}) ([]string, error) {
+	b, err := json.Marshal(map[string]interface{}{"input": input})
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToEncodeInput)
+	}
+	body, err := c.do(ctx, http.MethodPost, "/v1/data/"+strings.Trim(path, "/"), "application/json", b)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := struct {
+		Result []json.RawMessage `json:"result"`
+	}{}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, errors.Wrap(err, errFailedToDecodeResponse)
+	}
+
+	violations := make([]string, 0, len(resp.Result))
+	for _, r := range resp.Result {
+		var s string
+		if err := json.Unmarshal(r, &s); err != nil {
+			// Not a plain message, report the violation as is.
+			s = string(r)
+		}
+		violations = append(violations, s)
+	}
+	return violations, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path, contentType string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToBuildRequest)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToCallServer)
+	}
+	defer resp.Body.Close() // nolint:errcheck
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToReadResponse)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if len(b) > maxErrorBodySize {
+			b = b[:maxErrorBodySize]
+		}
+		return nil, errors.Errorf(errUnexpectedStatus, resp.StatusCode, string(b))
+	}
+	return b, nil
+}
@@ -0,0 +1,74 @@
+package opa
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestEvaluate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/data/helm/deny":
+			in := map[string]map[string]string{}
+			_ = json.NewDecoder(r.Body).Decode(&in)
+			if in["input"]["kind"] == "Pod" {
+				_, _ = w.Write([]byte(`{"result": ["pods are not allowed", {"msg": "structured"}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"result": []}`))
+		case "/v1/data/helm/undefined":
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("not found"))
+		}
+	}))
+	defer srv.Close()
+
+	type want struct {
+		violations []string
+		err        error
+	}
+	cases := map[string]struct {
+		path  string
+		input interface{}
+		want  want
+	}{
+		"Violations": {
+			path:  "helm/deny",
+			input: map[string]string{"kind": "Pod"},
+			want:  want{violations: []string{"pods are not allowed", `{"msg": "structured"}`}},
+		},
+		"NoViolations": {
+			path:  "/helm/deny/",
+			input: map[string]string{"kind": "ConfigMap"},
+			want:  want{violations: []string{}},
+		},
+		"Undefined": {
+			path: "helm/undefined",
+			want: want{violations: []string{}},
+		},
+		"UnexpectedStatus": {
+			path: "other",
+			want: want{err: errors.Errorf(errUnexpectedStatus, http.StatusNotFound, "not found")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewClient(srv.URL+"/").Evaluate(context.Background(), tc.path, tc.input)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("Evaluate(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.violations, got); diff != "" {
+				t.Errorf("Evaluate(...): -want violations, +got violations: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+)
+
+// discoveryBurst is the burst of the discovery client. Discovery takes two
+// requests per API group, and clusters with many CRDs have many groups.
+const discoveryBurst = 100
+
+// minInvalidateInterval is how often a LazyRESTMapper rediscovers the APIs of
+// a cluster at most because a kind or resource could not be mapped.
+const minInvalidateInterval = 10 * time.Second
+
+// A LazyRESTMapper maps the kinds and resources of a target cluster using
+// discovery information that is fetched on first use and cached. The cache
+// is only invalidated when a kind or resource cannot be mapped, e.g. because
+// its CRD was installed since, at most once per minInvalidateInterval, or
+// explicitly by Invalidate. It may be shared by all clients of a cluster.
+type LazyRESTMapper struct {
+	discovery discovery.CachedDiscoveryInterface
+	mapper    *restmapper.DeferredDiscoveryRESTMapper
+
+	now         func() time.Time
+	mu          sync.Mutex
+	invalidated time.Time
+}
+
+// NewLazyRESTMapper returns a LazyRESTMapper of the cluster of the supplied
+// REST config. Nothing is discovered until the first mapping.
+func NewLazyRESTMapper(config *rest.Config) (*LazyRESTMapper, error) {
+	config = rest.CopyConfig(config)
+	config.Burst = discoveryBurst
+	dc, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create discovery client")
+	}
+	return NewLazyRESTMapperForDiscovery(memory.NewMemCacheClient(dc)), nil
+}
+
+// NewLazyRESTMapperForDiscovery returns a LazyRESTMapper that uses the
+// supplied discovery client.
+func NewLazyRESTMapperForDiscovery(dc discovery.CachedDiscoveryInterface) *LazyRESTMapper {
+	return &LazyRESTMapper{
+		discovery: dc,
+		mapper:    restmapper.NewDeferredDiscoveryRESTMapper(dc),
+		now:       time.Now,
+	}
+}
+
+// Discovery returns the cached discovery client of the mapper.
+func (m *LazyRESTMapper) Discovery() discovery.CachedDiscoveryInterface {
+	return m.discovery
+}
+
+// Invalidate the discovery information, e.g. because CRDs were installed.
+func (m *LazyRESTMapper) Invalidate() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.invalidate()
+}
+
+func (m *LazyRESTMapper) invalidate() {
+	m.discovery.Invalidate()
+	m.mapper.Reset()
+	m.invalidated = m.now()
+}
+
+// retry returns whether a mapping that failed with the supplied error should
+// be retried, invalidating the discovery information if so.
+func (m *LazyRESTMapper) retry(err error) bool {
+	if !meta.IsNoMatchError(err) {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.now().Sub(m.invalidated) < minInvalidateInterval {
+		return false
+	}
+	m.invalidate()
+	return true
+}
+
+// KindFor takes a partial resource and returns the single match.
+func (m *LazyRESTMapper) KindFor(resource schema.GroupVersionResource) (schema.GroupVersionKind, error) {
+	gvk, err := m.mapper.KindFor(resource)
+	if m.retry(err) {
+		gvk, err = m.mapper.KindFor(resource)
+	}
+	return gvk, err
+}
+
+// KindsFor takes a partial resource and returns the list of potential kinds
+// in priority order.
+func (m *LazyRESTMapper) KindsFor(resource schema.GroupVersionResource) ([]schema.GroupVersionKind, error) {
+	gvks, err := m.mapper.KindsFor(resource)
+	if m.retry(err) {
+		gvks, err = m.mapper.KindsFor(resource)
+	}
+	return gvks, err
+}
+
+// ResourceFor takes a partial resource and returns the single match.
+func (m *LazyRESTMapper) ResourceFor(input schema.GroupVersionResource) (schema.GroupVersionResource, error) {
+	gvr, err := m.mapper.ResourceFor(input)
+	if m.retry(err) {
+		gvr, err = m.mapper.ResourceFor(input)
+	}
+	return gvr, err
+}
+
+// ResourcesFor takes a partial resource and returns the list of potential
+// resources in priority order.
+func (m *LazyRESTMapper) ResourcesFor(input schema.GroupVersionResource) ([]schema.GroupVersionResource, error) {
+	gvrs, err := m.mapper.ResourcesFor(input)
+	if m.retry(err) {
+		gvrs, err = m.mapper.ResourcesFor(input)
+	}
+	return gvrs, err
+}
+
+// RESTMapping identifies a preferred resource mapping for the supplied group
+// kind.
+func (m *LazyRESTMapper) RESTMapping(gk schema.GroupKind, versions ...string) (*meta.RESTMapping, error) {
+	rm, err := m.mapper.RESTMapping(gk, versions...)
+	if m.retry(err) {
+		rm, err = m.mapper.RESTMapping(gk, versions...)
+	}
+	return rm, err
+}
+
+// RESTMappings returns all resource mappings for the supplied group kind.
+func (m *LazyRESTMapper) RESTMappings(gk schema.GroupKind, versions ...string) ([]*meta.RESTMapping, error) {
+	rms, err := m.mapper.RESTMappings(gk, versions...)
+	if m.retry(err) {
+		rms, err = m.mapper.RESTMappings(gk, versions...)
+	}
+	return rms, err
+}
+
+// ResourceSingularizer converts a resource name from plural to singular.
+func (m *LazyRESTMapper) ResourceSingularizer(resource string) (string, error) {
+	return m.mapper.ResourceSingularizer(resource)
+}
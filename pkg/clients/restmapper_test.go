@@ -0,0 +1,109 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery/cached/memory"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestLazyRESTMapper(t *testing.T) {
+	fd := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{Resources: []*metav1.APIResourceList{
+		{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{{Name: "deployments", Kind: "Deployment", Namespaced: true}}},
+	}}}
+	// install adds a CRD of its own group, as the fake discovery client only
+	// returns the first resource list of a group version.
+	install := func(kind, resource string) func() {
+		return func() {
+			fd.Resources = append(fd.Resources, &metav1.APIResourceList{
+				GroupVersion: resource + ".example.org/v1",
+				APIResources: []metav1.APIResource{{Name: resource, Kind: kind, Namespaced: true}},
+			})
+		}
+	}
+
+	now := time.Now()
+	m := NewLazyRESTMapperForDiscovery(memory.NewMemCacheClient(fd))
+	m.now = func() time.Time { return now }
+
+	type want struct {
+		resource string
+		noMatch  bool
+	}
+	for _, step := range []struct {
+		name  string
+		setup func()
+		kind  schema.GroupKind
+		want  want
+	}{
+		{
+			name: "Discovered",
+			kind: schema.GroupKind{Group: "apps", Kind: "Deployment"},
+			want: want{resource: "deployments"},
+		},
+		{
+			name:  "InstalledSinceDiscovery",
+			setup: install("CronTab", "crontabs"),
+			kind:  schema.GroupKind{Group: "crontabs.example.org", Kind: "CronTab"},
+			want:  want{resource: "crontabs"},
+		},
+		{
+			name:  "RecentlyRediscovered",
+			setup: install("Widget", "widgets"),
+			kind:  schema.GroupKind{Group: "widgets.example.org", Kind: "Widget"},
+			want:  want{noMatch: true},
+		},
+		{
+			name:  "RediscoveredAgain",
+			setup: func() { now = now.Add(minInvalidateInterval) },
+			kind:  schema.GroupKind{Group: "widgets.example.org", Kind: "Widget"},
+			want:  want{resource: "widgets"},
+		},
+		{
+			name:  "Invalidated",
+			setup: func() { install("Gadget", "gadgets")(); m.Invalidate() },
+			kind:  schema.GroupKind{Group: "gadgets.example.org", Kind: "Gadget"},
+			want:  want{resource: "gadgets"},
+		},
+		{
+			name: "Unknown",
+			kind: schema.GroupKind{Group: "example.org", Kind: "Gizmo"},
+			want: want{noMatch: true},
+		},
+	} {
+		if step.setup != nil {
+			step.setup()
+		}
+		got := want{}
+		rm, err := m.RESTMapping(step.kind)
+		if err == nil {
+			got.resource = rm.Resource.Resource
+		}
+		got.noMatch = meta.IsNoMatchError(err)
+		if diff := cmp.Diff(step.want, got, cmp.AllowUnexported(want{})); diff != "" {
+			t.Errorf("%s: RESTMapping(...): -want, +got: %s (%v)", step.name, diff, err)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package valuesource contains a client of value source plugins, which let
+// operators read values from proprietary sources such as internal
+// configuration services without changing the provider. Plugins serve the
+// gRPC service described in valuesource.proto on a unix socket.
+package valuesource
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative valuesource.proto
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const callTimeout = 30 * time.Second
+
+const (
+	errFmtUnknownPlugin = "value source plugin %q is not registered"
+	errFmtDialPlugin    = "cannot dial value source plugin %q"
+	errEncodeConfig     = "cannot encode config of value source"
+	errGetValues        = "value source plugin failed to get values"
+)
+
+// A Request of the values of a source.
+type Request struct {
+	// Config of the source, as set on the Release.
+	Config map[string]interface{}
+	// Key of the requested data, e.g. values.yaml for a values document or
+	// value for a single value.
+	Key string
+}
+
+// A Client of a value source plugin.
+type Client struct {
+	conn   *grpc.ClientConn
+	client ValueSourceServiceClient
+}
+
+// Dial returns a Client of the plugin serving the supplied unix socket. The
+// connection is established lazily, so the plugin need not be running yet.
+func Dial(socket string) (*Client, error) {
+	conn, err := grpc.Dial("unix://"+socket, grpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn, client: NewValueSourceServiceClient(conn)}, nil
+}
+
+// GetValues returns the data described by the supplied request. The boolean
+// is false if the plugin reports that the data does not exist.
+func (c *Client) GetValues(ctx context.Context, r Request) (string, bool, error) {
+	cfg, err := structpb.NewStruct(r.Config)
+	if err != nil {
+		return "", false, errors.Wrap(err, errEncodeConfig)
+	}
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+
+	rsp, err := c.client.GetValues(ctx, &GetValuesRequest{Config: cfg, Key: r.Key})
+	if status.Code(err) == codes.NotFound {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, errors.Wrap(err, errGetValues)
+	}
+	return rsp.GetData(), true, nil
+}
+
+// Close the connection to the plugin.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Plugins are the clients of value source plugins by name.
+type Plugins map[string]*Client
+
+// DialPlugins returns the clients of the plugins serving the supplied unix
+// sockets by name.
+func DialPlugins(sockets map[string]string) (Plugins, error) {
+	p := make(Plugins, len(sockets))
+	for name, socket := range sockets {
+		c, err := Dial(socket)
+		if err != nil {
+			p.Close()
+			return nil, errors.Wrapf(err, errFmtDialPlugin, name)
+		}
+		p[name] = c
+	}
+	return p, nil
+}
+
+// GetValues returns the data described by the supplied request from the
+// plugin of the supplied name. The boolean is false if the plugin reports
+// that the data does not exist.
+func (p Plugins) GetValues(ctx context.Context, plugin string, r Request) (string, bool, error) {
+	c, ok := p[plugin]
+	if !ok {
+		return "", false, errors.Errorf(errFmtUnknownPlugin, plugin)
+	}
+	return c.GetValues(ctx, r)
+}
+
+// Close the connections to all plugins.
+func (p Plugins) Close() {
+	for _, c := range p {
+		_ = c.Close()
+	}
+}
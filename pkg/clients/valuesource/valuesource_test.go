@@ -0,0 +1,105 @@
+package valuesource
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+// A fakeServer returns the values of the path of its config.
+type fakeServer struct {
+	UnimplementedValueSourceServiceServer
+	values map[string]string
+}
+
+func (s *fakeServer) GetValues(_ context.Context, req *GetValuesRequest) (*GetValuesResponse, error) {
+	if k := req.GetKey(); k != "values.yaml" {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported key %s", k)
+	}
+	path := req.GetConfig().GetFields()["path"].GetStringValue()
+	v, ok := s.values[path]
+	if !ok {
+		return nil, status.Error(codes.NotFound, path)
+	}
+	return &GetValuesResponse{Data: v}, nil
+}
+
+func TestGetValues(t *testing.T) {
+	dir, err := ioutil.TempDir("", "valuesource")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // nolint:errcheck
+
+	socket := filepath.Join(dir, "cmdb.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	RegisterValueSourceServiceServer(srv, &fakeServer{values: map[string]string{"apps/web": "replicas: 2\n"}})
+	go srv.Serve(l) // nolint:errcheck
+	defer srv.Stop()
+
+	p, err := DialPlugins(map[string]string{"cmdb": socket})
+	if err != nil {
+		t.Fatalf("DialPlugins(...): %s", err)
+	}
+	defer p.Close()
+
+	type want struct {
+		data  string
+		found bool
+		err   error
+	}
+	cases := map[string]struct {
+		plugin string
+		req    Request
+		want   want
+	}{
+		"Found": {
+			plugin: "cmdb",
+			req:    Request{Config: map[string]interface{}{"path": "apps/web"}, Key: "values.yaml"},
+			want:   want{data: "replicas: 2\n", found: true},
+		},
+		"NotFound": {
+			plugin: "cmdb",
+			req:    Request{Config: map[string]interface{}{"path": "apps/api"}, Key: "values.yaml"},
+			want:   want{},
+		},
+		"UnknownPlugin": {
+			plugin: "vault",
+			want:   want{err: errors.Errorf(errFmtUnknownPlugin, "vault")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			data, found, err := p.GetValues(context.Background(), tc.plugin, tc.req)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("GetValues(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.data, data); diff != "" {
+				t.Errorf("GetValues(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.found, found); diff != "" {
+				t.Errorf("GetValues(...): -want found, +got found:\n%s", diff)
+			}
+		})
+	}
+
+	_, _, err = p.GetValues(context.Background(), "cmdb", Request{Key: "value"})
+	if status.Code(errors.Cause(err)) != codes.InvalidArgument {
+		t.Errorf("GetValues(...): want InvalidArgument error of the plugin, got %v", err)
+	}
+}
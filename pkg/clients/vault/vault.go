@@ -0,0 +1,234 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package vault contains a client for writing secrets to the KV secrets
+// engine of a HashiCorp Vault server. It is a minimal client of the Vault HTTP
+// API; StoreConfigs of type Plugin publish through the Vault plugin of
+// Crossplane instead.
+package vault
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultTimeout   = 30 * time.Second
+	maxErrorBodySize = 1024
+
+	// v1MetadataKey is the key of the data of KV v1 secrets that their
+	// metadata is stored at as JSON, since KV v1 engines have no metadata.
+	v1MetadataKey = "_metadata"
+)
+
+const (
+	errInvalidCABundle        = "failed to parse CA bundle of vault server"
+	errFailedToBuildRequest   = "failed to build vault request"
+	errFailedToCallServer     = "failed to call vault server"
+	errFailedToReadResponse   = "failed to read vault response"
+	errUnexpectedStatus       = "vault returned status %d: %s"
+	errFailedToEncodeData     = "failed to encode secret data"
+	errFailedToDecodeResponse = "failed to decode vault response"
+	errFailedToDecodeMetadata = "failed to decode secret metadata"
+)
+
+// A KVVersion is a version of the KV secrets engine.
+type KVVersion string
+
+// Versions of the KV secrets engine.
+const (
+	KVVersionV1 KVVersion = "v1"
+	KVVersionV2 KVVersion = "v2"
+)
+
+// A Client writes secrets to a KV secrets engine of a Vault server.
+type Client struct {
+	url     string
+	mount   string
+	version KVVersion
+	token   string
+	client  *http.Client
+}
+
+// A Secret of a KV secrets engine.
+type Secret struct {
+	// Data of the secret.
+	Data map[string]string
+	// Metadata of the secret, e.g. the UID of its owner. It is the custom
+	// metadata of KV v2 secrets.
+	Metadata map[string]string
+}
+
+// NewClient returns a Client for the KV secrets engine mounted at the
+// supplied path of the Vault server at the supplied base URL. Requests are
+// authenticated with the supplied token. The server certificate is verified
+// with the supplied CA bundle, if any.
+func NewClient(url, mountPath string, version KVVersion, token string, caBundle []byte) (*Client, error) {
+	hc := &http.Client{Timeout: defaultTimeout}
+	if len(caBundle) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caBundle) {
+			return nil, errors.New(errInvalidCABundle)
+		}
+		hc.Transport = &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+		}
+	}
+	if version == "" {
+		version = KVVersionV2
+	}
+	return &Client{
+		url:     strings.TrimSuffix(url, "/"),
+		mount:   strings.Trim(mountPath, "/"),
+		version: version,
+		token:   token,
+		client:  hc,
+	}, nil
+}
+
+// Read returns the secret at the supplied path, or nil if it does not exist.
+func (c *Client) Read(ctx context.Context, path string) (*Secret, error) {
+	b, err := c.do(ctx, http.MethodGet, c.path("data", path), nil)
+	if err != nil || b == nil {
+		return nil, err
+	}
+	resp := struct {
+		Data json.RawMessage `json:"data"`
+	}{}
+	if err := json.Unmarshal(b, &resp); err != nil {
+		return nil, errors.Wrap(err, errFailedToDecodeResponse)
+	}
+	if c.version == KVVersionV2 {
+		v2 := struct {
+			Data     map[string]string `json:"data"`
+			Metadata struct {
+				CustomMetadata map[string]string `json:"custom_metadata"`
+			} `json:"metadata"`
+		}{}
+		if err := json.Unmarshal(resp.Data, &v2); err != nil {
+			return nil, errors.Wrap(err, errFailedToDecodeResponse)
+		}
+		return &Secret{Data: v2.Data, Metadata: v2.Metadata.CustomMetadata}, nil
+	}
+	s := &Secret{Data: map[string]string{}}
+	if err := json.Unmarshal(resp.Data, &s.Data); err != nil {
+		return nil, errors.Wrap(err, errFailedToDecodeResponse)
+	}
+	if md, ok := s.Data[v1MetadataKey]; ok {
+		delete(s.Data, v1MetadataKey)
+		if err := json.Unmarshal([]byte(md), &s.Metadata); err != nil {
+			return nil, errors.Wrap(err, errFailedToDecodeMetadata)
+		}
+	}
+	return s, nil
+}
+
+// Write creates or replaces the secret at the supplied path. The metadata of
+// KV v2 secrets is written before their data, so that no data is written
+// without it.
+func (c *Client) Write(ctx context.Context, path string, s *Secret) error {
+	var body interface{} = s.Data
+	switch {
+	case c.version == KVVersionV2:
+		if len(s.Metadata) > 0 {
+			b, err := json.Marshal(map[string]interface{}{"custom_metadata": s.Metadata})
+			if err != nil {
+				return errors.Wrap(err, errFailedToEncodeData)
+			}
+			if _, err := c.do(ctx, http.MethodPost, c.path("metadata", path), b); err != nil {
+				return err
+			}
+		}
+		body = map[string]interface{}{"data": s.Data}
+	case len(s.Metadata) > 0:
+		md, err := json.Marshal(s.Metadata)
+		if err != nil {
+			return errors.Wrap(err, errFailedToEncodeData)
+		}
+		data := make(map[string]string, len(s.Data)+1)
+		for k, v := range s.Data {
+			data[k] = v
+		}
+		data[v1MetadataKey] = string(md)
+		body = data
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return errors.Wrap(err, errFailedToEncodeData)
+	}
+	_, err = c.do(ctx, http.MethodPut, c.path("data", path), b)
+	return err
+}
+
+// Delete deletes the secret at the supplied path, including all of its
+// versions. Deleting a secret that does not exist is not an error.
+func (c *Client) Delete(ctx context.Context, path string) error {
+	_, err := c.do(ctx, http.MethodDelete, c.path("metadata", path), nil)
+	return err
+}
+
+// path returns the API path of the supplied secret path. KV v2 paths are
+// prefixed with the supplied operation, e.g. data or metadata.
+func (c *Client) path(op, path string) string {
+	p := "/v1/" + c.mount + "/"
+	if c.version == KVVersionV2 {
+		p += op + "/"
+	}
+	return p + strings.Trim(path, "/")
+}
+
+// do calls the supplied API path of the Vault server and returns the body of
+// its response. A nil body is returned if the secret to read or delete does
+// not exist.
+func (c *Client) do(ctx context.Context, method, path string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToBuildRequest)
+	}
+	req.Header.Set("X-Vault-Token", c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToCallServer)
+	}
+	defer resp.Body.Close() // nolint:errcheck
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToReadResponse)
+	}
+	if resp.StatusCode == http.StatusNotFound && (method == http.MethodGet || method == http.MethodDelete) {
+		return nil, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if len(b) > maxErrorBodySize {
+			b = b[:maxErrorBodySize]
+		}
+		return nil, errors.Errorf(errUnexpectedStatus, resp.StatusCode, string(b))
+	}
+	return b, nil
+}
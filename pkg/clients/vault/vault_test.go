@@ -0,0 +1,222 @@
+package vault
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+type request struct {
+	method string
+	path   string
+	token  string
+	body   string
+}
+
+func TestClient(t *testing.T) {
+	var got []request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		got = append(got, request{method: r.Method, path: r.URL.Path, token: r.Header.Get("X-Vault-Token"), body: string(b)})
+		switch r.URL.Path {
+		case "/v1/secret/data/denied", "/v1/kv/denied":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte("permission denied"))
+		case "/v1/secret/metadata/missing":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	data := &Secret{Data: map[string]string{"password": "12345"}}
+	owned := &Secret{Data: map[string]string{"password": "12345"}, Metadata: map[string]string{"owner": "uid"}}
+
+	type want struct {
+		req []request
+		err error
+	}
+	cases := map[string]struct {
+		reason  string
+		mount   string
+		version KVVersion
+		op      func(c *Client) error
+		want    want
+	}{
+		"WriteV2": {
+			reason: "Secrets should be written to the data path of KV v2 engines.",
+			mount:  "/secret/",
+			op:     func(c *Client) error { return c.Write(context.Background(), "crossplane/db", data) },
+			want: want{req: []request{{
+				method: http.MethodPut,
+				path:   "/v1/secret/data/crossplane/db",
+				token:  "s.token",
+				body:   `{"data":{"password":"12345"}}`,
+			}}},
+		},
+		"WriteV1": {
+			reason:  "Secrets should be written to the path of KV v1 engines as is.",
+			mount:   "kv",
+			version: KVVersionV1,
+			op:      func(c *Client) error { return c.Write(context.Background(), "/crossplane/db", data) },
+			want: want{req: []request{{
+				method: http.MethodPut,
+				path:   "/v1/kv/crossplane/db",
+				token:  "s.token",
+				body:   `{"password":"12345"}`,
+			}}},
+		},
+		"WriteV2Metadata": {
+			reason: "The custom metadata of KV v2 secrets should be written before their data.",
+			mount:  "secret",
+			op:     func(c *Client) error { return c.Write(context.Background(), "crossplane/db", owned) },
+			want: want{req: []request{
+				{
+					method: http.MethodPost,
+					path:   "/v1/secret/metadata/crossplane/db",
+					token:  "s.token",
+					body:   `{"custom_metadata":{"owner":"uid"}}`,
+				},
+				{
+					method: http.MethodPut,
+					path:   "/v1/secret/data/crossplane/db",
+					token:  "s.token",
+					body:   `{"data":{"password":"12345"}}`,
+				},
+			}},
+		},
+		"WriteV1Metadata": {
+			reason:  "The metadata of KV v1 secrets should be written to a reserved key of their data.",
+			mount:   "kv",
+			version: KVVersionV1,
+			op:      func(c *Client) error { return c.Write(context.Background(), "crossplane/db", owned) },
+			want: want{req: []request{{
+				method: http.MethodPut,
+				path:   "/v1/kv/crossplane/db",
+				token:  "s.token",
+				body:   `{"_metadata":"{\"owner\":\"uid\"}","password":"12345"}`,
+			}}},
+		},
+		"DeleteV2": {
+			reason: "All versions of secrets of KV v2 engines should be deleted.",
+			mount:  "secret",
+			op:     func(c *Client) error { return c.Delete(context.Background(), "crossplane/db") },
+			want: want{req: []request{{
+				method: http.MethodDelete,
+				path:   "/v1/secret/metadata/crossplane/db",
+				token:  "s.token",
+			}}},
+		},
+		"DeleteMissing": {
+			reason: "Deleting a secret that does not exist should not be an error.",
+			mount:  "secret",
+			op:     func(c *Client) error { return c.Delete(context.Background(), "missing") },
+			want: want{req: []request{{
+				method: http.MethodDelete,
+				path:   "/v1/secret/metadata/missing",
+				token:  "s.token",
+			}}},
+		},
+		"UnexpectedStatus": {
+			reason: "Errors returned by Vault should be returned.",
+			mount:  "secret",
+			op:     func(c *Client) error { return c.Write(context.Background(), "denied", data) },
+			want: want{
+				req: []request{{
+					method: http.MethodPut,
+					path:   "/v1/secret/data/denied",
+					token:  "s.token",
+					body:   `{"data":{"password":"12345"}}`,
+				}},
+				err: errors.Errorf(errUnexpectedStatus, http.StatusForbidden, "permission denied"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got = nil
+			c, err := NewClient(srv.URL+"/", tc.mount, tc.version, "s.token", nil)
+			if err != nil {
+				t.Fatalf("NewClient(...): %v", err)
+			}
+			err = tc.op(c)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\n-want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.req, got, cmp.AllowUnexported(request{})); diff != "" {
+				t.Errorf("\n%s\n-want request, +got request:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNewClientInvalidCABundle(t *testing.T) {
+	_, err := NewClient("https://vault", "secret", KVVersionV2, "s.token", []byte("not a certificate"))
+	if diff := cmp.Diff(errors.New(errInvalidCABundle), err, test.EquateErrors()); diff != "" {
+		t.Errorf("NewClient(...): -want error, +got error:\n%s", diff)
+	}
+}
+
+func TestRead(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/secret/data/existing":
+			_, _ = w.Write([]byte(`{"data":{"data":{"password":"12345"},"metadata":{"version":1,"custom_metadata":{"owner":"uid"}}}}`))
+		case "/v1/kv/existing":
+			_, _ = w.Write([]byte(`{"data":{"password":"12345","_metadata":"{\"owner\":\"uid\"}"}}`))
+		case "/v1/kv/plain":
+			_, _ = w.Write([]byte(`{"data":{"password":"12345"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	cases := map[string]struct {
+		mount   string
+		version KVVersion
+		path    string
+		want    *Secret
+	}{
+		"V2": {
+			mount: "secret",
+			path:  "existing",
+			want:  &Secret{Data: map[string]string{"password": "12345"}, Metadata: map[string]string{"owner": "uid"}},
+		},
+		"V1": {
+			mount:   "kv",
+			version: KVVersionV1,
+			path:    "existing",
+			want:    &Secret{Data: map[string]string{"password": "12345"}, Metadata: map[string]string{"owner": "uid"}},
+		},
+		"V1WithoutMetadata": {
+			mount:   "kv",
+			version: KVVersionV1,
+			path:    "plain",
+			want:    &Secret{Data: map[string]string{"password": "12345"}},
+		},
+		"Missing": {
+			mount: "secret",
+			path:  "missing",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, _ := NewClient(srv.URL, tc.mount, tc.version, "s.token", nil)
+			got, err := c.Read(context.Background(), tc.path)
+			if err != nil {
+				t.Fatalf("Read(...): %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Read(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -17,24 +17,48 @@ limitations under the License.
 package controller
 
 import (
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
 
 	"github.com/crossplane-contrib/provider-helm/pkg/controller/config"
 	"github.com/crossplane-contrib/provider-helm/pkg/controller/release"
+	"github.com/crossplane-contrib/provider-helm/pkg/controller/releaseset"
 
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 )
 
+// Options of the Helm controllers.
+type Options struct {
+	// Release configures the Release controller.
+	Release release.Options
+	// ReleaseSet configures the workers and rate limiting of the ReleaseSet
+	// controller.
+	ReleaseSet controller.Options
+	// ProviderConfig configures the workers and rate limiting of the
+	// ProviderConfig controller.
+	ProviderConfig controller.Options
+}
+
+// NewRateLimiter returns a rate limiter of a controller that requeues
+// failing resources with an exponential backoff between the supplied base
+// and max delays, and all resources no faster than the supplied rate limiter
+// of the provider allows, which may be shared by all controllers.
+func NewRateLimiter(provider ratelimiter.RateLimiter, base, max time.Duration) ratelimiter.RateLimiter {
+	return workqueue.NewMaxOfRateLimiter(workqueue.NewItemExponentialFailureRateLimiter(base, max), provider)
+}
+
 // Setup creates all Helm controllers with the supplied logger and adds them
-// to the supplied manager.
-func Setup(mgr ctrl.Manager, l logging.Logger) error {
-	for _, setup := range []func(ctrl.Manager, logging.Logger) error{
-		config.Setup,
-		release.Setup,
-	} {
-		if err := setup(mgr, l); err != nil {
-			return err
-		}
+// to the supplied manager, configured with the supplied options.
+func Setup(mgr ctrl.Manager, l logging.Logger, o Options) error {
+	if err := config.Setup(mgr, l, o.ProviderConfig); err != nil {
+		return err
+	}
+	if err := releaseset.Setup(mgr, l, o.ReleaseSet); err != nil {
+		return err
 	}
-	return nil
+	return release.Setup(mgr, l, o.Release)
 }
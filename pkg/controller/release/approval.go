@@ -0,0 +1,73 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	ktype "sigs.k8s.io/kustomize/api/types"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	errFailedToDigest  = "failed to compute diff digest"
+	errPendingApproval = "upgrade waits for approval of diff %s"
+)
+
+// approved returns a deployAction that runs the supplied action only if the
+// upgrade is approved. Otherwise the proposed upgrade is reported in the
+// PendingApproval condition.
+func (e *helmExternal) approved(cr *v1beta1.Release, action deployAction) deployAction {
+	return func(rel string, ch *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (*release.Release, error) {
+		d, err := diffDigest(ch, vals, patches)
+		if err != nil {
+			return nil, errors.Wrap(err, errFailedToDigest)
+		}
+		if d != cr.Spec.ForProvider.ApprovedDiff && d != cr.GetAnnotations()[v1beta1.AnnotationKeyApprovedDiff] {
+			cr.Status.SetConditions(v1beta1.AwaitingApproval(cr.Status.AtProvider.Revision+1, d))
+			return nil, errors.Errorf(errPendingApproval, d)
+		}
+		cr.Status.SetConditions(v1beta1.Approved(d))
+		return action(rel, ch, vals, patches)
+	}
+}
+
+// diffDigest returns a digest of the chart, values and patches of an
+// upgrade. Unlike the rendered manifests, these don't change between renders
+// of charts that generate random values.
+func diffDigest(ch *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (string, error) {
+	in := struct {
+		Chart   string                 `json:"chart"`
+		Version string                 `json:"version"`
+		Values  map[string]interface{} `json:"values"`
+		Patches []ktype.Patch          `json:"patches"`
+	}{Values: vals, Patches: patches}
+	if ch != nil && ch.Metadata != nil {
+		in.Chart, in.Version = ch.Metadata.Name, ch.Metadata.Version
+	}
+	b, err := json.Marshal(in)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(b)), nil
+}
@@ -0,0 +1,77 @@
+package release
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/kustomize/api/types"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+func Test_approved(t *testing.T) {
+	ch := &chart.Chart{Metadata: &chart.Metadata{Name: testChart, Version: testVersion}}
+	vals := map[string]interface{}{"replicas": 2}
+	digest, err := diffDigest(ch, vals, nil)
+	if err != nil {
+		t.Fatalf("diffDigest(...): %s", err)
+	}
+
+	type want struct {
+		err    error
+		status corev1.ConditionStatus
+		called bool
+	}
+	cases := map[string]struct {
+		approvedDiff string
+		annotation   string
+		want         want
+	}{
+		"NotApproved": {
+			want: want{err: errors.Errorf(errPendingApproval, digest), status: corev1.ConditionTrue},
+		},
+		"OtherDiffApproved": {
+			approvedDiff: "sha256:other",
+			want:         want{err: errors.Errorf(errPendingApproval, digest), status: corev1.ConditionTrue},
+		},
+		"Approved": {
+			approvedDiff: digest,
+			want:         want{status: corev1.ConditionFalse, called: true},
+		},
+		"ApprovedByAnnotation": {
+			annotation: digest,
+			want:       want{status: corev1.ConditionFalse, called: true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := helmRelease(func(r *v1beta1.Release) {
+				r.Spec.ForProvider.ApprovedDiff = tc.approvedDiff
+				if tc.annotation != "" {
+					r.SetAnnotations(map[string]string{v1beta1.AnnotationKeyApprovedDiff: tc.annotation})
+				}
+			})
+			called := false
+			e := &helmExternal{}
+			action := e.approved(cr, func(string, *chart.Chart, map[string]interface{}, []types.Patch) (*release.Release, error) {
+				called = true
+				return &release.Release{}, nil
+			})
+			_, err := action(testReleaseName, ch, vals, nil)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("approved(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.called, called); diff != "" {
+				t.Errorf("approved(...): -want called, +got called: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.status, cr.Status.GetCondition(v1beta1.TypePendingApproval).Status); diff != "" {
+				t.Errorf("approved(...): -want status, +got status: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	argoCDDefaultInstanceLabel     = "app.kubernetes.io/instance"
+	argoCDSyncOptionsAnnotation    = "argocd.argoproj.io/sync-options"
+	argoCDCompareOptionsAnnotation = "argocd.argoproj.io/compare-options"
+
+	argoCDSyncOptionsNoPrune        = "Prune=false"
+	argoCDCompareOptionsIgnoreExtra = "IgnoreExtraneous"
+)
+
+// commonMetadata returns the metadata added to every resource rendered for
+// a Release: its CommonMetadata and the metadata Argo CD relies on, if any.
+func commonMetadata(p *v1beta1.ReleaseParameters) *v1beta1.CommonMetadata {
+	a := p.ArgoCD
+	if a == nil || (a.Application == "" && !a.Exclude) {
+		return p.CommonMetadata
+	}
+
+	cm := p.CommonMetadata.DeepCopy()
+	if cm == nil {
+		cm = &v1beta1.CommonMetadata{}
+	}
+	if a.Application != "" {
+		k := a.InstanceLabelKey
+		if k == "" {
+			k = argoCDDefaultInstanceLabel
+		}
+		if cm.Labels == nil {
+			cm.Labels = map[string]string{}
+		}
+		cm.Labels[k] = a.Application
+	}
+	if a.Exclude {
+		if cm.Annotations == nil {
+			cm.Annotations = map[string]string{}
+		}
+		cm.Annotations[argoCDSyncOptionsAnnotation] = argoCDSyncOptionsNoPrune
+		cm.Annotations[argoCDCompareOptionsAnnotation] = argoCDCompareOptionsIgnoreExtra
+	}
+	return cm
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+func Test_commonMetadata(t *testing.T) {
+	common := &v1beta1.CommonMetadata{Labels: map[string]string{"team": "platform"}}
+
+	cases := map[string]struct {
+		in   v1beta1.ReleaseParameters
+		want *v1beta1.CommonMetadata
+	}{
+		"NoArgoCD": {
+			in:   v1beta1.ReleaseParameters{CommonMetadata: common},
+			want: common,
+		},
+		"Tracked": {
+			in: v1beta1.ReleaseParameters{
+				CommonMetadata: common,
+				ArgoCD:         &v1beta1.ArgoCD{Application: "platform"},
+			},
+			want: &v1beta1.CommonMetadata{Labels: map[string]string{
+				"team":                       "platform",
+				"app.kubernetes.io/instance": "platform",
+			}},
+		},
+		"TrackedByCustomLabel": {
+			in: v1beta1.ReleaseParameters{
+				ArgoCD: &v1beta1.ArgoCD{Application: "platform", InstanceLabelKey: "argocd.argoproj.io/instance"},
+			},
+			want: &v1beta1.CommonMetadata{Labels: map[string]string{"argocd.argoproj.io/instance": "platform"}},
+		},
+		"Excluded": {
+			in: v1beta1.ReleaseParameters{
+				ArgoCD: &v1beta1.ArgoCD{Exclude: true},
+			},
+			want: &v1beta1.CommonMetadata{Annotations: map[string]string{
+				"argocd.argoproj.io/sync-options":    "Prune=false",
+				"argocd.argoproj.io/compare-options": "IgnoreExtraneous",
+			}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, commonMetadata(&tc.in)); diff != "" {
+				t.Errorf("commonMetadata(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+	if diff := cmp.Diff(&v1beta1.CommonMetadata{Labels: map[string]string{"team": "platform"}}, common); diff != "" {
+		t.Errorf("commonMetadata(...): CommonMetadata must not be modified: -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ktype "sigs.k8s.io/kustomize/api/types"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+// attempted returns a deployAction that records the supplied action as the
+// last attempted revision of the Release and, once it succeeded, as its last
+// deployed revision. The action is added to the audit log of the Release as
+// the supplied operation.
+func (e *helmExternal) attempted(cr *v1beta1.Release, op v1beta1.AuditOperation, action deployAction) deployAction {
+	return func(rel string, ch *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (*release.Release, error) {
+		a := &v1beta1.DeployAttempt{Time: metav1.Now()}
+		if ch != nil && ch.Metadata != nil {
+			a.ChartVersion = ch.Metadata.Version
+		}
+		var err error
+		if a.Digest, err = diffDigest(ch, vals, patches); err != nil {
+			e.logger.Debug(errFailedToDigest, "error", err)
+		}
+		cr.Status.LastAttemptedRevision = a
+
+		r, err := action(rel, ch, vals, patches)
+		if r != nil {
+			a.Revision = r.Version
+		}
+		if err == nil {
+			cr.Status.LastDeployedRevision = a.DeepCopy()
+		}
+		audit(cr, v1beta1.AuditEntry{
+			Time:         a.Time,
+			Operation:    op,
+			Revision:     a.Revision,
+			ChartVersion: a.ChartVersion,
+			Digest:       a.Digest,
+		}, err)
+		return r, err
+	}
+}
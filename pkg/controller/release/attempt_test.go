@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	ktype "sigs.k8s.io/kustomize/api/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+func Test_helmExternal_attempted(t *testing.T) {
+	ch := &chart.Chart{Metadata: &chart.Metadata{Name: "wordpress", Version: "2.0.0"}}
+	vals := map[string]interface{}{"replicas": 2}
+	digest, _ := diffDigest(ch, vals, nil)
+	deployed := &v1beta1.DeployAttempt{ChartVersion: "1.0.0", Digest: "sha256:previous", Revision: 1}
+
+	type want struct {
+		attempted *v1beta1.DeployAttempt
+		deployed  *v1beta1.DeployAttempt
+	}
+	cases := map[string]struct {
+		rel  *release.Release
+		err  error
+		want want
+	}{
+		"Succeeded": {
+			rel: &release.Release{Version: 2},
+			want: want{
+				attempted: &v1beta1.DeployAttempt{ChartVersion: "2.0.0", Digest: digest, Revision: 2},
+				deployed:  &v1beta1.DeployAttempt{ChartVersion: "2.0.0", Digest: digest, Revision: 2},
+			},
+		},
+		"FailedWithRevision": {
+			rel: &release.Release{Version: 2},
+			err: errBoom,
+			want: want{
+				attempted: &v1beta1.DeployAttempt{ChartVersion: "2.0.0", Digest: digest, Revision: 2},
+				deployed:  deployed,
+			},
+		},
+		"FailedWithoutRevision": {
+			err: errBoom,
+			want: want{
+				attempted: &v1beta1.DeployAttempt{ChartVersion: "2.0.0", Digest: digest},
+				deployed:  deployed,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1beta1.Release{}
+			cr.Status.LastDeployedRevision = deployed.DeepCopy()
+			action := func(_ string, _ *chart.Chart, _ map[string]interface{}, _ []ktype.Patch) (*release.Release, error) {
+				return tc.rel, tc.err
+			}
+			e := &helmExternal{logger: logging.NewNopLogger()}
+			if _, err := e.attempted(cr, v1beta1.AuditOperationUpgrade, action)("wordpress", ch, vals, nil); err != tc.err {
+				t.Errorf("attempted(...): want error %v, got %v", tc.err, err)
+			}
+			ignoreTime := cmpopts.IgnoreFields(v1beta1.DeployAttempt{}, "Time")
+			if diff := cmp.Diff(tc.want.attempted, cr.Status.LastAttemptedRevision, ignoreTime); diff != "" {
+				t.Errorf("attempted(...): -want last attempted, +got: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.deployed, cr.Status.LastDeployedRevision, ignoreTime); diff != "" {
+				t.Errorf("attempted(...): -want last deployed, +got: %s", diff)
+			}
+		})
+	}
+}
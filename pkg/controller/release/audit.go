@@ -0,0 +1,57 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+// maxAuditEntries is the number of most recent operations that are kept in
+// the audit log of a Release.
+const maxAuditEntries = 20
+
+// audit appends the supplied entry to the audit log of the supplied Release,
+// dropping the oldest entries beyond maxAuditEntries. The outcome of the
+// entry is derived from the supplied error.
+func audit(cr *v1beta1.Release, entry v1beta1.AuditEntry, err error) {
+	if entry.Time.IsZero() {
+		entry.Time = metav1.Now()
+	}
+	entry.Generation = cr.GetGeneration()
+	entry.Outcome = v1beta1.AuditOutcomeSucceeded
+	if err != nil {
+		entry.Outcome = v1beta1.AuditOutcomeFailed
+		entry.Message = err.Error()
+	}
+	l := append(cr.Status.AuditLog, entry)
+	if len(l) > maxAuditEntries {
+		l = l[len(l)-maxAuditEntries:]
+	}
+	cr.Status.AuditLog = l
+}
+
+// auditCurrent appends an entry for the supplied operation on the current
+// revision of the release of the supplied Release to its audit log.
+func auditCurrent(cr *v1beta1.Release, op v1beta1.AuditOperation, err error) {
+	audit(cr, v1beta1.AuditEntry{
+		Operation:    op,
+		Revision:     cr.Status.AtProvider.Revision,
+		ChartVersion: cr.Status.AtProvider.ChartVersion,
+	}, err)
+}
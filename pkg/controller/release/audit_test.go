@@ -0,0 +1,89 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+func Test_audit(t *testing.T) {
+	full := func() []v1beta1.AuditEntry {
+		l := make([]v1beta1.AuditEntry, 0, maxAuditEntries)
+		for r := 1; r <= maxAuditEntries; r++ {
+			l = append(l, v1beta1.AuditEntry{Operation: v1beta1.AuditOperationUpgrade, Revision: r})
+		}
+		return l
+	}
+
+	type args struct {
+		log   []v1beta1.AuditEntry
+		entry v1beta1.AuditEntry
+		err   error
+	}
+	cases := map[string]struct {
+		args args
+		want []v1beta1.AuditEntry
+	}{
+		"Succeeded": {
+			args: args{
+				entry: v1beta1.AuditEntry{Operation: v1beta1.AuditOperationInstall, Revision: 1, ChartVersion: "1.0.0"},
+			},
+			want: []v1beta1.AuditEntry{
+				{Operation: v1beta1.AuditOperationInstall, Outcome: v1beta1.AuditOutcomeSucceeded, Generation: 3, Revision: 1, ChartVersion: "1.0.0"},
+			},
+		},
+		"Failed": {
+			args: args{
+				log:   []v1beta1.AuditEntry{{Operation: v1beta1.AuditOperationInstall, Revision: 1}},
+				entry: v1beta1.AuditEntry{Operation: v1beta1.AuditOperationRollback, Revision: 2},
+				err:   errBoom,
+			},
+			want: []v1beta1.AuditEntry{
+				{Operation: v1beta1.AuditOperationInstall, Revision: 1},
+				{Operation: v1beta1.AuditOperationRollback, Outcome: v1beta1.AuditOutcomeFailed, Generation: 3, Revision: 2, Message: errBoom.Error()},
+			},
+		},
+		"DropOldest": {
+			args: args{
+				log:   full(),
+				entry: v1beta1.AuditEntry{Operation: v1beta1.AuditOperationUninstall, Revision: maxAuditEntries},
+			},
+			want: append(full()[1:], v1beta1.AuditEntry{
+				Operation: v1beta1.AuditOperationUninstall, Outcome: v1beta1.AuditOutcomeSucceeded, Generation: 3, Revision: maxAuditEntries,
+			}),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1beta1.Release{}
+			cr.SetGeneration(3)
+			cr.Status.AuditLog = tc.args.log
+			audit(cr, tc.args.entry, tc.args.err)
+			if diff := cmp.Diff(tc.want, cr.Status.AuditLog, cmpopts.IgnoreFields(v1beta1.AuditEntry{}, "Time")); diff != "" {
+				t.Errorf("audit(...): -want, +got: %s", diff)
+			}
+			if cr.Status.AuditLog[len(cr.Status.AuditLog)-1].Time.IsZero() {
+				t.Errorf("audit(...): time of the entry is not set")
+			}
+		})
+	}
+}
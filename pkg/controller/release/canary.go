@@ -0,0 +1,152 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	ktype "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	defaultCanarySuffix = "-canary"
+
+	reasonCanaryPromoted event.Reason = "CanaryPromoted"
+)
+
+const (
+	errFailedToParseCanaryValues = "failed to parse canary values"
+	errFailedToDeployCanary      = "failed to deploy canary release"
+	errFailedToCheckCanary       = "failed to check health of canary release"
+	errFailedToUninstallCanary   = "failed to uninstall canary release"
+)
+
+// errCanaryInProgress is returned by a canaried deployAction while the
+// canary release is not verified yet.
+var errCanaryInProgress = errors.New("canary release is not verified yet")
+
+// canaried returns a deployAction that deploys a canary release with the
+// supplied chart, values and patches first, and runs the supplied action
+// only once all resources of the canary release are healthy. The canary
+// release is uninstalled after the action succeeded. errCanaryInProgress is
+// returned until then.
+func (e *helmExternal) canaried(ctx context.Context, cr *v1beta1.Release, action deployAction) deployAction {
+	return func(rel string, ch *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (*release.Release, error) {
+		d, err := diffDigest(ch, vals, patches)
+		if err != nil {
+			return nil, errors.Wrap(err, errFailedToDigest)
+		}
+		s := cr.Spec.ForProvider.UpgradeStrategy.Canary
+		if s == nil {
+			s = &v1beta1.CanaryStrategy{}
+		}
+		name := rel + defaultCanarySuffix
+		if s.Suffix != "" {
+			name = rel + s.Suffix
+		}
+
+		c := cr.Status.Canary
+		if c == nil || c.Digest != d || c.ReleaseName != name {
+			cv, err := canaryValues(vals, s.Values.Raw)
+			if err != nil {
+				return nil, errors.Wrap(err, errFailedToParseCanaryValues)
+			}
+			if err := e.deployCanary(name, ch, cv, patches); err != nil {
+				return nil, errors.Wrap(err, errFailedToDeployCanary)
+			}
+			cr.Status.Canary = &v1beta1.CanaryStatus{ReleaseName: name, Digest: d}
+			cr.Status.SetConditions(v1beta1.CanaryVerifying(name, nil))
+			return nil, errCanaryInProgress
+		}
+
+		canary, err := e.helm.GetLastRelease(name)
+		if err != nil {
+			return nil, errors.Wrap(err, errFailedToCheckCanary)
+		}
+		u, err := unhealthy(ctx, e.kube, e.kube.RESTMapper(), cr.Spec.ForProvider.Namespace, canary.Manifest)
+		if err != nil {
+			return nil, errors.Wrap(err, errFailedToCheckCanary)
+		}
+		c.Unhealthy = u
+		if canary.Info == nil || canary.Info.Status != release.StatusDeployed || len(u) > 0 {
+			cr.Status.SetConditions(v1beta1.CanaryVerifying(name, u))
+			return nil, errCanaryInProgress
+		}
+
+		r, err := action(rel, ch, vals, patches)
+		if err != nil {
+			return nil, err
+		}
+		if err := e.helm.Uninstall(name); err != nil && !errors.Is(err, driver.ErrReleaseNotFound) {
+			return nil, errors.Wrap(err, errFailedToUninstallCanary)
+		}
+		cr.Status.Canary = nil
+		cr.Status.SetConditions(v1beta1.CanaryPromoted())
+		e.recorder.Event(cr, event.Normal(reasonCanaryPromoted, "Canary release "+name+" is healthy, upgraded release"))
+		return r, nil
+	}
+}
+
+// deployCanary installs or upgrades the canary release.
+func (e *helmExternal) deployCanary(name string, ch *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) error {
+	_, err := e.helm.GetLastRelease(name)
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		_, err = e.helm.Install(name, ch, vals, patches)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	_, err = e.helm.Upgrade(name, ch, vals, patches)
+	return err
+}
+
+// canaryValues merges the supplied overlay into the values of the release.
+func canaryValues(vals map[string]interface{}, overlay []byte) (map[string]interface{}, error) {
+	if len(overlay) == 0 {
+		return vals, nil
+	}
+	o := map[string]interface{}{}
+	if err := yaml.Unmarshal(overlay, &o); err != nil {
+		return nil, err
+	}
+	return chartutil.CoalesceTables(o, vals), nil
+}
+
+// uninstallCanary uninstalls the canary release of an upgrade in progress,
+// if there is one.
+func (e *helmExternal) uninstallCanary(cr *v1beta1.Release) error {
+	c := cr.Status.Canary
+	if c == nil {
+		return nil
+	}
+	if err := e.helm.Uninstall(c.ReleaseName); err != nil && !errors.Is(err, driver.ErrReleaseNotFound) {
+		return errors.Wrap(err, errFailedToUninstallCanary)
+	}
+	cr.Status.Canary = nil
+	return nil
+}
@@ -0,0 +1,153 @@
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/kustomize/api/types"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+func Test_canaried(t *testing.T) {
+	ch := &chart.Chart{Metadata: &chart.Metadata{Name: testChart, Version: testVersion}}
+	vals := map[string]interface{}{"replicas": 3, "image": "app:v2"}
+	digest, err := diffDigest(ch, vals, nil)
+	if err != nil {
+		t.Fatalf("diffDigest(...): %s", err)
+	}
+	canaryName := testReleaseName + defaultCanarySuffix
+
+	type want struct {
+		err         error
+		called      bool
+		installed   map[string]interface{}
+		uninstalled string
+		canary      *v1beta1.CanaryStatus
+	}
+	cases := map[string]struct {
+		status *v1beta1.CanaryStatus
+		canary *release.Release
+		want   want
+	}{
+		"InstallCanary": {
+			want: want{
+				err:       errCanaryInProgress,
+				installed: map[string]interface{}{"replicas": float64(1), "image": "app:v2"},
+				canary:    &v1beta1.CanaryStatus{ReleaseName: canaryName, Digest: digest},
+			},
+		},
+		"ReplaceOutdatedCanary": {
+			status: &v1beta1.CanaryStatus{ReleaseName: canaryName, Digest: "sha256:old"},
+			want: want{
+				err:       errCanaryInProgress,
+				installed: map[string]interface{}{"replicas": float64(1), "image": "app:v2"},
+				canary:    &v1beta1.CanaryStatus{ReleaseName: canaryName, Digest: digest},
+			},
+		},
+		"CanaryNotDeployed": {
+			status: &v1beta1.CanaryStatus{ReleaseName: canaryName, Digest: digest},
+			canary: &release.Release{Info: &release.Info{Status: release.StatusPendingInstall}},
+			want: want{
+				err:    errCanaryInProgress,
+				canary: &v1beta1.CanaryStatus{ReleaseName: canaryName, Digest: digest},
+			},
+		},
+		"Promote": {
+			status: &v1beta1.CanaryStatus{ReleaseName: canaryName, Digest: digest},
+			canary: &release.Release{Info: &release.Info{Status: release.StatusDeployed}},
+			want: want{
+				called:      true,
+				uninstalled: canaryName,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var installed map[string]interface{}
+			uninstalled := ""
+			called := false
+			e := &helmExternal{
+				kube:     &test.MockClient{},
+				recorder: event.NewNopRecorder(),
+				helm: &MockHelmClient{
+					MockGetLastRelease: func(_ string) (*release.Release, error) {
+						if tc.canary == nil {
+							return nil, driver.ErrReleaseNotFound
+						}
+						return tc.canary, nil
+					},
+					MockInstall: func(_ string, _ *chart.Chart, vals map[string]interface{}, _ []types.Patch) (*release.Release, error) {
+						installed = vals
+						return &release.Release{}, nil
+					},
+					MockUninstall: func(r string) error {
+						uninstalled = r
+						return nil
+					},
+				},
+			}
+			cr := helmRelease(func(r *v1beta1.Release) {
+				r.Spec.ForProvider.UpgradeStrategy = &v1beta1.UpgradeStrategy{
+					Type:   v1beta1.UpgradeStrategyCanary,
+					Canary: &v1beta1.CanaryStrategy{Values: runtime.RawExtension{Raw: []byte(`{"replicas": 1}`)}},
+				}
+				r.Status.Canary = tc.status
+			})
+			action := e.canaried(context.Background(), cr, func(string, *chart.Chart, map[string]interface{}, []types.Patch) (*release.Release, error) {
+				called = true
+				return &release.Release{}, nil
+			})
+			_, err := action(testReleaseName, ch, vals, nil)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("canaried(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.called, called); diff != "" {
+				t.Errorf("canaried(...): -want called, +got called: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.installed, installed); diff != "" {
+				t.Errorf("canaried(...): -want installed values, +got installed values: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.uninstalled, uninstalled); diff != "" {
+				t.Errorf("canaried(...): -want uninstalled, +got uninstalled: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.canary, cr.Status.Canary); diff != "" {
+				t.Errorf("canaried(...): -want canary status, +got canary status: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_canaryValues(t *testing.T) {
+	cases := map[string]struct {
+		overlay string
+		want    map[string]interface{}
+		err     error
+	}{
+		"NoOverlay": {
+			want: map[string]interface{}{"replicas": 3},
+		},
+		"Overlay": {
+			overlay: `{"replicas": 1, "canary": true}`,
+			want:    map[string]interface{}{"replicas": float64(1), "canary": true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := canaryValues(map[string]interface{}{"replicas": 3}, []byte(tc.overlay))
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("canaryValues(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("canaryValues(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 const (
 	testChart               = "testchart"
 	testVersion             = "v1"
+	testRepo                = "https://charts.example.org/stable"
 	testPullSecretName      = "testcreds"
 	testPullSecretNamespace = "testns"
 	testUser                = "testuser"
@@ -0,0 +1,149 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"path"
+	"sort"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	errFmtInvalidChartPattern = "invalid chart name pattern %q"
+	errFmtInvalidVersionRange = "invalid version range %q of charts %q"
+	errFmtRepositoryDenied    = "chart repository %q is denied by the chart policy of the provider"
+	errFmtChartDenied         = "chart %q is denied by the chart policy of the provider"
+	errFmtInvalidChartVersion = "chart %q has invalid version %q"
+	errFmtChartVersionDenied  = "version %s of chart %q is denied by the chart policy of the provider, which allows %s"
+)
+
+// A ChartPolicy restricts the charts the Releases of the provider may
+// deploy, e.g. when they are created by tenants that are not trusted.
+// Denied repositories and charts take precedence over allowed ones.
+type ChartPolicy struct {
+	// AllowedRepositories are the repositories charts may be pulled from.
+	// Repositories include those below them, e.g. the chart URLs and
+	// sub-paths of https://charts.example.org/stable are in it. All are
+	// allowed if empty.
+	AllowedRepositories []string
+	// DeniedRepositories are the repositories charts may not be pulled
+	// from.
+	DeniedRepositories []string
+	// AllowedCharts are glob patterns of the names of the charts that may
+	// be deployed. All are allowed if empty.
+	AllowedCharts []string
+	// DeniedCharts are glob patterns of the names of the charts that may
+	// not be deployed.
+	DeniedCharts []string
+	// AllowedVersions are semver ranges, like ">=1.0.0 <2.0.0", the versions
+	// of charts must be in, by glob pattern of the names of the charts.
+	AllowedVersions map[string]string
+}
+
+// empty returns whether the policy allows all charts.
+func (p *ChartPolicy) empty() bool {
+	return p == nil || len(p.AllowedRepositories)+len(p.DeniedRepositories)+len(p.AllowedCharts)+len(p.DeniedCharts)+len(p.AllowedVersions) == 0
+}
+
+// Validate returns an error if a pattern or range of the policy is invalid.
+func (p *ChartPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	for _, pt := range append(append([]string{}, p.AllowedCharts...), p.DeniedCharts...) {
+		if _, err := path.Match(pt, ""); err != nil {
+			return errors.Wrapf(err, errFmtInvalidChartPattern, pt)
+		}
+	}
+	for pt, r := range p.AllowedVersions {
+		if _, err := path.Match(pt, ""); err != nil {
+			return errors.Wrapf(err, errFmtInvalidChartPattern, pt)
+		}
+		if _, err := semver.NewConstraint(r); err != nil {
+			return errors.Wrapf(err, errFmtInvalidVersionRange, r, pt)
+		}
+	}
+	return nil
+}
+
+// allows returns an error if the policy denies the supplied chart. The name
+// and version are not checked if empty, e.g. before the chart is pulled.
+func (p *ChartPolicy) allows(spec v1beta1.ChartSpec, name, version string) error {
+	if p.empty() {
+		return nil
+	}
+	repo := spec.URL
+	if repo == "" {
+		repo = spec.Repository
+	}
+	if inAnyRepository(repo, p.DeniedRepositories) || (len(p.AllowedRepositories) > 0 && !inAnyRepository(repo, p.AllowedRepositories)) {
+		return errors.Errorf(errFmtRepositoryDenied, repo)
+	}
+	if name == "" {
+		return nil
+	}
+	if matchesAny(name, p.DeniedCharts) || (len(p.AllowedCharts) > 0 && !matchesAny(name, p.AllowedCharts)) {
+		return errors.Errorf(errFmtChartDenied, name)
+	}
+	if version == "" {
+		return nil
+	}
+
+	// Ranges are checked in a stable order so that denials of versions
+	// out of several ranges always report the same one.
+	patterns := make([]string, 0, len(p.AllowedVersions))
+	for pt := range p.AllowedVersions {
+		patterns = append(patterns, pt)
+	}
+	sort.Strings(patterns)
+	for _, pt := range patterns {
+		if ok, _ := path.Match(pt, name); !ok {
+			continue
+		}
+		c, err := semver.NewConstraint(p.AllowedVersions[pt])
+		if err != nil {
+			return errors.Wrapf(err, errFmtInvalidVersionRange, p.AllowedVersions[pt], pt)
+		}
+		v, err := semver.NewVersion(version)
+		if err != nil {
+			return errors.Wrapf(err, errFmtInvalidChartVersion, name, version)
+		}
+		if !c.Check(v) {
+			return errors.Errorf(errFmtChartVersionDenied, version, name, p.AllowedVersions[pt])
+		}
+	}
+	return nil
+}
+
+// chartAllowed returns an error if the chart policy of the provider denies
+// the supplied chart of the supplied Release, in which case the denial is
+// reported in its ChartAllowed condition.
+func (e *helmExternal) chartAllowed(cr *v1beta1.Release, name, version string) error {
+	if e.chartPolicy.empty() {
+		return nil
+	}
+	if err := e.chartPolicy.allows(cr.Spec.ForProvider.Chart, name, version); err != nil {
+		cr.Status.SetConditions(v1beta1.ChartDenied(err))
+		return err
+	}
+	cr.Status.SetConditions(v1beta1.ChartAllowed())
+	return nil
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+func TestChartPolicyAllows(t *testing.T) {
+	policy := &ChartPolicy{
+		AllowedRepositories: []string{"https://charts.example.org/", "oci://registry.example.org/"},
+		DeniedRepositories:  []string{"https://charts.example.org/incubator"},
+		DeniedCharts:        []string{"*-operator"},
+		AllowedVersions:     map[string]string{"ingress-*": ">=4.0.0 <5.0.0"},
+	}
+
+	type args struct {
+		policy  *ChartPolicy
+		spec    v1beta1.ChartSpec
+		name    string
+		version string
+	}
+	cases := map[string]struct {
+		args
+		want error
+	}{
+		"NoPolicy": {
+			args: args{
+				spec: v1beta1.ChartSpec{Repository: "https://evil.example.com"},
+				name: "anything",
+			},
+		},
+		"Allowed": {
+			args: args{
+				policy:  policy,
+				spec:    v1beta1.ChartSpec{Repository: "https://charts.example.org/stable"},
+				name:    "ingress-nginx",
+				version: "4.1.0",
+			},
+		},
+		"AllowedURL": {
+			args: args{
+				policy: policy,
+				spec:   v1beta1.ChartSpec{URL: "oci://registry.example.org/charts/wordpress"},
+			},
+		},
+		"RepositoryNotAllowed": {
+			args: args{
+				policy: policy,
+				spec:   v1beta1.ChartSpec{Repository: "https://evil.example.com"},
+				name:   "wordpress",
+			},
+			want: errors.Errorf(errFmtRepositoryDenied, "https://evil.example.com"),
+		},
+		"HostSuffixNotAllowed": {
+			args: args{
+				policy: policy,
+				spec:   v1beta1.ChartSpec{Repository: "https://charts.example.org.evil.io/stable"},
+			},
+			want: errors.Errorf(errFmtRepositoryDenied, "https://charts.example.org.evil.io/stable"),
+		},
+		"UserInfoNotAllowed": {
+			args: args{
+				policy: policy,
+				spec:   v1beta1.ChartSpec{URL: "https://charts.example.org@evil.io/wordpress-9.3.19.tgz"},
+			},
+			want: errors.Errorf(errFmtRepositoryDenied, "https://charts.example.org@evil.io/wordpress-9.3.19.tgz"),
+		},
+		"SchemeNotAllowed": {
+			args: args{
+				policy: policy,
+				spec:   v1beta1.ChartSpec{Repository: "http://charts.example.org/stable"},
+			},
+			want: errors.Errorf(errFmtRepositoryDenied, "http://charts.example.org/stable"),
+		},
+		"PathSegmentAllowed": {
+			args: args{
+				policy: &ChartPolicy{AllowedRepositories: []string{"oci://registry.example.org/team"}},
+				spec:   v1beta1.ChartSpec{URL: "oci://registry.example.org/team/wordpress"},
+			},
+		},
+		"PathPrefixNotAllowed": {
+			args: args{
+				policy: &ChartPolicy{AllowedRepositories: []string{"oci://registry.example.org/team"}},
+				spec:   v1beta1.ChartSpec{URL: "oci://registry.example.org/team-evil/wordpress"},
+			},
+			want: errors.Errorf(errFmtRepositoryDenied, "oci://registry.example.org/team-evil/wordpress"),
+		},
+		"PathTraversalNotAllowed": {
+			args: args{
+				policy: &ChartPolicy{AllowedRepositories: []string{"oci://registry.example.org/team"}},
+				spec:   v1beta1.ChartSpec{URL: "oci://registry.example.org/team/../evil/wordpress"},
+			},
+			want: errors.Errorf(errFmtRepositoryDenied, "oci://registry.example.org/team/../evil/wordpress"),
+		},
+		"RepositoryDenied": {
+			args: args{
+				policy: policy,
+				spec:   v1beta1.ChartSpec{Repository: "https://charts.example.org/incubator"},
+			},
+			want: errors.Errorf(errFmtRepositoryDenied, "https://charts.example.org/incubator"),
+		},
+		"PathPrefixNotDenied": {
+			args: args{
+				policy: policy,
+				spec:   v1beta1.ChartSpec{Repository: "https://charts.example.org/incubator-legacy"},
+			},
+		},
+		"ChartDenied": {
+			args: args{
+				policy: policy,
+				spec:   v1beta1.ChartSpec{Repository: "https://charts.example.org/stable"},
+				name:   "prometheus-operator",
+			},
+			want: errors.Errorf(errFmtChartDenied, "prometheus-operator"),
+		},
+		"ChartNotAllowed": {
+			args: args{
+				policy: &ChartPolicy{AllowedCharts: []string{"ingress-*"}},
+				spec:   v1beta1.ChartSpec{Repository: "https://charts.example.org/stable"},
+				name:   "wordpress",
+			},
+			want: errors.Errorf(errFmtChartDenied, "wordpress"),
+		},
+		"VersionDenied": {
+			args: args{
+				policy:  policy,
+				spec:    v1beta1.ChartSpec{Repository: "https://charts.example.org/stable"},
+				name:    "ingress-nginx",
+				version: "3.9.0",
+			},
+			want: errors.Errorf(errFmtChartVersionDenied, "3.9.0", "ingress-nginx", ">=4.0.0 <5.0.0"),
+		},
+		"VersionOfOtherChart": {
+			args: args{
+				policy:  policy,
+				spec:    v1beta1.ChartSpec{Repository: "https://charts.example.org/stable"},
+				name:    "wordpress",
+				version: "3.9.0",
+			},
+		},
+		"VersionNotChecked": {
+			args: args{
+				policy: policy,
+				spec:   v1beta1.ChartSpec{Repository: "https://charts.example.org/stable"},
+				name:   "ingress-nginx",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.args.policy.allows(tc.args.spec, tc.args.name, tc.args.version)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("allows(...): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestChartPolicyValidate(t *testing.T) {
+	cases := map[string]struct {
+		policy *ChartPolicy
+		valid  bool
+	}{
+		"NoPolicy": {
+			valid: true,
+		},
+		"Valid": {
+			policy: &ChartPolicy{DeniedCharts: []string{"*-operator"}, AllowedVersions: map[string]string{"*": "<1.0.0 || >=1.2.0"}},
+			valid:  true,
+		},
+		"InvalidPattern": {
+			policy: &ChartPolicy{AllowedCharts: []string{"ingress-["}},
+		},
+		"InvalidRange": {
+			policy: &ChartPolicy{AllowedVersions: map[string]string{"*": "latest"}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.policy.Validate()
+			if diff := cmp.Diff(tc.valid, err == nil); diff != "" {
+				t.Errorf("Validate(...): -want valid, +got valid:\n%s\nerror: %v", diff, err)
+			}
+		})
+	}
+}
+
+func TestChartAllowed(t *testing.T) {
+	e := &helmExternal{chartPolicy: &ChartPolicy{DeniedCharts: []string{"wordpress"}}}
+	cr := &v1beta1.Release{}
+
+	if err := e.chartAllowed(cr, "ingress-nginx", "4.1.0"); err != nil {
+		t.Fatalf("chartAllowed(...): unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(v1beta1.ChartAllowed(), cr.Status.GetCondition(v1beta1.TypeChartAllowed), cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+		t.Errorf("chartAllowed(...): -want condition, +got condition:\n%s", diff)
+	}
+
+	err := e.chartAllowed(cr, "wordpress", "1.0.0")
+	if diff := cmp.Diff(errors.Errorf(errFmtChartDenied, "wordpress"), err, test.EquateErrors()); diff != "" {
+		t.Errorf("chartAllowed(...): -want error, +got error:\n%s", diff)
+	}
+	if diff := cmp.Diff(v1beta1.ChartDenied(err), cr.Status.GetCondition(v1beta1.TypeChartAllowed), cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+		t.Errorf("chartAllowed(...): -want condition, +got condition:\n%s", diff)
+	}
+}
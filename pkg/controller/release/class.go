@@ -0,0 +1,167 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	errFailedToGetReleaseClass = "failed to get release class"
+	errRepositoryNotAllowed    = "chart repository %q is not allowed by release class %q"
+	errNamespaceNotAllowed     = "namespace %q is not allowed by release class %q"
+)
+
+// releaseClass returns the ReleaseClass the Release refers to, or nil if it
+// does not refer to one.
+func releaseClass(ctx context.Context, kube client.Client, cr *v1beta1.Release) (*v1beta1.ReleaseClass, error) {
+	n := cr.Spec.ForProvider.ReleaseClassName
+	if n == "" {
+		return nil, nil
+	}
+	rc := &v1beta1.ReleaseClass{}
+	if err := kube.Get(ctx, types.NamespacedName{Name: n}, rc); err != nil {
+		return nil, errors.Wrap(err, errFailedToGetReleaseClass)
+	}
+	return rc, nil
+}
+
+// withClassDefaults returns a copy of the parameters of the supplied Release
+// with the defaults of the supplied class, if any, applied. The Release is
+// not modified, so that the defaults are never persisted, e.g. when it is
+// late initialized.
+func withClassDefaults(cr *v1beta1.Release, rc *v1beta1.ReleaseClass) *v1beta1.ReleaseParameters {
+	p := cr.Spec.ForProvider.DeepCopy()
+	if rc != nil {
+		applyClassDefaults(p, rc.Spec.Defaults)
+	}
+	return p
+}
+
+// applyClassDefaults sets the defaults of the class on the fields that are
+// not set on the Release. Fields set to false on the Release stay false.
+func applyClassDefaults(p *v1beta1.ReleaseParameters, d v1beta1.ReleaseClassDefaults) {
+	p.Wait = defaultBool(p.Wait, d.Wait)
+	p.SkipCRDs = defaultBool(p.SkipCRDs, d.SkipCRDs)
+	p.SkipCreateNamespace = defaultBool(p.SkipCreateNamespace, d.SkipCreateNamespace)
+	p.ServerSideDryRun = defaultBool(p.ServerSideDryRun, d.ServerSideDryRun)
+	p.RBACPreflight = defaultBool(p.RBACPreflight, d.RBACPreflight)
+	p.Lint = defaultBool(p.Lint, d.Lint)
+	if p.WaitTimeout == nil {
+		p.WaitTimeout = d.WaitTimeout
+	}
+	if p.MaxHistory == nil {
+		p.MaxHistory = d.MaxHistory
+	}
+	if p.NamespaceMetadata == nil {
+		p.NamespaceMetadata = d.NamespaceMetadata
+	}
+	if p.ReleaseNameChangePolicy == "" {
+		p.ReleaseNameChangePolicy = d.ReleaseNameChangePolicy
+	}
+	if p.NamespaceChangePolicy == "" {
+		p.NamespaceChangePolicy = d.NamespaceChangePolicy
+	}
+	if p.DriftPolicy == "" {
+		p.DriftPolicy = d.DriftPolicy
+	}
+	if p.ConflictPolicy == "" {
+		p.ConflictPolicy = d.ConflictPolicy
+	}
+}
+
+// classAllows returns an error if the chart or the namespace of the Release
+// is not allowed by the class.
+func classAllows(rc *v1beta1.ReleaseClass, p v1beta1.ReleaseParameters) error {
+	if rc == nil {
+		return nil
+	}
+	if len(rc.Spec.AllowedRepositories) > 0 {
+		repo := p.Chart.URL
+		if repo == "" {
+			repo = p.Chart.Repository
+		}
+		if !inAnyRepository(repo, rc.Spec.AllowedRepositories) {
+			return errors.Errorf(errRepositoryNotAllowed, repo, rc.Name)
+		}
+	}
+	if len(rc.Spec.AllowedNamespaces) > 0 && !matchesAny(p.Namespace, rc.Spec.AllowedNamespaces) {
+		return errors.Errorf(errNamespaceNotAllowed, p.Namespace, rc.Name)
+	}
+	return nil
+}
+
+// defaultBool returns the supplied field, or the supplied default if the
+// field is not set.
+func defaultBool(b *bool, d bool) *bool {
+	if b != nil || !d {
+		return b
+	}
+	return pointer.Bool(d)
+}
+
+// inAnyRepository returns true if the supplied chart repository or URL is in
+// any of the supplied repositories.
+func inAnyRepository(s string, repos []string) bool {
+	for _, r := range repos {
+		if inRepository(s, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// inRepository returns true if the supplied chart repository or URL is in
+// the supplied repository, i.e. has the same scheme and host and a path
+// below that of the repository. Paths are compared by whole segments, so
+// that e.g. oci://registry.example.org/team-evil is not in
+// oci://registry.example.org/team.
+func inRepository(s, repo string) bool {
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return false
+	}
+	r, err := url.Parse(repo)
+	if err != nil || r.Scheme == "" || r.Host == "" {
+		return false
+	}
+	if !strings.EqualFold(u.Scheme, r.Scheme) || !strings.EqualFold(u.Host, r.Host) {
+		return false
+	}
+	rp := strings.TrimSuffix(path.Clean("/"+r.Path), "/")
+	up := path.Clean("/" + u.Path)
+	return rp == "" || up == rp || strings.HasPrefix(up, rp+"/")
+}
+
+func matchesAny(s string, patterns []string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, s); ok {
+			return true
+		}
+	}
+	return false
+}
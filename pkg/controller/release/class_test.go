@@ -0,0 +1,176 @@
+package release
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+func Test_applyClassDefaults(t *testing.T) {
+	five := int32(5)
+	ten := int32(10)
+	minute := &metav1.Duration{Duration: time.Minute}
+	hour := &metav1.Duration{Duration: time.Hour}
+
+	cases := map[string]struct {
+		params   v1beta1.ReleaseParameters
+		defaults v1beta1.ReleaseClassDefaults
+		want     v1beta1.ReleaseParameters
+	}{
+		"NoDefaults": {
+			params: v1beta1.ReleaseParameters{Wait: pointer.Bool(true), MaxHistory: &five},
+			want:   v1beta1.ReleaseParameters{Wait: pointer.Bool(true), MaxHistory: &five},
+		},
+		"DefaultsApplied": {
+			defaults: v1beta1.ReleaseClassDefaults{
+				Wait:           true,
+				WaitTimeout:    minute,
+				MaxHistory:     &ten,
+				Lint:           true,
+				DriftPolicy:    v1beta1.DriftPolicyDetect,
+				ConflictPolicy: v1beta1.ConflictPolicyAdopt,
+			},
+			want: v1beta1.ReleaseParameters{
+				Wait:           pointer.Bool(true),
+				WaitTimeout:    minute,
+				MaxHistory:     &ten,
+				Lint:           pointer.Bool(true),
+				DriftPolicy:    v1beta1.DriftPolicyDetect,
+				ConflictPolicy: v1beta1.ConflictPolicyAdopt,
+			},
+		},
+		"ReleaseTakesPrecedence": {
+			params: v1beta1.ReleaseParameters{
+				WaitTimeout: hour,
+				MaxHistory:  &five,
+				DriftPolicy: v1beta1.DriftPolicyCorrect,
+			},
+			defaults: v1beta1.ReleaseClassDefaults{
+				WaitTimeout: minute,
+				MaxHistory:  &ten,
+				DriftPolicy: v1beta1.DriftPolicyDetect,
+			},
+			want: v1beta1.ReleaseParameters{
+				WaitTimeout: hour,
+				MaxHistory:  &five,
+				DriftPolicy: v1beta1.DriftPolicyCorrect,
+			},
+		},
+		"ExplicitFalseTakesPrecedence": {
+			params: v1beta1.ReleaseParameters{
+				Wait:             pointer.Bool(false),
+				ServerSideDryRun: pointer.Bool(false),
+			},
+			defaults: v1beta1.ReleaseClassDefaults{
+				Wait:             true,
+				ServerSideDryRun: true,
+				Lint:             true,
+			},
+			want: v1beta1.ReleaseParameters{
+				Wait:             pointer.Bool(false),
+				ServerSideDryRun: pointer.Bool(false),
+				Lint:             pointer.Bool(true),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := tc.params
+			applyClassDefaults(&p, tc.defaults)
+			if diff := cmp.Diff(tc.want, p); diff != "" {
+				t.Errorf("applyClassDefaults(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_withClassDefaults(t *testing.T) {
+	class := &v1beta1.ReleaseClass{Spec: v1beta1.ReleaseClassSpec{Defaults: v1beta1.ReleaseClassDefaults{
+		Wait:        true,
+		DriftPolicy: v1beta1.DriftPolicyDetect,
+	}}}
+	cr := &v1beta1.Release{Spec: v1beta1.ReleaseSpec{ForProvider: v1beta1.ReleaseParameters{Namespace: "team-a"}}}
+	orig := cr.DeepCopy()
+
+	want := &v1beta1.ReleaseParameters{Namespace: "team-a", Wait: pointer.Bool(true), DriftPolicy: v1beta1.DriftPolicyDetect}
+	if diff := cmp.Diff(want, withClassDefaults(cr, class)); diff != "" {
+		t.Errorf("withClassDefaults(...): -want, +got: %s", diff)
+	}
+	if diff := cmp.Diff(orig, cr); diff != "" {
+		t.Errorf("withClassDefaults(...): the Release must not be modified: -want, +got: %s", diff)
+	}
+}
+
+func Test_classAllows(t *testing.T) {
+	class := &v1beta1.ReleaseClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "restricted"},
+		Spec: v1beta1.ReleaseClassSpec{
+			AllowedRepositories: []string{"https://charts.example.org/"},
+			AllowedNamespaces:   []string{"team-*", "monitoring"},
+		},
+	}
+
+	cases := map[string]struct {
+		class  *v1beta1.ReleaseClass
+		params v1beta1.ReleaseParameters
+		want   error
+	}{
+		"NoClass": {
+			params: v1beta1.ReleaseParameters{Chart: v1beta1.ChartSpec{Repository: "https://other.example.org"}},
+		},
+		"Allowed": {
+			class: class,
+			params: v1beta1.ReleaseParameters{
+				Chart:     v1beta1.ChartSpec{Repository: "https://charts.example.org/stable"},
+				Namespace: "team-a",
+			},
+		},
+		"URLAllowed": {
+			class: class,
+			params: v1beta1.ReleaseParameters{
+				Chart:     v1beta1.ChartSpec{URL: "https://charts.example.org/wordpress-9.3.19.tgz"},
+				Namespace: "monitoring",
+			},
+		},
+		"RepositoryNotAllowed": {
+			class: class,
+			params: v1beta1.ReleaseParameters{
+				Chart:     v1beta1.ChartSpec{Repository: "https://other.example.org"},
+				Namespace: "team-a",
+			},
+			want: errors.Errorf(errRepositoryNotAllowed, "https://other.example.org", "restricted"),
+		},
+		"HostSuffixNotAllowed": {
+			class: class,
+			params: v1beta1.ReleaseParameters{
+				Chart:     v1beta1.ChartSpec{Repository: "https://charts.example.org.evil.io/"},
+				Namespace: "team-a",
+			},
+			want: errors.Errorf(errRepositoryNotAllowed, "https://charts.example.org.evil.io/", "restricted"),
+		},
+		"NamespaceNotAllowed": {
+			class: class,
+			params: v1beta1.ReleaseParameters{
+				Chart:     v1beta1.ChartSpec{Repository: "https://charts.example.org/stable"},
+				Namespace: "kube-system",
+			},
+			want: errors.Errorf(errNamespaceNotAllowed, "kube-system", "restricted"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := classAllows(tc.class, tc.params)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("classAllows(...): -want error, +got error: %s", diff)
+			}
+		})
+	}
+}
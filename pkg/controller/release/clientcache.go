@@ -0,0 +1,260 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-helm/pkg/clients"
+	helmClient "github.com/crossplane-contrib/provider-helm/pkg/clients/helm"
+)
+
+const (
+	defaultClientCacheTTL  = 10 * time.Minute
+	defaultClientCacheSize = 100
+
+	// Cached clients are checked against the target cluster at most this
+	// often, and evicted if it cannot be reached.
+	clientHealthInterval = 1 * time.Minute
+)
+
+// A clientCacheKey identifies the clients of a target cluster. The generation
+// of the ProviderConfig is part of the key, so that clients are rebuilt once
+// its spec changes, but not when only its status or metadata changes.
+type clientCacheKey struct {
+	providerConfig string
+	generation     int64
+	// serviceAccount is the namespace/name of the ServiceAccount that is
+	// impersonated on behalf of the Release, if any.
+	serviceAccount string
+}
+
+// clusterClients are the clients of a target cluster.
+type clusterClients struct {
+	rc   *rest.Config
+	kube client.Client
+
+	// mapper maps the kinds and resources of the cluster for all of its
+	// clients. It discovers the API of the cluster on first use and again
+	// only when a kind is not found or CRDs were installed.
+	mapper *clients.LazyRESTMapper
+
+	mu sync.Mutex
+	// getters are shared by the Helm clients of a namespace, so that
+	// discovery is not repeated on every reconcile.
+	getters map[string]genericclioptions.RESTClientGetter
+
+	// capabilities of the cluster are shared by its Helm clients, so that
+	// they are not discovered on every install and upgrade.
+	capabilities *helmClient.CapabilitiesCache
+
+	// storage caches the release records of the cluster. Release records
+	// are read from the API server if nil.
+	storage *helmClient.StorageCache
+
+	// watches of the resources of the Releases of the cluster that watch
+	// for drift. Drift is only observed at every poll if nil.
+	watches *resourceWatches
+}
+
+func newClusterClients(rc *rest.Config, kube client.Client, mapper *clients.LazyRESTMapper) *clusterClients {
+	return &clusterClients{
+		rc:           rc,
+		kube:         kube,
+		mapper:       mapper,
+		getters:      map[string]genericclioptions.RESTClientGetter{},
+		capabilities: helmClient.NewCapabilitiesCache(),
+	}
+}
+
+// getter returns the RESTClientGetter for the supplied namespace.
+func (cc *clusterClients) getter(namespace string) genericclioptions.RESTClientGetter {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	g, ok := cc.getters[namespace]
+	if !ok {
+		g = helmClient.NewRESTClientGetter(cc.rc, namespace, cc.mapper)
+		cc.getters[namespace] = g
+	}
+	return g
+}
+
+// close stops the caches of the clients.
+func (cc *clusterClients) close() {
+	if cc.storage != nil {
+		cc.storage.Stop()
+	}
+	if cc.watches != nil {
+		cc.watches.stop()
+	}
+}
+
+type clientCacheEntry struct {
+	key     clientCacheKey
+	clients *clusterClients
+	created time.Time
+	checked time.Time
+}
+
+// A clientCache caches the clients of target clusters across reconciles.
+// Entries expire after the TTL, the least recently used entries are evicted
+// once the cache is full, and entries of unreachable clusters are evicted by
+// periodic health checks. A nil clientCache caches nothing.
+type clientCache struct {
+	ttl            time.Duration
+	size           int
+	healthInterval time.Duration
+
+	healthy func(rc *rest.Config) error
+	now     func() time.Time
+
+	mu      sync.Mutex
+	lru     *list.List
+	entries map[clientCacheKey]*list.Element
+}
+
+func newClientCache(ttl time.Duration, size int) *clientCache {
+	if ttl == 0 {
+		ttl = defaultClientCacheTTL
+	}
+	if size == 0 {
+		size = defaultClientCacheSize
+	}
+	return &clientCache{
+		ttl:            ttl,
+		size:           size,
+		healthInterval: clientHealthInterval,
+		healthy:        serverReachable,
+		now:            time.Now,
+		lru:            list.New(),
+		entries:        map[clientCacheKey]*list.Element{},
+	}
+}
+
+// serverReachable returns an error if the version of the cluster of the
+// supplied REST config cannot be retrieved.
+func serverReachable(rc *rest.Config) error {
+	dc, err := discovery.NewDiscoveryClientForConfig(rc)
+	if err != nil {
+		return err
+	}
+	_, err = dc.ServerVersion()
+	return err
+}
+
+// Get returns the clients cached for the supplied key, if any.
+func (c *clientCache) Get(key clientCacheKey) (*clusterClients, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	el, ok := c.entries[key]
+	if !ok {
+		c.mu.Unlock()
+		return nil, false
+	}
+	e := el.Value.(*clientCacheEntry)
+	now := c.now()
+	if now.Sub(e.created) > c.ttl {
+		c.remove(el)
+		c.mu.Unlock()
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	check := now.Sub(e.checked) > c.healthInterval
+	if check {
+		// Only one reconcile checks the health of an entry at a time.
+		e.checked = now
+	}
+	c.mu.Unlock()
+
+	if check && c.healthy(e.clients.rc) != nil {
+		c.mu.Lock()
+		if el, ok := c.entries[key]; ok && el.Value == e {
+			c.remove(el)
+		}
+		c.mu.Unlock()
+		return nil, false
+	}
+	return e.clients, true
+}
+
+// Add caches the supplied clients, evicting expired entries, entries of
+// previous generations of the ProviderConfig and, if the cache is full, the
+// least recently used entries. The caches of evicted clients are stopped.
+func (c *clientCache) Add(key clientCacheKey, cc *clusterClients) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for k, el := range c.entries {
+		superseded := k.providerConfig == key.providerConfig && k.serviceAccount == key.serviceAccount
+		if superseded || now.Sub(el.Value.(*clientCacheEntry).created) > c.ttl {
+			c.remove(el)
+		}
+	}
+	c.entries[key] = c.lru.PushFront(&clientCacheEntry{key: key, clients: cc, created: now, checked: now})
+	for c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+	}
+}
+
+// Invalidate evicts all clients of the supplied ProviderConfig, e.g. because
+// its credentials changed.
+func (c *clientCache) Invalidate(providerConfig string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, el := range c.entries {
+		if key.providerConfig == providerConfig {
+			c.remove(el)
+		}
+	}
+}
+
+// Len returns the number of cached entries.
+func (c *clientCache) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+func (c *clientCache) remove(el *list.Element) {
+	e := el.Value.(*clientCacheEntry)
+	c.lru.Remove(el)
+	delete(c.entries, e.key)
+	e.clients.close()
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/rest"
+)
+
+func TestClientCache(t *testing.T) {
+	start := time.Unix(0, 0)
+	keyA := clientCacheKey{providerConfig: "a", generation: 1}
+	keyA2 := clientCacheKey{providerConfig: "a", generation: 2}
+	keyB := clientCacheKey{providerConfig: "b", generation: 1}
+	keyC := clientCacheKey{providerConfig: "a", generation: 1, serviceAccount: "ns/sa"}
+
+	type step struct {
+		// elapsed since the cache was created.
+		elapsed    time.Duration
+		add        *clientCacheKey
+		invalidate string
+		get        *clientCacheKey
+		want       bool
+	}
+
+	cases := map[string]struct {
+		reason     string
+		unhealthy  bool
+		steps      []step
+		wantLen    int
+		wantClosed int
+	}{
+		"Hit": {
+			reason: "Cached clients should be returned.",
+			steps: []step{
+				{add: &keyA},
+				{get: &keyA, want: true},
+				{get: &keyC, want: false},
+			},
+			wantLen: 1,
+		},
+		"Expired": {
+			reason: "Clients should not be returned once their TTL passed.",
+			steps: []step{
+				{add: &keyA},
+				{elapsed: 11 * time.Second, get: &keyA, want: false},
+			},
+			wantLen:    0,
+			wantClosed: 1,
+		},
+		"Evicted": {
+			reason: "The least recently used clients should be evicted once the cache is full.",
+			steps: []step{
+				{add: &keyA},
+				{add: &keyB},
+				{get: &keyA, want: true},
+				{add: &keyC},
+				{get: &keyB, want: false},
+				{get: &keyA, want: true},
+			},
+			wantLen:    2,
+			wantClosed: 1,
+		},
+		"Invalidated": {
+			reason: "All clients of an invalidated ProviderConfig should be evicted.",
+			steps: []step{
+				{add: &keyA},
+				{add: &keyC},
+				{invalidate: "a"},
+				{get: &keyA, want: false},
+				{get: &keyC, want: false},
+			},
+			wantLen:    0,
+			wantClosed: 2,
+		},
+		"Unhealthy": {
+			reason:    "Clients of unreachable clusters should be evicted once checked.",
+			unhealthy: true,
+			steps: []step{
+				{add: &keyA},
+				{get: &keyA, want: true},
+				{elapsed: 2 * time.Second, get: &keyA, want: false},
+			},
+			wantLen:    0,
+			wantClosed: 1,
+		},
+		"HealthCheckNotDue": {
+			reason:    "Clients should not be checked more often than the health interval.",
+			unhealthy: true,
+			steps: []step{
+				{add: &keyA},
+				{elapsed: time.Millisecond, get: &keyA, want: true},
+			},
+			wantLen: 1,
+		},
+		"SweptOnAdd": {
+			reason: "Expired clients should be evicted and closed when clients are added.",
+			steps: []step{
+				{add: &keyA},
+				{elapsed: 11 * time.Second, add: &keyB},
+			},
+			wantLen:    1,
+			wantClosed: 1,
+		},
+		"Superseded": {
+			reason: "Clients of a previous generation of a ProviderConfig should be evicted and closed when clients of a new one are added.",
+			steps: []step{
+				{add: &keyA},
+				{add: &keyC},
+				{add: &keyA2},
+				{get: &keyA, want: false},
+				{get: &keyA2, want: true},
+				{get: &keyC, want: true},
+			},
+			wantLen:    2,
+			wantClosed: 1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			now := start
+			c := newClientCache(10*time.Second, 2)
+			c.now = func() time.Time { return now }
+			c.healthy = func(_ *rest.Config) error {
+				if tc.unhealthy {
+					return errors.New("unreachable")
+				}
+				return nil
+			}
+			c.healthInterval = time.Second
+
+			var added []*clusterClients
+			for i, s := range tc.steps {
+				now = start.Add(s.elapsed)
+				switch {
+				case s.add != nil:
+					cc := newClusterClients(&rest.Config{}, nil, nil)
+					cc.watches = &resourceWatches{releases: map[string]releaseWatch{"r": {}}, owners: map[releaseKey]string{}}
+					added = append(added, cc)
+					c.Add(*s.add, cc)
+				case s.invalidate != "":
+					c.Invalidate(s.invalidate)
+				case s.get != nil:
+					_, got := c.Get(*s.get)
+					if diff := cmp.Diff(s.want, got); diff != "" {
+						t.Errorf("\n%s\nstep %d: c.Get(...): -want, +got:\n%s", tc.reason, i, diff)
+					}
+				}
+			}
+			if diff := cmp.Diff(tc.wantLen, c.Len()); diff != "" {
+				t.Errorf("\n%s\nc.Len(): -want, +got:\n%s", tc.reason, diff)
+			}
+			closed := 0
+			for _, cc := range added {
+				if len(cc.watches.releases) == 0 {
+					closed++
+				}
+			}
+			if diff := cmp.Diff(tc.wantClosed, closed); diff != "" {
+				t.Errorf("\n%s\nclosed clients: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNilClientCache(t *testing.T) {
+	var c *clientCache
+	key := clientCacheKey{providerConfig: "a"}
+	c.Add(key, newClusterClients(&rest.Config{}, nil, nil))
+	c.Invalidate("a")
+	if _, ok := c.Get(key); ok {
+		t.Errorf("c.Get(...): a nil cache should not return clients")
+	}
+}
+
+func TestClusterClientsGetter(t *testing.T) {
+	cc := newClusterClients(&rest.Config{}, nil, nil)
+	if cc.getter("a") != cc.getter("a") {
+		t.Errorf("cc.getter(...): getters of the same namespace should be shared")
+	}
+	if cc.getter("a") == cc.getter("b") {
+		t.Errorf("cc.getter(...): getters of different namespaces should not be shared")
+	}
+}
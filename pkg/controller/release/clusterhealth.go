@@ -0,0 +1,125 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/providerconfig"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	helmv1beta1 "github.com/crossplane-contrib/provider-helm/apis/v1beta1"
+)
+
+const (
+	defaultHealthProbeInterval = 5 * time.Minute
+	healthProbeTimeout         = 30 * time.Second
+)
+
+const (
+	errGetProviderConfig    = "cannot get ProviderConfig"
+	errUpdateProviderConfig = "cannot update ProviderConfig status"
+)
+
+// A healthProber periodically probes the target cluster of ProviderConfigs
+// and reports whether it is reachable, so that unreachable clusters can be
+// told apart from broken releases. It also publishes the operation
+// statistics of the Releases using each ProviderConfig.
+type healthProber struct {
+	kube     client.Client
+	log      logging.Logger
+	interval time.Duration
+	stats    *operationStats
+
+	restConfig func(ctx context.Context, p *helmv1beta1.ProviderConfig, sa *types.NamespacedName) (*rest.Config, error)
+	probe      func(rc *rest.Config) error
+}
+
+// setupHealthProber adds a controller that probes the target cluster of
+// ProviderConfigs at the supplied interval, using the supplied connector.
+func setupHealthProber(mgr ctrl.Manager, l logging.Logger, c *connector, interval time.Duration) error {
+	name := "health/" + providerconfig.ControllerName(helmv1beta1.ProviderConfigGroupKind)
+
+	if interval == 0 {
+		interval = defaultHealthProbeInterval
+	}
+
+	// Only spec changes trigger an immediate probe; status updates, e.g.
+	// by the prober itself, don't.
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&helmv1beta1.ProviderConfig{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(&healthProber{
+			kube:       mgr.GetClient(),
+			log:        l.WithValues("controller", name),
+			interval:   interval,
+			stats:      c.stats,
+			restConfig: c.restConfig,
+			probe:      probeCluster,
+		})
+}
+
+// probeCluster returns an error if the version of the cluster of the supplied
+// REST config cannot be retrieved in time.
+func probeCluster(rc *rest.Config) error {
+	rc = rest.CopyConfig(rc)
+	rc.Timeout = healthProbeTimeout
+	return serverReachable(rc)
+}
+
+func (h *healthProber) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := h.log.WithValues("request", req)
+
+	pc := &helmv1beta1.ProviderConfig{}
+	if err := h.kube.Get(ctx, req.NamespacedName, pc); err != nil {
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetProviderConfig)
+	}
+	if meta.WasDeleted(pc) {
+		return reconcile.Result{}, nil
+	}
+
+	rc, err := h.restConfig(ctx, pc, nil)
+	if err == nil {
+		err = h.probe(rc)
+	}
+	if err != nil {
+		log.Debug("Target cluster is unreachable", "error", err)
+		pc.SetConditions(helmv1beta1.ClusterUnreachable(err))
+	} else {
+		pc.SetConditions(helmv1beta1.ClusterReachable())
+	}
+	now := metav1.Now()
+	pc.Status.LastCheckedTime = &now
+	if st := h.stats.get(pc.GetName()); st != nil {
+		pc.Status.Operations = st
+	}
+
+	return reconcile.Result{RequeueAfter: h.interval}, errors.Wrap(h.kube.Status().Update(ctx, pc), errUpdateProviderConfig)
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	helmv1beta1 "github.com/crossplane-contrib/provider-helm/apis/v1beta1"
+)
+
+func TestHealthProberReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		restConfigErr error
+		probeErr      error
+		updateErr     error
+	}
+	type want struct {
+		result    reconcile.Result
+		err       error
+		condition xpv1.Condition
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Reachable": {
+			reason: "A reachable cluster should be reported healthy.",
+			want: want{
+				result:    reconcile.Result{RequeueAfter: time.Minute},
+				condition: helmv1beta1.ClusterReachable(),
+			},
+		},
+		"Unreachable": {
+			reason: "An unreachable cluster should be reported unhealthy.",
+			args:   args{probeErr: errBoom},
+			want: want{
+				result:    reconcile.Result{RequeueAfter: time.Minute},
+				condition: helmv1beta1.ClusterUnreachable(errBoom),
+			},
+		},
+		"InvalidCredentials": {
+			reason: "A cluster whose credentials cannot be loaded should be reported unhealthy.",
+			args:   args{restConfigErr: errBoom},
+			want: want{
+				result:    reconcile.Result{RequeueAfter: time.Minute},
+				condition: helmv1beta1.ClusterUnreachable(errBoom),
+			},
+		},
+		"UpdateError": {
+			reason: "Errors updating the status should be returned.",
+			args:   args{updateErr: errBoom},
+			want: want{
+				result:    reconcile.Result{RequeueAfter: time.Minute},
+				err:       errors.Wrap(errBoom, errUpdateProviderConfig),
+				condition: helmv1beta1.ClusterReachable(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got *helmv1beta1.ProviderConfig
+			kube := &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					obj.SetName(providerName)
+					return nil
+				}),
+				MockStatusUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+					got = obj.(*helmv1beta1.ProviderConfig)
+					return tc.args.updateErr
+				},
+			}
+			stats := newOperationStats()
+			stats.record(providerName, nil)
+			h := &healthProber{
+				kube:     kube,
+				log:      logging.NewNopLogger(),
+				interval: time.Minute,
+				stats:    stats,
+				restConfig: func(_ context.Context, _ *helmv1beta1.ProviderConfig, _ *types.NamespacedName) (*rest.Config, error) {
+					return &rest.Config{}, tc.args.restConfigErr
+				},
+				probe: func(_ *rest.Config) error { return tc.args.probeErr },
+			}
+
+			r, err := h.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: providerName}})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nh.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, r); diff != "" {
+				t.Errorf("\n%s\nh.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if got == nil {
+				t.Fatalf("\n%s\nh.Reconcile(...): status was not updated", tc.reason)
+			}
+			if diff := cmp.Diff(tc.want.condition, got.GetCondition(helmv1beta1.TypeHealthy), test.EquateConditions(), cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("\n%s\nh.Reconcile(...): -want condition, +got condition:\n%s", tc.reason, diff)
+			}
+			if got.Status.LastCheckedTime == nil {
+				t.Errorf("\n%s\nh.Reconcile(...): lastCheckedTime was not set", tc.reason)
+			}
+			if got.Status.Operations == nil || got.Status.Operations.RecentOperations != 1 {
+				t.Errorf("\n%s\nh.Reconcile(...): operation statistics were not published", tc.reason)
+			}
+		})
+	}
+}
+
+func TestHealthProberReconcileDeleted(t *testing.T) {
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			now := metav1.Now()
+			obj.SetDeletionTimestamp(&now)
+			return nil
+		},
+	}
+	h := &healthProber{kube: kube, log: logging.NewNopLogger()}
+	r, err := h.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: providerName}})
+	if diff := cmp.Diff(reconcile.Result{}, r); diff != "" {
+		t.Errorf("h.Reconcile(...): -want, +got:\n%s", diff)
+	}
+	if err != nil {
+		t.Errorf("h.Reconcile(...): %v", err)
+	}
+}
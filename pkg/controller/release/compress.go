@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// compressionAnnotation marks the ConfigMaps and Secrets of manifest
+	// outputs that the provider wrote compressed. Only their values are
+	// decompressed when read.
+	compressionAnnotation = "helm.crossplane.io/compression"
+	compressionGzip       = "gzip"
+
+	// maxDecompressedSize is the maximum size of decompressed data, so that
+	// a small value can't expand into an arbitrary amount of memory.
+	maxDecompressedSize = 16 << 20
+)
+
+const (
+	errFailedToCompress   = "failed to compress data"
+	errFailedToDecompress = "failed to decompress data"
+	errFmtTooLarge        = "decompressed data exceeds %d bytes"
+)
+
+// gzipMagic are the first bytes of gzip compressed data.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// compress returns the gzip compressed supplied data.
+func compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, errors.Wrap(err, errFailedToCompress)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, errors.Wrap(err, errFailedToCompress)
+	}
+	return buf.Bytes(), nil
+}
+
+// isCompressed returns true if the supplied object was written compressed by
+// the provider.
+func isCompressed(o metav1.Object) bool {
+	return o.GetAnnotations()[compressionAnnotation] == compressionGzip
+}
+
+// decompress returns the decompressed supplied data if it is gzip
+// compressed, and the supplied data as is otherwise. Data that decompresses
+// to more than maxDecompressedSize bytes is an error.
+func decompress(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, gzipMagic) {
+		return data, nil
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToDecompress)
+	}
+	d, err := ioutil.ReadAll(io.LimitReader(zr, maxDecompressedSize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToDecompress)
+	}
+	if len(d) > maxDecompressedSize {
+		return nil, errors.Errorf(errFmtTooLarge, maxDecompressedSize)
+	}
+	return d, nil
+}
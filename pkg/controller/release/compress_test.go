@@ -0,0 +1,59 @@
+package release
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func Test_decompress(t *testing.T) {
+	compressed, err := compress([]byte("kind: ConfigMap"))
+	if err != nil {
+		t.Fatalf("compress(...): unexpected error: %s", err)
+	}
+	bomb, err := compress(bytes.Repeat([]byte{'a'}, maxDecompressedSize+1))
+	if err != nil {
+		t.Fatalf("compress(...): unexpected error: %s", err)
+	}
+
+	type want struct {
+		out string
+		err error
+	}
+	cases := map[string]struct {
+		data []byte
+		want want
+	}{
+		"Compressed": {
+			data: compressed,
+			want: want{out: "kind: ConfigMap"},
+		},
+		"Uncompressed": {
+			data: []byte("kind: ConfigMap"),
+			want: want{out: "kind: ConfigMap"},
+		},
+		"NotGzip": {
+			data: []byte{0x1f, 0x8b, 'x'},
+			want: want{err: errors.Wrap(errors.New("unexpected EOF"), errFailedToDecompress)},
+		},
+		"TooLarge": {
+			data: bomb,
+			want: want{err: errors.Errorf(errFmtTooLarge, maxDecompressedSize)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := decompress(tc.data)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("decompress(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.out, string(got)); diff != "" {
+				t.Errorf("decompress(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ktype "sigs.k8s.io/kustomize/api/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	errFailedToRenderForConflicts = "failed to render release for conflict detection"
+	errFailedToGetExistingObject  = "failed to get existing resource"
+	errFailedToAdoptResource      = "failed to adopt existing resource"
+	errFailedToReplaceResource    = "failed to delete existing resource"
+	errResourcesConflict          = "rendered resources already exist and are not part of the release: %s"
+)
+
+// conflictsResolved returns a deployAction that resolves conflicts with
+// existing resources according to the conflict policy of the Release before
+// running the supplied action. Existing resources conflict if they are not
+// part of the release.
+func (e *helmExternal) conflictsResolved(ctx context.Context, cr *v1beta1.Release, template, action deployAction) deployAction {
+	return func(rel string, ch *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (*release.Release, error) {
+		r, err := template(rel, ch, vals, patches)
+		if err != nil {
+			return nil, errors.Wrap(err, errFailedToRenderForConflicts)
+		}
+		ns := cr.Spec.ForProvider.Namespace
+		conflicts, err := conflicting(ctx, e.kube, e.kube.RESTMapper(), ns, rel, r.Manifest)
+		if err != nil {
+			return nil, err
+		}
+		if len(conflicts) == 0 {
+			cr.Status.SetConditions(v1beta1.NoConflicts())
+			return action(rel, ch, vals, patches)
+		}
+
+		keys := make([]string, len(conflicts))
+		for i := range conflicts {
+			keys[i] = resourceKey(conflicts[i])
+		}
+
+		switch e.params(cr).ConflictPolicy {
+		case v1beta1.ConflictPolicyAdopt:
+			for _, o := range conflicts {
+				if err := adopt(ctx, e.kube, o, rel, ns); err != nil {
+					return nil, errors.Wrap(err, errFailedToAdoptResource)
+				}
+			}
+			cr.Status.SetConditions(v1beta1.ConflictsResolved(v1beta1.ReasonResourcesAdopted, keys))
+		case v1beta1.ConflictPolicyForce:
+			for _, o := range conflicts {
+				if err := e.kube.Delete(ctx, o); resource.IgnoreNotFound(err) != nil {
+					return nil, errors.Wrap(err, errFailedToReplaceResource)
+				}
+			}
+			cr.Status.SetConditions(v1beta1.ConflictsResolved(v1beta1.ReasonResourcesReplaced, keys))
+		default:
+			err := errors.Errorf(errResourcesConflict, strings.Join(keys, ", "))
+			cr.Status.SetConditions(v1beta1.ConflictDetected(err))
+			return nil, err
+		}
+		return action(rel, ch, vals, patches)
+	}
+}
+
+// conflicting returns the existing resources of the supplied manifest that
+// are not part of the release.
+func conflicting(ctx context.Context, kube client.Client, mapper meta.RESTMapper, namespace, rel, manifest string) ([]*unstructured.Unstructured, error) {
+	objs, err := parseManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*unstructured.Unstructured
+	for i := range objs {
+		o := &objs[i]
+		err := defaultNamespace(mapper, o, namespace)
+		// Resources of kinds that do not exist yet can not conflict.
+		if meta.IsNoMatchError(errors.Cause(err)) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(o.GroupVersionKind())
+		err = kube.Get(ctx, types.NamespacedName{Name: o.GetName(), Namespace: o.GetNamespace()}, live)
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errFailedToGetExistingObject)
+		}
+		if !partOfRelease(*live, rel, namespace) {
+			res = append(res, live)
+		}
+	}
+	return res, nil
+}
+
+// adopt adds the Helm ownership metadata of the supplied release to an
+// existing resource, so that Helm imports it into the release.
+func adopt(ctx context.Context, kube client.Client, o *unstructured.Unstructured, rel, namespace string) error {
+	p := client.MergeFrom(o.DeepCopy())
+	l := o.GetLabels()
+	if l == nil {
+		l = map[string]string{}
+	}
+	l[helmNamespaceLabel] = helmManagedBy
+	o.SetLabels(l)
+	a := o.GetAnnotations()
+	if a == nil {
+		a = map[string]string{}
+	}
+	a[helmReleaseNameAnnotation] = rel
+	a[helmReleaseNamespaceAnnotation] = namespace
+	o.SetAnnotations(a)
+	return kube.Patch(ctx, o, p)
+}
@@ -0,0 +1,139 @@
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/kustomize/api/types"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+// mapperClient is a mock client with a working REST mapper.
+type mapperClient struct {
+	*test.MockClient
+}
+
+func (c *mapperClient) RESTMapper() meta.RESTMapper {
+	return testRESTMapper()
+}
+
+func Test_conflictsResolved(t *testing.T) {
+	const manifest = "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: owned\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: foreign\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: new\n"
+
+	get := func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+		u := obj.(*unstructured.Unstructured)
+		u.SetName(key.Name)
+		u.SetNamespace(key.Namespace)
+		switch key.Name {
+		case "owned":
+			u.SetAnnotations(map[string]string{
+				helmReleaseNameAnnotation:      testReleaseName,
+				helmReleaseNamespaceAnnotation: testNamespace,
+			})
+		case "foreign":
+			u.SetAnnotations(map[string]string{
+				helmReleaseNameAnnotation:      "other",
+				helmReleaseNamespaceAnnotation: testNamespace,
+			})
+		default:
+			return kerrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, key.Name)
+		}
+		return nil
+	}
+
+	type want struct {
+		err     error
+		reason  string
+		called  bool
+		patched []string
+		deleted []string
+	}
+	cases := map[string]struct {
+		policy v1beta1.ConflictPolicy
+		want   want
+	}{
+		"Fail": {
+			policy: v1beta1.ConflictPolicyFail,
+			want: want{
+				err:    errors.Errorf(errResourcesConflict, "ConfigMap "+testNamespace+"/foreign"),
+				reason: string(v1beta1.ReasonConflictDetected),
+			},
+		},
+		"Adopt": {
+			policy: v1beta1.ConflictPolicyAdopt,
+			want: want{
+				reason:  string(v1beta1.ReasonResourcesAdopted),
+				called:  true,
+				patched: []string{"foreign"},
+			},
+		},
+		"Force": {
+			policy: v1beta1.ConflictPolicyForce,
+			want: want{
+				reason:  string(v1beta1.ReasonResourcesReplaced),
+				called:  true,
+				deleted: []string{"foreign"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var patched, deleted []string
+			kube := &mapperClient{MockClient: &test.MockClient{
+				MockGet: get,
+				MockPatch: func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+					if obj.GetAnnotations()[helmReleaseNameAnnotation] != testReleaseName || obj.GetLabels()[helmNamespaceLabel] != helmManagedBy {
+						return errBoom
+					}
+					patched = append(patched, obj.GetName())
+					return nil
+				},
+				MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+					deleted = append(deleted, obj.GetName())
+					return nil
+				},
+			}}
+			cr := helmRelease(func(r *v1beta1.Release) {
+				r.Spec.ForProvider.Namespace = testNamespace
+				r.Spec.ForProvider.ConflictPolicy = tc.policy
+			})
+			called := false
+			e := &helmExternal{kube: kube, helm: &MockHelmClient{
+				MockTemplate: func(string, *chart.Chart, map[string]interface{}, []types.Patch) (*release.Release, error) {
+					return &release.Release{Manifest: manifest}, nil
+				},
+			}}
+			action := e.conflictsResolved(context.Background(), cr, e.helm.Template, func(string, *chart.Chart, map[string]interface{}, []types.Patch) (*release.Release, error) {
+				called = true
+				return &release.Release{}, nil
+			})
+			_, err := action(testReleaseName, &chart.Chart{}, nil, nil)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("conflictsResolved(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.called, called); diff != "" {
+				t.Errorf("conflictsResolved(...): -want called, +got called: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.reason, string(cr.Status.GetCondition(v1beta1.TypeConflictFree).Reason)); diff != "" {
+				t.Errorf("conflictsResolved(...): -want reason, +got reason: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.patched, patched); diff != "" {
+				t.Errorf("conflictsResolved(...): -want patched, +got patched: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.deleted, deleted); diff != "" {
+				t.Errorf("conflictsResolved(...): -want deleted, +got deleted: %s", diff)
+			}
+		})
+	}
+}
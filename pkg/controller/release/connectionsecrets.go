@@ -0,0 +1,129 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	errApplyConnectionSecret  = "cannot apply additional connection secret %s/%s"
+	errGetConnectionSecret    = "cannot get additional connection secret %s/%s"
+	errDeleteConnectionSecret = "cannot delete additional connection secret %s/%s"
+)
+
+// A secretsPublisher writes subsets of the connection details of Releases to
+// their AdditionalConnectionSecrets.
+type secretsPublisher struct {
+	kube   client.Client
+	secret resource.Applicator
+}
+
+func newSecretsPublisher(kube client.Client) *secretsPublisher {
+	return &secretsPublisher{kube: kube, secret: resource.NewAPIPatchingApplicator(kube)}
+}
+
+// PublishConnection writes the supplied connection details to the additional
+// connection secrets of the supplied Release.
+func (p *secretsPublisher) PublishConnection(ctx context.Context, mg resource.Managed, c managed.ConnectionDetails) error {
+	cr, ok := mg.(*v1beta1.Release)
+	if !ok {
+		return errors.New(errNotRelease)
+	}
+	// Connection details are empty until the release is deployed; they
+	// should not replace the ones published before.
+	if len(c) == 0 {
+		return nil
+	}
+	for _, cs := range cr.Spec.AdditionalConnectionSecrets {
+		s := connectionSecret(cr, cs, c)
+		if err := p.secret.Apply(ctx, s, resource.ConnectionSecretMustBeControllableBy(cr.GetUID())); err != nil {
+			return errors.Wrapf(err, errApplyConnectionSecret, cs.Namespace, cs.Name)
+		}
+	}
+	return nil
+}
+
+// UnpublishConnection deletes the additional connection secrets of the
+// supplied Release. Secrets it does not control are left in place.
+func (p *secretsPublisher) UnpublishConnection(ctx context.Context, mg resource.Managed, _ managed.ConnectionDetails) error {
+	cr, ok := mg.(*v1beta1.Release)
+	if !ok {
+		return errors.New(errNotRelease)
+	}
+	for _, cs := range cr.Spec.AdditionalConnectionSecrets {
+		s := &corev1.Secret{}
+		if err := p.kube.Get(ctx, types.NamespacedName{Namespace: cs.Namespace, Name: cs.Name}, s); err != nil {
+			if resource.IgnoreNotFound(err) != nil {
+				return errors.Wrapf(err, errGetConnectionSecret, cs.Namespace, cs.Name)
+			}
+			continue
+		}
+		if !metav1.IsControlledBy(s, cr) {
+			continue
+		}
+		if err := resource.IgnoreNotFound(p.kube.Delete(ctx, s)); err != nil {
+			return errors.Wrapf(err, errDeleteConnectionSecret, cs.Namespace, cs.Name)
+		}
+	}
+	return nil
+}
+
+// connectionSecret returns the supplied additional connection secret of the
+// supplied Release, containing the selected keys of the supplied connection
+// details. Selected keys that are missing from the connection details are
+// omitted.
+func connectionSecret(cr *v1beta1.Release, cs v1beta1.ConnectionSecret, c managed.ConnectionDetails) *corev1.Secret {
+	data := c
+	if len(cs.Keys) > 0 {
+		data = make(managed.ConnectionDetails, len(cs.Keys))
+		for _, k := range cs.Keys {
+			if v, ok := c[k]; ok {
+				data[k] = v
+			}
+		}
+	}
+	s := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            cs.Name,
+			Namespace:       cs.Namespace,
+			OwnerReferences: []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(cr, v1beta1.ReleaseGroupVersionKind))},
+		},
+		Type: resource.SecretTypeConnection,
+		Data: data,
+	}
+	if md := cs.Metadata; md != nil {
+		s.SetLabels(md.Labels)
+		s.SetAnnotations(md.Annotations)
+		if md.Type != nil {
+			s.Type = *md.Type
+		}
+	}
+	return s
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+func TestSecretsPublisher(t *testing.T) {
+	errBoom := errors.New("boom")
+	details := managed.ConnectionDetails{"username": []byte("app"), "password": []byte("12345"), "adminPassword": []byte("admin")}
+
+	secret := func(namespace, name string, data managed.ConnectionDetails) *corev1.Secret {
+		s := &corev1.Secret{Type: resource.SecretTypeConnection, Data: data}
+		s.SetName(name)
+		s.SetNamespace(namespace)
+		return s
+	}
+
+	type want struct {
+		err     error
+		applied []*corev1.Secret
+		deleted []*corev1.Secret
+	}
+	cases := map[string]struct {
+		reason    string
+		secrets   []v1beta1.ConnectionSecret
+		unpublish bool
+		details   managed.ConnectionDetails
+		apply     resource.ApplyFn
+		get       test.MockGetFn
+		delete    test.MockDeleteFn
+		want      want
+	}{
+		"NoDetails": {
+			reason:  "Empty connection details should not replace published ones.",
+			secrets: []v1beta1.ConnectionSecret{{Name: "app", Namespace: "team-a"}},
+			details: managed.ConnectionDetails{},
+		},
+		"Subsets": {
+			reason: "The selected keys of the connection details should be written to each Secret.",
+			secrets: []v1beta1.ConnectionSecret{
+				{Name: "app", Namespace: "team-a", Keys: []string{"username", "password", "missing"}},
+				{Name: "admin", Namespace: "platform", Keys: []string{"adminPassword"}},
+				{Name: "all", Namespace: "platform"},
+			},
+			details: details,
+			want: want{
+				applied: []*corev1.Secret{
+					secret("team-a", "app", managed.ConnectionDetails{"username": []byte("app"), "password": []byte("12345")}),
+					secret("platform", "admin", managed.ConnectionDetails{"adminPassword": []byte("admin")}),
+					secret("platform", "all", details),
+				},
+			},
+		},
+		"Metadata": {
+			reason: "The metadata of a Secret should be applied.",
+			secrets: []v1beta1.ConnectionSecret{{
+				Name:      "app",
+				Namespace: "team-a",
+				Keys:      []string{"password"},
+				Metadata:  &v1beta1.ConnectionSecretMetadata{Labels: map[string]string{"team": "a"}},
+			}},
+			details: details,
+			want: want{
+				applied: []*corev1.Secret{func() *corev1.Secret {
+					s := secret("team-a", "app", managed.ConnectionDetails{"password": []byte("12345")})
+					s.SetLabels(map[string]string{"team": "a"})
+					return s
+				}()},
+			},
+		},
+		"ApplyError": {
+			reason:  "Errors applying a Secret should be returned.",
+			secrets: []v1beta1.ConnectionSecret{{Name: "app", Namespace: "team-a"}},
+			details: details,
+			apply: func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+				return errBoom
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errApplyConnectionSecret, "team-a", "app"),
+			},
+		},
+		"Unpublish": {
+			reason:    "All additional connection secrets the Release controls should be deleted when unpublished.",
+			secrets:   []v1beta1.ConnectionSecret{{Name: "app", Namespace: "team-a"}, {Name: "admin", Namespace: "platform"}},
+			unpublish: true,
+			delete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+				if obj.GetName() == "admin" {
+					return kerrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, obj.GetName())
+				}
+				return nil
+			},
+			want: want{
+				deleted: []*corev1.Secret{
+					func() *corev1.Secret { s := &corev1.Secret{}; s.SetName("app"); s.SetNamespace("team-a"); return s }(),
+					func() *corev1.Secret { s := &corev1.Secret{}; s.SetName("admin"); s.SetNamespace("platform"); return s }(),
+				},
+			},
+		},
+		"UnpublishNotControlled": {
+			reason:    "Secrets the Release does not control should not be deleted.",
+			secrets:   []v1beta1.ConnectionSecret{{Name: "app", Namespace: "team-a"}},
+			unpublish: true,
+			get: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+				obj.SetOwnerReferences([]metav1.OwnerReference{{UID: "other", Controller: pointer.Bool(true)}})
+				return nil
+			},
+		},
+		"UnpublishNotFound": {
+			reason:    "Secrets that don't exist should be skipped.",
+			secrets:   []v1beta1.ConnectionSecret{{Name: "app", Namespace: "team-a"}},
+			unpublish: true,
+			get:       test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "app")),
+		},
+		"UnpublishGetError": {
+			reason:    "Errors getting a Secret should be returned.",
+			secrets:   []v1beta1.ConnectionSecret{{Name: "app", Namespace: "team-a"}},
+			unpublish: true,
+			get:       test.NewMockGetFn(errBoom),
+			want: want{
+				err: errors.Wrapf(errBoom, errGetConnectionSecret, "team-a", "app"),
+			},
+		},
+		"UnpublishError": {
+			reason:    "Errors deleting a Secret should be returned.",
+			secrets:   []v1beta1.ConnectionSecret{{Name: "app", Namespace: "team-a"}},
+			unpublish: true,
+			delete:    test.NewMockDeleteFn(errBoom),
+			want: want{
+				err: errors.Wrapf(errBoom, errDeleteConnectionSecret, "team-a", "app"),
+				deleted: []*corev1.Secret{
+					func() *corev1.Secret { s := &corev1.Secret{}; s.SetName("app"); s.SetNamespace("team-a"); return s }(),
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var applied, deleted []*corev1.Secret
+			p := &secretsPublisher{
+				kube: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						obj.SetName(key.Name)
+						obj.SetNamespace(key.Namespace)
+						obj.SetOwnerReferences([]metav1.OwnerReference{{UID: "uid", Controller: pointer.Bool(true)}})
+						if tc.get != nil {
+							return tc.get(ctx, key, obj)
+						}
+						return nil
+					},
+					MockDelete: func(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+						s := obj.(*corev1.Secret).DeepCopy()
+						s.OwnerReferences = nil
+						deleted = append(deleted, s)
+						if tc.delete != nil {
+							return tc.delete(ctx, obj, opts...)
+						}
+						return nil
+					},
+				},
+				secret: resource.ApplyFn(func(ctx context.Context, obj client.Object, opts ...resource.ApplyOption) error {
+					s := obj.(*corev1.Secret)
+					if tc.apply != nil {
+						return tc.apply(ctx, obj, opts...)
+					}
+					s.OwnerReferences = nil
+					applied = append(applied, s)
+					return nil
+				}),
+			}
+			cr := &v1beta1.Release{
+				ObjectMeta: metav1.ObjectMeta{UID: "uid"},
+				Spec:       v1beta1.ReleaseSpec{AdditionalConnectionSecrets: tc.secrets},
+			}
+
+			var err error
+			if tc.unpublish {
+				err = p.UnpublishConnection(context.Background(), cr, nil)
+			} else {
+				err = p.PublishConnection(context.Background(), cr, tc.details)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\n-want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.applied, applied); diff != "" {
+				t.Errorf("\n%s\n-want applied, +got applied:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.deleted, deleted); diff != "" {
+				t.Errorf("\n%s\n-want deleted, +got deleted:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/release"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	errFailedToParseChartCRDs = "failed to parse CRDs of chart"
+	errFailedToGetCRD         = "failed to get CRD"
+)
+
+var crdGroupKind = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+
+// observeCRDs checks whether the CRDs installed by the release are
+// established and returns whether the Release may report Ready. CRDs that are
+// not established yet are reported in the CRDsEstablished condition.
+func (e *helmExternal) observeCRDs(ctx context.Context, cr *v1beta1.Release, rel *release.Release) (bool, error) {
+	if !cr.Spec.ForProvider.WaitForCRDs {
+		return true, nil
+	}
+
+	crds, err := releaseCRDs(rel)
+	if err != nil {
+		return false, err
+	}
+	u, err := unestablishedCRDs(ctx, e.kube, e.kube.RESTMapper(), crds)
+	if err != nil {
+		return false, err
+	}
+	if len(u) > 0 {
+		cr.Status.SetConditions(v1beta1.CRDsNotEstablished(u))
+		return false, nil
+	}
+	cr.Status.SetConditions(v1beta1.CRDsEstablished())
+	return true, nil
+}
+
+// releaseCRDs returns the CRDs of the crds directory of the chart as well as
+// the CRDs rendered from its templates.
+func releaseCRDs(rel *release.Release) ([]unstructured.Unstructured, error) {
+	var crds []unstructured.Unstructured
+	if rel.Chart != nil {
+		for _, f := range rel.Chart.CRDObjects() {
+			objs, err := parseManifest(string(f.File.Data))
+			if err != nil {
+				return nil, errors.Wrap(err, errFailedToParseChartCRDs)
+			}
+			crds = append(crds, objs...)
+		}
+	}
+
+	objs, err := parseManifest(rel.Manifest)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range objs {
+		if o.GroupVersionKind().GroupKind() == crdGroupKind {
+			crds = append(crds, o)
+		}
+	}
+	return crds, nil
+}
+
+// unestablishedCRDs returns the names of the supplied CRDs that are not
+// established yet or whose kinds are not served by the REST mapper yet.
+// Looking up the kinds refreshes a dynamic REST mapper, so that custom
+// resources of the CRDs can be mapped once they are established.
+func unestablishedCRDs(ctx context.Context, kube client.Client, mapper meta.RESTMapper, crds []unstructured.Unstructured) ([]string, error) {
+	var res []string
+	for _, crd := range crds {
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(crd.GroupVersionKind())
+		err := kube.Get(ctx, types.NamespacedName{Name: crd.GetName()}, live)
+		if kerrors.IsNotFound(err) {
+			res = append(res, crd.GetName())
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errFailedToGetCRD)
+		}
+		if !conditionIs(live, "Established", "True") {
+			res = append(res, crd.GetName())
+			continue
+		}
+
+		g, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+		k, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+		if _, err := mapper.RESTMapping(schema.GroupKind{Group: g, Kind: k}, crdVersions(crd)...); err != nil {
+			res = append(res, crd.GetName())
+		}
+	}
+	return res, nil
+}
+
+func crdVersions(crd unstructured.Unstructured) []string {
+	vs, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	res := make([]string, 0, len(vs))
+	for _, v := range vs {
+		if m, ok := v.(map[string]interface{}); ok {
+			if n, ok := m["name"].(string); ok {
+				res = append(res, n)
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,89 @@
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func testCRD(name, group, kind string) string {
+	return "apiVersion: apiextensions.k8s.io/v1\nkind: CustomResourceDefinition\nmetadata:\n  name: " + name +
+		"\nspec:\n  group: " + group + "\n  names:\n    kind: " + kind + "\n  versions:\n  - name: v1\n"
+}
+
+func Test_releaseCRDs(t *testing.T) {
+	rel := &release.Release{
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "test"},
+			Files: []*chart.File{
+				{Name: "crds/databases.yaml", Data: []byte(testCRD("databases.example.org", "example.org", "Database"))},
+				{Name: "README.md", Data: []byte("# readme")},
+			},
+		},
+		Manifest: testManifest + "---\n" + testCRD("caches.example.org", "example.org", "Cache"),
+	}
+	crds, err := releaseCRDs(rel)
+	if err != nil {
+		t.Fatalf("releaseCRDs(...): unexpected error: %s", err)
+	}
+	got := make([]string, 0, len(crds))
+	for _, c := range crds {
+		got = append(got, c.GetName())
+	}
+	if diff := cmp.Diff([]string{"databases.example.org", "caches.example.org"}, got); diff != "" {
+		t.Errorf("releaseCRDs(...): -want, +got: %s", diff)
+	}
+}
+
+func Test_unestablishedCRDs(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Database"}, meta.RESTScopeNamespace)
+
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			u := obj.(*unstructured.Unstructured)
+			switch key.Name {
+			case "missing.example.org":
+				return kerrors.NewNotFound(schema.GroupResource{Resource: "customresourcedefinitions"}, key.Name)
+			case "pending.example.org":
+				u.Object["status"] = map[string]interface{}{"conditions": testConditions("Established", "False")}
+			default:
+				u.Object["status"] = map[string]interface{}{"conditions": testConditions("Established", "True")}
+			}
+			return nil
+		},
+	}
+
+	var crds []unstructured.Unstructured
+	for _, m := range []string{
+		testCRD("databases.example.org", "example.org", "Database"),
+		testCRD("missing.example.org", "example.org", "Missing"),
+		testCRD("pending.example.org", "example.org", "Pending"),
+		testCRD("unmapped.example.org", "example.org", "Unmapped"),
+	} {
+		objs, err := parseManifest(m)
+		if err != nil {
+			t.Fatalf("parseManifest(...): unexpected error: %s", err)
+		}
+		crds = append(crds, objs...)
+	}
+
+	got, err := unestablishedCRDs(context.Background(), kube, mapper, crds)
+	if err != nil {
+		t.Fatalf("unestablishedCRDs(...): unexpected error: %s", err)
+	}
+	want := []string{"missing.example.org", "pending.example.org", "unmapped.example.org"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unestablishedCRDs(...): -want, +got: %s", diff)
+	}
+}
@@ -18,15 +18,18 @@ package release
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+	"github.com/crossplane-contrib/provider-helm/pkg/clients/valuesource"
 )
 
 const (
@@ -39,9 +42,25 @@ const (
 	errFailedToGetDataFromSecretRef    = "failed to get data from secret ref"
 	errFailedToGetDataFromConfigMapRef = "failed to get data from configmap ref"
 	errMissingKeyForValuesFrom         = "missing key \"%s\" in values from source"
+	errFailedToDecodePluginConfig      = "failed to decode value source plugin config"
+	errFailedToGetDataFromPlugin       = "failed to get data from value source plugin \"%s\""
+	errMissingDataFromPlugin           = "value source plugin \"%s\" has no data for key \"%s\""
 )
 
+// valuePlugins read data from value source plugins by name.
+type valuePlugins interface {
+	GetValues(ctx context.Context, plugin string, r valuesource.Request) (string, bool, error)
+}
+
 func getSecretData(ctx context.Context, kube client.Client, nn types.NamespacedName) (map[string][]byte, error) {
+	s, err := getSecret(ctx, kube, nn)
+	if err != nil {
+		return nil, err
+	}
+	return s.Data, nil
+}
+
+func getSecret(ctx context.Context, kube client.Client, nn types.NamespacedName) (*corev1.Secret, error) {
 	s := &corev1.Secret{}
 	if err := kube.Get(ctx, nn, s); err != nil {
 		return nil, errors.Wrap(err, fmt.Sprintf(errFailedToGetSecret, nn.Namespace))
@@ -49,24 +68,49 @@ func getSecretData(ctx context.Context, kube client.Client, nn types.NamespacedN
 	if s.Data == nil {
 		return nil, errors.New(errSecretDataIsNil)
 	}
-	return s.Data, nil
+	return s, nil
 }
 
 func getConfigMapData(ctx context.Context, kube client.Client, nn types.NamespacedName) (map[string]string, error) {
+	cm, err := getConfigMap(ctx, kube, nn)
+	if err != nil {
+		return nil, err
+	}
+	d := make(map[string]string, len(cm.Data)+len(cm.BinaryData))
+	for k, v := range cm.BinaryData {
+		d[k] = string(v)
+	}
+	for k, v := range cm.Data {
+		d[k] = v
+	}
+	return d, nil
+}
+
+func getConfigMap(ctx context.Context, kube client.Client, nn types.NamespacedName) (*corev1.ConfigMap, error) {
 	cm := &corev1.ConfigMap{}
 	if err := kube.Get(ctx, nn, cm); err != nil {
 		return nil, errors.Wrap(err, fmt.Sprintf(errFailedToGetConfigMap, nn.Namespace))
 	}
-	if cm.Data == nil {
+	if cm.Data == nil && cm.BinaryData == nil {
 		return nil, errors.New(errConfigMapDataIsNil)
 	}
-	return cm.Data, nil
+	return cm, nil
 }
 
-func getDataValueFromSource(ctx context.Context, kube client.Client, source v1beta1.ValueFromSource, defaultKey string) (string, error) { // nolint:gocyclo
+// getDataValue returns the supplied value of a ConfigMap or Secret,
+// decompressed if the provider wrote it compressed.
+func getDataValue(o metav1.Object, v []byte) (string, error) {
+	if !isCompressed(o) {
+		return string(v), nil
+	}
+	d, err := decompress(v)
+	return string(d), err
+}
+
+func getDataValueFromSource(ctx context.Context, kube client.Client, plugins valuePlugins, source v1beta1.ValueFromSource, defaultKey string) (string, error) { // nolint:gocyclo
 	if source.SecretKeyRef != nil {
 		r := source.SecretKeyRef
-		d, err := getSecretData(ctx, kube, types.NamespacedName{Name: r.Name, Namespace: r.Namespace})
+		s, err := getSecret(ctx, kube, types.NamespacedName{Name: r.Name, Namespace: r.Namespace})
 		if kerrors.IsNotFound(errors.Cause(err)) && !r.Optional {
 			return "", errors.Wrap(err, errFailedToGetDataFromSecretRef)
 		}
@@ -77,15 +121,18 @@ func getDataValueFromSource(ctx context.Context, kube client.Client, source v1be
 		if r.Key != "" {
 			k = r.Key
 		}
-		valBytes, ok := d[k]
+		if s == nil {
+			return "", nil
+		}
+		valBytes, ok := s.Data[k]
 		if !ok && !r.Optional {
 			return "", errors.New(fmt.Sprintf(errMissingKeyForValuesFrom, k))
 		}
-		return string(valBytes), nil
+		return getDataValue(s, valBytes)
 	}
 	if source.ConfigMapKeyRef != nil {
 		r := source.ConfigMapKeyRef
-		d, err := getConfigMapData(ctx, kube, types.NamespacedName{Name: r.Name, Namespace: r.Namespace})
+		cm, err := getConfigMap(ctx, kube, types.NamespacedName{Name: r.Name, Namespace: r.Namespace})
 		if kerrors.IsNotFound(errors.Cause(err)) && !r.Optional {
 			return "", errors.Wrap(err, errFailedToGetDataFromConfigMapRef)
 		}
@@ -96,11 +143,39 @@ func getDataValueFromSource(ctx context.Context, kube client.Client, source v1be
 		if r.Key != "" {
 			k = r.Key
 		}
-		valString, ok := d[k]
+		if cm == nil {
+			return "", nil
+		}
+		if v, ok := cm.Data[k]; ok {
+			return v, nil
+		}
+		v, ok := cm.BinaryData[k]
 		if !ok && !r.Optional {
 			return "", errors.New(fmt.Sprintf(errMissingKeyForValuesFrom, k))
 		}
-		return valString, nil
+		return getDataValue(cm, v)
+	}
+	if source.Plugin != nil {
+		return getDataFromPlugin(ctx, plugins, source.Plugin, defaultKey)
 	}
 	return "", errors.New(errSourceNotSetForValueFrom)
 }
+
+// getDataFromPlugin reads the data of the supplied key from the supplied
+// value source plugin.
+func getDataFromPlugin(ctx context.Context, plugins valuePlugins, p *v1beta1.PluginValueSource, key string) (string, error) {
+	var cfg map[string]interface{}
+	if len(p.Config.Raw) > 0 {
+		if err := json.Unmarshal(p.Config.Raw, &cfg); err != nil {
+			return "", errors.Wrap(err, errFailedToDecodePluginConfig)
+		}
+	}
+	d, ok, err := plugins.GetValues(ctx, p.Name, valuesource.Request{Config: cfg, Key: key})
+	if err != nil {
+		return "", errors.Wrap(err, fmt.Sprintf(errFailedToGetDataFromPlugin, p.Name))
+	}
+	if !ok && !p.Optional {
+		return "", errors.New(fmt.Sprintf(errMissingDataFromPlugin, p.Name, key))
+	}
+	return d, nil
+}
@@ -10,11 +10,14 @@ import (
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+	"github.com/crossplane-contrib/provider-helm/pkg/clients/valuesource"
 )
 
 const (
@@ -221,6 +224,8 @@ func Test_getConfigMapData(t *testing.T) {
 }
 
 func Test_getDataValueFromSource(t *testing.T) {
+	compressed, _ := compress([]byte("ok"))
+
 	type args struct {
 		kube       client.Client
 		source     v1beta1.ValueFromSource
@@ -644,10 +649,84 @@ func Test_getDataValueFromSource(t *testing.T) {
 				err: nil,
 			},
 		},
+		"SuccessCompressedCMBinaryData": {
+			args: args{
+				kube: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						*obj.(*corev1.ConfigMap) = corev1.ConfigMap{
+							ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{compressionAnnotation: compressionGzip}},
+							BinaryData: map[string][]byte{"manifest": compressed},
+						}
+						return nil
+					},
+				},
+				source: v1beta1.ValueFromSource{
+					ConfigMapKeyRef: &v1beta1.DataKeySelector{
+						NamespacedName: v1beta1.NamespacedName{
+							Namespace: testNamespace,
+							Name:      testCMName,
+						},
+						Key: "manifest",
+					},
+				},
+			},
+			want: want{
+				out: "ok",
+			},
+		},
+		"SuccessCompressedSecret": {
+			args: args{
+				kube: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						*obj.(*corev1.Secret) = corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{compressionAnnotation: compressionGzip}},
+							Data:       map[string][]byte{"manifest": compressed},
+						}
+						return nil
+					},
+				},
+				source: v1beta1.ValueFromSource{
+					SecretKeyRef: &v1beta1.DataKeySelector{
+						NamespacedName: v1beta1.NamespacedName{
+							Namespace: testNamespace,
+							Name:      testSecretName,
+						},
+						Key: "manifest",
+					},
+				},
+			},
+			want: want{
+				out: "ok",
+			},
+		},
+		"CompressedSecretNotWrittenByProvider": {
+			args: args{
+				kube: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						*obj.(*corev1.Secret) = corev1.Secret{
+							Data: map[string][]byte{"manifest": compressed},
+						}
+						return nil
+					},
+				},
+				source: v1beta1.ValueFromSource{
+					SecretKeyRef: &v1beta1.DataKeySelector{
+						NamespacedName: v1beta1.NamespacedName{
+							Namespace: testNamespace,
+							Name:      testSecretName,
+						},
+						Key: "manifest",
+					},
+				},
+			},
+			want: want{
+				out: string(compressed),
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			got, gotErr := getDataValueFromSource(context.Background(), tc.args.kube, tc.args.source, tc.args.defaultKey)
+			got, gotErr := getDataValueFromSource(context.Background(), tc.args.kube, nil, tc.args.source, tc.args.defaultKey)
 			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
 				t.Fatalf("getDataValueFromSource(...): -want error, +got error: %s", diff)
 			}
@@ -658,3 +737,85 @@ func Test_getDataValueFromSource(t *testing.T) {
 	}
 
 }
+
+// fakePlugins return the data of value source plugins.
+type fakePlugins func(ctx context.Context, plugin string, r valuesource.Request) (string, bool, error)
+
+func (f fakePlugins) GetValues(ctx context.Context, plugin string, r valuesource.Request) (string, bool, error) {
+	return f(ctx, plugin, r)
+}
+
+func Test_getDataFromPlugin(t *testing.T) {
+	type args struct {
+		source *v1beta1.PluginValueSource
+		get    fakePlugins
+	}
+	type want struct {
+		out string
+		err error
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"Success": {
+			args: args{
+				source: &v1beta1.PluginValueSource{
+					Name:   "cmdb",
+					Config: runtime.RawExtension{Raw: []byte(`{"path":"apps/web"}`)},
+				},
+				get: func(_ context.Context, plugin string, r valuesource.Request) (string, bool, error) {
+					if plugin != "cmdb" || r.Key != keyDefaultValuesFrom || r.Config["path"] != "apps/web" {
+						return "", false, errBoom
+					}
+					return "ok", true, nil
+				},
+			},
+			want: want{out: "ok"},
+		},
+		"ErrWhileDecodingConfig": {
+			args: args{
+				source: &v1beta1.PluginValueSource{Name: "cmdb", Config: runtime.RawExtension{Raw: []byte(`[]`)}},
+			},
+			want: want{err: errors.Wrap(errors.New("json: cannot unmarshal array into Go value of type map[string]interface {}"), errFailedToDecodePluginConfig)},
+		},
+		"ErrWhileGettingData": {
+			args: args{
+				source: &v1beta1.PluginValueSource{Name: "cmdb"},
+				get: func(_ context.Context, _ string, _ valuesource.Request) (string, bool, error) {
+					return "", false, errBoom
+				},
+			},
+			want: want{err: errors.Wrap(errBoom, fmt.Sprintf(errFailedToGetDataFromPlugin, "cmdb"))},
+		},
+		"MissingData": {
+			args: args{
+				source: &v1beta1.PluginValueSource{Name: "cmdb"},
+				get: func(_ context.Context, _ string, _ valuesource.Request) (string, bool, error) {
+					return "", false, nil
+				},
+			},
+			want: want{err: errors.New(fmt.Sprintf(errMissingDataFromPlugin, "cmdb", keyDefaultValuesFrom))},
+		},
+		"MissingOptionalData": {
+			args: args{
+				source: &v1beta1.PluginValueSource{Name: "cmdb", Optional: true},
+				get: func(_ context.Context, _ string, _ valuesource.Request) (string, bool, error) {
+					return "", false, nil
+				},
+			},
+			want: want{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, gotErr := getDataFromPlugin(context.Background(), tc.args.get, tc.args.source, keyDefaultValuesFrom)
+			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
+				t.Fatalf("getDataFromPlugin(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.out, got); diff != "" {
+				t.Errorf("getDataFromPlugin(...): -want result, +got result: %s", diff)
+			}
+		})
+	}
+}
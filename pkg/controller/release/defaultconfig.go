@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+	helmv1beta1 "github.com/crossplane-contrib/provider-helm/apis/v1beta1"
+)
+
+// defaultProviderConfigName is the ProviderConfig Releases reference unless
+// they specify another one.
+const defaultProviderConfigName = "default"
+
+const (
+	errListProviderConfigs     = "cannot list ProviderConfigs"
+	errAmbiguousDefault        = "Release is selected by more than one ProviderConfig: %s"
+	errInvalidReleaseSelector  = "invalid release selector of ProviderConfig %s"
+	errUpdateProviderConfigRef = "cannot update ProviderConfig reference"
+)
+
+// selectProviderConfig updates the reference of a Release that references
+// the default ProviderConfig to the ProviderConfig that selects it, if any.
+func selectProviderConfig(ctx context.Context, kube client.Client, cr *v1beta1.Release) error {
+	if ref := cr.GetProviderConfigReference(); ref == nil || ref.Name != defaultProviderConfigName {
+		return nil
+	}
+
+	pcs := &helmv1beta1.ProviderConfigList{}
+	if err := kube.List(ctx, pcs); err != nil {
+		return errors.Wrap(err, errListProviderConfigs)
+	}
+	var names []string
+	for _, pc := range pcs.Items {
+		ok, err := selects(pc.Spec.DefaultFor, cr)
+		if err != nil {
+			return errors.Wrapf(err, errInvalidReleaseSelector, pc.GetName())
+		}
+		if ok {
+			names = append(names, pc.GetName())
+		}
+	}
+
+	switch len(names) {
+	case 0:
+		return nil
+	case 1:
+	default:
+		return errors.Errorf(errAmbiguousDefault, strings.Join(names, ", "))
+	}
+
+	cr.SetProviderConfigReference(&xpv1.Reference{Name: names[0]})
+	return errors.Wrap(kube.Update(ctx, cr), errUpdateProviderConfigRef)
+}
+
+// selects returns true if the supplied Release matches all criteria that are
+// set. Nothing is selected if no criteria are set.
+func selects(d *helmv1beta1.DefaultFor, cr *v1beta1.Release) (bool, error) {
+	if d == nil || (len(d.Namespaces) == 0 && d.ReleaseSelector == nil) {
+		return false, nil
+	}
+	if len(d.Namespaces) > 0 && !matchesAny(cr.Spec.ForProvider.Namespace, d.Namespaces) {
+		return false, nil
+	}
+	if d.ReleaseSelector == nil {
+		return true, nil
+	}
+	s, err := metav1.LabelSelectorAsSelector(d.ReleaseSelector)
+	if err != nil {
+		return false, err
+	}
+	return s.Matches(labels.Set(cr.GetLabels())), nil
+}
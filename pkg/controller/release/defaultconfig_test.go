@@ -0,0 +1,134 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+	helmv1beta1 "github.com/crossplane-contrib/provider-helm/apis/v1beta1"
+)
+
+func TestSelectProviderConfig(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	pc := func(name string, d *helmv1beta1.DefaultFor) helmv1beta1.ProviderConfig {
+		return helmv1beta1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: helmv1beta1.ProviderConfigSpec{DefaultFor: d}}
+	}
+	tenant := pc("tenant", &helmv1beta1.DefaultFor{Namespaces: []string{"team-*"}})
+	prod := pc("prod", &helmv1beta1.DefaultFor{ReleaseSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}})
+	tenantProd := pc("tenant-prod", &helmv1beta1.DefaultFor{
+		Namespaces:      []string{"team-*"},
+		ReleaseSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+	})
+	none := pc("none", &helmv1beta1.DefaultFor{})
+
+	release := func(ref, namespace string, l map[string]string) *v1beta1.Release {
+		r := &v1beta1.Release{ObjectMeta: metav1.ObjectMeta{Name: testReleaseName, Labels: l}}
+		r.Spec.ForProvider.Namespace = namespace
+		r.SetProviderConfigReference(&xpv1.Reference{Name: ref})
+		return r
+	}
+
+	type args struct {
+		pcs     []helmv1beta1.ProviderConfig
+		listErr error
+		cr      *v1beta1.Release
+	}
+	type want struct {
+		ref     string
+		updated bool
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ExplicitReference": {
+			reason: "Releases that reference another ProviderConfig should not be changed.",
+			args:   args{pcs: []helmv1beta1.ProviderConfig{tenant}, cr: release("other", "team-a", nil)},
+			want:   want{ref: "other"},
+		},
+		"Namespace": {
+			reason: "Releases into a selected namespace should use the selecting ProviderConfig.",
+			args:   args{pcs: []helmv1beta1.ProviderConfig{tenant, prod, none}, cr: release(defaultProviderConfigName, "team-a", nil)},
+			want:   want{ref: "tenant", updated: true},
+		},
+		"Labels": {
+			reason: "Releases with selected labels should use the selecting ProviderConfig.",
+			args:   args{pcs: []helmv1beta1.ProviderConfig{tenant, prod}, cr: release(defaultProviderConfigName, "default", map[string]string{"env": "prod"})},
+			want:   want{ref: "prod", updated: true},
+		},
+		"AllCriteria": {
+			reason: "Releases should only be selected if they match all criteria.",
+			args:   args{pcs: []helmv1beta1.ProviderConfig{tenantProd}, cr: release(defaultProviderConfigName, "team-a", map[string]string{"env": "dev"})},
+			want:   want{ref: defaultProviderConfigName},
+		},
+		"NotSelected": {
+			reason: "Releases that are not selected should keep the default ProviderConfig.",
+			args:   args{pcs: []helmv1beta1.ProviderConfig{tenant, prod, none}, cr: release(defaultProviderConfigName, "default", nil)},
+			want:   want{ref: defaultProviderConfigName},
+		},
+		"Ambiguous": {
+			reason: "Releases selected by more than one ProviderConfig should not be changed.",
+			args:   args{pcs: []helmv1beta1.ProviderConfig{tenant, prod}, cr: release(defaultProviderConfigName, "team-a", map[string]string{"env": "prod"})},
+			want:   want{ref: defaultProviderConfigName, err: errors.Errorf(errAmbiguousDefault, "tenant, prod")},
+		},
+		"ListError": {
+			reason: "Errors listing ProviderConfigs should be returned.",
+			args:   args{listErr: errBoom, cr: release(defaultProviderConfigName, "team-a", nil)},
+			want:   want{ref: defaultProviderConfigName, err: errors.Wrap(errBoom, errListProviderConfigs)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			updated := false
+			kube := &test.MockClient{
+				MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+					obj.(*helmv1beta1.ProviderConfigList).Items = tc.args.pcs
+					return tc.args.listErr
+				},
+				MockUpdate: func(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
+					updated = true
+					return nil
+				},
+			}
+			err := selectProviderConfig(context.Background(), kube, tc.args.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nselectProviderConfig(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ref, tc.args.cr.GetProviderConfigReference().Name); diff != "" {
+				t.Errorf("\n%s\nselectProviderConfig(...): -want reference, +got reference:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.updated, updated); diff != "" {
+				t.Errorf("\n%s\nselectProviderConfig(...): -want updated, +got updated:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	errFailedToGetDependency = "failed to get dependency"
+	errDependenciesNotReady  = "waiting for dependencies to become ready: %s"
+)
+
+// dependenciesReady returns an error naming every dependency of the Release
+// that does not exist or is not ready yet.
+func (e *helmExternal) dependenciesReady(ctx context.Context, cr *v1beta1.Release) error {
+	var pending []string
+	for _, d := range cr.Spec.ForProvider.DependsOn {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(d.APIVersion)
+		if d.APIVersion == "" {
+			u.SetAPIVersion(v1beta1.SchemeGroupVersion.String())
+		}
+		u.SetKind(d.Kind)
+		if d.Kind == "" {
+			u.SetKind(v1beta1.ReleaseKind)
+		}
+		u.SetName(d.Name)
+		u.SetNamespace(d.Namespace)
+		key := resourceKey(u)
+
+		err := e.localKube.Get(ctx, types.NamespacedName{Name: d.Name, Namespace: d.Namespace}, u)
+		if kerrors.IsNotFound(err) {
+			pending = append(pending, key+" not found")
+			continue
+		}
+		if err != nil {
+			return errors.Wrap(err, errFailedToGetDependency)
+		}
+		if !conditionIs(u, string(xpv1.TypeReady), "True") {
+			pending = append(pending, key+" not ready")
+		}
+	}
+	if len(pending) > 0 {
+		return errors.Errorf(errDependenciesNotReady, strings.Join(pending, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+func Test_dependenciesReady(t *testing.T) {
+	localKube := &test.MockClient{
+		MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			u := obj.(*unstructured.Unstructured)
+			switch key.Name {
+			case "missing":
+				return kerrors.NewNotFound(schema.GroupResource{Resource: "releases"}, key.Name)
+			case "broken":
+				return errBoom
+			case "ready":
+				if u.GetKind() != v1beta1.ReleaseKind || u.GetAPIVersion() != v1beta1.SchemeGroupVersion.String() {
+					return errBoom
+				}
+				u.Object["status"] = map[string]interface{}{"conditions": testConditions("Ready", "True")}
+			default:
+				u.Object["status"] = map[string]interface{}{"conditions": testConditions("Ready", "False")}
+			}
+			return nil
+		},
+	}
+
+	cases := map[string]struct {
+		deps []v1beta1.Dependency
+		want error
+	}{
+		"NoDependencies": {},
+		"Ready": {
+			deps: []v1beta1.Dependency{{Name: "ready"}},
+		},
+		"NotReady": {
+			deps: []v1beta1.Dependency{
+				{Name: "ready"},
+				{Name: "missing"},
+				{APIVersion: "database.example.org/v1alpha1", Kind: "Instance", Name: "db"},
+			},
+			want: errors.Errorf(errDependenciesNotReady, "Release missing not found, Instance db not ready"),
+		},
+		"FailedToGet": {
+			deps: []v1beta1.Dependency{{Name: "broken"}},
+			want: errors.Wrap(errBoom, errFailedToGetDependency),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &helmExternal{localKube: localKube}
+			cr := helmRelease(func(r *v1beta1.Release) {
+				r.Spec.ForProvider.DependsOn = tc.deps
+			})
+			err := e.dependenciesReady(context.Background(), cr)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("e.dependenciesReady(...): -want error, +got error: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ktype "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	reasonUpgradeDiff event.Reason = "UpgradeDiff"
+
+	// maxDiffEventSize limits the size of the detailed diff recorded in an
+	// event.
+	maxDiffEventSize = 4096
+
+	maskedValue        = "***"
+	maskedChangedValue = "*** (changed)"
+)
+
+const (
+	errFailedToRenderForDiff = "failed to render release for diff"
+	errFailedToDiff          = "failed to diff manifests"
+)
+
+// A manifestDiff describes the difference between two release manifests.
+type manifestDiff struct {
+	added   []string
+	changed []string
+	removed []string
+	details string
+}
+
+// diffed returns a deployAction that computes the difference between the
+// deployed and the desired manifests before running the supplied action. The
+// summary is recorded in the status of the Release and the details in an
+// event. Failing to compute the difference does not block the action.
+func (e *helmExternal) diffed(cr *v1beta1.Release, template, action deployAction) deployAction {
+	return func(rel string, ch *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (*release.Release, error) {
+		if err := e.recordDiff(cr, template, rel, ch, vals, patches); err != nil {
+			e.logger.Debug(errFailedToDiff, "error", err)
+		}
+		return action(rel, ch, vals, patches)
+	}
+}
+
+func (e *helmExternal) recordDiff(cr *v1beta1.Release, template deployAction, rel string, ch *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) error {
+	deployed, err := e.helm.GetLastRelease(rel)
+	if err != nil {
+		return errors.Wrap(err, errFailedToGetLastRelease)
+	}
+	desired, err := template(rel, ch, vals, patches)
+	if err != nil {
+		return errors.Wrap(err, errFailedToRenderForDiff)
+	}
+	if deployed == nil || desired == nil {
+		return errors.New(errLastReleaseIsNil)
+	}
+	d, err := diffManifests(deployed.Manifest, desired.Manifest)
+	if err != nil {
+		return err
+	}
+
+	cr.Status.LastDiff = &v1beta1.DiffSummary{
+		FromRevision: deployed.Version,
+		Added:        d.added,
+		Changed:      d.changed,
+		Removed:      d.removed,
+	}
+
+	msg := fmt.Sprintf("Upgrading from revision %d: %d added, %d changed, %d removed", deployed.Version, len(d.added), len(d.changed), len(d.removed))
+	if d.details != "" {
+		details := d.details
+		if len(details) > maxDiffEventSize {
+			details = truncateUTF8(details, maxDiffEventSize) + "\n... (truncated)"
+		}
+		msg += "\n" + details
+	}
+	e.recorder.Event(cr, event.Normal(reasonUpgradeDiff, msg))
+	return nil
+}
+
+// diffManifests compares the resources of two manifests. Resources are
+// identified by their kind, namespace and name. Secret data is masked.
+func diffManifests(from, to string) (*manifestDiff, error) {
+	f, err := indexManifest(from)
+	if err != nil {
+		return nil, err
+	}
+	t, err := indexManifest(to)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &manifestDiff{}
+	var details []string
+	for _, k := range sortedResourceKeys(t) {
+		old, ok := f[k]
+		if !ok {
+			d.added = append(d.added, k)
+			continue
+		}
+		o, n := old.DeepCopy(), t[k].DeepCopy()
+		maskSecretData(o, n)
+		ob, err := yaml.Marshal(o.Object)
+		if err != nil {
+			return nil, err
+		}
+		nb, err := yaml.Marshal(n.Object)
+		if err != nil {
+			return nil, err
+		}
+		if string(ob) == string(nb) {
+			continue
+		}
+		d.changed = append(d.changed, k)
+		ud, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(ob)),
+			B:        difflib.SplitLines(string(nb)),
+			FromFile: k,
+			ToFile:   k,
+			Context:  3,
+		})
+		if err != nil {
+			return nil, err
+		}
+		details = append(details, ud)
+	}
+	for _, k := range sortedResourceKeys(f) {
+		if _, ok := t[k]; !ok {
+			d.removed = append(d.removed, k)
+		}
+	}
+	d.details = strings.Join(details, "")
+	return d, nil
+}
+
+func indexManifest(manifest string) (map[string]*unstructured.Unstructured, error) {
+	objs, err := parseManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]*unstructured.Unstructured, len(objs))
+	for i := range objs {
+		res[resourceKey(&objs[i])] = &objs[i]
+	}
+	return res, nil
+}
+
+// resourceKey identifies a resource of a manifest, e.g. "Deployment
+// default/nginx" or "ClusterRole admin".
+func resourceKey(u *unstructured.Unstructured) string {
+	n := u.GetName()
+	if u.GetNamespace() != "" {
+		n = u.GetNamespace() + "/" + n
+	}
+	return u.GetKind() + " " + n
+}
+
+func sortedResourceKeys(m map[string]*unstructured.Unstructured) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// maskSecretData replaces the data of Secrets with a placeholder, which
+// reveals whether a value changed but not the value itself.
+func maskSecretData(from, to *unstructured.Unstructured) {
+	if to.GetKind() != "Secret" || to.GetAPIVersion() != "v1" {
+		return
+	}
+	for _, field := range []string{"data", "stringData"} {
+		fd, _, _ := unstructured.NestedMap(from.Object, field)
+		td, _, _ := unstructured.NestedMap(to.Object, field)
+		for k, v := range td {
+			if fv, ok := fd[k]; ok && fv == v {
+				td[k] = maskedValue
+			} else {
+				td[k] = maskedChangedValue
+			}
+		}
+		for k := range fd {
+			fd[k] = maskedValue
+		}
+		if fd != nil {
+			_ = unstructured.SetNestedMap(from.Object, fd, field)
+		}
+		if td != nil {
+			_ = unstructured.SetNestedMap(to.Object, td, field)
+		}
+	}
+}
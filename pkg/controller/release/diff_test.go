@@ -0,0 +1,116 @@
+package release
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"sigs.k8s.io/kustomize/api/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	testDiffFrom = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: kept
+data:
+  a: "1"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: removed
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: creds
+  namespace: ns
+data:
+  password: b2xk
+  user: YWRtaW4=
+`
+	testDiffTo = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: kept
+data:
+  a: "1"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: added
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: creds
+  namespace: ns
+data:
+  password: bmV3
+  user: YWRtaW4=
+`
+)
+
+func Test_diffManifests(t *testing.T) {
+	d, err := diffManifests(testDiffFrom, testDiffTo)
+	if err != nil {
+		t.Fatalf("diffManifests(...): unexpected error: %s", err)
+	}
+	if diff := cmp.Diff([]string{"ConfigMap added"}, d.added); diff != "" {
+		t.Errorf("diffManifests(...): -want added, +got added: %s", diff)
+	}
+	if diff := cmp.Diff([]string{"Secret ns/creds"}, d.changed); diff != "" {
+		t.Errorf("diffManifests(...): -want changed, +got changed: %s", diff)
+	}
+	if diff := cmp.Diff([]string{"ConfigMap removed"}, d.removed); diff != "" {
+		t.Errorf("diffManifests(...): -want removed, +got removed: %s", diff)
+	}
+	for _, secret := range []string{"b2xk", "bmV3", "YWRtaW4="} {
+		if strings.Contains(d.details, secret) {
+			t.Errorf("diffManifests(...): details reveal secret data %q:\n%s", secret, d.details)
+		}
+	}
+	if !strings.Contains(d.details, "+  password: '*** (changed)'") {
+		t.Errorf("diffManifests(...): details do not mark changed secret data:\n%s", d.details)
+	}
+}
+
+func Test_diffed(t *testing.T) {
+	cr := helmRelease()
+	e := &helmExternal{
+		logger:   logging.NewNopLogger(),
+		recorder: event.NewNopRecorder(),
+		helm: &MockHelmClient{
+			MockGetLastRelease: func(string) (*release.Release, error) {
+				return &release.Release{Version: 2, Manifest: testDiffFrom}, nil
+			},
+			MockTemplate: func(string, *chart.Chart, map[string]interface{}, []types.Patch) (*release.Release, error) {
+				return &release.Release{Manifest: testDiffTo}, nil
+			},
+		},
+	}
+	action := e.diffed(cr, e.helm.Template, func(string, *chart.Chart, map[string]interface{}, []types.Patch) (*release.Release, error) {
+		return &release.Release{}, nil
+	})
+	if _, err := action(testReleaseName, &chart.Chart{}, nil, nil); err != nil {
+		t.Fatalf("diffed(...): unexpected error: %s", err)
+	}
+	want := &v1beta1.DiffSummary{
+		FromRevision: 2,
+		Added:        []string{"ConfigMap added"},
+		Changed:      []string{"Secret ns/creds"},
+		Removed:      []string{"ConfigMap removed"},
+	}
+	if diff := cmp.Diff(want, cr.Status.LastDiff); diff != "" {
+		t.Errorf("diffed(...): -want summary, +got summary: %s", diff)
+	}
+}
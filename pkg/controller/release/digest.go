@@ -0,0 +1,98 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	ktype "sigs.k8s.io/kustomize/api/types"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+// digested returns a deployAction that records the digest of the chart,
+// values and patches as well as the revision of the release once the
+// supplied action succeeded.
+func (e *helmExternal) digested(cr *v1beta1.Release, action deployAction) deployAction {
+	return func(rel string, ch *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (*release.Release, error) {
+		r, err := action(rel, ch, vals, patches)
+		if err != nil || r == nil {
+			return r, err
+		}
+		if cr.Status.SyncedDigest, err = diffDigest(ch, vals, patches); err != nil {
+			e.logger.Debug(errFailedToDigest, "error", err)
+		}
+		cr.Status.SyncedRevision = r.Version
+		return r, nil
+	}
+}
+
+// unchangedSinceSync returns whether the supplied release is the one of the
+// last successful install or upgrade and the chart, values and patches of
+// the Release did not change since. Comparing digests is cheaper than
+// comparing the release with the Release.
+func (e *helmExternal) unchangedSinceSync(ctx context.Context, cr *v1beta1.Release, rel *release.Release) (bool, error) {
+	if cr.Status.SyncedDigest == "" || rel.Info == nil || rel.Info.Status != release.StatusDeployed || rel.Version != cr.Status.SyncedRevision {
+		return false, nil
+	}
+	d, err := e.desiredDigest(ctx, cr)
+	if err != nil {
+		return false, err
+	}
+	if d != cr.Status.SyncedDigest {
+		return false, nil
+	}
+	return hasCommonMetadata(rel.Manifest, commonMetadata(&cr.Spec.ForProvider))
+}
+
+// markSynced records the digest of the chart, values and patches of the
+// Release and the revision of the supplied deployed release, which was found
+// up to date by comparing it with the Release. This lets releases that were
+// not installed or upgraded by the provider since it started recording
+// digests, e.g. adopted or rolled back ones, skip the comparison on the next
+// observation. Failures are only logged; the comparison is repeated then.
+func (e *helmExternal) markSynced(ctx context.Context, cr *v1beta1.Release, rel *release.Release) {
+	if rel.Info == nil || rel.Info.Status != release.StatusDeployed {
+		return
+	}
+	d, err := e.desiredDigest(ctx, cr)
+	if err != nil {
+		e.logger.Debug(errFailedToDigest, "error", err)
+		return
+	}
+	cr.Status.SyncedDigest = d
+	cr.Status.SyncedRevision = rel.Version
+}
+
+// desiredDigest returns the digest of the chart, values and patches of the
+// supplied Release without pulling its chart.
+func (e *helmExternal) desiredDigest(ctx context.Context, cr *v1beta1.Release) (string, error) {
+	vals, err := composeValuesFromSpec(ctx, e.localKube, e.plugins, cr.Spec.ForProvider.ValuesSpec)
+	if err != nil {
+		return "", errors.Wrap(err, errFailedToComposeValues)
+	}
+	p, err := e.patch.getFromSpec(ctx, e.localKube, e.plugins, &cr.Spec.ForProvider)
+	if err != nil {
+		return "", errors.Wrap(err, errFailedToLoadPatches)
+	}
+	ch := &chart.Chart{Metadata: &chart.Metadata{Name: cr.Spec.ForProvider.Chart.Name, Version: cr.Spec.ForProvider.Chart.Version}}
+	d, err := diffDigest(ch, vals, p)
+	return d, errors.Wrap(err, errFailedToDigest)
+}
@@ -0,0 +1,165 @@
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/kustomize/api/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+func Test_digested(t *testing.T) {
+	ch := &chart.Chart{Metadata: &chart.Metadata{Name: testChart, Version: testVersion}}
+	vals := map[string]interface{}{"replicas": 2}
+	digest, _ := diffDigest(ch, vals, nil)
+
+	cases := map[string]struct {
+		err      error
+		digest   string
+		revision int
+	}{
+		"Succeeded": {
+			digest:   digest,
+			revision: 3,
+		},
+		"Failed": {
+			err: errBoom,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := helmRelease()
+			e := &helmExternal{logger: logging.NewNopLogger()}
+			action := e.digested(cr, func(string, *chart.Chart, map[string]interface{}, []types.Patch) (*release.Release, error) {
+				if tc.err != nil {
+					return nil, tc.err
+				}
+				return &release.Release{Version: 3}, nil
+			})
+			_, err := action(testReleaseName, ch, vals, nil)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("digested(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.digest, cr.Status.SyncedDigest); diff != "" {
+				t.Errorf("digested(...): -want digest, +got digest: %s", diff)
+			}
+			if diff := cmp.Diff(tc.revision, cr.Status.SyncedRevision); diff != "" {
+				t.Errorf("digested(...): -want revision, +got revision: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_unchangedSinceSync(t *testing.T) {
+	ch := &chart.Chart{Metadata: &chart.Metadata{Name: testChart, Version: testVersion}}
+	digest, _ := diffDigest(ch, map[string]interface{}{"replicas": float64(2)}, nil)
+	deployed := &release.Release{Version: 3, Info: &release.Info{Status: release.StatusDeployed}}
+
+	cases := map[string]struct {
+		digest   string
+		revision int
+		values   string
+		rel      *release.Release
+		want     bool
+	}{
+		"Unchanged": {
+			digest:   digest,
+			revision: 3,
+			values:   `{"replicas": 2}`,
+			rel:      deployed,
+			want:     true,
+		},
+		"NeverSynced": {
+			values: `{"replicas": 2}`,
+			rel:    deployed,
+		},
+		"ValuesChanged": {
+			digest:   digest,
+			revision: 3,
+			values:   `{"replicas": 3}`,
+			rel:      deployed,
+		},
+		"ReleaseChanged": {
+			digest:   digest,
+			revision: 2,
+			values:   `{"replicas": 2}`,
+			rel:      deployed,
+		},
+		"ReleaseFailed": {
+			digest:   digest,
+			revision: 3,
+			values:   `{"replicas": 2}`,
+			rel:      &release.Release{Version: 3, Info: &release.Info{Status: release.StatusFailed}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := helmRelease(func(r *v1beta1.Release) {
+				r.Spec.ForProvider.Values = runtime.RawExtension{Raw: []byte(tc.values)}
+				r.Status.SyncedDigest = tc.digest
+				r.Status.SyncedRevision = tc.revision
+			})
+			e := &helmExternal{patch: newPatcher()}
+			got, err := e.unchangedSinceSync(context.Background(), cr, tc.rel)
+			if err != nil {
+				t.Fatalf("e.unchangedSinceSync(...): %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("e.unchangedSinceSync(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_markSynced(t *testing.T) {
+	ch := &chart.Chart{Metadata: &chart.Metadata{Name: testChart, Version: testVersion}}
+	digest, _ := diffDigest(ch, map[string]interface{}{"replicas": float64(2)}, nil)
+
+	cases := map[string]struct {
+		rel      *release.Release
+		digest   string
+		revision int
+	}{
+		"Deployed": {
+			rel:      &release.Release{Version: 3, Info: &release.Info{Status: release.StatusDeployed}},
+			digest:   digest,
+			revision: 3,
+		},
+		"Failed": {
+			rel: &release.Release{Version: 3, Info: &release.Info{Status: release.StatusFailed}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := helmRelease(func(r *v1beta1.Release) {
+				r.Spec.ForProvider.Values = runtime.RawExtension{Raw: []byte(`{"replicas": 2}`)}
+			})
+			e := &helmExternal{logger: logging.NewNopLogger(), patch: newPatcher()}
+			e.markSynced(context.Background(), cr, tc.rel)
+			if diff := cmp.Diff(tc.digest, cr.Status.SyncedDigest); diff != "" {
+				t.Errorf("e.markSynced(...): -want digest, +got digest: %s", diff)
+			}
+			if diff := cmp.Diff(tc.revision, cr.Status.SyncedRevision); diff != "" {
+				t.Errorf("e.markSynced(...): -want revision, +got revision: %s", diff)
+			}
+			if tc.digest == "" {
+				return
+			}
+			unchanged, err := e.unchangedSinceSync(context.Background(), cr, tc.rel)
+			if err != nil {
+				t.Fatalf("e.unchangedSinceSync(...): %s", err)
+			}
+			if !unchanged {
+				t.Errorf("e.unchangedSinceSync(...): want the release unchanged after e.markSynced(...)")
+			}
+		})
+	}
+}
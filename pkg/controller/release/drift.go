@@ -0,0 +1,275 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	reasonDriftDetected event.Reason = "DriftDetected"
+)
+
+const (
+	errFailedToGetLiveResource = "failed to get live resource"
+	errFailedToDetectDrift     = "failed to detect drift"
+)
+
+// observeDrift compares the resources of the deployed manifest against their
+// live state on the target cluster according to the drift policy of the
+// Release. Drifted resources are reported in its status. It returns true if
+// the drift should be corrected by upgrading the release.
+func (e *helmExternal) observeDrift(ctx context.Context, cr *v1beta1.Release, manifest string) (bool, error) {
+	e.watchDrift(cr, manifest)
+
+	p := e.params(cr).DriftPolicy
+	if p == "" || p == v1beta1.DriftPolicyIgnore {
+		return false, nil
+	}
+
+	d, err := drifted(ctx, e.kube, e.kube.RESTMapper(), cr.Spec.ForProvider.Namespace, manifest, e.params(cr).IgnoreDifferences)
+	if err != nil {
+		return false, errors.Wrap(err, errFailedToDetectDrift)
+	}
+	cr.Status.AtProvider.Drifted = d
+	if len(d) == 0 {
+		return false, nil
+	}
+
+	e.recorder.Event(cr, event.Warning(reasonDriftDetected, errors.Errorf("resources drifted from the deployed release: %s", strings.Join(d, ", "))))
+	return p == v1beta1.DriftPolicyCorrect, nil
+}
+
+// drifted returns the resources of the supplied manifest whose live state
+// differs from the manifest, including resources that no longer exist.
+func drifted(ctx context.Context, kube client.Client, mapper meta.RESTMapper, namespace, manifest string, ignore []v1beta1.IgnoreDifferences) ([]string, error) {
+	objs, err := parseManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []string
+	for i := range objs {
+		desired := &objs[i]
+		if err := defaultNamespace(mapper, desired, namespace); err != nil {
+			return nil, err
+		}
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(desired.GroupVersionKind())
+		err := kube.Get(ctx, types.NamespacedName{Name: desired.GetName(), Namespace: desired.GetNamespace()}, live)
+		if kerrors.IsNotFound(err) {
+			res = append(res, resourceKey(desired))
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errFailedToGetLiveResource)
+		}
+		if differs(desired, live, ignore) {
+			res = append(res, resourceKey(desired))
+		}
+	}
+	return res, nil
+}
+
+// defaultNamespace sets the supplied namespace on a namespaced resource that
+// does not specify one, as Helm does when deploying it.
+func defaultNamespace(mapper meta.RESTMapper, u *unstructured.Unstructured, namespace string) error {
+	gvk := u.GroupVersionKind()
+	m, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return errors.Wrap(err, errFailedToMapResource)
+	}
+	if m.Scope.Name() == meta.RESTScopeNameNamespace && u.GetNamespace() == "" {
+		u.SetNamespace(namespace)
+	}
+	return nil
+}
+
+// differs returns true if the live state of a resource differs from its
+// desired state. Only fields set in the desired state are compared, fields
+// matching any of the supplied rules are ignored.
+func differs(desired, live *unstructured.Unstructured, ignore []v1beta1.IgnoreDifferences) bool {
+	d, l := desired.DeepCopy(), live.DeepCopy()
+	for _, r := range ignore {
+		if !ignoreRuleMatches(r, desired) {
+			continue
+		}
+		for _, p := range r.JSONPointers {
+			removeJSONPointer(d.Object, p)
+			removeJSONPointer(l.Object, p)
+		}
+	}
+	return !isSubset(d.Object, l.Object)
+}
+
+func ignoreRuleMatches(r v1beta1.IgnoreDifferences, u *unstructured.Unstructured) bool {
+	gk := u.GroupVersionKind().GroupKind()
+	if (gk != schema.GroupKind{Group: r.Group, Kind: r.Kind}) {
+		return false
+	}
+	if r.Name != "" && r.Name != u.GetName() {
+		return false
+	}
+	if r.Namespace != "" && r.Namespace != u.GetNamespace() {
+		return false
+	}
+	return true
+}
+
+// removeJSONPointer removes the field referred to by the supplied RFC 6901
+// JSON pointer, e.g. /spec/replicas. Pointers to fields that do not exist are
+// ignored.
+func removeJSONPointer(obj map[string]interface{}, pointer string) {
+	if !strings.HasPrefix(pointer, "/") {
+		return
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(tokens[i])
+	}
+
+	var cur interface{} = obj
+	for i, t := range tokens {
+		last := i == len(tokens)-1
+		switch c := cur.(type) {
+		case map[string]interface{}:
+			if last {
+				delete(c, t)
+				return
+			}
+			cur = c[t]
+		case []interface{}:
+			idx, ok := listIndex(t, len(c))
+			if !ok {
+				return
+			}
+			if last {
+				// Blank the element rather than removing it, so that the
+				// remaining elements are still compared by position.
+				c[idx] = nil
+				return
+			}
+			cur = c[idx]
+		default:
+			return
+		}
+	}
+}
+
+func listIndex(token string, length int) (int, bool) {
+	idx, err := strconv.Atoi(token)
+	if err != nil || idx < 0 || idx >= length {
+		return 0, false
+	}
+	return idx, true
+}
+
+// isSubset returns true if every field set in a is set to the same value in
+// b. Lists must be of the same length and are compared element by element.
+// Numbers and resource quantities are compared by value, because the API
+// server normalizes them, e.g. cpu: 0.5 becomes cpu: 500m.
+func isSubset(a, b interface{}) bool {
+	switch av := a.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for k, v := range av {
+			if !isSubset(v, bv[k]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !isSubset(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	af, aok := toFloat(a)
+	bf, bok := toFloat(b)
+	if aok && bok {
+		return af == bf
+	}
+	return a == b || equalQuantities(a, b)
+}
+
+// equalQuantities returns true if both values are resource quantities of
+// the same value. Quantities are strings, or numbers in a manifest.
+func equalQuantities(a, b interface{}) bool {
+	aq, ok := toQuantity(a)
+	if !ok {
+		return false
+	}
+	bq, ok := toQuantity(b)
+	return ok && aq.Cmp(bq) == 0
+}
+
+func toQuantity(v interface{}) (resource.Quantity, bool) {
+	s, ok := v.(string)
+	if !ok {
+		f, ok := toFloat(v)
+		if !ok {
+			return resource.Quantity{}, false
+		}
+		s = strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	q, err := resource.ParseQuantity(s)
+	return q, err == nil
+}
+
+// toFloat normalizes numbers, which are float64 when parsed from a manifest
+// but int64 when read from the API server.
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	}
+	return 0, false
+}
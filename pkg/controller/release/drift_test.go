@@ -0,0 +1,196 @@
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+func testDeployment(replicas interface{}, annotations map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":        "nginx",
+			"namespace":   testNamespace,
+			"annotations": annotations,
+		},
+		"spec": map[string]interface{}{
+			"replicas": replicas,
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "nginx", "image": "nginx:1.21"},
+					},
+				},
+			},
+		},
+	}}
+}
+
+func Test_differs(t *testing.T) {
+	cases := map[string]struct {
+		desired *unstructured.Unstructured
+		live    *unstructured.Unstructured
+		ignore  []v1beta1.IgnoreDifferences
+		want    bool
+	}{
+		"InSync": {
+			desired: testDeployment(float64(1), nil),
+			live:    testDeployment(int64(1), map[string]interface{}{"deployment.kubernetes.io/revision": "1"}),
+			want:    false,
+		},
+		"ReplicasChanged": {
+			desired: testDeployment(float64(1), nil),
+			live:    testDeployment(int64(3), nil),
+			want:    true,
+		},
+		"ReplicasIgnored": {
+			desired: testDeployment(float64(1), nil),
+			live:    testDeployment(int64(3), nil),
+			ignore: []v1beta1.IgnoreDifferences{
+				{Group: "apps", Kind: "Deployment", JSONPointers: []string{"/spec/replicas"}},
+			},
+			want: false,
+		},
+		"RuleForOtherKind": {
+			desired: testDeployment(float64(1), nil),
+			live:    testDeployment(int64(3), nil),
+			ignore: []v1beta1.IgnoreDifferences{
+				{Group: "apps", Kind: "StatefulSet", JSONPointers: []string{"/spec/replicas"}},
+			},
+			want: true,
+		},
+		"RuleForOtherName": {
+			desired: testDeployment(float64(1), nil),
+			live:    testDeployment(int64(3), nil),
+			ignore: []v1beta1.IgnoreDifferences{
+				{Group: "apps", Kind: "Deployment", Name: "other", JSONPointers: []string{"/spec/replicas"}},
+			},
+			want: true,
+		},
+		"EscapedPointerIgnored": {
+			desired: testDeployment(float64(1), map[string]interface{}{"example.org/hash": "a"}),
+			live:    testDeployment(int64(1), map[string]interface{}{"example.org/hash": "b"}),
+			ignore: []v1beta1.IgnoreDifferences{
+				{Group: "apps", Kind: "Deployment", JSONPointers: []string{"/metadata/annotations/example.org~1hash"}},
+			},
+			want: false,
+		},
+		"ListElementIgnored": {
+			desired: testDeployment(float64(1), nil),
+			live: func() *unstructured.Unstructured {
+				u := testDeployment(int64(1), nil)
+				_ = unstructured.SetNestedSlice(u.Object, []interface{}{map[string]interface{}{"name": "nginx", "image": "nginx:1.22"}}, "spec", "template", "spec", "containers")
+				return u
+			}(),
+			ignore: []v1beta1.IgnoreDifferences{
+				{Group: "apps", Kind: "Deployment", JSONPointers: []string{"/spec/template/spec/containers/0/image"}},
+			},
+			want: false,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := differs(tc.desired, tc.live, tc.ignore)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("differs(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_isSubset(t *testing.T) {
+	cases := map[string]struct {
+		a    interface{}
+		b    interface{}
+		want bool
+	}{
+		"IntAndFloat": {
+			a:    float64(2),
+			b:    int64(2),
+			want: true,
+		},
+		"DifferentNumbers": {
+			a:    float64(2),
+			b:    int64(3),
+			want: false,
+		},
+		"NormalizedCPU": {
+			a:    map[string]interface{}{"cpu": "0.5"},
+			b:    map[string]interface{}{"cpu": "500m"},
+			want: true,
+		},
+		"NumericCPU": {
+			a:    map[string]interface{}{"cpu": float64(1)},
+			b:    map[string]interface{}{"cpu": "1"},
+			want: true,
+		},
+		"NormalizedMemory": {
+			a:    map[string]interface{}{"memory": "1Gi"},
+			b:    map[string]interface{}{"memory": "1024Mi"},
+			want: true,
+		},
+		"DifferentQuantities": {
+			a:    map[string]interface{}{"cpu": "0.5"},
+			b:    map[string]interface{}{"cpu": "250m"},
+			want: false,
+		},
+		"DifferentStrings": {
+			a:    "nginx:1.21",
+			b:    "nginx:1.22",
+			want: false,
+		},
+		"Missing": {
+			a:    map[string]interface{}{"cpu": "1"},
+			b:    map[string]interface{}{},
+			want: false,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, isSubset(tc.a, tc.b)); diff != "" {
+				t.Errorf("isSubset(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_drifted(t *testing.T) {
+	manifest := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: same\ndata:\n  a: b\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: changed\ndata:\n  a: b\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: deleted\n"
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			if key.Namespace != testNamespace {
+				return errBoom
+			}
+			u := obj.(*unstructured.Unstructured)
+			u.SetName(key.Name)
+			u.SetNamespace(key.Namespace)
+			switch key.Name {
+			case "same":
+				u.Object["data"] = map[string]interface{}{"a": "b", "extra": "c"}
+			case "changed":
+				u.Object["data"] = map[string]interface{}{"a": "c"}
+			default:
+				return kerrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, key.Name)
+			}
+			return nil
+		},
+	}
+	got, err := drifted(context.Background(), kube, testRESTMapper(), testNamespace, manifest, nil)
+	if err != nil {
+		t.Fatalf("drifted(...): unexpected error: %s", err)
+	}
+	want := []string{"ConfigMap " + testNamespace + "/changed", "ConfigMap " + testNamespace + "/deleted"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("drifted(...): -want, +got: %s", diff)
+	}
+}
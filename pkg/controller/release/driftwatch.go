@@ -0,0 +1,303 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	ctrlevent "sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	// driftEventBuffer is how many Releases whose resources changed may
+	// wait to be enqueued. Further changes are observed at the next poll.
+	driftEventBuffer = 1024
+
+	// helmManagedBy is the value of the managed-by label that Helm charts
+	// conventionally set on their resources. Only resources carrying it
+	// are watched.
+	helmManagedBy = "Helm"
+)
+
+// A watchKey identifies the resources of a kind in a namespace, or all
+// resources of a cluster scoped kind.
+type watchKey struct {
+	resource  schema.GroupVersionResource
+	namespace string
+}
+
+// A releaseKey identifies a Helm release of a target cluster.
+type releaseKey struct {
+	name      string
+	namespace string
+}
+
+type watchedInformer struct {
+	stop chan struct{}
+	refs int
+}
+
+type releaseWatch struct {
+	release releaseKey
+	keys    []watchKey
+	// ignore are the differences of the resources that are ignored, so
+	// that their changes do not enqueue the Release.
+	ignore []v1beta1.IgnoreDifferences
+}
+
+// resourceWatches watch the deployed resources of the Releases of a target
+// cluster that opted into it, and enqueue a Release as soon as one of its
+// resources changes, so that drift is observed without waiting for the
+// next poll. Resources are watched by kind and namespace, selected by the
+// label Helm charts conventionally set, and are mapped to their Release by
+// the annotations Helm sets.
+type resourceWatches struct {
+	config *rest.Config
+	events chan<- ctrlevent.GenericEvent
+
+	// newInformer returns an informer of the supplied resources.
+	newInformer func(dynamic.Interface, watchKey) cache.SharedIndexInformer
+
+	mu        sync.Mutex
+	client    dynamic.Interface
+	informers map[watchKey]*watchedInformer
+	releases  map[string]releaseWatch
+	owners    map[releaseKey]string
+}
+
+func newResourceWatches(rc *rest.Config, events chan<- ctrlevent.GenericEvent) *resourceWatches {
+	return &resourceWatches{
+		config:      rc,
+		events:      events,
+		newInformer: newHelmResourceInformer,
+		informers:   map[watchKey]*watchedInformer{},
+		releases:    map[string]releaseWatch{},
+		owners:      map[releaseKey]string{},
+	}
+}
+
+func newHelmResourceInformer(c dynamic.Interface, k watchKey) cache.SharedIndexInformer {
+	selector := func(o *metav1.ListOptions) {
+		o.LabelSelector = helmNamespaceLabel + "=" + helmManagedBy
+	}
+	return dynamicinformer.NewFilteredDynamicInformer(c, k.resource, k.namespace, 0, cache.Indexers{}, selector).Informer()
+}
+
+// watchKeys returns the watches of the resources of the supplied manifest.
+// Resources of kinds that cannot be mapped are not watched.
+func watchKeys(mapper apimeta.RESTMapper, namespace, manifest string) ([]watchKey, error) {
+	objs, err := parseManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[watchKey]bool{}
+	var keys []watchKey
+	for i := range objs {
+		gvk := objs[i].GroupVersionKind()
+		m, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			continue
+		}
+		k := watchKey{resource: m.Resource}
+		if m.Scope.Name() == apimeta.RESTScopeNameNamespace {
+			k.namespace = objs[i].GetNamespace()
+			if k.namespace == "" {
+				k.namespace = namespace
+			}
+		}
+		if !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+// watchDrift watches the resources of the supplied manifest if the Release
+// asks to, so that it is observed again as soon as they change, and forgets
+// them otherwise. Resources that can't be watched are still observed at the
+// next poll, so failures are only logged.
+func (e *helmExternal) watchDrift(cr *v1beta1.Release, manifest string) {
+	if e.watches == nil {
+		return
+	}
+	p := e.params(cr).DriftPolicy
+	if !cr.Spec.ForProvider.WatchDrift || p == "" || p == v1beta1.DriftPolicyIgnore {
+		e.watches.forget(cr.GetName())
+		return
+	}
+	keys, err := watchKeys(e.kube.RESTMapper(), cr.Spec.ForProvider.Namespace, manifest)
+	if err == nil {
+		rel := releaseKey{name: meta.GetExternalName(cr), namespace: cr.Spec.ForProvider.Namespace}
+		err = e.watches.watch(cr.GetName(), rel, keys, e.params(cr).IgnoreDifferences)
+	}
+	if err != nil {
+		e.logger.Debug("Cannot watch resources for drift", "error", err)
+	}
+}
+
+// watch the supplied resources of the Helm release of the supplied Release,
+// instead of those watched before. Changes of the supplied differences are
+// ignored.
+func (w *resourceWatches) watch(name string, rel releaseKey, keys []watchKey, ignore []v1beta1.IgnoreDifferences) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.client == nil {
+		c, err := dynamic.NewForConfig(w.config)
+		if err != nil {
+			return err
+		}
+		w.client = c
+	}
+
+	w.forgetLocked(name)
+	for _, k := range keys {
+		wi, ok := w.informers[k]
+		if !ok {
+			wi = &watchedInformer{stop: make(chan struct{})}
+			i := w.newInformer(w.client, k)
+			i.AddEventHandler(cache.ResourceEventHandlerFuncs{
+				UpdateFunc: func(oldObj, newObj interface{}) {
+					o, ok1 := oldObj.(*unstructured.Unstructured)
+					n, ok2 := newObj.(*unstructured.Unstructured)
+					if ok1 && ok2 && changed(o, n, w.ignored(n)) {
+						w.enqueue(n)
+					}
+				},
+				DeleteFunc: func(obj interface{}) {
+					if t, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+						obj = t.Obj
+					}
+					if u, ok := obj.(*unstructured.Unstructured); ok {
+						w.enqueue(u)
+					}
+				},
+			})
+			go i.Run(wi.stop)
+			w.informers[k] = wi
+		}
+		wi.refs++
+	}
+	w.releases[name] = releaseWatch{release: rel, keys: keys, ignore: ignore}
+	w.owners[rel] = name
+	return nil
+}
+
+// forget the resources of the supplied Release.
+func (w *resourceWatches) forget(name string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.forgetLocked(name)
+}
+
+func (w *resourceWatches) forgetLocked(name string) {
+	rw, ok := w.releases[name]
+	if !ok {
+		return
+	}
+	for _, k := range rw.keys {
+		wi := w.informers[k]
+		if wi.refs--; wi.refs == 0 {
+			close(wi.stop)
+			delete(w.informers, k)
+		}
+	}
+	delete(w.releases, name)
+	if w.owners[rw.release] == name {
+		delete(w.owners, rw.release)
+	}
+}
+
+// stop all watches.
+func (w *resourceWatches) stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for name := range w.releases {
+		w.forgetLocked(name)
+	}
+}
+
+// ignored returns the ignored differences of the Release of the supplied
+// resource, if any.
+func (w *resourceWatches) ignored(u *unstructured.Unstructured) []v1beta1.IgnoreDifferences {
+	a := u.GetAnnotations()
+	rel := releaseKey{name: a[helmReleaseNameAnnotation], namespace: a[helmReleaseNamespaceAnnotation]}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.releases[w.owners[rel]].ignore
+}
+
+// enqueue the Release of the supplied resource, if any.
+func (w *resourceWatches) enqueue(u *unstructured.Unstructured) {
+	a := u.GetAnnotations()
+	rel := releaseKey{name: a[helmReleaseNameAnnotation], namespace: a[helmReleaseNamespaceAnnotation]}
+	w.mu.Lock()
+	name, ok := w.owners[rel]
+	w.mu.Unlock()
+	if !ok {
+		return
+	}
+	select {
+	case w.events <- ctrlevent.GenericEvent{Object: &v1beta1.Release{ObjectMeta: metav1.ObjectMeta{Name: name}}}:
+	default:
+	}
+}
+
+// changed returns true if the desired state of a resource may have changed,
+// ignoring changes of its status and bookkeeping metadata, and of fields
+// matching any of the supplied rules.
+func changed(prev, cur *unstructured.Unstructured, ignore []v1beta1.IgnoreDifferences) bool {
+	o, n := prev.DeepCopy(), cur.DeepCopy()
+	ignored := false
+	for _, r := range ignore {
+		if !ignoreRuleMatches(r, cur) {
+			continue
+		}
+		for _, p := range r.JSONPointers {
+			removeJSONPointer(o.Object, p)
+			removeJSONPointer(n.Object, p)
+			ignored = true
+		}
+	}
+	if !equality.Semantic.DeepEqual(o.GetLabels(), n.GetLabels()) || !equality.Semantic.DeepEqual(o.GetAnnotations(), n.GetAnnotations()) {
+		return true
+	}
+	// The generation of resources that have one changes iff their desired
+	// state does, including ignored fields.
+	if n.GetGeneration() != 0 && !ignored {
+		return o.GetGeneration() != n.GetGeneration()
+	}
+	for _, u := range []*unstructured.Unstructured{o, n} {
+		delete(u.Object, "metadata")
+		delete(u.Object, "status")
+	}
+	return !equality.Semantic.DeepEqual(o.Object, n.Object)
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
+	ctrlevent "sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+type fakeInformer struct {
+	cache.SharedIndexInformer
+	handler cache.ResourceEventHandler
+}
+
+func (i *fakeInformer) AddEventHandler(h cache.ResourceEventHandler) { i.handler = h }
+func (i *fakeInformer) Run(<-chan struct{})                          {}
+
+func newTestWatches(events chan ctrlevent.GenericEvent) (*resourceWatches, map[watchKey]*fakeInformer) {
+	informers := map[watchKey]*fakeInformer{}
+	w := newResourceWatches(nil, events)
+	w.client = fake.NewSimpleDynamicClient(runtime.NewScheme())
+	w.newInformer = func(_ dynamic.Interface, k watchKey) cache.SharedIndexInformer {
+		i := &fakeInformer{}
+		informers[k] = i
+		return i
+	}
+	return w, informers
+}
+
+func helmResource(release, namespace string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("v1")
+	u.SetKind("ConfigMap")
+	u.SetName("cm")
+	u.SetAnnotations(map[string]string{
+		helmReleaseNameAnnotation:      release,
+		helmReleaseNamespaceAnnotation: namespace,
+	})
+	return u
+}
+
+func Test_watchKeys(t *testing.T) {
+	manifest := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: b
+  namespace: other
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: c
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.org
+---
+apiVersion: example.org/v1
+kind: Widget
+metadata:
+  name: w
+`
+	cm := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	crd := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	want := []watchKey{
+		{resource: cm, namespace: "default"},
+		{resource: cm, namespace: "other"},
+		{resource: crd},
+	}
+
+	got, err := watchKeys(testRESTMapper(), "default", manifest)
+	if err != nil {
+		t.Fatalf("watchKeys(...): %v", err)
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(watchKey{})); diff != "" {
+		t.Errorf("watchKeys(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestResourceWatches(t *testing.T) {
+	cm := watchKey{resource: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, namespace: "default"}
+	secret := watchKey{resource: schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, namespace: "default"}
+
+	events := make(chan ctrlevent.GenericEvent, 1)
+	w, informers := newTestWatches(events)
+
+	if err := w.watch("a", releaseKey{name: "rel-a", namespace: "default"}, []watchKey{cm, secret}, nil); err != nil {
+		t.Fatalf("watch(a): %v", err)
+	}
+	ignore := []v1beta1.IgnoreDifferences{{Kind: "ConfigMap", JSONPointers: []string{"/data/key"}}}
+	if err := w.watch("b", releaseKey{name: "rel-b", namespace: "default"}, []watchKey{cm}, ignore); err != nil {
+		t.Fatalf("watch(b): %v", err)
+	}
+	if len(informers) != 2 {
+		t.Fatalf("watch(...): want 2 informers, got %d", len(informers))
+	}
+	stopCM, stopSecret := w.informers[cm].stop, w.informers[secret].stop
+
+	// A change of a resource of release b enqueues Release b.
+	old, cur := helmResource("rel-b", "default"), helmResource("rel-b", "default")
+	cur.SetLabels(map[string]string{"drifted": "true"})
+	informers[cm].handler.OnUpdate(old, cur)
+	select {
+	case e := <-events:
+		if e.Object.GetName() != "b" {
+			t.Errorf("OnUpdate(...): want Release b enqueued, got %q", e.Object.GetName())
+		}
+	default:
+		t.Errorf("OnUpdate(...): want Release b enqueued")
+	}
+
+	// Updates that don't change the resource enqueue nothing.
+	informers[cm].handler.OnUpdate(old, old.DeepCopy())
+	// Changes of ignored fields enqueue nothing.
+	prev, edited := old.DeepCopy(), old.DeepCopy()
+	prev.Object["data"] = map[string]interface{}{"key": "a"}
+	edited.Object["data"] = map[string]interface{}{"key": "b"}
+	informers[cm].handler.OnUpdate(prev, edited)
+	// Resources of unknown releases enqueue nothing.
+	informers[cm].handler.OnDelete(helmResource("rel-c", "default"))
+	select {
+	case e := <-events:
+		t.Errorf("want nothing enqueued, got %q", e.Object.GetName())
+	default:
+	}
+
+	// Watching fewer resources stops the informers nobody else watches.
+	if err := w.watch("a", releaseKey{name: "rel-a", namespace: "default"}, []watchKey{cm}, nil); err != nil {
+		t.Fatalf("watch(a): %v", err)
+	}
+	if !closed(stopSecret) {
+		t.Errorf("watch(a): want secrets informer stopped")
+	}
+	if closed(stopCM) {
+		t.Errorf("watch(a): want configmaps informer running")
+	}
+
+	w.forget("a")
+	if closed(stopCM) {
+		t.Errorf("forget(a): want configmaps informer running while b watches it")
+	}
+	w.stop()
+	if !closed(stopCM) {
+		t.Errorf("stop(): want configmaps informer stopped")
+	}
+	if len(w.informers) != 0 || len(w.releases) != 0 || len(w.owners) != 0 {
+		t.Errorf("stop(): want no watches left")
+	}
+}
+
+func closed(c <-chan struct{}) bool {
+	select {
+	case <-c:
+		return true
+	default:
+		return false
+	}
+}
+
+func Test_changed(t *testing.T) {
+	withGeneration := func(g int64) *unstructured.Unstructured {
+		u := helmResource("rel", "default")
+		u.SetGeneration(g)
+		u.Object["spec"] = map[string]interface{}{"replicas": int64(1)}
+		return u
+	}
+	withData := func(v string) *unstructured.Unstructured {
+		u := helmResource("rel", "default")
+		u.Object["data"] = map[string]interface{}{"key": v}
+		return u
+	}
+	withStatus := func(u *unstructured.Unstructured, v string) *unstructured.Unstructured {
+		u.Object["status"] = map[string]interface{}{"phase": v}
+		u.SetResourceVersion(v)
+		return u
+	}
+	relabelled := withData("a")
+	relabelled.SetLabels(map[string]string{"k": "v"})
+	scaled := withGeneration(2)
+	scaled.Object["spec"] = map[string]interface{}{"replicas": int64(3)}
+	ignoreReplicas := []v1beta1.IgnoreDifferences{{Kind: "ConfigMap", JSONPointers: []string{"/spec/replicas"}}}
+
+	cases := map[string]struct {
+		prev   *unstructured.Unstructured
+		cur    *unstructured.Unstructured
+		ignore []v1beta1.IgnoreDifferences
+		want   bool
+	}{
+		"Unchanged": {
+			prev: withData("a"),
+			cur:  withData("a"),
+			want: false,
+		},
+		"DataChanged": {
+			prev: withData("a"),
+			cur:  withData("b"),
+			want: true,
+		},
+		"LabelsChanged": {
+			prev: withData("a"),
+			cur:  relabelled,
+			want: true,
+		},
+		"OnlyStatusChanged": {
+			prev: withStatus(withData("a"), "1"),
+			cur:  withStatus(withData("a"), "2"),
+			want: false,
+		},
+		"GenerationUnchanged": {
+			prev: withStatus(withGeneration(1), "1"),
+			cur:  withStatus(withGeneration(1), "2"),
+			want: false,
+		},
+		"GenerationChanged": {
+			prev: withGeneration(1),
+			cur:  withGeneration(2),
+			want: true,
+		},
+		"IgnoredFieldChanged": {
+			prev:   withGeneration(1),
+			cur:    scaled,
+			ignore: ignoreReplicas,
+			want:   false,
+		},
+		"IgnoredFieldOfOtherKindChanged": {
+			prev:   withGeneration(1),
+			cur:    scaled,
+			ignore: []v1beta1.IgnoreDifferences{{Kind: "Deployment", JSONPointers: []string{"/spec/replicas"}}},
+			want:   true,
+		},
+		"OtherFieldChanged": {
+			prev: withGeneration(1),
+			cur: func() *unstructured.Unstructured {
+				u := withGeneration(2)
+				u.Object["data"] = map[string]interface{}{"key": "b"}
+				return u
+			}(),
+			ignore: ignoreReplicas,
+			want:   true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := changed(tc.prev, tc.cur, tc.ignore); got != tc.want {
+				t.Errorf("changed(...): want %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
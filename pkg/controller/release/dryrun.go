@@ -0,0 +1,118 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ktype "sigs.k8s.io/kustomize/api/types"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	errFailedToRenderForDryRun = "failed to render release for server-side dry-run"
+	errDryRunRejected          = "server-side dry-run rejected %d resource(s): %s"
+	errFailedToMapResource     = "failed to map resource kind"
+)
+
+// validated returns a deployAction that runs a server-side dry-run of the
+// rendered manifests against the target cluster before running the supplied
+// action. The Validated condition of the Release reflects the outcome.
+func (e *helmExternal) validated(ctx context.Context, cr *v1beta1.Release, template, action deployAction) deployAction {
+	return func(rel string, ch *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (*release.Release, error) {
+		// The chart, values and patches of the last successful install or
+		// upgrade don't need to be validated again, e.g. to correct drift.
+		if d, err := diffDigest(ch, vals, patches); err == nil && d == cr.Status.SyncedDigest {
+			return action(rel, ch, vals, patches)
+		}
+		sctx, span := startSpan(ctx, "DryRun", cr)
+		r, err := template(rel, ch, vals, patches)
+		if err != nil {
+			err = errors.Wrap(err, errFailedToRenderForDryRun)
+			endSpan(span, err)
+			return nil, err
+		}
+		err = dryRun(sctx, e.kube, e.kube.RESTMapper(), cr.Spec.ForProvider.Namespace, r.Manifest)
+		endSpan(span, err)
+		if err != nil {
+			cr.Status.SetConditions(v1beta1.DryRunFailed(err))
+			return nil, err
+		}
+		cr.Status.SetConditions(v1beta1.DryRunSucceeded())
+		return action(rel, ch, vals, patches)
+	}
+}
+
+// dryRun server-side applies every resource of the manifest with dry-run
+// enabled and returns an error describing all rejected resources. Resources
+// of kinds defined by a CRD in the same manifest are skipped as long as the
+// CRD does not exist yet.
+func dryRun(ctx context.Context, kube client.Client, mapper meta.RESTMapper, namespace, manifest string) error {
+	objs, err := parseManifest(manifest)
+	if err != nil {
+		return err
+	}
+
+	crds := manifestCRDs(objs)
+
+	var rejected []string
+	for i := range objs {
+		o := &objs[i]
+		gvk := o.GroupVersionKind()
+		err := defaultNamespace(mapper, o, namespace)
+		if meta.IsNoMatchError(errors.Cause(err)) && crds[gvk.GroupKind()] {
+			continue
+		}
+		if err != nil {
+			rejected = append(rejected, fmt.Sprintf("%s %s: %s", gvk.Kind, o.GetName(), err))
+			continue
+		}
+		if err := kube.Patch(ctx, o, client.Apply, client.DryRunAll, client.ForceOwnership, client.FieldOwner(helmProviderName)); err != nil {
+			rejected = append(rejected, fmt.Sprintf("%s %s: %s", gvk.Kind, o.GetName(), err))
+		}
+	}
+
+	if len(rejected) > 0 {
+		return errors.Errorf(errDryRunRejected, len(rejected), strings.Join(rejected, "; "))
+	}
+	return nil
+}
+
+// manifestCRDs returns the kinds defined by the CRDs of the supplied
+// resources.
+func manifestCRDs(objs []unstructured.Unstructured) map[schema.GroupKind]bool {
+	crds := map[schema.GroupKind]bool{}
+	for _, o := range objs {
+		if o.GroupVersionKind().GroupKind() != (schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}) {
+			continue
+		}
+		g, _, _ := unstructured.NestedString(o.Object, "spec", "group")
+		k, _, _ := unstructured.NestedString(o.Object, "spec", "names", "kind")
+		crds[schema.GroupKind{Group: g, Kind: k}] = true
+	}
+	return crds
+}
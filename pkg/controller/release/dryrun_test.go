@@ -0,0 +1,92 @@
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const testDryRunManifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.org
+spec:
+  group: example.org
+  names:
+    kind: Widget
+---
+apiVersion: example.org/v1
+kind: Widget
+metadata:
+  name: w
+`
+
+func testRESTMapper() meta.RESTMapper {
+	m := meta.NewDefaultRESTMapper(nil)
+	m.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	m.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	m.Add(schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}, meta.RESTScopeRoot)
+	return m
+}
+
+func Test_dryRun(t *testing.T) {
+	type args struct {
+		kube     client.Client
+		manifest string
+	}
+	cases := map[string]struct {
+		args
+		want error
+	}{
+		"Accepted": {
+			args: args{
+				kube: &test.MockClient{
+					MockPatch: func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+						if obj.GetObjectKind().GroupVersionKind().Kind == "ConfigMap" && obj.GetNamespace() != testNamespace {
+							return errBoom
+						}
+						return nil
+					},
+				},
+				manifest: testDryRunManifest,
+			},
+		},
+		"Rejected": {
+			args: args{
+				kube: &test.MockClient{
+					MockPatch: test.NewMockPatchFn(errBoom),
+				},
+				manifest: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n",
+			},
+			want: errors.Errorf(errDryRunRejected, 1, "ConfigMap cm: "+errBoom.Error()),
+		},
+		"UnknownKind": {
+			args: args{
+				kube: &test.MockClient{
+					MockPatch: test.NewMockPatchFn(nil),
+				},
+				manifest: "apiVersion: example.org/v1\nkind: Widget\nmetadata:\n  name: w\n",
+			},
+			want: errors.Errorf(errDryRunRejected, 1, "Widget w: "+errFailedToMapResource+": no matches for kind \"Widget\" in version \"example.org/v1\""),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := dryRun(context.Background(), tc.args.kube, testRESTMapper(), testNamespace, tc.args.manifest)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("dryRun(...): -want error, +got error: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+// Reasons of the events of the lifecycle of a release.
+const (
+	reasonChartResolved   event.Reason = "ChartResolved"
+	reasonInstalling      event.Reason = "Installing"
+	reasonInstalled       event.Reason = "Installed"
+	reasonInstallFailed   event.Reason = "InstallFailed"
+	reasonUpgrading       event.Reason = "Upgrading"
+	reasonUpgraded        event.Reason = "Upgraded"
+	reasonUpgradeFailed   event.Reason = "UpgradeFailed"
+	reasonRolledBack      event.Reason = "RolledBack"
+	reasonRollbackFailed  event.Reason = "RollbackFailed"
+	reasonUninstalled     event.Reason = "Uninstalled"
+	reasonUninstallFailed event.Reason = "UninstallFailed"
+	reasonTestsFailed     event.Reason = "TestsFailed"
+)
+
+// recordUninstall emits an event reporting the supplied outcome of an
+// uninstall of the supplied Release and adds it to its audit log.
+func (e *helmExternal) recordUninstall(cr *v1beta1.Release, err error, msg string) {
+	auditCurrent(cr, v1beta1.AuditOperationUninstall, err)
+	if err != nil {
+		e.recorder.Event(cr, event.Warning(reasonUninstallFailed, err))
+		return
+	}
+	e.recorder.Event(cr, event.Normal(reasonUninstalled, msg))
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"helm.sh/helm/v3/pkg/release"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+// An eventRecorder records the reasons of the events it is passed.
+type eventRecorder struct {
+	reasons []event.Reason
+}
+
+func (r *eventRecorder) Event(_ runtime.Object, e event.Event) {
+	r.reasons = append(r.reasons, e.Reason)
+}
+
+func (r *eventRecorder) WithAnnotations(_ ...string) event.Recorder {
+	return r
+}
+
+func TestLifecycleEvents(t *testing.T) {
+	failed := func(revision int) *v1beta1.Release {
+		limit := int32(3)
+		return helmRelease(func(r *v1beta1.Release) {
+			r.Spec.RollbackRetriesLimit = &limit
+			r.Status.Synced = true
+			r.Status.AtProvider.State = release.StatusFailed
+			r.Status.AtProvider.Revision = revision
+		})
+	}
+
+	cases := map[string]struct {
+		reason string
+		cr     *v1beta1.Release
+		helm   *MockHelmClient
+		op     func(e *helmExternal, cr *v1beta1.Release) error
+		want   []event.Reason
+	}{
+		"RolledBack": {
+			reason: "A rollback of a failed release should be reported.",
+			cr:     failed(2),
+			helm:   &MockHelmClient{MockRollBack: func(string) error { return nil }},
+			op: func(e *helmExternal, cr *v1beta1.Release) error {
+				_, err := e.Update(context.Background(), cr)
+				return err
+			},
+			want: []event.Reason{reasonRolledBack},
+		},
+		"RollbackFailed": {
+			reason: "A failed rollback should be reported.",
+			cr:     failed(2),
+			helm:   &MockHelmClient{MockRollBack: func(string) error { return errBoom }},
+			op: func(e *helmExternal, cr *v1beta1.Release) error {
+				_, err := e.Update(context.Background(), cr)
+				if err != errBoom {
+					return err
+				}
+				return nil
+			},
+			want: []event.Reason{reasonRollbackFailed},
+		},
+		"UninstalledToRetry": {
+			reason: "Uninstalling a failed first revision should be reported.",
+			cr:     failed(1),
+			helm:   &MockHelmClient{MockUninstall: func(string) error { return nil }},
+			op: func(e *helmExternal, cr *v1beta1.Release) error {
+				_, err := e.Update(context.Background(), cr)
+				return err
+			},
+			want: []event.Reason{reasonUninstalled},
+		},
+		"Uninstalled": {
+			reason: "Uninstalling a deleted Release should be reported.",
+			cr:     helmRelease(),
+			helm:   &MockHelmClient{MockUninstall: func(string) error { return nil }},
+			op: func(e *helmExternal, cr *v1beta1.Release) error {
+				return e.Delete(context.Background(), cr)
+			},
+			want: []event.Reason{reasonUninstalled},
+		},
+		"UninstallFailed": {
+			reason: "A failed uninstall should be reported.",
+			cr:     helmRelease(),
+			helm:   &MockHelmClient{MockUninstall: func(string) error { return errBoom }},
+			op: func(e *helmExternal, cr *v1beta1.Release) error {
+				if err := e.Delete(context.Background(), cr); err == nil {
+					return errBoom
+				}
+				return nil
+			},
+			want: []event.Reason{reasonUninstallFailed},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &eventRecorder{}
+			e := &helmExternal{logger: logging.NewNopLogger(), recorder: r, helm: tc.helm}
+			if err := tc.op(e, tc.cr); err != nil {
+				t.Fatalf("\n%s\nunexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, r.reasons); diff != "" {
+				t.Errorf("\n%s\n-want events, +got events:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	errFailedToAssessHealth = "failed to assess health of deployed resources"
+)
+
+// observeHealth assesses the health of the deployed resources if health
+// checks are enabled and returns whether the Release may report Ready. The
+// unhealthy resources are reported in the Healthy condition.
+func (e *helmExternal) observeHealth(ctx context.Context, cr *v1beta1.Release, manifest string) (bool, error) {
+	if !cr.Spec.ForProvider.HealthCheck {
+		return true, nil
+	}
+
+	u, err := unhealthy(ctx, e.kube, e.kube.RESTMapper(), cr.Spec.ForProvider.Namespace, manifest)
+	if err != nil {
+		return false, errors.Wrap(err, errFailedToAssessHealth)
+	}
+	if len(u) > 0 {
+		cr.Status.SetConditions(v1beta1.ResourcesUnhealthy(u))
+		return false, nil
+	}
+	cr.Status.SetConditions(v1beta1.ResourcesHealthy())
+	return true, nil
+}
+
+// unhealthy returns the resources of the supplied manifest that are not
+// healthy, including resources that do not exist, together with the reason.
+func unhealthy(ctx context.Context, kube client.Client, mapper meta.RESTMapper, namespace, manifest string) ([]string, error) {
+	objs, err := parseManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []string
+	for i := range objs {
+		desired := &objs[i]
+		if err := defaultNamespace(mapper, desired, namespace); err != nil {
+			return nil, err
+		}
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(desired.GroupVersionKind())
+		err := kube.Get(ctx, types.NamespacedName{Name: desired.GetName(), Namespace: desired.GetNamespace()}, live)
+		if kerrors.IsNotFound(err) {
+			res = append(res, resourceKey(desired)+": not found")
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errFailedToGetLiveResource)
+		}
+		if ok, msg := healthy(live); !ok {
+			res = append(res, resourceKey(desired)+": "+msg)
+		}
+	}
+	return res, nil
+}
+
+// healthy assesses the health of a live resource following the semantics of
+// kstatus: a resource is healthy once its controller observed its latest
+// generation and it reached its desired state. Resources of kinds without
+// known health semantics are healthy unless their conditions say otherwise.
+func healthy(u *unstructured.Unstructured) (bool, string) {
+	if g, ok, _ := unstructured.NestedInt64(u.Object, "status", "observedGeneration"); ok && g < u.GetGeneration() {
+		return false, "latest generation not observed yet"
+	}
+	if conditionIs(u, "Stalled", "True") {
+		return false, "stalled"
+	}
+	if conditionIs(u, "Reconciling", "True") {
+		return false, "reconciling"
+	}
+
+	switch u.GroupVersionKind().GroupKind().String() {
+	case "Deployment.apps":
+		return replicasHealthy(u, "replicas", "updatedReplicas", "availableReplicas")
+	case "StatefulSet.apps":
+		return replicasHealthy(u, "replicas", "updatedReplicas", "readyReplicas")
+	case "ReplicaSet.apps":
+		return replicasHealthy(u, "replicas", "availableReplicas")
+	case "DaemonSet.apps":
+		d, _, _ := unstructured.NestedInt64(u.Object, "status", "desiredNumberScheduled")
+		for _, f := range []string{"updatedNumberScheduled", "numberAvailable"} {
+			if n, _, _ := unstructured.NestedInt64(u.Object, "status", f); n < d {
+				return false, fmt.Sprintf("%d/%d %s", n, d, f)
+			}
+		}
+		return true, ""
+	case "Job.batch":
+		switch {
+		case conditionIs(u, "Failed", "True"):
+			return false, "failed"
+		case conditionIs(u, "Complete", "True"):
+			return true, ""
+		}
+		return false, "not completed"
+	case "Pod":
+		p, _, _ := unstructured.NestedString(u.Object, "status", "phase")
+		if p == "Succeeded" || (p == "Running" && conditionIs(u, "Ready", "True")) {
+			return true, ""
+		}
+		return false, "not ready"
+	case "PersistentVolumeClaim":
+		if p, _, _ := unstructured.NestedString(u.Object, "status", "phase"); p != "Bound" {
+			return false, "not bound"
+		}
+		return true, ""
+	case "Service":
+		if t, _, _ := unstructured.NestedString(u.Object, "spec", "type"); t != "LoadBalancer" {
+			return true, ""
+		}
+		if in, _, _ := unstructured.NestedSlice(u.Object, "status", "loadBalancer", "ingress"); len(in) == 0 {
+			return false, "load balancer not provisioned"
+		}
+		return true, ""
+	case "CustomResourceDefinition.apiextensions.k8s.io":
+		if !conditionIs(u, "Established", "True") {
+			return false, "not established"
+		}
+		return true, ""
+	}
+
+	if conditionIs(u, "Ready", "False") {
+		return false, "not ready"
+	}
+	return true, ""
+}
+
+// replicasHealthy returns true if the status fields of a workload are at
+// least its desired number of replicas, which defaults to 1.
+func replicasHealthy(u *unstructured.Unstructured, desired string, fields ...string) (bool, string) {
+	want, ok, _ := unstructured.NestedInt64(u.Object, "spec", desired)
+	if !ok {
+		want = 1
+	}
+	for _, f := range fields {
+		if n, _, _ := unstructured.NestedInt64(u.Object, "status", f); n < want {
+			return false, fmt.Sprintf("%d/%d %s", n, want, f)
+		}
+	}
+	return true, ""
+}
+
+func conditionIs(u *unstructured.Unstructured, typ, status string) bool {
+	cs, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	for _, c := range cs {
+		m, ok := c.(map[string]interface{})
+		if ok && m["type"] == typ {
+			return m["status"] == status
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func testLive(apiVersion, kind string, spec, status map[string]interface{}) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": "test", "generation": int64(2)},
+	}}
+	if spec != nil {
+		u.Object["spec"] = spec
+	}
+	if status != nil {
+		u.Object["status"] = status
+	}
+	return u
+}
+
+func testConditions(typ, status string) []interface{} {
+	return []interface{}{map[string]interface{}{"type": typ, "status": status}}
+}
+
+func Test_healthy(t *testing.T) {
+	type want struct {
+		ok  bool
+		msg string
+	}
+	cases := map[string]struct {
+		u    *unstructured.Unstructured
+		want want
+	}{
+		"GenerationNotObserved": {
+			u:    testLive("v1", "ConfigMap", nil, map[string]interface{}{"observedGeneration": int64(1)}),
+			want: want{msg: "latest generation not observed yet"},
+		},
+		"Stalled": {
+			u:    testLive("example.org/v1", "Database", nil, map[string]interface{}{"conditions": testConditions("Stalled", "True")}),
+			want: want{msg: "stalled"},
+		},
+		"DeploymentUnavailable": {
+			u: testLive("apps/v1", "Deployment", map[string]interface{}{"replicas": int64(3)},
+				map[string]interface{}{"observedGeneration": int64(2), "updatedReplicas": int64(3), "availableReplicas": int64(1)}),
+			want: want{msg: "1/3 availableReplicas"},
+		},
+		"DeploymentAvailable": {
+			u: testLive("apps/v1", "Deployment", nil,
+				map[string]interface{}{"observedGeneration": int64(2), "updatedReplicas": int64(1), "availableReplicas": int64(1)}),
+			want: want{ok: true},
+		},
+		"DaemonSetRollingOut": {
+			u: testLive("apps/v1", "DaemonSet", nil,
+				map[string]interface{}{"desiredNumberScheduled": int64(2), "updatedNumberScheduled": int64(1), "numberAvailable": int64(2)}),
+			want: want{msg: "1/2 updatedNumberScheduled"},
+		},
+		"JobRunning": {
+			u:    testLive("batch/v1", "Job", nil, map[string]interface{}{"active": int64(1)}),
+			want: want{msg: "not completed"},
+		},
+		"JobFailed": {
+			u:    testLive("batch/v1", "Job", nil, map[string]interface{}{"conditions": testConditions("Failed", "True")}),
+			want: want{msg: "failed"},
+		},
+		"JobComplete": {
+			u:    testLive("batch/v1", "Job", nil, map[string]interface{}{"conditions": testConditions("Complete", "True")}),
+			want: want{ok: true},
+		},
+		"PVCPending": {
+			u:    testLive("v1", "PersistentVolumeClaim", nil, map[string]interface{}{"phase": "Pending"}),
+			want: want{msg: "not bound"},
+		},
+		"LoadBalancerPending": {
+			u:    testLive("v1", "Service", map[string]interface{}{"type": "LoadBalancer"}, nil),
+			want: want{msg: "load balancer not provisioned"},
+		},
+		"ClusterIPService": {
+			u:    testLive("v1", "Service", map[string]interface{}{"type": "ClusterIP"}, nil),
+			want: want{ok: true},
+		},
+		"CustomResourceNotReady": {
+			u:    testLive("example.org/v1", "Database", nil, map[string]interface{}{"conditions": testConditions("Ready", "False")}),
+			want: want{msg: "not ready"},
+		},
+		"CustomResourceWithoutStatus": {
+			u:    testLive("example.org/v1", "Database", nil, nil),
+			want: want{ok: true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ok, msg := healthy(tc.u)
+			if diff := cmp.Diff(tc.want, want{ok: ok, msg: msg}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("healthy(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_unhealthy(t *testing.T) {
+	manifest := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: present\n---\napiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: app\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: deleted\n"
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			u := obj.(*unstructured.Unstructured)
+			u.SetName(key.Name)
+			u.SetNamespace(key.Namespace)
+			switch key.Name {
+			case "present":
+			case "app":
+				u.Object["status"] = map[string]interface{}{"availableReplicas": int64(0)}
+			default:
+				return kerrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, key.Name)
+			}
+			return nil
+		},
+	}
+	got, err := unhealthy(context.Background(), kube, testRESTMapper(), testNamespace, manifest)
+	if err != nil {
+		t.Fatalf("unhealthy(...): unexpected error: %s", err)
+	}
+	want := []string{
+		"Deployment " + testNamespace + "/app: 0/1 updatedReplicas",
+		"ConfigMap " + testNamespace + "/deleted: not found",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unhealthy(...): -want, +got: %s", diff)
+	}
+}
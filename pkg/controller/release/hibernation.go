@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	errInvalidHibernateSchedule = "invalid hibernate schedule %q"
+	errInvalidWakeUpSchedule    = "invalid wake up schedule %q"
+	errFailedToHibernate        = "failed to uninstall hibernating release"
+
+	reasonHibernated event.Reason = "Hibernated"
+
+	// hibernationHorizon is how far back the last hibernate and wake up
+	// times are looked up.
+	hibernationHorizon = 366 * 24 * time.Hour
+)
+
+// hibernating returns whether the supplied hibernation schedule has put the
+// release to sleep at the supplied time, i.e. whether it last hibernated
+// after it last woke up.
+func hibernating(h *v1beta1.Hibernation, now time.Time) (bool, error) {
+	if h == nil {
+		return false, nil
+	}
+	hc, err := parseCron(h.Hibernate)
+	if err != nil {
+		return false, errors.Wrapf(err, errInvalidHibernateSchedule, h.Hibernate)
+	}
+	wc, err := parseCron(h.WakeUp)
+	if err != nil {
+		return false, errors.Wrapf(err, errInvalidWakeUpSchedule, h.WakeUp)
+	}
+	loc, err := location(h.TimeZone)
+	if err != nil {
+		return false, err
+	}
+
+	t := now.In(loc)
+	ht, ok := hc.prev(t, t.Add(-hibernationHorizon))
+	if !ok {
+		return false, nil
+	}
+	wt, ok := wc.prev(t, t.Add(-hibernationHorizon))
+	return !ok || ht.After(wt), nil
+}
@@ -0,0 +1,73 @@
+package release
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+func Test_hibernating(t *testing.T) {
+	workingHours := &v1beta1.Hibernation{Hibernate: "0 20 * * 1-5", WakeUp: "0 7 * * 1-5"}
+
+	type want struct {
+		hibernating bool
+		err         error
+	}
+	cases := map[string]struct {
+		hibernation *v1beta1.Hibernation
+		now         time.Time
+		want        want
+	}{
+		"NoHibernation": {
+			now: time.Date(2021, time.July, 14, 22, 0, 0, 0, time.UTC),
+		},
+		"Awake": {
+			hibernation: workingHours,
+			now:         time.Date(2021, time.July, 14, 10, 0, 0, 0, time.UTC),
+		},
+		"HibernatingAtNight": {
+			hibernation: workingHours,
+			now:         time.Date(2021, time.July, 14, 22, 0, 0, 0, time.UTC),
+			want:        want{hibernating: true},
+		},
+		"HibernatingOverWeekend": {
+			hibernation: workingHours,
+			// A Sunday.
+			now:  time.Date(2021, time.July, 18, 12, 0, 0, 0, time.UTC),
+			want: want{hibernating: true},
+		},
+		"WakeUpInTimeZone": {
+			hibernation: &v1beta1.Hibernation{Hibernate: "0 20 * * 1-5", WakeUp: "0 7 * * 1-5", TimeZone: "America/New_York"},
+			// 08:00 in New York.
+			now:  time.Date(2021, time.July, 14, 12, 0, 0, 0, time.UTC),
+			want: want{hibernating: false},
+		},
+		"StillAsleepInTimeZone": {
+			hibernation: &v1beta1.Hibernation{Hibernate: "0 20 * * 1-5", WakeUp: "0 7 * * 1-5", TimeZone: "America/New_York"},
+			// 06:00 in New York.
+			now:  time.Date(2021, time.July, 14, 10, 0, 0, 0, time.UTC),
+			want: want{hibernating: true},
+		},
+		"InvalidSchedule": {
+			hibernation: &v1beta1.Hibernation{Hibernate: "0 20 * *", WakeUp: "0 7 * * 1-5"},
+			want:        want{err: errors.Wrapf(errors.Errorf(errCronFields, 4), errInvalidHibernateSchedule, "0 20 * *")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := hibernating(tc.hibernation, tc.now)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("hibernating(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.hibernating, got); diff != "" {
+				t.Errorf("hibernating(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
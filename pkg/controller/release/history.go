@@ -0,0 +1,65 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/release"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+// maxStatusHistory is the number of most recent revisions of a release that
+// are listed in the status of its Release.
+const maxStatusHistory = 10
+
+const errFailedToGetHistory = "failed to get revision history"
+
+// history returns the most recent revisions of the release of the supplied
+// Release, newest first. The supplied previous history is returned as is if
+// its newest revision is the supplied current revision, in the same status,
+// to not read the history of the release on every observation.
+func (e *helmExternal) history(cr *v1beta1.Release, current *release.Release, prev []v1beta1.ReleaseRevision) ([]v1beta1.ReleaseRevision, error) {
+	if len(prev) > 0 && current.Info != nil && prev[0].Revision == current.Version && prev[0].Status == current.Info.Status {
+		return prev, nil
+	}
+	h, err := e.helm.History(meta.GetExternalName(cr))
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToGetHistory)
+	}
+	var out []v1beta1.ReleaseRevision
+	for i := len(h) - 1; i >= 0 && len(out) < maxStatusHistory; i-- {
+		out = append(out, releaseRevision(h[i]))
+	}
+	return out, nil
+}
+
+func releaseRevision(r *release.Release) v1beta1.ReleaseRevision {
+	rev := v1beta1.ReleaseRevision{Revision: r.Version}
+	if r.Info != nil {
+		rev.Status = r.Info.Status
+		rev.Description = r.Info.Description
+		rev.Deployed = metaTime(r.Info.LastDeployed.Time)
+	}
+	if r.Chart != nil && r.Chart.Metadata != nil {
+		rev.ChartVersion = r.Chart.Metadata.Version
+		rev.AppVersion = r.Chart.Metadata.AppVersion
+	}
+	return rev
+}
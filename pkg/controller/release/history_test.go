@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/release"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+func Test_helmExternal_history(t *testing.T) {
+	revision := func(v int, s release.Status) *release.Release {
+		return &release.Release{Version: v, Info: &release.Info{Status: s}}
+	}
+	type args struct {
+		helm    *MockHelmClient
+		current *release.Release
+		prev    []v1beta1.ReleaseRevision
+	}
+	type want struct {
+		out []v1beta1.ReleaseRevision
+		err error
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"Unchanged": {
+			args: args{
+				helm: &MockHelmClient{MockHistory: func(string) ([]*release.Release, error) {
+					return nil, errBoom
+				}},
+				current: revision(2, release.StatusDeployed),
+				prev:    []v1beta1.ReleaseRevision{{Revision: 2, Status: release.StatusDeployed}},
+			},
+			want: want{
+				out: []v1beta1.ReleaseRevision{{Revision: 2, Status: release.StatusDeployed}},
+			},
+		},
+		"NewestFirst": {
+			args: args{
+				helm: &MockHelmClient{MockHistory: func(string) ([]*release.Release, error) {
+					return []*release.Release{
+						revision(1, release.StatusSuperseded),
+						revision(2, release.StatusDeployed),
+					}, nil
+				}},
+				current: revision(2, release.StatusDeployed),
+				prev:    []v1beta1.ReleaseRevision{{Revision: 1, Status: release.StatusDeployed}},
+			},
+			want: want{
+				out: []v1beta1.ReleaseRevision{
+					{Revision: 2, Status: release.StatusDeployed},
+					{Revision: 1, Status: release.StatusSuperseded},
+				},
+			},
+		},
+		"Capped": {
+			args: args{
+				helm: &MockHelmClient{MockHistory: func(string) ([]*release.Release, error) {
+					h := make([]*release.Release, 0, maxStatusHistory+2)
+					for v := 1; v <= maxStatusHistory+2; v++ {
+						h = append(h, &release.Release{Version: v})
+					}
+					return h, nil
+				}},
+				current: revision(maxStatusHistory+2, release.StatusDeployed),
+			},
+			want: want{
+				out: func() []v1beta1.ReleaseRevision {
+					out := make([]v1beta1.ReleaseRevision, 0, maxStatusHistory)
+					for v := maxStatusHistory + 2; v > 2; v-- {
+						out = append(out, v1beta1.ReleaseRevision{Revision: v})
+					}
+					return out
+				}(),
+			},
+		},
+		"HistoryError": {
+			args: args{
+				helm: &MockHelmClient{MockHistory: func(string) ([]*release.Release, error) {
+					return nil, errBoom
+				}},
+				current: revision(1, release.StatusDeployed),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFailedToGetHistory),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &helmExternal{helm: tc.args.helm}
+			got, err := e.history(helmRelease(), tc.args.current, tc.args.prev)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("history(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.out, got); diff != "" {
+				t.Errorf("history(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	ktype "sigs.k8s.io/kustomize/api/types"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	errFailedToLint = "failed to lint chart"
+	errLintFailed   = "chart lint failed: %s"
+)
+
+// linted returns a deployAction that lints the chart with the composed values
+// before running the supplied action. Lint errors block the action, warnings
+// are reported in the Linted condition of the Release.
+func (e *helmExternal) linted(cr *v1beta1.Release, action deployAction) deployAction {
+	return func(rel string, ch *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (*release.Release, error) {
+		r, err := e.helm.Lint(ch, vals)
+		if err != nil {
+			return nil, errors.Wrap(err, errFailedToLint)
+		}
+		if len(r.Errors) > 0 {
+			err := errors.Errorf(errLintFailed, strings.Join(r.Errors, "; "))
+			cr.Status.SetConditions(v1beta1.LintFailed(err))
+			return nil, err
+		}
+		cr.Status.SetConditions(v1beta1.LintSucceeded(strings.Join(r.Warnings, "; ")))
+		return action(rel, ch, vals, patches)
+	}
+}
@@ -0,0 +1,68 @@
+package release
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/kustomize/api/types"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+	helmClient "github.com/crossplane-contrib/provider-helm/pkg/clients/helm"
+)
+
+func Test_linted(t *testing.T) {
+	type want struct {
+		err    error
+		status corev1.ConditionStatus
+		called bool
+	}
+	cases := map[string]struct {
+		lint MockLintFn
+		want want
+	}{
+		"LintPassed": {
+			lint: func(_ *chart.Chart, _ map[string]interface{}) (*helmClient.LintResult, error) {
+				return &helmClient.LintResult{Warnings: []string{"deprecated"}}, nil
+			},
+			want: want{status: corev1.ConditionTrue, called: true},
+		},
+		"LintFailed": {
+			lint: func(_ *chart.Chart, _ map[string]interface{}) (*helmClient.LintResult, error) {
+				return &helmClient.LintResult{Errors: []string{"broken"}}, nil
+			},
+			want: want{err: errors.Errorf(errLintFailed, "broken"), status: corev1.ConditionFalse},
+		},
+		"LintError": {
+			lint: func(_ *chart.Chart, _ map[string]interface{}) (*helmClient.LintResult, error) {
+				return nil, errBoom
+			},
+			want: want{err: errors.Wrap(errBoom, errFailedToLint), status: corev1.ConditionUnknown},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := helmRelease()
+			called := false
+			e := &helmExternal{helm: &MockHelmClient{MockLint: tc.lint}}
+			action := e.linted(cr, func(string, *chart.Chart, map[string]interface{}, []types.Patch) (*release.Release, error) {
+				called = true
+				return &release.Release{}, nil
+			})
+			_, err := action(testReleaseName, &chart.Chart{}, nil, nil)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("linted(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.called, called); diff != "" {
+				t.Errorf("linted(...): -want called, +got called: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.status, cr.Status.GetCondition(v1beta1.TypeLinted).Status); diff != "" {
+				t.Errorf("linted(...): -want status, +got status: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	errCreateNotAllowed = "release does not exist and the management policies do not allow creating it"
+)
+
+// managementAllows returns whether the management policies of the Release
+// allow the supplied action. Observe only Releases only allow observing.
+func managementAllows(cr *v1beta1.Release, a v1beta1.ManagementAction) bool {
+	if cr.Spec.ObserveOnly {
+		return a == v1beta1.ManagementActionObserve
+	}
+	p := cr.Spec.ManagementPolicies
+	if len(p) == 0 {
+		return true
+	}
+	for _, x := range p {
+		if x == a || x == v1beta1.ManagementActionAll {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package release
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+func Test_managementAllows(t *testing.T) {
+	cases := map[string]struct {
+		policies    []v1beta1.ManagementAction
+		observeOnly bool
+		action      v1beta1.ManagementAction
+		want        bool
+	}{
+		"Default": {
+			action: v1beta1.ManagementActionDelete,
+			want:   true,
+		},
+		"All": {
+			policies: []v1beta1.ManagementAction{v1beta1.ManagementActionAll},
+			action:   v1beta1.ManagementActionUpdate,
+			want:     true,
+		},
+		"Allowed": {
+			policies: []v1beta1.ManagementAction{v1beta1.ManagementActionObserve, v1beta1.ManagementActionCreate},
+			action:   v1beta1.ManagementActionCreate,
+			want:     true,
+		},
+		"NotAllowed": {
+			policies: []v1beta1.ManagementAction{v1beta1.ManagementActionObserve, v1beta1.ManagementActionCreate},
+			action:   v1beta1.ManagementActionDelete,
+		},
+		"ObserveOnly": {
+			policies:    []v1beta1.ManagementAction{v1beta1.ManagementActionAll},
+			observeOnly: true,
+			action:      v1beta1.ManagementActionUpdate,
+		},
+		"ObserveOnlyObserves": {
+			observeOnly: true,
+			action:      v1beta1.ManagementActionObserve,
+			want:        true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := helmRelease(func(r *v1beta1.Release) {
+				r.Spec.ManagementPolicies = tc.policies
+				r.Spec.ObserveOnly = tc.observeOnly
+			})
+			if diff := cmp.Diff(tc.want, managementAllows(cr, tc.action)); diff != "" {
+				t.Errorf("managementAllows(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/kube"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+	helmClient "github.com/crossplane-contrib/provider-helm/pkg/clients/helm"
+)
+
+const (
+	errFailedToParseManifest = "failed to parse release manifest"
+)
+
+// parseManifest splits a rendered release manifest into its resources, in
+// the order they appear in the manifest. Empty documents are skipped.
+func parseManifest(manifest string) ([]unstructured.Unstructured, error) {
+	docs := releaseutil.SplitManifests(manifest)
+	keys := make([]string, 0, len(docs))
+	for k := range docs {
+		keys = append(keys, k)
+	}
+	sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+
+	res := make([]unstructured.Unstructured, 0, len(keys))
+	for _, k := range keys {
+		u := unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(docs[k]), &u.Object); err != nil {
+			return nil, errors.Wrap(err, errFailedToParseManifest)
+		}
+		if len(u.Object) == 0 {
+			continue
+		}
+		res = append(res, u)
+	}
+	return res, nil
+}
+
+// hasCommonMetadata returns true if every resource in the manifest carries
+// the desired common labels and annotations, and no others were applied to
+// it. The keys that were applied are recorded in an annotation of each
+// resource.
+func hasCommonMetadata(manifest string, cm *v1beta1.CommonMetadata) (bool, error) {
+	if cm == nil {
+		cm = &v1beta1.CommonMetadata{}
+	}
+	if len(cm.Labels) == 0 && len(cm.Annotations) == 0 && !strings.Contains(manifest, v1beta1.AnnotationKeyCommonMetadata) {
+		return true, nil
+	}
+	objs, err := parseManifest(manifest)
+	if err != nil {
+		return false, err
+	}
+	for _, o := range objs {
+		if !containsAll(o.GetLabels(), cm.Labels) || !containsAll(o.GetAnnotations(), cm.Annotations) {
+			return false, nil
+		}
+		a := helmClient.AppliedMetadata{}
+		if v, ok := o.GetAnnotations()[v1beta1.AnnotationKeyCommonMetadata]; ok {
+			// A record that cannot be parsed is replaced by an upgrade.
+			if err := json.Unmarshal([]byte(v), &a); err != nil {
+				return false, nil
+			}
+		}
+		if !containsKeys(cm.Labels, a.Labels) || !containsKeys(cm.Annotations, a.Annotations) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// keptResources returns the resources of the manifest that Helm keeps on
+// uninstall because of their helm.sh/resource-policy annotation.
+func keptResources(manifest string) ([]string, error) {
+	objs, err := parseManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+	var kept []string
+	for i := range objs {
+		// Helm compares the policy the same way when uninstalling.
+		p := objs[i].GetAnnotations()[kube.ResourcePolicyAnno]
+		if strings.ToLower(strings.TrimSpace(p)) == kube.KeepPolicy {
+			kept = append(kept, resourceKey(&objs[i]))
+		}
+	}
+	return kept, nil
+}
+
+// inventory returns a reference to every resource of the manifest. Resources
+// of kinds that cannot be mapped yet, e.g. because their CRD is not
+// established, keep the namespace of the manifest.
+func inventory(mapper meta.RESTMapper, namespace, manifest string) ([]v1beta1.ResourceRef, error) {
+	objs, err := parseManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]v1beta1.ResourceRef, 0, len(objs))
+	for i := range objs {
+		o := &objs[i]
+		_ = defaultNamespace(mapper, o, namespace)
+		res = append(res, v1beta1.ResourceRef{
+			APIVersion: o.GetAPIVersion(),
+			Kind:       o.GetKind(),
+			Namespace:  o.GetNamespace(),
+			Name:       o.GetName(),
+		})
+	}
+	return res, nil
+}
+
+// images returns the sorted container images referenced by the resources of
+// the manifest. Containers are found anywhere in a resource, so that images of
+// custom resources embedding a pod template are included as well.
+func images(manifest string) ([]string, error) {
+	objs, err := parseManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+	found := map[string]bool{}
+	for _, o := range objs {
+		collectImages(o.Object, found)
+	}
+	res := make([]string, 0, len(found))
+	for i := range found {
+		res = append(res, i)
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
+func collectImages(v interface{}, found map[string]bool) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, c := range t {
+			if k == "containers" || k == "initContainers" || k == "ephemeralContainers" {
+				for _, ct := range asSlice(c) {
+					if m, ok := ct.(map[string]interface{}); ok {
+						if i, ok := m["image"].(string); ok && i != "" {
+							found[i] = true
+						}
+					}
+				}
+			}
+			collectImages(c, found)
+		}
+	case []interface{}:
+		for _, c := range t {
+			collectImages(c, found)
+		}
+	}
+}
+
+func asSlice(v interface{}) []interface{} {
+	s, _ := v.([]interface{})
+	return s
+}
+
+func containsKeys(have map[string]string, keys []string) bool {
+	for _, k := range keys {
+		if _, ok := have[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func containsAll(have, want map[string]string) bool {
+	for k, v := range want {
+		if hv, ok := have[k]; !ok || hv != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,168 @@
+package release
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const testManifest = `---
+# Source: chart/templates/cm.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+  labels:
+    team: platform
+---
+# Source: chart/templates/empty.yaml
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second
+  namespace: other
+  labels:
+    team: platform
+  annotations:
+    owner: ops
+    helm.sh/resource-policy: keep
+`
+
+func Test_parseManifest(t *testing.T) {
+	objs, err := parseManifest(testManifest)
+	if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+		t.Fatalf("parseManifest(...): -want error, +got error: %s", diff)
+	}
+	got := make([]string, 0, len(objs))
+	for _, o := range objs {
+		got = append(got, o.GetNamespace()+"/"+o.GetName())
+	}
+	if diff := cmp.Diff([]string{"/first", "other/second"}, got); diff != "" {
+		t.Errorf("parseManifest(...): -want, +got: %s", diff)
+	}
+}
+
+func Test_hasCommonMetadata(t *testing.T) {
+	recorded := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: first\n  labels:\n    team: platform\n    tier: web\n  annotations:\n    helm.crossplane.io/common-metadata: '{\"labels\":[\"team\",\"tier\"]}'\n"
+	cases := map[string]struct {
+		manifest string
+		cm       *v1beta1.CommonMetadata
+		want     bool
+	}{
+		"NoCommonMetadata": {
+			want: true,
+		},
+		"AllLabelsPresent": {
+			cm:   &v1beta1.CommonMetadata{Labels: map[string]string{"team": "platform"}},
+			want: true,
+		},
+		"LabelValueDifferent": {
+			cm:   &v1beta1.CommonMetadata{Labels: map[string]string{"team": "other"}},
+			want: false,
+		},
+		"AnnotationMissing": {
+			cm:   &v1beta1.CommonMetadata{Annotations: map[string]string{"owner": "ops"}},
+			want: false,
+		},
+		"RecordedLabelsPresent": {
+			manifest: recorded,
+			cm:       &v1beta1.CommonMetadata{Labels: map[string]string{"team": "platform", "tier": "web"}},
+			want:     true,
+		},
+		"RecordedLabelRemoved": {
+			manifest: recorded,
+			cm:       &v1beta1.CommonMetadata{Labels: map[string]string{"team": "platform"}},
+			want:     false,
+		},
+		"AllRecordedLabelsRemoved": {
+			manifest: recorded,
+			want:     false,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := tc.manifest
+			if m == "" {
+				m = testManifest
+			}
+			got, err := hasCommonMetadata(m, tc.cm)
+			if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("hasCommonMetadata(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("hasCommonMetadata(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_keptResources(t *testing.T) {
+	got, err := keptResources(testManifest)
+	if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+		t.Fatalf("keptResources(...): -want error, +got error: %s", diff)
+	}
+	if diff := cmp.Diff([]string{"ConfigMap other/second"}, got); diff != "" {
+		t.Errorf("keptResources(...): -want, +got: %s", diff)
+	}
+}
+
+func Test_inventory(t *testing.T) {
+	manifest := testManifest + "---\napiVersion: example.org/v1\nkind: Unknown\nmetadata:\n  name: third\n"
+	got, err := inventory(testRESTMapper(), testNamespace, manifest)
+	if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+		t.Fatalf("inventory(...): -want error, +got error: %s", diff)
+	}
+	want := []v1beta1.ResourceRef{
+		{APIVersion: "v1", Kind: "ConfigMap", Namespace: testNamespace, Name: "first"},
+		{APIVersion: "v1", Kind: "ConfigMap", Namespace: "other", Name: "second"},
+		{APIVersion: "example.org/v1", Kind: "Unknown", Name: "third"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("inventory(...): -want, +got: %s", diff)
+	}
+}
+
+func Test_images(t *testing.T) {
+	manifest := `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      initContainers:
+        - name: init
+          image: busybox:1.33
+      containers:
+        - name: app
+          image: nginx:1.21
+        - name: sidecar
+          image: envoyproxy/envoy:v1.19.0
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: job
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - name: job
+              image: nginx:1.21
+`
+	got, err := images(testManifest + manifest)
+	if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+		t.Fatalf("images(...): -want error, +got error: %s", diff)
+	}
+	want := []string{"busybox:1.33", "envoyproxy/envoy:v1.19.0", "nginx:1.21"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("images(...): -want, +got: %s", diff)
+	}
+}
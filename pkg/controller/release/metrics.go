@@ -0,0 +1,65 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	valueSourceSecret    = "secret"
+	valueSourceConfigMap = "configmap"
+	valueSourcePlugin    = "plugin"
+	valueSourceUnknown   = "unknown"
+)
+
+var (
+	valuesCompositionDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "provider_helm",
+		Subsystem: "values",
+		Name:      "composition_duration_seconds",
+		Help:      "Latency of composing the values of releases from their sources.",
+		Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5},
+	})
+
+	valueSourceFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "provider_helm",
+		Subsystem: "values",
+		Name:      "source_fetch_failures_total",
+		Help:      "Failures to fetch values from their sources, by source type.",
+	}, []string{"source"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(valuesCompositionDuration, valueSourceFailures)
+}
+
+// valueSourceType returns the type of the supplied value source.
+func valueSourceType(s v1beta1.ValueFromSource) string {
+	switch {
+	case s.SecretKeyRef != nil:
+		return valueSourceSecret
+	case s.ConfigMapKeyRef != nil:
+		return valueSourceConfigMap
+	case s.Plugin != nil:
+		return valueSourcePlugin
+	}
+	return valueSourceUnknown
+}
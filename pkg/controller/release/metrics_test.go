@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+func TestValueSourceType(t *testing.T) {
+	cases := map[string]struct {
+		source v1beta1.ValueFromSource
+		want   string
+	}{
+		"Secret": {
+			source: v1beta1.ValueFromSource{SecretKeyRef: &v1beta1.DataKeySelector{}},
+			want:   valueSourceSecret,
+		},
+		"ConfigMap": {
+			source: v1beta1.ValueFromSource{ConfigMapKeyRef: &v1beta1.DataKeySelector{}},
+			want:   valueSourceConfigMap,
+		},
+		"Unknown": {
+			want: valueSourceUnknown,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, valueSourceType(tc.source)); diff != "" {
+				t.Errorf("valueSourceType(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValueSourceFailures(t *testing.T) {
+	spec := v1beta1.ValuesSpec{
+		Set: []v1beta1.SetVal{{Name: "password", ValueFrom: &v1beta1.ValueFromSource{SecretKeyRef: &v1beta1.DataKeySelector{}}}},
+	}
+	before := testutil.ToFloat64(valueSourceFailures.WithLabelValues(valueSourceSecret))
+	if _, err := composeValuesFromSpec(context.Background(), &test.MockClient{MockGet: test.NewMockGetFn(errBoom)}, nil, spec); err == nil {
+		t.Fatal("composeValuesFromSpec(...): expected an error")
+	}
+	if diff := cmp.Diff(before+1, testutil.ToFloat64(valueSourceFailures.WithLabelValues(valueSourceSecret))); diff != "" {
+		t.Errorf("source_fetch_failures_total{source=\"secret\"}: -want, +got:\n%s", diff)
+	}
+}
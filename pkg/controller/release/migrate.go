@@ -0,0 +1,100 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/storage/driver"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	reasonPreviousReleaseKept event.Reason = "PreviousReleaseKept"
+)
+
+const (
+	errFailedToUninstallPrevious  = "failed to uninstall previous release"
+	errFailedToCreatePreviousHelm = "failed to create helm client for previous namespace"
+	errPreviousReleaseKept        = "release %s was renamed to %s, the previous release is kept but no longer managed"
+	errPreviousNamespaceKept      = "release moved from namespace %s to %s, the previous release is kept but no longer managed"
+	errNamespaceChangeForbidden   = "release cannot be moved from namespace %s to %s, change the namespace back or set a different namespaceChangePolicy"
+)
+
+// migrate handles the previously deployed release of a Release whose release
+// name or namespace changed according to its change policies.
+func (e *helmExternal) migrate(cr *v1beta1.Release) error {
+	prevNs, ns := cr.Status.ReleaseNamespace, cr.Spec.ForProvider.Namespace
+	if prevNs != "" && prevNs != ns {
+		return e.migrateNamespace(cr, prevNs)
+	}
+
+	prev, name := cr.Status.ReleaseName, meta.GetExternalName(cr)
+	if prev == "" || prev == name {
+		return nil
+	}
+
+	switch e.params(cr).ReleaseNameChangePolicy {
+	case v1beta1.ReleaseNameChangePolicyRecreate:
+		if err := e.helm.Uninstall(prev); err != nil && !errors.Is(err, driver.ErrReleaseNotFound) {
+			return errors.Wrap(err, errFailedToUninstallPrevious)
+		}
+		cr.Status.SetConditions(v1beta1.PreviousReleaseUninstalled(prev))
+	default:
+		e.recorder.Event(cr, event.Warning(reasonPreviousReleaseKept, errors.Errorf(errPreviousReleaseKept, prev, name)))
+		cr.Status.SetConditions(v1beta1.PreviousReleaseKept(prev))
+	}
+
+	// The previous release was handled, the current one is recorded once it
+	// is observed or deployed.
+	cr.Status.ReleaseName = ""
+	return nil
+}
+
+func (e *helmExternal) migrateNamespace(cr *v1beta1.Release, prevNs string) error {
+	ns := cr.Spec.ForProvider.Namespace
+	prev := cr.Status.ReleaseName
+	if prev == "" {
+		prev = meta.GetExternalName(cr)
+	}
+
+	switch e.params(cr).NamespaceChangePolicy {
+	case v1beta1.NamespaceChangePolicyRecreate:
+		h, err := e.newHelm(prevNs)
+		if err != nil {
+			return errors.Wrap(err, errFailedToCreatePreviousHelm)
+		}
+		if err := h.Uninstall(prev); err != nil && !errors.Is(err, driver.ErrReleaseNotFound) {
+			return errors.Wrap(err, errFailedToUninstallPrevious)
+		}
+		cr.Status.SetConditions(v1beta1.PreviousReleaseUninstalled(prevNs + "/" + prev))
+	case v1beta1.NamespaceChangePolicyOrphan:
+		e.recorder.Event(cr, event.Warning(reasonPreviousReleaseKept, errors.Errorf(errPreviousNamespaceKept, prevNs, ns)))
+		cr.Status.SetConditions(v1beta1.PreviousReleaseKept(prevNs + "/" + prev))
+	default:
+		err := errors.Errorf(errNamespaceChangeForbidden, prevNs, ns)
+		cr.Status.SetConditions(v1beta1.MigrationForbidden(err))
+		return err
+	}
+
+	cr.Status.ReleaseName = ""
+	cr.Status.ReleaseNamespace = ""
+	return nil
+}
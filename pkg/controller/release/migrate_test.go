@@ -0,0 +1,169 @@
+package release
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/storage/driver"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+	helmClient "github.com/crossplane-contrib/provider-helm/pkg/clients/helm"
+)
+
+func Test_migrate(t *testing.T) {
+	type want struct {
+		err         error
+		uninstalled string
+		reason      xpv1.ConditionReason
+		releaseName string
+		releaseNs   string
+	}
+	cases := map[string]struct {
+		previous     string
+		previousNs   string
+		policy       v1beta1.ReleaseNameChangePolicy
+		nsPolicy     v1beta1.NamespaceChangePolicy
+		class        *v1beta1.ReleaseClass
+		uninstallErr error
+		want         want
+	}{
+		"NoPreviousRelease": {},
+		"Unchanged": {
+			previous: testReleaseName,
+			want: want{
+				releaseName: testReleaseName,
+			},
+		},
+		"KeepByDefault": {
+			previous: "old",
+			want: want{
+				reason: v1beta1.ReasonPreviousReleaseKept,
+			},
+		},
+		"Recreate": {
+			previous: "old",
+			policy:   v1beta1.ReleaseNameChangePolicyRecreate,
+			want: want{
+				uninstalled: "old",
+				reason:      v1beta1.ReasonPreviousReleaseUninstalled,
+			},
+		},
+		"RecreateByClass": {
+			previous: "old",
+			class:    &v1beta1.ReleaseClass{Spec: v1beta1.ReleaseClassSpec{Defaults: v1beta1.ReleaseClassDefaults{ReleaseNameChangePolicy: v1beta1.ReleaseNameChangePolicyRecreate}}},
+			want: want{
+				uninstalled: "old",
+				reason:      v1beta1.ReasonPreviousReleaseUninstalled,
+			},
+		},
+		"KeepOverridesClass": {
+			previous: "old",
+			policy:   v1beta1.ReleaseNameChangePolicyKeep,
+			class:    &v1beta1.ReleaseClass{Spec: v1beta1.ReleaseClassSpec{Defaults: v1beta1.ReleaseClassDefaults{ReleaseNameChangePolicy: v1beta1.ReleaseNameChangePolicyRecreate}}},
+			want: want{
+				reason: v1beta1.ReasonPreviousReleaseKept,
+			},
+		},
+		"RecreatePreviousReleaseGone": {
+			previous:     "old",
+			policy:       v1beta1.ReleaseNameChangePolicyRecreate,
+			uninstallErr: errors.Wrap(driver.ErrReleaseNotFound, "uninstall"),
+			want: want{
+				uninstalled: "old",
+				reason:      v1beta1.ReasonPreviousReleaseUninstalled,
+			},
+		},
+		"RecreateFailed": {
+			previous:     "old",
+			policy:       v1beta1.ReleaseNameChangePolicyRecreate,
+			uninstallErr: errBoom,
+			want: want{
+				err:         errors.Wrap(errBoom, errFailedToUninstallPrevious),
+				uninstalled: "old",
+				releaseName: "old",
+			},
+		},
+		"NamespaceChangeForbiddenByDefault": {
+			previous:   testReleaseName,
+			previousNs: "old-ns",
+			want: want{
+				err:         errors.Errorf(errNamespaceChangeForbidden, "old-ns", testNamespace),
+				reason:      v1beta1.ReasonMigrationForbidden,
+				releaseName: testReleaseName,
+				releaseNs:   "old-ns",
+			},
+		},
+		"NamespaceChangeRecreate": {
+			previous:   testReleaseName,
+			previousNs: "old-ns",
+			nsPolicy:   v1beta1.NamespaceChangePolicyRecreate,
+			want: want{
+				uninstalled: "old-ns/" + testReleaseName,
+				reason:      v1beta1.ReasonPreviousReleaseUninstalled,
+			},
+		},
+		"NamespaceChangeOrphan": {
+			previous:   testReleaseName,
+			previousNs: "old-ns",
+			nsPolicy:   v1beta1.NamespaceChangePolicyOrphan,
+			want: want{
+				reason: v1beta1.ReasonPreviousReleaseKept,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			uninstalled := ""
+			e := &helmExternal{
+				logger:   logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
+				class:    tc.class,
+				helm: &MockHelmClient{
+					MockUninstall: func(release string) error {
+						uninstalled = release
+						return tc.uninstallErr
+					},
+				},
+				newHelm: func(namespace string) (helmClient.Client, error) {
+					return &MockHelmClient{
+						MockUninstall: func(release string) error {
+							uninstalled = namespace + "/" + release
+							return tc.uninstallErr
+						},
+					}, nil
+				},
+			}
+			cr := helmRelease(func(r *v1beta1.Release) {
+				meta.SetExternalName(r, testReleaseName)
+				r.Spec.ForProvider.Namespace = testNamespace
+				r.Spec.ForProvider.ReleaseNameChangePolicy = tc.policy
+				r.Spec.ForProvider.NamespaceChangePolicy = tc.nsPolicy
+				r.Status.ReleaseName = tc.previous
+				r.Status.ReleaseNamespace = tc.previousNs
+			})
+			err := e.migrate(cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("e.migrate(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.uninstalled, uninstalled); diff != "" {
+				t.Errorf("e.migrate(...): -want uninstalled, +got uninstalled: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.reason, cr.Status.GetCondition(v1beta1.TypeMigrated).Reason); diff != "" {
+				t.Errorf("e.migrate(...): -want reason, +got reason: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.releaseName, cr.Status.ReleaseName); diff != "" {
+				t.Errorf("e.migrate(...): -want release name, +got release name: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.releaseNs, cr.Status.ReleaseNamespace); diff != "" {
+				t.Errorf("e.migrate(...): -want release namespace, +got release namespace: %s", diff)
+			}
+		})
+	}
+}
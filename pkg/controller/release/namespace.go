@@ -0,0 +1,148 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/kube"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	// namespaceReleaseAnnotation records the release a namespace was created
+	// for. Only namespaces carrying it are deleted with their release.
+	namespaceReleaseAnnotation = "helm.crossplane.io/release"
+
+	helmStorageOwnerLabel = "owner"
+	helmStorageNameLabel  = "name"
+	helmStorageOwner      = "helm"
+
+	reasonNamespaceKept event.Reason = "NamespaceKept"
+)
+
+const (
+	errFailedToGetNamespace      = "failed to get release namespace"
+	errFailedToListNamespace     = "failed to list resources in release namespace"
+	errFailedToDeleteNamespace   = "failed to delete release namespace"
+	errNamespaceNotCreatedForRel = "namespace %s was not created for this release"
+	errNamespaceNotEmpty         = "namespace %s still contains resources: %s"
+)
+
+// namespaceKinds are checked for remaining resources before the namespace of
+// a release is deleted.
+var namespaceKinds = []schema.GroupVersionKind{
+	{Version: "v1", Kind: "ConfigMapList"},
+	{Version: "v1", Kind: "SecretList"},
+	{Version: "v1", Kind: "ServiceList"},
+	{Version: "v1", Kind: "ServiceAccountList"},
+	{Version: "v1", Kind: "PersistentVolumeClaimList"},
+	{Group: "apps", Version: "v1", Kind: "DeploymentList"},
+	{Group: "apps", Version: "v1", Kind: "StatefulSetList"},
+	{Group: "apps", Version: "v1", Kind: "DaemonSetList"},
+	{Group: "batch", Version: "v1", Kind: "JobList"},
+}
+
+// deleteNamespace deletes the namespace of an uninstalled release if it was
+// created for the release and nothing else lives in it anymore. The release
+// is uninstalled already, so a namespace that cannot be deleted is reported
+// in an event rather than failing the deletion of the Release.
+func (e *helmExternal) deleteNamespace(ctx context.Context, cr *v1beta1.Release) {
+	ns := cr.Spec.ForProvider.Namespace
+	if err := deleteNamespace(ctx, e.kube, ns, meta.GetExternalName(cr)); err != nil {
+		e.recorder.Event(cr, event.Warning(reasonNamespaceKept, err))
+	}
+}
+
+func deleteNamespace(ctx context.Context, kube client.Client, name, rel string) error {
+	ns := &corev1.Namespace{}
+	if err := kube.Get(ctx, types.NamespacedName{Name: name}, ns); err != nil {
+		return errors.Wrap(resource.IgnoreNotFound(err), errFailedToGetNamespace)
+	}
+	if ns.GetAnnotations()[namespaceReleaseAnnotation] != rel {
+		return errors.Errorf(errNamespaceNotCreatedForRel, name)
+	}
+
+	rem, err := remainingResources(ctx, kube, name, rel)
+	if err != nil {
+		return err
+	}
+	if len(rem) > 0 {
+		return errors.Errorf(errNamespaceNotEmpty, name, strings.Join(rem, ", "))
+	}
+
+	return errors.Wrap(resource.IgnoreNotFound(kube.Delete(ctx, ns)), errFailedToDeleteNamespace)
+}
+
+// remainingResources returns the resources in the namespace that keep it from
+// being deleted with the release: resources that are not part of the
+// release, resources Helm kept on uninstall and other Helm releases. Resources
+// Kubernetes creates in every namespace are ignored.
+func remainingResources(ctx context.Context, kube client.Client, namespace, rel string) ([]string, error) {
+	var rem []string
+	for _, gvk := range namespaceKinds {
+		l := &unstructured.UnstructuredList{}
+		l.SetGroupVersionKind(gvk)
+		if err := kube.List(ctx, l, client.InNamespace(namespace)); err != nil {
+			return nil, errors.Wrap(err, errFailedToListNamespace)
+		}
+		for i := range l.Items {
+			o := l.Items[i]
+			if o.GetKind() == "" {
+				o.SetKind(strings.TrimSuffix(gvk.Kind, "List"))
+			}
+			if ignoredInNamespace(o, namespace, rel) {
+				continue
+			}
+			if o.GetKind() == "Secret" && o.GetLabels()[helmStorageOwnerLabel] == helmStorageOwner {
+				rem = append(rem, "release "+o.GetLabels()[helmStorageNameLabel])
+				continue
+			}
+			rem = append(rem, resourceKey(&o))
+		}
+	}
+	return rem, nil
+}
+
+func ignoredInNamespace(o unstructured.Unstructured, namespace, rel string) bool {
+	switch {
+	case o.GetKind() == "ConfigMap" && o.GetName() == "kube-root-ca.crt":
+		return true
+	case o.GetKind() == "ServiceAccount" && o.GetName() == "default":
+		return true
+	case o.GetKind() == "Secret" && o.Object["type"] == string(corev1.SecretTypeServiceAccountToken):
+		return true
+	case o.GetKind() == "Secret" && o.GetLabels()[helmStorageOwnerLabel] == helmStorageOwner:
+		// Storage records of the uninstalled release itself.
+		return o.GetLabels()[helmStorageNameLabel] == rel
+	}
+	// Resources of the release that are still being deleted.
+	return partOfRelease(o, rel, namespace) &&
+		strings.ToLower(strings.TrimSpace(o.GetAnnotations()[kube.ResourcePolicyAnno])) != kube.KeepPolicy
+}
@@ -0,0 +1,124 @@
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func testNamespaceGet(annotations map[string]string) test.MockGetFn {
+	return func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+		ns := obj.(*corev1.Namespace)
+		ns.SetName(key.Name)
+		ns.SetAnnotations(annotations)
+		return nil
+	}
+}
+
+func testNamespaceList(items map[string][]unstructured.Unstructured) test.MockListFn {
+	return func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+		l := list.(*unstructured.UnstructuredList)
+		l.Items = items[l.GetKind()]
+		return nil
+	}
+}
+
+func testObject(kind, name string, annotations, labels map[string]string) unstructured.Unstructured {
+	u := unstructured.Unstructured{}
+	u.SetKind(kind)
+	u.SetName(name)
+	u.SetNamespace(testNamespace)
+	u.SetAnnotations(annotations)
+	u.SetLabels(labels)
+	return u
+}
+
+func Test_deleteNamespace(t *testing.T) {
+	ownAnnotations := map[string]string{namespaceReleaseAnnotation: testReleaseName}
+	releaseAnnotations := map[string]string{
+		helmReleaseNameAnnotation:      testReleaseName,
+		helmReleaseNamespaceAnnotation: testNamespace,
+	}
+
+	cases := map[string]struct {
+		kube    *test.MockClient
+		deleted bool
+		err     error
+	}{
+		"NamespaceNotFound": {
+			kube: &test.MockClient{
+				MockGet: test.NewMockGetFn(kerrors.NewNotFound(corev1.Resource("namespaces"), testNamespace)),
+			},
+		},
+		"NotCreatedForRelease": {
+			kube: &test.MockClient{
+				MockGet: testNamespaceGet(nil),
+			},
+			err: errors.Errorf(errNamespaceNotCreatedForRel, testNamespace),
+		},
+		"OtherResourcesRemain": {
+			kube: &test.MockClient{
+				MockGet: testNamespaceGet(ownAnnotations),
+				MockList: testNamespaceList(map[string][]unstructured.Unstructured{
+					"SecretList": {
+						testObject("Secret", "sh.helm.release.v1.other.v1", nil, map[string]string{helmStorageOwnerLabel: helmStorageOwner, helmStorageNameLabel: "other"}),
+						testObject("Secret", "sh.helm.release.v1.test-release.v1", nil, map[string]string{helmStorageOwnerLabel: helmStorageOwner, helmStorageNameLabel: testReleaseName}),
+					},
+					"ConfigMapList": {
+						testObject("ConfigMap", "kube-root-ca.crt", nil, nil),
+						testObject("ConfigMap", "kept", map[string]string{
+							helmReleaseNameAnnotation:      testReleaseName,
+							helmReleaseNamespaceAnnotation: testNamespace,
+							"helm.sh/resource-policy":      "keep",
+						}, nil),
+					},
+					"DeploymentList": {
+						testObject("Deployment", "unrelated", nil, nil),
+					},
+				}),
+			},
+			err: errors.Errorf(errNamespaceNotEmpty, testNamespace,
+				"ConfigMap testns/kept, release other, Deployment testns/unrelated"),
+		},
+		"Success": {
+			kube: &test.MockClient{
+				MockGet: testNamespaceGet(ownAnnotations),
+				MockList: testNamespaceList(map[string][]unstructured.Unstructured{
+					"ServiceAccountList": {
+						testObject("ServiceAccount", "default", nil, nil),
+					},
+					"DeploymentList": {
+						testObject("Deployment", "terminating", releaseAnnotations, nil),
+					},
+				}),
+			},
+			deleted: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			deleted := false
+			tc.kube.MockDelete = func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+				if _, ok := obj.(*corev1.Namespace); ok && obj.GetName() == testNamespace {
+					deleted = true
+				}
+				return nil
+			}
+			err := deleteNamespace(context.Background(), tc.kube, testNamespace, testReleaseName)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("deleteNamespace(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.deleted, deleted); diff != "" {
+				t.Errorf("deleteNamespace(...): -want deleted, +got deleted: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+	helmv1beta1 "github.com/crossplane-contrib/provider-helm/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-helm/pkg/clients/notify"
+)
+
+const (
+	errFailedToGetNotificationURL = "failed to get notification URL"
+	errMissingNotificationURL     = "missing key \"%s\" in notification URL secret"
+	errFailedToConfigureSink      = "failed to configure notification sink"
+	errFailedToNotify             = "failed to send notification"
+)
+
+// defaultNotificationEvents are notified by sinks that don't select events.
+var defaultNotificationEvents = []helmv1beta1.NotificationEvent{
+	helmv1beta1.NotificationEvent(reasonInstallFailed),
+	helmv1beta1.NotificationEvent(reasonUpgradeFailed),
+	helmv1beta1.NotificationEvent(reasonRollbackFailed),
+	helmv1beta1.NotificationEvent(reasonTestsFailed),
+}
+
+// A notificationSink sends notifications about the events it selects.
+type notificationSink struct {
+	sink   sender
+	events map[event.Reason]bool
+}
+
+type sender interface {
+	Send(ctx context.Context, n notify.Notification) error
+}
+
+// notificationSinks returns the sinks of the notifications of the supplied
+// Release, configured by the Release as well as its ProviderConfig.
+func notificationSinks(ctx context.Context, kube client.Client, pc *helmv1beta1.ProviderConfig, cr *v1beta1.Release) ([]notificationSink, error) {
+	cfgs := append(append([]helmv1beta1.NotificationSink{}, pc.Spec.Notifications...), cr.Spec.ForProvider.Notifications...)
+	sinks := make([]notificationSink, 0, len(cfgs))
+	for _, c := range cfgs {
+		url := c.URL
+		if r := c.URLSecretRef; r != nil {
+			d, err := getSecretData(ctx, kube, types.NamespacedName{Name: r.Name, Namespace: r.Namespace})
+			if err != nil {
+				return nil, errors.Wrap(err, errFailedToGetNotificationURL)
+			}
+			u, ok := d[r.Key]
+			if !ok {
+				return nil, errors.Errorf(errMissingNotificationURL, r.Key)
+			}
+			url = string(u)
+		}
+		nc := notify.Config{Type: string(c.Type), URL: url, Template: c.Template, Headers: c.Headers}
+		if c.Timeout != nil {
+			nc.Timeout = c.Timeout.Duration
+		}
+		s, err := notify.NewSink(nc)
+		if err != nil {
+			return nil, errors.Wrap(err, errFailedToConfigureSink)
+		}
+		events := c.Events
+		if len(events) == 0 {
+			events = defaultNotificationEvents
+		}
+		ns := notificationSink{sink: s, events: make(map[event.Reason]bool, len(events))}
+		for _, e := range events {
+			ns.events[event.Reason(e)] = true
+		}
+		sinks = append(sinks, ns)
+	}
+	return sinks, nil
+}
+
+// A notifyingRecorder records events and sends notifications about those of
+// Releases that the supplied sinks select. Failures to send notifications are
+// logged rather than returned, so that they don't block deploys.
+type notifyingRecorder struct {
+	event.Recorder
+	sinks  []notificationSink
+	logger logging.Logger
+}
+
+// withNotifications returns a recorder that notifies the supplied sinks of
+// the events it records, or the supplied recorder if there are no sinks.
+func withNotifications(r event.Recorder, sinks []notificationSink, l logging.Logger) event.Recorder {
+	if len(sinks) == 0 {
+		return r
+	}
+	return &notifyingRecorder{Recorder: r, sinks: sinks, logger: l}
+}
+
+func (r *notifyingRecorder) Event(obj runtime.Object, e event.Event) {
+	r.Recorder.Event(obj, e)
+
+	cr, ok := obj.(*v1beta1.Release)
+	if !ok {
+		return
+	}
+	n := notify.Notification{
+		Release:     cr.GetName(),
+		ReleaseName: meta.GetExternalName(cr),
+		Namespace:   cr.Spec.ForProvider.Namespace,
+		Type:        string(e.Type),
+		Reason:      string(e.Reason),
+		Message:     e.Message,
+	}
+	for _, s := range r.sinks {
+		if !s.events[e.Reason] {
+			continue
+		}
+		if err := s.sink.Send(context.Background(), n); err != nil {
+			r.logger.Info(errFailedToNotify, "reason", e.Reason, "error", err)
+		}
+	}
+}
+
+func (r *notifyingRecorder) WithAnnotations(keysAndValues ...string) event.Recorder {
+	return &notifyingRecorder{Recorder: r.Recorder.WithAnnotations(keysAndValues...), sinks: r.sinks, logger: r.logger}
+}
+
+// recordTests emits an event if the test hooks of the release failed, and
+// did not fail the same way before, as reported by the supplied previous
+// Tested condition.
+func (e *helmExternal) recordTests(cr *v1beta1.Release, prev xpv1.Condition) {
+	c := cr.Status.GetCondition(v1beta1.TypeTested)
+	if c.Reason != v1beta1.ReasonTestsFailed || (prev.Reason == c.Reason && prev.Message == c.Message) {
+		return
+	}
+	e.recorder.Event(cr, event.Warning(reasonTestsFailed, errors.Errorf("test hooks of revision %d failed: %s", cr.Status.AtProvider.Revision, c.Message)))
+}
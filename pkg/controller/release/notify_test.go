@@ -0,0 +1,180 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+	helmv1beta1 "github.com/crossplane-contrib/provider-helm/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-helm/pkg/clients/notify"
+)
+
+// A fakeSender records the notifications it is passed.
+type fakeSender struct {
+	sent []notify.Notification
+	err  error
+}
+
+func (s *fakeSender) Send(_ context.Context, n notify.Notification) error {
+	s.sent = append(s.sent, n)
+	return s.err
+}
+
+func Test_notificationSinks(t *testing.T) {
+	ref := &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Name: testSecretName, Namespace: testNamespace}, Key: "url"}
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			*obj.(*corev1.Secret) = corev1.Secret{Data: map[string][]byte{"url": []byte("https://hooks.example.org")}}
+			return nil
+		},
+	}
+
+	type want struct {
+		events []map[event.Reason]bool
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		pc     []helmv1beta1.NotificationSink
+		cr     []helmv1beta1.NotificationSink
+		want   want
+	}{
+		"None": {
+			reason: "No sinks should be returned if no notifications are configured.",
+			want:   want{events: []map[event.Reason]bool{}},
+		},
+		"ProviderConfigAndRelease": {
+			reason: "Sinks of the ProviderConfig and the Release should be returned, with failures notified by default.",
+			pc:     []helmv1beta1.NotificationSink{{URL: "https://notify.example.org"}},
+			cr: []helmv1beta1.NotificationSink{{
+				Type:         helmv1beta1.NotificationSinkSlack,
+				URLSecretRef: ref,
+				Events:       []helmv1beta1.NotificationEvent{"Installed"},
+			}},
+			want: want{events: []map[event.Reason]bool{
+				{reasonInstallFailed: true, reasonUpgradeFailed: true, reasonRollbackFailed: true, reasonTestsFailed: true},
+				{reasonInstalled: true},
+			}},
+		},
+		"MissingKey": {
+			reason: "A missing key of the URL secret should be returned as an error.",
+			cr: []helmv1beta1.NotificationSink{{
+				URLSecretRef: &xpv1.SecretKeySelector{SecretReference: ref.SecretReference, Key: "nope"},
+			}},
+			want: want{err: errors.Errorf(errMissingNotificationURL, "nope")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pc := &helmv1beta1.ProviderConfig{Spec: helmv1beta1.ProviderConfigSpec{Notifications: tc.pc}}
+			cr := helmRelease(func(r *v1beta1.Release) { r.Spec.ForProvider.Notifications = tc.cr })
+			sinks, err := notificationSinks(context.Background(), kube, pc, cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nnotificationSinks(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			events := make([]map[event.Reason]bool, 0, len(sinks))
+			for _, s := range sinks {
+				events = append(events, s.events)
+			}
+			if diff := cmp.Diff(tc.want.events, events); diff != "" {
+				t.Errorf("\n%s\nnotificationSinks(...): -want events, +got events:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func Test_notifyingRecorder(t *testing.T) {
+	failures := &fakeSender{err: errBoom}
+	installs := &fakeSender{}
+	rec := &eventRecorder{}
+	r := withNotifications(rec, []notificationSink{
+		{sink: failures, events: map[event.Reason]bool{reasonInstallFailed: true}},
+		{sink: installs, events: map[event.Reason]bool{reasonInstalled: true, reasonInstallFailed: true}},
+	}, logging.NewNopLogger())
+
+	cr := helmRelease(func(r *v1beta1.Release) { r.Spec.ForProvider.Namespace = "apps" })
+	meta.SetExternalName(cr, "web")
+	r.Event(cr, event.Warning(reasonInstallFailed, errBoom))
+	r.Event(cr, event.Normal(reasonInstalled, "Installed revision 1"))
+	r.Event(cr, event.Normal(reasonUpgraded, "Upgraded to revision 2"))
+
+	failed := notify.Notification{Release: testReleaseName, ReleaseName: "web", Namespace: "apps", Type: "Warning", Reason: string(reasonInstallFailed), Message: errBoom.Error()}
+	installed := notify.Notification{Release: testReleaseName, ReleaseName: "web", Namespace: "apps", Type: "Normal", Reason: string(reasonInstalled), Message: "Installed revision 1"}
+	if diff := cmp.Diff([]event.Reason{reasonInstallFailed, reasonInstalled, reasonUpgraded}, rec.reasons); diff != "" {
+		t.Errorf("Event(...): all events must be recorded: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff([]notify.Notification{failed}, failures.sent); diff != "" {
+		t.Errorf("Event(...): -want notifications, +got notifications:\n%s", diff)
+	}
+	if diff := cmp.Diff([]notify.Notification{failed, installed}, installs.sent); diff != "" {
+		t.Errorf("Event(...): failing sinks must not block others: -want notifications, +got notifications:\n%s", diff)
+	}
+}
+
+func Test_recordTests(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		prev   xpv1.Condition
+		cur    xpv1.Condition
+		want   []event.Reason
+	}{
+		"Failed": {
+			reason: "Failed test hooks should be reported.",
+			prev:   v1beta1.TestsNotRun(),
+			cur:    v1beta1.TestsFailed([]string{"test-connection"}),
+			want:   []event.Reason{reasonTestsFailed},
+		},
+		"StillFailed": {
+			reason: "Test hooks that still fail the same way should not be reported again.",
+			prev:   v1beta1.TestsFailed([]string{"test-connection"}),
+			cur:    v1beta1.TestsFailed([]string{"test-connection"}),
+		},
+		"Passed": {
+			reason: "Passed test hooks should not be reported.",
+			prev:   v1beta1.TestsFailed([]string{"test-connection"}),
+			cur:    v1beta1.TestsPassed(),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rec := &eventRecorder{}
+			e := &helmExternal{recorder: rec}
+			cr := helmRelease()
+			cr.Status.SetConditions(tc.cur)
+			e.recordTests(cr, tc.prev)
+			if diff := cmp.Diff(tc.want, rec.reasons); diff != "" {
+				t.Errorf("\n%s\nrecordTests(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
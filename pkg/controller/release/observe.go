@@ -17,16 +17,23 @@ limitations under the License.
 package release
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"fmt"
+	"regexp"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/jsonpath"
 
 	"github.com/pkg/errors"
 	"helm.sh/helm/v3/pkg/release"
@@ -36,11 +43,26 @@ import (
 	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
 )
 
+// Objects of connection details may be missing for this long after a
+// release was deployed, unless configured otherwise.
+const defaultConnectionDetailsTimeout = 5 * time.Minute
+
 const (
 	errReleaseInfoNilInObservedRelease = "release info is nil in observed helm release"
 	errChartNilInObservedRelease       = "chart field is nil in observed helm release"
 	errChartMetaNilInObservedRelease   = "chart metadata field is nil in observed helm release"
 	errObjectNotPartOfRelease          = "object is not part of release: %v"
+	errParseJSONPath                   = "cannot parse jsonPath: %s"
+	errParseConnectionTemplate         = "cannot parse template of connection detail %q"
+	errRenderConnectionTemplate        = "cannot render template of connection detail %q"
+	errConnectionDetailsMissing        = "objects of connection details do not exist: %s"
+	errAllKeysNotSecret                = "cannot propagate all keys of %s: not a Secret"
+	errDecodeConnectionDetail          = "failed to decode base64 encoded value"
+	errParseNotesRegex                 = "cannot parse notesRegex: %s"
+	errNotesRegexNoMatch               = "release notes do not match notesRegex: %s"
+	errParseTransformRegex             = "cannot parse regex of transform: %s"
+	errTransformRegexNoMatch           = "value does not match regex of transform: %s"
+	errUnknownTransform                = "unknown transform type %q"
 )
 
 // generateObservation generates release observation for the input release object
@@ -52,12 +74,28 @@ func generateObservation(in *release.Release) v1beta1.ReleaseObservation {
 		o.State = relInfo.Status
 		o.ReleaseDescription = relInfo.Description
 		o.Revision = in.Version
+		o.FirstDeployed = metaTime(relInfo.FirstDeployed.Time)
+		o.LastDeployed = metaTime(relInfo.LastDeployed.Time)
+		o.Notes = relInfo.Notes
+	}
+	if in.Chart != nil && in.Chart.Metadata != nil {
+		o.ChartVersion = in.Chart.Metadata.Version
+		o.AppVersion = in.Chart.Metadata.AppVersion
 	}
 	return o
 }
 
+// metaTime returns the supplied time, or nil if it is zero.
+func metaTime(t time.Time) *metav1.Time {
+	if t.IsZero() {
+		return nil
+	}
+	mt := metav1.NewTime(t)
+	return &mt
+}
+
 // isUpToDate checks whether desired spec up to date with the observed state for a given release
-func isUpToDate(ctx context.Context, kube client.Client, in *v1beta1.ReleaseParameters, observed *release.Release, s v1beta1.ReleaseStatus) (bool, error) {
+func isUpToDate(ctx context.Context, kube client.Client, plugins valuePlugins, in *v1beta1.ReleaseParameters, observed *release.Release, s v1beta1.ReleaseStatus) (bool, error) {
 	if observed.Info == nil {
 		return false, errors.New(errReleaseInfoNilInObservedRelease)
 	}
@@ -81,7 +119,7 @@ func isUpToDate(ctx context.Context, kube client.Client, in *v1beta1.ReleasePara
 	if in.Chart.Version != ocm.Version {
 		return false, nil
 	}
-	desiredConfig, err := composeValuesFromSpec(ctx, kube, in.ValuesSpec)
+	desiredConfig, err := composeValuesFromSpec(ctx, kube, plugins, in.ValuesSpec)
 	if err != nil {
 		return false, errors.Wrap(err, errFailedToComposeValues)
 	}
@@ -107,7 +145,7 @@ func isUpToDate(ctx context.Context, kube client.Client, in *v1beta1.ReleasePara
 		return false, nil
 	}
 
-	changed, err := newPatcher().hasUpdates(ctx, kube, in.PatchesFrom, s)
+	changed, err := newPatcher().hasUpdates(ctx, kube, plugins, in, s)
 	if err != nil {
 		return false, errors.Wrap(err, errFailedToLoadPatches)
 	}
@@ -116,46 +154,290 @@ func isUpToDate(ctx context.Context, kube client.Client, in *v1beta1.ReleasePara
 		return false, nil
 	}
 
-	return true, nil
+	return hasCommonMetadata(observed.Manifest, commonMetadata(in))
+}
+
+// lateInitialize sets the chart name, version and repository of the supplied
+// parameters from the observed release if they are not set. Values are not
+// late initialized, because they may contain secrets that would be copied
+// into the spec. It returns true if any parameter was set.
+func lateInitialize(in *v1beta1.ReleaseParameters, observed *release.Release) bool {
+	ocm := observed.Chart
+	if ocm == nil || ocm.Metadata == nil {
+		return false
+	}
+	li := false
+	if in.Chart.Name == "" && in.Chart.URL == "" {
+		in.Chart.Name = ocm.Metadata.Name
+		li = true
+	}
+	if in.Chart.Version == "" {
+		in.Chart.Version = ocm.Metadata.Version
+		li = true
+	}
+	if r := ocm.Metadata.Annotations[v1beta1.AnnotationKeyChartRepository]; r != "" && in.Chart.Repository == "" && in.Chart.URL == "" {
+		in.Chart.Repository = r
+		li = true
+	}
+	return li
 }
 
 func isPending(s release.Status) bool {
 	return s == release.StatusPendingInstall || s == release.StatusPendingUpgrade || s == release.StatusPendingRollback
 }
 
-func connectionDetails(ctx context.Context, kube client.Client, connDetails []v1beta1.ConnectionDetail, relName, relNamespace string) (managed.ConnectionDetails, error) {
+// connectionDetails returns the supplied connection details of the supplied
+// release, and the objects of connection details that do not exist (yet).
+func connectionDetails(ctx context.Context, kube client.Client, connDetails []v1beta1.ConnectionDetail, relName, relNamespace, notes string) (managed.ConnectionDetails, []string, error) {
 	mcd := managed.ConnectionDetails{}
+	var missing []string
 
 	for _, cd := range connDetails {
+		if cd.NotesRegex != "" {
+			v, err := notesValue(notes, cd.NotesRegex)
+			if err != nil {
+				return mcd, nil, err
+			}
+			fv, err := encodeConnectionDetail(cd, v)
+			if err != nil {
+				return mcd, nil, err
+			}
+			if fv, err = transformConnectionDetail(cd.Transforms, fv); err != nil {
+				return mcd, nil, err
+			}
+			mcd[cd.ToConnectionSecretKey] = fv
+			continue
+		}
+
 		ro := unstructuredFromObjectRef(cd.ObjectReference)
-		if err := kube.Get(ctx, types.NamespacedName{Name: ro.GetName(), Namespace: ro.GetNamespace()}, &ro); err != nil {
-			return mcd, errors.Wrap(err, "cannot get object")
+		if cd.AllKeys && (cd.Kind != "Secret" || cd.APIVersion != "v1") {
+			return mcd, nil, errors.Errorf(errAllKeysNotSecret, resourceKey(&ro))
+		}
+		err := kube.Get(ctx, types.NamespacedName{Name: ro.GetName(), Namespace: ro.GetNamespace()}, &ro)
+		if kerrors.IsNotFound(err) {
+			missing = append(missing, resourceKey(&ro))
+			continue
+		}
+		if err != nil {
+			return mcd, nil, errors.Wrap(err, "cannot get object")
 		}
 
-		// TODO(hasan): consider making this check configurable, i.e. possible to skip via a field in spec
-		if !partOfRelease(ro, relName, relNamespace) {
-			return mcd, errors.Errorf(errObjectNotPartOfRelease, cd.ObjectReference)
+		if !cd.SkipPartOfReleaseCheck && !partOfRelease(ro, relName, relNamespace) {
+			return mcd, nil, errors.Errorf(errObjectNotPartOfRelease, cd.ObjectReference)
 		}
 
-		paved := fieldpath.Pave(ro.Object)
-		v, err := paved.GetValue(cd.FieldPath)
+		if cd.AllKeys {
+			if err := secretConnectionDetails(mcd, ro, cd); err != nil {
+				return mcd, nil, err
+			}
+			continue
+		}
+
+		s, err := connectionDetailValue(ro, cd)
 		if err != nil {
-			return mcd, errors.Wrapf(err, "failed to get value at fieldPath: %s", cd.FieldPath)
+			return mcd, nil, err
+		}
+		fv, err := encodeConnectionDetail(cd, s)
+		if err != nil {
+			return mcd, nil, err
+		}
+		if fv, err = transformConnectionDetail(cd.Transforms, fv); err != nil {
+			return mcd, nil, err
+		}
+
+		mcd[cd.ToConnectionSecretKey] = fv
+	}
+
+	return mcd, missing, nil
+}
+
+// notesValue returns the first capture group of the supplied regular
+// expression in the supplied release notes, or the whole match if it has no
+// capture groups.
+func notesValue(notes, expr string) (string, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return "", errors.Wrapf(err, errParseNotesRegex, expr)
+	}
+	m := re.FindStringSubmatch(notes)
+	if m == nil {
+		return "", errors.Errorf(errNotesRegexNoMatch, expr)
+	}
+	if len(m) > 1 {
+		return m[1], nil
+	}
+	return m[0], nil
+}
+
+// secretConnectionDetails adds all keys of the supplied Secret to the supplied
+// connection details, prefixed as configured by the supplied connection detail.
+func secretConnectionDetails(mcd managed.ConnectionDetails, u unstructured.Unstructured, cd v1beta1.ConnectionDetail) error {
+	data, _, err := unstructured.NestedStringMap(u.Object, "data")
+	if err != nil {
+		return errors.Wrap(err, "failed to get secret data")
+	}
+	for k, v := range data {
+		b := []byte(v)
+		// Secret data is base64 encoded already.
+		if cd.Encoding != v1beta1.ValueEncodingNone && cd.Encoding != v1beta1.ValueEncodingBase64Encode {
+			if b, err = base64.StdEncoding.DecodeString(v); err != nil {
+				return errors.Wrap(err, "failed to decode secret data")
+			}
+		}
+		if b, err = transformConnectionDetail(cd.Transforms, b); err != nil {
+			return err
+		}
+		mcd[cd.KeyPrefix+k] = b
+	}
+	return nil
+}
+
+// encodeConnectionDetail transforms the supplied value of the supplied
+// connection detail according to its encoding.
+func encodeConnectionDetail(cd v1beta1.ConnectionDetail, v string) ([]byte, error) {
+	switch cd.Encoding {
+	case v1beta1.ValueEncodingNone:
+		return []byte(v), nil
+	case v1beta1.ValueEncodingBase64Encode:
+		return []byte(base64.StdEncoding.EncodeToString([]byte(v))), nil
+	case v1beta1.ValueEncodingBase64Decode:
+		// Decoded below.
+	default:
+		// Prevent Secret data and ConfigMap binaryData being encoded twice.
+		p := connectionDetailPath(cd)
+		secretData := cd.Kind == "Secret" && strings.HasPrefix(p, "data")
+		binaryData := cd.Kind == "ConfigMap" && strings.HasPrefix(p, "binaryData")
+		if cd.APIVersion != "v1" || !secretData && !binaryData {
+			return []byte(v), nil
 		}
-		s := fmt.Sprintf("%v", v)
-		fv := []byte(s)
-		// prevent secret data being encoded twice
-		if cd.Kind == "Secret" && cd.APIVersion == "v1" && strings.HasPrefix(cd.FieldPath, "data") {
-			fv, err = base64.StdEncoding.DecodeString(s)
+	}
+	b, err := base64.StdEncoding.DecodeString(v)
+	return b, errors.Wrap(err, errDecodeConnectionDetail)
+}
+
+// transformConnectionDetail applies the supplied transformations to the
+// supplied value, in order.
+func transformConnectionDetail(ts []v1beta1.ValueTransform, v []byte) ([]byte, error) {
+	for _, t := range ts {
+		switch t.Type {
+		case v1beta1.ValueTransformTrim:
+			v = bytes.TrimSpace(v)
+		case v1beta1.ValueTransformToLower:
+			v = bytes.ToLower(v)
+		case v1beta1.ValueTransformBase64Encode:
+			v = []byte(base64.StdEncoding.EncodeToString(v))
+		case v1beta1.ValueTransformBase64Decode:
+			b, err := base64.StdEncoding.DecodeString(string(v))
+			if err != nil {
+				return nil, errors.Wrap(err, errDecodeConnectionDetail)
+			}
+			v = b
+		case v1beta1.ValueTransformRegex:
+			re, err := regexp.Compile(t.Regex)
 			if err != nil {
-				return mcd, errors.Wrap(err, "failed to decode secret data")
+				return nil, errors.Wrapf(err, errParseTransformRegex, t.Regex)
 			}
+			m := re.FindSubmatch(v)
+			if m == nil {
+				return nil, errors.Errorf(errTransformRegexNoMatch, t.Regex)
+			}
+			v = m[0]
+			if len(m) > 1 {
+				v = m[1]
+			}
+		default:
+			return nil, errors.Errorf(errUnknownTransform, t.Type)
 		}
+	}
+	return v, nil
+}
 
-		mcd[cd.ToConnectionSecretKey] = fv
+// connectionDetailsPending returns whether the supplied objects of the
+// connection details of the supplied deployed release are still expected to
+// be created, and an error if they were not created in time.
+func connectionDetailsPending(cr *v1beta1.Release, rel *release.Release, missing []string, now time.Time) (bool, error) {
+	if len(missing) == 0 {
+		if len(cr.Spec.ConnectionDetails) > 0 {
+			cr.Status.SetConditions(v1beta1.ConnectionDetailsPublished())
+		}
+		return false, nil
 	}
+	cr.Status.SetConditions(v1beta1.ConnectionDetailsPending(missing))
 
-	return mcd, nil
+	timeout := defaultConnectionDetailsTimeout
+	if t := cr.Spec.ConnectionDetailsTimeout; t != nil {
+		timeout = t.Duration
+	}
+	if rel.Info != nil && now.Sub(rel.Info.LastDeployed.Time) > timeout {
+		return false, errors.Errorf(errConnectionDetailsMissing, strings.Join(missing, ", "))
+	}
+	return true, nil
+}
+
+// renderConnectionDetails adds the connection details rendered by the supplied
+// templates to the supplied connection details. Templates can use the
+// connection details rendered by preceding templates.
+func renderConnectionDetails(mcd managed.ConnectionDetails, ts []v1beta1.ConnectionDetailTemplate) error {
+	values := make(map[string]string, len(mcd)+len(ts))
+	for k, v := range mcd {
+		values[k] = string(v)
+	}
+	for _, t := range ts {
+		tpl, err := template.New(t.ToConnectionSecretKey).Option("missingkey=error").Parse(t.Template)
+		if err != nil {
+			return errors.Wrapf(err, errParseConnectionTemplate, t.ToConnectionSecretKey)
+		}
+		b := &bytes.Buffer{}
+		if err := tpl.Execute(b, values); err != nil {
+			return errors.Wrapf(err, errRenderConnectionTemplate, t.ToConnectionSecretKey)
+		}
+		mcd[t.ToConnectionSecretKey] = b.Bytes()
+		values[t.ToConnectionSecretKey] = b.String()
+	}
+	return nil
+}
+
+// connectionDetailValue returns the value of the supplied connection detail
+// in the supplied object, using its JSONPath expression if any.
+func connectionDetailValue(u unstructured.Unstructured, cd v1beta1.ConnectionDetail) (string, error) {
+	if cd.JSONPath == "" {
+		v, err := fieldpath.Pave(u.Object).GetValue(cd.FieldPath)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to get value at fieldPath: %s", cd.FieldPath)
+		}
+		return fmt.Sprintf("%v", v), nil
+	}
+
+	jp := jsonpath.New(cd.ToConnectionSecretKey)
+	if err := jp.Parse(relaxedJSONPath(cd.JSONPath)); err != nil {
+		return "", errors.Wrapf(err, errParseJSONPath, cd.JSONPath)
+	}
+	b := &bytes.Buffer{}
+	if err := jp.Execute(b, u.Object); err != nil {
+		return "", errors.Wrapf(err, "failed to get value at jsonPath: %s", cd.JSONPath)
+	}
+	return b.String(), nil
+}
+
+// connectionDetailPath returns the path of the supplied connection detail,
+// without any JSONPath delimiters.
+func connectionDetailPath(cd v1beta1.ConnectionDetail) string {
+	if cd.JSONPath == "" {
+		return cd.FieldPath
+	}
+	return strings.TrimLeft(cd.JSONPath, "{.")
+}
+
+// relaxedJSONPath wraps the supplied JSONPath expression in braces unless it
+// already contains a template, so that both .a.b and {.a.b} are accepted.
+func relaxedJSONPath(expr string) string {
+	if strings.Contains(expr, "{") {
+		return expr
+	}
+	if !strings.HasPrefix(expr, ".") {
+		expr = "." + expr
+	}
+	return "{" + expr + "}"
 }
 
 func unstructuredFromObjectRef(r corev1.ObjectReference) unstructured.Unstructured {
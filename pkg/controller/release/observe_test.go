@@ -2,17 +2,24 @@ package release
 
 import (
 	"context"
+	"encoding/base64"
 	"testing"
+	"time"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/release"
+	helmtime "helm.sh/helm/v3/pkg/time"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
@@ -78,6 +85,33 @@ func Test_generateObservation(t *testing.T) {
 				},
 			},
 		},
+		"Metadata": {
+			args: args{
+				in: &release.Release{
+					Version: 3,
+					Info: &release.Info{
+						Description:   testDescription,
+						Status:        release.StatusDeployed,
+						FirstDeployed: helmtime.Unix(1600000000, 0),
+						LastDeployed:  helmtime.Unix(1700000000, 0),
+						Notes:         "Visit http://wordpress.example.org",
+					},
+					Chart: &chart.Chart{Metadata: &chart.Metadata{Version: "12.1.0", AppVersion: "5.8.0"}},
+				},
+			},
+			want: want{
+				out: v1beta1.ReleaseObservation{
+					State:              release.StatusDeployed,
+					ReleaseDescription: testDescription,
+					Revision:           3,
+					FirstDeployed:      &metav1.Time{Time: time.Unix(1600000000, 0)},
+					LastDeployed:       &metav1.Time{Time: time.Unix(1700000000, 0)},
+					ChartVersion:       "12.1.0",
+					AppVersion:         "5.8.0",
+					Notes:              "Visit http://wordpress.example.org",
+				},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -327,6 +361,43 @@ func Test_isUpToDate(t *testing.T) {
 				err: nil,
 			},
 		},
+		"NotUpToDate_CommonMetadataMissing": {
+			args: args{
+				kube: &test.MockClient{
+					MockGet: nil,
+				},
+				in: &v1beta1.ReleaseParameters{
+					Chart: v1beta1.ChartSpec{
+						Name:    testChart,
+						Version: testVersion,
+					},
+					ValuesSpec: v1beta1.ValuesSpec{
+						Values: runtime.RawExtension{
+							Raw: []byte(testReleaseConfigStr),
+						},
+					},
+					CommonMetadata: &v1beta1.CommonMetadata{
+						Labels: map[string]string{"team": "platform"},
+					},
+				},
+				observed: &release.Release{
+					Info: &release.Info{},
+					Chart: &chart.Chart{
+						Raw: nil,
+						Metadata: &chart.Metadata{
+							Name:    testChart,
+							Version: testVersion,
+						},
+					},
+					Config:   testReleaseConfig,
+					Manifest: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n",
+				},
+			},
+			want: want{
+				out: false,
+				err: nil,
+			},
+		},
 		"SuccessPatchesAdded": {
 			args: args{
 				kube: &test.MockClient{
@@ -364,7 +435,7 @@ func Test_isUpToDate(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			got, gotErr := isUpToDate(context.Background(), tc.args.kube, tc.args.in, tc.args.observed, v1beta1.ReleaseStatus{})
+			got, gotErr := isUpToDate(context.Background(), tc.args.kube, nil, tc.args.in, tc.args.observed, v1beta1.ReleaseStatus{})
 			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
 				t.Fatalf("isUpToDate(...): -want error, +got error: %s", diff)
 			}
@@ -375,21 +446,135 @@ func Test_isUpToDate(t *testing.T) {
 	}
 }
 
+func Test_lateInitialize(t *testing.T) {
+	observed := &release.Release{
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{
+				Name:        testChart,
+				Version:     testVersion,
+				Annotations: map[string]string{v1beta1.AnnotationKeyChartRepository: testRepo},
+			},
+		},
+		Config: map[string]interface{}{"password": "secret"},
+	}
+	type want struct {
+		in v1beta1.ReleaseParameters
+		li bool
+	}
+	cases := map[string]struct {
+		in       v1beta1.ReleaseParameters
+		observed *release.Release
+		want     want
+	}{
+		"AllUnset": {
+			in: v1beta1.ReleaseParameters{},
+			want: want{
+				in: v1beta1.ReleaseParameters{
+					Chart: v1beta1.ChartSpec{Name: testChart, Version: testVersion, Repository: testRepo},
+				},
+				li: true,
+			},
+		},
+		"AllSet": {
+			in: v1beta1.ReleaseParameters{
+				Chart: v1beta1.ChartSpec{Name: "other", Version: "2.0.0", Repository: "https://charts.example.org"},
+				ValuesSpec: v1beta1.ValuesSpec{
+					Set: []v1beta1.SetVal{{Name: "keyA", Value: "valB"}},
+				},
+			},
+			want: want{
+				in: v1beta1.ReleaseParameters{
+					Chart: v1beta1.ChartSpec{Name: "other", Version: "2.0.0", Repository: "https://charts.example.org"},
+					ValuesSpec: v1beta1.ValuesSpec{
+						Set: []v1beta1.SetVal{{Name: "keyA", Value: "valB"}},
+					},
+				},
+				li: false,
+			},
+		},
+		"URLSet": {
+			in: v1beta1.ReleaseParameters{
+				Chart: v1beta1.ChartSpec{URL: "https://charts.example.org/test-0.1.0.tgz", Version: "0.1.0"},
+			},
+			want: want{
+				in: v1beta1.ReleaseParameters{
+					Chart: v1beta1.ChartSpec{URL: "https://charts.example.org/test-0.1.0.tgz", Version: "0.1.0"},
+				},
+				li: false,
+			},
+		},
+		"RepositoryNotRecorded": {
+			in: v1beta1.ReleaseParameters{},
+			observed: &release.Release{
+				Chart: &chart.Chart{Metadata: &chart.Metadata{Name: testChart, Version: testVersion}},
+			},
+			want: want{
+				in: v1beta1.ReleaseParameters{
+					Chart: v1beta1.ChartSpec{Name: testChart, Version: testVersion},
+				},
+				li: true,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := tc.observed
+			if o == nil {
+				o = observed
+			}
+			li := lateInitialize(&tc.in, o)
+			if diff := cmp.Diff(tc.want.li, li); diff != "" {
+				t.Errorf("lateInitialize(...): -want late initialized, +got late initialized: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.in, tc.in); diff != "" {
+				t.Errorf("lateInitialize(...): -want parameters, +got parameters: %s", diff)
+			}
+		})
+	}
+}
+
 func Test_connectionDetails(t *testing.T) {
 	type args struct {
 		kube         client.Client
 		connDetails  []v1beta1.ConnectionDetail
 		relName      string
 		relNamespace string
+		notes        string
 	}
 	type want struct {
-		out managed.ConnectionDetails
-		err error
+		out     managed.ConnectionDetails
+		missing []string
+		err     error
 	}
 	cases := map[string]struct {
 		args
 		want
 	}{
+		"Success_NotFound": {
+			args: args{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, testSecretName)),
+				},
+				connDetails: []v1beta1.ConnectionDetail{
+					{
+						ObjectReference: corev1.ObjectReference{
+							Kind:       "Secret",
+							Namespace:  testNamespace,
+							Name:       testSecretName,
+							APIVersion: "v1",
+							FieldPath:  "data.db-password",
+						},
+						ToConnectionSecretKey: "password",
+					},
+				},
+				relName:      testReleaseName,
+				relNamespace: testNamespace,
+			},
+			want: want{
+				out:     managed.ConnectionDetails{},
+				missing: []string{"Secret " + testNamespace + "/" + testSecretName},
+			},
+		},
 		"Fail_NotPartOfRelease": {
 			args: args{
 				kube: &test.MockClient{
@@ -432,6 +617,44 @@ func Test_connectionDetails(t *testing.T) {
 				}),
 			},
 		},
+		"Success_SkipPartOfReleaseCheck": {
+			args: args{
+				kube: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						if o, ok := obj.(*unstructured.Unstructured); o.GetKind() == "Secret" && ok && key.Name == testSecretName && key.Namespace == testNamespace {
+							*obj.(*unstructured.Unstructured) = unstructured.Unstructured{
+								Object: map[string]interface{}{
+									"data": map[string]interface{}{
+										"db-password": "MTIzNDU=",
+									},
+								},
+							}
+						}
+						return nil
+					},
+				},
+				connDetails: []v1beta1.ConnectionDetail{
+					{
+						ObjectReference: corev1.ObjectReference{
+							Kind:       "Secret",
+							Namespace:  testNamespace,
+							Name:       testSecretName,
+							APIVersion: "v1",
+							FieldPath:  "data.db-password",
+						},
+						ToConnectionSecretKey:  "password",
+						SkipPartOfReleaseCheck: true,
+					},
+				},
+				relName:      testReleaseName,
+				relNamespace: testNamespace,
+			},
+			want: want{
+				out: managed.ConnectionDetails{
+					"password": []byte("12345"),
+				},
+			},
+		},
 		"Success_PartOfRelease": {
 			args: args{
 				kube: &test.MockClient{
@@ -475,16 +698,497 @@ func Test_connectionDetails(t *testing.T) {
 				},
 			},
 		},
+		"Success_Notes": {
+			args: args{
+				kube: &test.MockClient{},
+				connDetails: []v1beta1.ConnectionDetail{
+					{NotesRegex: `URL: (\S+)`, ToConnectionSecretKey: "url"},
+					{NotesRegex: `admin`, ToConnectionSecretKey: "user"},
+				},
+				notes: "1. Get the application URL:\n  URL: http://example.org/admin\n",
+			},
+			want: want{
+				out: managed.ConnectionDetails{
+					"url":  []byte("http://example.org/admin"),
+					"user": []byte("admin"),
+				},
+			},
+		},
+		"Fail_NotesNoMatch": {
+			args: args{
+				kube: &test.MockClient{},
+				connDetails: []v1beta1.ConnectionDetail{
+					{NotesRegex: `URL: (\S+)`, ToConnectionSecretKey: "url"},
+				},
+				notes: "Thank you for installing.",
+			},
+			want: want{
+				out: managed.ConnectionDetails{},
+				err: errors.Errorf(errNotesRegexNoMatch, `URL: (\S+)`),
+			},
+		},
+		"Success_AllKeys": {
+			args: args{
+				kube: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						*obj.(*unstructured.Unstructured) = unstructured.Unstructured{
+							Object: map[string]interface{}{
+								"metadata": map[string]interface{}{
+									"annotations": map[string]interface{}{
+										helmReleaseNameAnnotation:      testReleaseName,
+										helmReleaseNamespaceAnnotation: testNamespace,
+									},
+								},
+								"data": map[string]interface{}{
+									"username": "YWRtaW4=",
+									"password": "MTIzNDU=",
+								},
+							},
+						}
+						return nil
+					},
+				},
+				connDetails: []v1beta1.ConnectionDetail{
+					{
+						ObjectReference: corev1.ObjectReference{
+							Kind:       "Secret",
+							Namespace:  testNamespace,
+							Name:       testSecretName,
+							APIVersion: "v1",
+						},
+						AllKeys:   true,
+						KeyPrefix: "db-",
+					},
+				},
+				relName:      testReleaseName,
+				relNamespace: testNamespace,
+			},
+			want: want{
+				out: managed.ConnectionDetails{
+					"db-username": []byte("admin"),
+					"db-password": []byte("12345"),
+				},
+			},
+		},
+		"Fail_AllKeysNotSecret": {
+			args: args{
+				kube: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						*obj.(*unstructured.Unstructured) = unstructured.Unstructured{
+							Object: map[string]interface{}{
+								"metadata": map[string]interface{}{
+									"annotations": map[string]interface{}{
+										helmReleaseNameAnnotation:      testReleaseName,
+										helmReleaseNamespaceAnnotation: testNamespace,
+									},
+								},
+								"data": map[string]interface{}{
+									"username": "YWRtaW4=",
+									"password": "MTIzNDU=",
+								},
+							},
+						}
+						return nil
+					},
+				},
+				connDetails: []v1beta1.ConnectionDetail{
+					{
+						ObjectReference: corev1.ObjectReference{
+							Kind:       "ConfigMap",
+							Namespace:  testNamespace,
+							Name:       testSecretName,
+							APIVersion: "v1",
+						},
+						AllKeys: true,
+					},
+				},
+				relName:      testReleaseName,
+				relNamespace: testNamespace,
+			},
+			want: want{
+				out: managed.ConnectionDetails{},
+				err: errors.Errorf(errAllKeysNotSecret, "ConfigMap "+testNamespace+"/"+testSecretName),
+			},
+		},
+		"Success_JSONPath": {
+			args: args{
+				kube: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						*obj.(*unstructured.Unstructured) = unstructured.Unstructured{
+							Object: map[string]interface{}{
+								"metadata": map[string]interface{}{
+									"annotations": map[string]interface{}{
+										helmReleaseNameAnnotation:      testReleaseName,
+										helmReleaseNamespaceAnnotation: testNamespace,
+									},
+								},
+								"status": map[string]interface{}{
+									"loadBalancer": map[string]interface{}{
+										"ingress": []interface{}{
+											map[string]interface{}{"hostname": "lb.example.org"},
+											map[string]interface{}{"ip": "10.0.0.1"},
+										},
+									},
+								},
+							},
+						}
+						return nil
+					},
+				},
+				connDetails: []v1beta1.ConnectionDetail{
+					{
+						ObjectReference: corev1.ObjectReference{
+							Kind:       "Service",
+							Namespace:  testNamespace,
+							Name:       "db",
+							APIVersion: "v1",
+						},
+						JSONPath:              "{.status.loadBalancer.ingress[?(@.ip)].ip}",
+						ToConnectionSecretKey: "ip",
+					},
+					{
+						ObjectReference: corev1.ObjectReference{
+							Kind:       "Service",
+							Namespace:  testNamespace,
+							Name:       "db",
+							APIVersion: "v1",
+						},
+						JSONPath:              ".status.loadBalancer.ingress[0].hostname",
+						ToConnectionSecretKey: "hostname",
+					},
+				},
+				relName:      testReleaseName,
+				relNamespace: testNamespace,
+			},
+			want: want{
+				out: managed.ConnectionDetails{
+					"ip":       []byte("10.0.0.1"),
+					"hostname": []byte("lb.example.org"),
+				},
+			},
+		},
+		"Fail_InvalidJSONPath": {
+			args: args{
+				kube: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						*obj.(*unstructured.Unstructured) = unstructured.Unstructured{
+							Object: map[string]interface{}{
+								"metadata": map[string]interface{}{
+									"annotations": map[string]interface{}{
+										helmReleaseNameAnnotation:      testReleaseName,
+										helmReleaseNamespaceAnnotation: testNamespace,
+									},
+								},
+								"status": map[string]interface{}{
+									"loadBalancer": map[string]interface{}{
+										"ingress": []interface{}{
+											map[string]interface{}{"hostname": "lb.example.org"},
+											map[string]interface{}{"ip": "10.0.0.1"},
+										},
+									},
+								},
+							},
+						}
+						return nil
+					},
+				},
+				connDetails: []v1beta1.ConnectionDetail{
+					{
+						ObjectReference: corev1.ObjectReference{
+							Kind:       "Service",
+							Namespace:  testNamespace,
+							Name:       "db",
+							APIVersion: "v1",
+						},
+						JSONPath:              "{.status[",
+						ToConnectionSecretKey: "ip",
+					},
+				},
+				relName:      testReleaseName,
+				relNamespace: testNamespace,
+			},
+			want: want{
+				out: managed.ConnectionDetails{},
+				err: errors.Wrapf(errors.New("unterminated array"), errParseJSONPath, "{.status["),
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			got, gotErr := connectionDetails(context.Background(), tc.args.kube, tc.args.connDetails, tc.args.relName, tc.args.relNamespace)
+			got, gotMissing, gotErr := connectionDetails(context.Background(), tc.args.kube, tc.args.connDetails, tc.args.relName, tc.args.relNamespace, tc.args.notes)
 			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
 				t.Fatalf("connectionDetails(...): -want error, +got error: %s", diff)
 			}
 			if diff := cmp.Diff(tc.want.out, got); diff != "" {
 				t.Errorf("connectionDetails(...): -want result, +got result: %s", diff)
 			}
+			if diff := cmp.Diff(tc.want.missing, gotMissing); diff != "" {
+				t.Errorf("connectionDetails(...): -want missing, +got missing: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_renderConnectionDetails(t *testing.T) {
+	type args struct {
+		mcd managed.ConnectionDetails
+		ts  []v1beta1.ConnectionDetailTemplate
+	}
+	type want struct {
+		out managed.ConnectionDetails
+		err error
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"Success": {
+			args: args{
+				mcd: managed.ConnectionDetails{
+					"user":        []byte("admin"),
+					"db-password": []byte("p@ss"),
+					"host":        []byte("db"),
+				},
+				ts: []v1beta1.ConnectionDetailTemplate{
+					{ToConnectionSecretKey: "address", Template: "{{ .host }}:5432"},
+					{ToConnectionSecretKey: "url", Template: `postgres://{{ .user }}:{{ urlquery (index . "db-password") }}@{{ .address }}/app`},
+				},
+			},
+			want: want{
+				out: managed.ConnectionDetails{
+					"user":        []byte("admin"),
+					"db-password": []byte("p@ss"),
+					"host":        []byte("db"),
+					"address":     []byte("db:5432"),
+					"url":         []byte("postgres://admin:p%40ss@db:5432/app"),
+				},
+			},
+		},
+		"Fail_MissingKey": {
+			args: args{
+				mcd: managed.ConnectionDetails{},
+				ts:  []v1beta1.ConnectionDetailTemplate{{ToConnectionSecretKey: "url", Template: "{{ .host }}"}},
+			},
+			want: want{
+				out: managed.ConnectionDetails{},
+				err: errors.Wrapf(errors.New(`template: url:1:3: executing "url" at <.host>: map has no entry for key "host"`), errRenderConnectionTemplate, "url"),
+			},
+		},
+		"Fail_InvalidTemplate": {
+			args: args{
+				mcd: managed.ConnectionDetails{},
+				ts:  []v1beta1.ConnectionDetailTemplate{{ToConnectionSecretKey: "url", Template: "{{ .host "}},
+			},
+			want: want{
+				out: managed.ConnectionDetails{},
+				err: errors.Wrapf(errors.New(`template: url:1: unclosed action`), errParseConnectionTemplate, "url"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			gotErr := renderConnectionDetails(tc.args.mcd, tc.args.ts)
+			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
+				t.Fatalf("renderConnectionDetails(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.out, tc.args.mcd); diff != "" {
+				t.Errorf("renderConnectionDetails(...): -want result, +got result: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_connectionDetailsPending(t *testing.T) {
+	now := time.Now()
+	deployedAt := func(ago time.Duration) *release.Release {
+		return &release.Release{Info: &release.Info{LastDeployed: helmtime.Time{Time: now.Add(-ago)}}}
+	}
+	missing := []string{"Secret ns/db"}
+
+	type args struct {
+		timeout *metav1.Duration
+		rel     *release.Release
+		missing []string
+	}
+	type want struct {
+		pending   bool
+		condition xpv1.Condition
+		err       error
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"Published": {
+			args: args{rel: deployedAt(time.Hour)},
+			want: want{condition: v1beta1.ConnectionDetailsPublished()},
+		},
+		"Pending": {
+			args: args{rel: deployedAt(time.Minute), missing: missing},
+			want: want{pending: true, condition: v1beta1.ConnectionDetailsPending(missing)},
+		},
+		"TimedOut": {
+			args: args{rel: deployedAt(10 * time.Minute), missing: missing},
+			want: want{
+				condition: v1beta1.ConnectionDetailsPending(missing),
+				err:       errors.Errorf(errConnectionDetailsMissing, "Secret ns/db"),
+			},
+		},
+		"CustomTimeout": {
+			args: args{timeout: &metav1.Duration{Duration: time.Hour}, rel: deployedAt(10 * time.Minute), missing: missing},
+			want: want{pending: true, condition: v1beta1.ConnectionDetailsPending(missing)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1beta1.Release{Spec: v1beta1.ReleaseSpec{
+				ConnectionDetails:        []v1beta1.ConnectionDetail{{ToConnectionSecretKey: "password"}},
+				ConnectionDetailsTimeout: tc.args.timeout,
+			}}
+			got, gotErr := connectionDetailsPending(cr, tc.args.rel, tc.args.missing, now)
+			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
+				t.Fatalf("connectionDetailsPending(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.pending, got); diff != "" {
+				t.Errorf("connectionDetailsPending(...): -want, +got: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.condition, cr.Status.GetCondition(v1beta1.TypeConnectionDetails), test.EquateConditions()); diff != "" {
+				t.Errorf("connectionDetailsPending(...): -want condition, +got condition: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_encodeConnectionDetail(t *testing.T) {
+	// Not valid UTF-8, e.g. a keystore.
+	binary := []byte{0xfe, 0xed, 0xfe, 0xed, 0x00}
+	encoded := "/u3+7QA="
+
+	ref := func(kind, fieldPath string) corev1.ObjectReference {
+		return corev1.ObjectReference{APIVersion: "v1", Kind: kind, FieldPath: fieldPath}
+	}
+	type want struct {
+		out []byte
+		err error
+	}
+	cases := map[string]struct {
+		cd    v1beta1.ConnectionDetail
+		value string
+		want  want
+	}{
+		"AutoSecretData": {
+			cd:    v1beta1.ConnectionDetail{ObjectReference: ref("Secret", "data.keystore")},
+			value: encoded,
+			want:  want{out: binary},
+		},
+		"AutoConfigMapBinaryData": {
+			cd:    v1beta1.ConnectionDetail{ObjectReference: ref("ConfigMap", "binaryData.keystore")},
+			value: encoded,
+			want:  want{out: binary},
+		},
+		"AutoOtherField": {
+			cd:    v1beta1.ConnectionDetail{ObjectReference: ref("Service", "spec.clusterIP")},
+			value: "10.0.0.1",
+			want:  want{out: []byte("10.0.0.1")},
+		},
+		"None": {
+			cd:    v1beta1.ConnectionDetail{ObjectReference: ref("Secret", "data.keystore"), Encoding: v1beta1.ValueEncodingNone},
+			value: encoded,
+			want:  want{out: []byte(encoded)},
+		},
+		"Base64Decode": {
+			cd:    v1beta1.ConnectionDetail{ObjectReference: ref("MutatingWebhookConfiguration", ""), JSONPath: "{.webhooks[0].clientConfig.caBundle}", Encoding: v1beta1.ValueEncodingBase64Decode},
+			value: encoded,
+			want:  want{out: binary},
+		},
+		"Base64Encode": {
+			cd:    v1beta1.ConnectionDetail{ObjectReference: ref("ConfigMap", "data.ca.crt"), Encoding: v1beta1.ValueEncodingBase64Encode},
+			value: "cert",
+			want:  want{out: []byte("Y2VydA==")},
+		},
+		"InvalidBase64": {
+			cd:    v1beta1.ConnectionDetail{ObjectReference: ref("Secret", "data.keystore")},
+			value: "not base64",
+			want:  want{out: []byte{}, err: errors.Wrap(base64.CorruptInputError(3), errDecodeConnectionDetail)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := encodeConnectionDetail(tc.cd, tc.value)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("encodeConnectionDetail(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.out, got); diff != "" {
+				t.Errorf("encodeConnectionDetail(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_transformConnectionDetail(t *testing.T) {
+	type want struct {
+		out []byte
+		err error
+	}
+	cases := map[string]struct {
+		ts    []v1beta1.ValueTransform
+		value string
+		want  want
+	}{
+		"None": {
+			value: "value",
+			want:  want{out: []byte("value")},
+		},
+		"TrimToLower": {
+			ts:    []v1beta1.ValueTransform{{Type: v1beta1.ValueTransformTrim}, {Type: v1beta1.ValueTransformToLower}},
+			value: " DB.Example.org\n",
+			want:  want{out: []byte("db.example.org")},
+		},
+		"Base64": {
+			ts:    []v1beta1.ValueTransform{{Type: v1beta1.ValueTransformBase64Decode}, {Type: v1beta1.ValueTransformTrim}, {Type: v1beta1.ValueTransformBase64Encode}},
+			value: "Y2VydAo=",
+			want:  want{out: []byte("Y2VydA==")},
+		},
+		"InvalidBase64": {
+			ts:    []v1beta1.ValueTransform{{Type: v1beta1.ValueTransformBase64Decode}},
+			value: "not base64",
+			want:  want{err: errors.Wrap(base64.CorruptInputError(3), errDecodeConnectionDetail)},
+		},
+		"RegexCaptureGroup": {
+			ts:    []v1beta1.ValueTransform{{Type: v1beta1.ValueTransformRegex, Regex: "^https?://([^/:]+)"}},
+			value: "https://db.example.org:5432/app",
+			want:  want{out: []byte("db.example.org")},
+		},
+		"RegexMatch": {
+			ts:    []v1beta1.ValueTransform{{Type: v1beta1.ValueTransformRegex, Regex: "[0-9]+"}},
+			value: "port 5432",
+			want:  want{out: []byte("5432")},
+		},
+		"RegexNoMatch": {
+			ts:    []v1beta1.ValueTransform{{Type: v1beta1.ValueTransformRegex, Regex: "[0-9]+"}},
+			value: "port",
+			want:  want{err: errors.Errorf(errTransformRegexNoMatch, "[0-9]+")},
+		},
+		"InvalidRegex": {
+			ts:    []v1beta1.ValueTransform{{Type: v1beta1.ValueTransformRegex, Regex: "("}},
+			value: "value",
+			want:  want{err: errors.Wrapf(errors.New("error parsing regexp: missing closing ): `(`"), errParseTransformRegex, "(")},
+		},
+		"UnknownType": {
+			ts:    []v1beta1.ValueTransform{{Type: "ToUpper"}},
+			value: "value",
+			want:  want{err: errors.Errorf(errUnknownTransform, "ToUpper")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := transformConnectionDetail(tc.ts, []byte(tc.value))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("transformConnectionDetail(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.out, got); diff != "" {
+				t.Errorf("transformConnectionDetail(...): -want, +got: %s", diff)
+			}
 		})
 	}
 }
@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	keyDefaultManifestOutput = "manifest"
+
+	// manifestOutputAnnotation records the UID of the Release the manifest
+	// output on a target cluster belongs to, as Releases can't own objects
+	// on other clusters.
+	manifestOutputAnnotation = "helm.crossplane.io/manifest-of"
+)
+
+const (
+	errFailedToPublishManifest   = "failed to publish manifest"
+	errFailedToUnpublishManifest = "failed to delete published manifest"
+	errFmtNotManifestOutputOf    = "existing object is the manifest output of Release UID %q"
+)
+
+// publishManifest writes the supplied manifest to the ConfigMap or Secret
+// configured by the supplied output, on the local or the target cluster.
+// Compressed manifests are written to the binary data of ConfigMaps. Objects
+// that belong to another Release are not overwritten.
+func (e *helmExternal) publishManifest(ctx context.Context, cr *v1beta1.Release, manifest string) error {
+	out := cr.Spec.ForProvider.ManifestOutput
+	if out == nil {
+		return nil
+	}
+
+	kube := e.localKube
+	om := metav1.ObjectMeta{
+		Name:            out.Name,
+		Namespace:       out.Namespace,
+		Labels:          map[string]string{helmNamespaceLabel: helmProviderName},
+		OwnerReferences: []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(cr, v1beta1.ReleaseGroupVersionKind))},
+	}
+	owned := resource.MustBeControllableBy(cr.GetUID())
+	if out.Cluster == v1beta1.ManifestOutputClusterTarget {
+		kube = e.kube
+		om.OwnerReferences = nil
+		om.Annotations = map[string]string{manifestOutputAnnotation: string(cr.GetUID())}
+		owned = mustBeManifestOutputOf(cr.GetUID())
+	}
+
+	key := out.Key
+	if key == "" {
+		key = keyDefaultManifestOutput
+	}
+
+	var o client.Object = &corev1.ConfigMap{ObjectMeta: om, Data: map[string]string{key: manifest}}
+	if out.Kind == v1beta1.ManifestOutputKindSecret {
+		o = &corev1.Secret{ObjectMeta: om, Data: map[string][]byte{key: []byte(manifest)}}
+	}
+	if out.Compression == v1beta1.ManifestCompressionGzip {
+		d, err := compress([]byte(manifest))
+		if err != nil {
+			return errors.Wrap(err, errFailedToPublishManifest)
+		}
+		meta.AddAnnotations(&om, map[string]string{compressionAnnotation: compressionGzip})
+		o = &corev1.ConfigMap{ObjectMeta: om, BinaryData: map[string][]byte{key: d}}
+		if out.Kind == v1beta1.ManifestOutputKindSecret {
+			o = &corev1.Secret{ObjectMeta: om, Data: map[string][]byte{key: d}}
+		}
+	}
+
+	return errors.Wrap(resource.NewAPIPatchingApplicator(kube).Apply(ctx, o, owned), errFailedToPublishManifest)
+}
+
+// unpublishManifest deletes the ConfigMap or Secret the manifest of the
+// supplied Release was published to, if it belongs to the Release.
+func (e *helmExternal) unpublishManifest(ctx context.Context, cr *v1beta1.Release) error {
+	out := cr.Spec.ForProvider.ManifestOutput
+	if out == nil {
+		return nil
+	}
+
+	kube := e.localKube
+	if out.Cluster == v1beta1.ManifestOutputClusterTarget {
+		kube = e.kube
+	}
+	var o client.Object = &corev1.ConfigMap{}
+	if out.Kind == v1beta1.ManifestOutputKindSecret {
+		o = &corev1.Secret{}
+	}
+	if err := kube.Get(ctx, types.NamespacedName{Namespace: out.Namespace, Name: out.Name}, o); err != nil {
+		return errors.Wrap(resource.IgnoreNotFound(err), errFailedToUnpublishManifest)
+	}
+
+	owned := metav1.IsControlledBy(o, cr)
+	if out.Cluster == v1beta1.ManifestOutputClusterTarget {
+		owned = o.GetAnnotations()[manifestOutputAnnotation] == string(cr.GetUID())
+	}
+	if !owned {
+		return nil
+	}
+	return errors.Wrap(resource.IgnoreNotFound(kube.Delete(ctx, o)), errFailedToUnpublishManifest)
+}
+
+// mustBeManifestOutputOf requires that the current object on a target
+// cluster is the manifest output of the Release with the supplied UID, or of
+// no Release, like resource.MustBeControllableBy does for controllers.
+func mustBeManifestOutputOf(u types.UID) resource.ApplyOption {
+	return func(_ context.Context, current, _ runtime.Object) error {
+		v, ok := current.(metav1.Object).GetAnnotations()[manifestOutputAnnotation]
+		if ok && v != string(u) {
+			return errors.Errorf(errFmtNotManifestOutputOf, v)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,221 @@
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const testOutputUID = "release-uid"
+
+func testOutputMeta(target bool) metav1.ObjectMeta {
+	om := metav1.ObjectMeta{
+		Name:      "out",
+		Namespace: testNamespace,
+		Labels:    map[string]string{helmNamespaceLabel: helmProviderName},
+	}
+	if target {
+		om.Annotations = map[string]string{manifestOutputAnnotation: testOutputUID}
+		return om
+	}
+	om.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: v1beta1.ReleaseGroupVersionKind.GroupVersion().String(),
+		Kind:       v1beta1.ReleaseKind,
+		Name:       testReleaseName,
+		UID:        testOutputUID,
+		Controller: pointer.Bool(true),
+	}}
+	return om
+}
+
+func Test_publishManifest(t *testing.T) {
+	compressed, _ := compress([]byte("kind: ConfigMap"))
+
+	type want struct {
+		err     error
+		created client.Object
+		cluster string
+	}
+	cases := map[string]struct {
+		out      *v1beta1.ManifestOutput
+		existing metav1.ObjectMeta
+		want     want
+	}{
+		"NoOutput": {},
+		"ConfigMapOnLocalCluster": {
+			out: &v1beta1.ManifestOutput{Kind: v1beta1.ManifestOutputKindConfigMap, Name: "out", Namespace: testNamespace},
+			want: want{
+				cluster: "local",
+				created: &corev1.ConfigMap{
+					ObjectMeta: testOutputMeta(false),
+					Data:       map[string]string{keyDefaultManifestOutput: "kind: ConfigMap"},
+				},
+			},
+		},
+		"SecretOnTargetCluster": {
+			out: &v1beta1.ManifestOutput{Kind: v1beta1.ManifestOutputKindSecret, Name: "out", Namespace: testNamespace, Key: "m", Cluster: v1beta1.ManifestOutputClusterTarget},
+			want: want{
+				cluster: "target",
+				created: &corev1.Secret{
+					ObjectMeta: testOutputMeta(true),
+					Data:       map[string][]byte{"m": []byte("kind: ConfigMap")},
+				},
+			},
+		},
+		"CompressedConfigMap": {
+			out: &v1beta1.ManifestOutput{Kind: v1beta1.ManifestOutputKindConfigMap, Name: "out", Namespace: testNamespace, Compression: v1beta1.ManifestCompressionGzip},
+			want: want{
+				cluster: "local",
+				created: &corev1.ConfigMap{
+					ObjectMeta: func() metav1.ObjectMeta {
+						om := testOutputMeta(false)
+						om.Annotations = map[string]string{compressionAnnotation: compressionGzip}
+						return om
+					}(),
+					BinaryData: map[string][]byte{keyDefaultManifestOutput: compressed},
+				},
+			},
+		},
+		"ControlledByOtherRelease": {
+			out: &v1beta1.ManifestOutput{Kind: v1beta1.ManifestOutputKindConfigMap, Name: "out", Namespace: testNamespace},
+			existing: metav1.ObjectMeta{
+				Name:            "out",
+				OwnerReferences: []metav1.OwnerReference{{UID: "other", Controller: pointer.Bool(true)}},
+			},
+			want: want{
+				err: errors.Wrap(errors.New(`existing object is not controlled by UID "release-uid"`), errFailedToPublishManifest),
+			},
+		},
+		"OutputOfOtherReleaseOnTargetCluster": {
+			out: &v1beta1.ManifestOutput{Kind: v1beta1.ManifestOutputKindSecret, Name: "out", Namespace: testNamespace, Cluster: v1beta1.ManifestOutputClusterTarget},
+			existing: metav1.ObjectMeta{
+				Name:        "out",
+				Annotations: map[string]string{manifestOutputAnnotation: "other"},
+			},
+			want: want{
+				err: errors.Wrap(errors.Errorf(errFmtNotManifestOutputOf, "other"), errFailedToPublishManifest),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var created client.Object
+			var cluster string
+			kube := func(c string) client.Client {
+				return &test.MockClient{
+					MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+						if tc.existing.Name == "" {
+							return kerrors.NewNotFound(schema.GroupResource{}, "out")
+						}
+						obj.SetName(tc.existing.Name)
+						obj.SetOwnerReferences(tc.existing.OwnerReferences)
+						obj.SetAnnotations(tc.existing.Annotations)
+						return nil
+					},
+					MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+						created, cluster = obj, c
+						return nil
+					},
+				}
+			}
+			e := &helmExternal{localKube: kube("local"), kube: kube("target")}
+			cr := helmRelease(func(r *v1beta1.Release) {
+				r.SetUID(testOutputUID)
+				r.Spec.ForProvider.ManifestOutput = tc.out
+			})
+			err := e.publishManifest(context.Background(), cr, "kind: ConfigMap")
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("publishManifest(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.created, created); diff != "" {
+				t.Errorf("publishManifest(...): -want created, +got created: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.cluster, cluster); diff != "" {
+				t.Errorf("publishManifest(...): -want cluster, +got cluster: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_unpublishManifest(t *testing.T) {
+	type want struct {
+		err     error
+		deleted string
+	}
+	cases := map[string]struct {
+		out      *v1beta1.ManifestOutput
+		existing *metav1.ObjectMeta
+		want     want
+	}{
+		"NoOutput": {},
+		"NotFound": {
+			out: &v1beta1.ManifestOutput{Kind: v1beta1.ManifestOutputKindConfigMap, Name: "out", Namespace: testNamespace},
+		},
+		"ControlledByRelease": {
+			out:      &v1beta1.ManifestOutput{Kind: v1beta1.ManifestOutputKindConfigMap, Name: "out", Namespace: testNamespace},
+			existing: func() *metav1.ObjectMeta { om := testOutputMeta(false); return &om }(),
+			want:     want{deleted: "local"},
+		},
+		"ControlledByOtherRelease": {
+			out: &v1beta1.ManifestOutput{Kind: v1beta1.ManifestOutputKindConfigMap, Name: "out", Namespace: testNamespace},
+			existing: &metav1.ObjectMeta{
+				Name:            "out",
+				OwnerReferences: []metav1.OwnerReference{{UID: "other", Controller: pointer.Bool(true)}},
+			},
+		},
+		"OutputOfReleaseOnTargetCluster": {
+			out:      &v1beta1.ManifestOutput{Kind: v1beta1.ManifestOutputKindSecret, Name: "out", Namespace: testNamespace, Cluster: v1beta1.ManifestOutputClusterTarget},
+			existing: func() *metav1.ObjectMeta { om := testOutputMeta(true); return &om }(),
+			want:     want{deleted: "target"},
+		},
+		"NotOutputOfReleaseOnTargetCluster": {
+			out:      &v1beta1.ManifestOutput{Kind: v1beta1.ManifestOutputKindSecret, Name: "out", Namespace: testNamespace, Cluster: v1beta1.ManifestOutputClusterTarget},
+			existing: &metav1.ObjectMeta{Name: "out"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var deleted string
+			kube := func(c string) client.Client {
+				return &test.MockClient{
+					MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+						if tc.existing == nil {
+							return kerrors.NewNotFound(schema.GroupResource{}, "out")
+						}
+						obj.SetName(tc.existing.Name)
+						obj.SetOwnerReferences(tc.existing.OwnerReferences)
+						obj.SetAnnotations(tc.existing.Annotations)
+						return nil
+					},
+					MockDelete: func(context.Context, client.Object, ...client.DeleteOption) error {
+						deleted = c
+						return nil
+					},
+				}
+			}
+			e := &helmExternal{localKube: kube("local"), kube: kube("target")}
+			cr := helmRelease(func(r *v1beta1.Release) {
+				r.SetUID(testOutputUID)
+				r.Spec.ForProvider.ManifestOutput = tc.out
+			})
+			err := e.unpublishManifest(context.Background(), cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("unpublishManifest(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.deleted, deleted); diff != "" {
+				t.Errorf("unpublishManifest(...): -want deleted, +got deleted: %s", diff)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	ktypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/resid"
 	"sigs.k8s.io/yaml"
 
 	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
@@ -22,13 +23,13 @@ const (
 
 // Patcher interface for managing Kustomize patches and detecting updates
 type Patcher interface {
-	hasUpdates(ctx context.Context, kube client.Client, in []v1beta1.ValueFromSource, s v1beta1.ReleaseStatus) (bool, error)
+	hasUpdates(ctx context.Context, kube client.Client, plugins valuePlugins, in *v1beta1.ReleaseParameters, s v1beta1.ReleaseStatus) (bool, error)
 	patchGetter
 	patchHasher
 }
 
 type patchGetter interface {
-	getFromSpec(ctx context.Context, kube client.Client, vals []v1beta1.ValueFromSource) ([]ktypes.Patch, error)
+	getFromSpec(ctx context.Context, kube client.Client, plugins valuePlugins, in *v1beta1.ReleaseParameters) ([]ktypes.Patch, error)
 }
 
 type patchHasher interface {
@@ -47,8 +48,8 @@ type patch struct {
 	patchGetter
 }
 
-func (p patch) hasUpdates(ctx context.Context, kube client.Client, in []v1beta1.ValueFromSource, s v1beta1.ReleaseStatus) (bool, error) {
-	patches, err := p.getFromSpec(ctx, kube, in)
+func (p patch) hasUpdates(ctx context.Context, kube client.Client, plugins valuePlugins, in *v1beta1.ReleaseParameters, s v1beta1.ReleaseStatus) (bool, error) {
+	patches, err := p.getFromSpec(ctx, kube, plugins, in)
 	if err != nil {
 		return false, err
 	}
@@ -78,11 +79,11 @@ func (patchSha) shaOf(patches []ktypes.Patch) (string, error) {
 
 type patchGet struct{}
 
-func (patchGet) getFromSpec(ctx context.Context, kube client.Client, vals []v1beta1.ValueFromSource) ([]ktypes.Patch, error) {
+func (patchGet) getFromSpec(ctx context.Context, kube client.Client, plugins valuePlugins, in *v1beta1.ReleaseParameters) ([]ktypes.Patch, error) {
 	var base []ktypes.Patch // nolint:prealloc
 
-	for _, vf := range vals {
-		s, err := getDataValueFromSource(ctx, kube, vf, keyDefaultPatchFrom)
+	for _, vf := range in.PatchesFrom {
+		s, err := getDataValueFromSource(ctx, kube, plugins, vf, keyDefaultPatchFrom)
 		if err != nil {
 			return nil, errors.Wrap(err, errFailedToGetValueFromSource)
 		}
@@ -100,5 +101,28 @@ func (patchGet) getFromSpec(ctx context.Context, kube client.Client, vals []v1be
 		base = append(base, p.Patches...)
 	}
 
+	// Inline patches are applied after the ones loaded from PatchesFrom.
+	if in.PostRender != nil && in.PostRender.Kustomize != nil {
+		for _, kp := range in.PostRender.Kustomize.Patches {
+			base = append(base, kustomizePatch(kp))
+		}
+	}
+
 	return base, nil
 }
+
+func kustomizePatch(in v1beta1.KustomizePatch) ktypes.Patch {
+	p := ktypes.Patch{Patch: in.Patch}
+	if t := in.Target; t != nil {
+		p.Target = &ktypes.Selector{
+			ResId: resid.ResId{
+				Gvk:       resid.Gvk{Group: t.Group, Version: t.Version, Kind: t.Kind},
+				Name:      t.Name,
+				Namespace: t.Namespace,
+			},
+			LabelSelector:      t.LabelSelector,
+			AnnotationSelector: t.AnnotationSelector,
+		}
+	}
+	return p
+}
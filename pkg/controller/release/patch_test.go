@@ -119,7 +119,7 @@ type mockPatchGet struct {
 	err     error
 }
 
-func (m mockPatchGet) getFromSpec(ctx context.Context, kube client.Client, vals []v1beta1.ValueFromSource) ([]types.Patch, error) {
+func (m mockPatchGet) getFromSpec(ctx context.Context, kube client.Client, plugins valuePlugins, in *v1beta1.ReleaseParameters) ([]types.Patch, error) {
 	return m.patches, m.err
 }
 
@@ -221,7 +221,7 @@ func Test_PatchHasUpdates(t *testing.T) {
 			s := v1beta1.ReleaseStatus{
 				PatchesSha: tc.existingSha,
 			}
-			got, gotErr := p.hasUpdates(context.Background(), nil, nil, s)
+			got, gotErr := p.hasUpdates(context.Background(), nil, nil, nil, s)
 
 			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
 				t.Fatalf("Patch.hasUpdates(...): -want error, +got error: %s", diff)
@@ -235,8 +235,9 @@ func Test_PatchHasUpdates(t *testing.T) {
 
 func Test_getPatchesFromSpec(t *testing.T) {
 	type args struct {
-		kube client.Client
-		spec []v1beta1.ValueFromSource
+		kube       client.Client
+		spec       []v1beta1.ValueFromSource
+		postRender *v1beta1.PostRender
 	}
 
 	type want struct {
@@ -391,11 +392,106 @@ func Test_getPatchesFromSpec(t *testing.T) {
 				err: nil,
 			},
 		},
+		"loadInlinePatch": {
+			args: args{
+				postRender: &v1beta1.PostRender{
+					Kustomize: &v1beta1.KustomizePostRender{
+						Patches: []v1beta1.KustomizePatch{
+							{
+								Patch: "- op: add\n  path: /spec/template/spec/nodeSelector\n  value:\n    patch.name: inline",
+								Target: &v1beta1.KustomizePatchTarget{
+									Kind: "Deployment",
+									Name: "nginx",
+								},
+							},
+							{
+								Patch: "apiVersion: v1\nkind: Service\nmetadata:\n  name: nginx\nspec:\n  type: ClusterIP",
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				out: []types.Patch{
+					{
+						Patch: "- op: add\n  path: /spec/template/spec/nodeSelector\n  value:\n    patch.name: inline",
+						Target: &types.Selector{
+							ResId: resid.ResId{
+								Gvk:  resid.Gvk{Kind: "Deployment"},
+								Name: "nginx",
+							},
+						},
+					},
+					{
+						Patch: "apiVersion: v1\nkind: Service\nmetadata:\n  name: nginx\nspec:\n  type: ClusterIP",
+					},
+				},
+				err: nil,
+			},
+		},
+		"inlinePatchesAfterPatchesFrom": {
+			args: args{
+				kube: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						s := corev1.ConfigMap{
+							Data: map[string]string{
+								keyDefaultPatchFrom: fmt.Sprintf(testPatchConfig, key.Name),
+							},
+						}
+						*obj.(*corev1.ConfigMap) = s
+						return nil
+					},
+				},
+				spec: []v1beta1.ValueFromSource{
+					{
+						ConfigMapKeyRef: &v1beta1.DataKeySelector{
+							NamespacedName: v1beta1.NamespacedName{
+								Name:      "1",
+								Namespace: testNamespace,
+							},
+							Key: keyDefaultPatchFrom,
+						},
+					},
+				},
+				postRender: &v1beta1.PostRender{
+					Kustomize: &v1beta1.KustomizePostRender{
+						Patches: []v1beta1.KustomizePatch{
+							{
+								Patch:  "- op: remove\n  path: /spec/template/spec/nodeSelector",
+								Target: &v1beta1.KustomizePatchTarget{Kind: "Deployment"},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				out: []types.Patch{
+					{
+						Patch: "- op: add\n  path: /spec/template/spec/nodeSelector\n  value:\n    node.size: really-big\n    aws.az: us-west-2a\n    patch.name: 1",
+						Target: &types.Selector{
+							ResId: resid.ResId{
+								Gvk: resid.Gvk{Kind: "Deployment"},
+							},
+						},
+					},
+					{
+						Patch: "- op: remove\n  path: /spec/template/spec/nodeSelector",
+						Target: &types.Selector{
+							ResId: resid.ResId{
+								Gvk: resid.Gvk{Kind: "Deployment"},
+							},
+						},
+					},
+				},
+				err: nil,
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			pg := patchGet{}
-			got, gotErr := pg.getFromSpec(context.Background(), tc.args.kube, tc.args.spec)
+			in := &v1beta1.ReleaseParameters{PatchesFrom: tc.args.spec, PostRender: tc.args.postRender}
+			got, gotErr := pg.getFromSpec(context.Background(), tc.args.kube, nil, in)
 			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
 				t.Fatalf("getFromSpec(...): -want error, +got error: %s", diff)
 			}
@@ -0,0 +1,101 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	ktype "sigs.k8s.io/kustomize/api/types"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+	"github.com/crossplane-contrib/provider-helm/pkg/clients/opa"
+)
+
+const (
+	defaultPolicyQuery = "helm/deny"
+)
+
+const (
+	errFailedToRenderForPolicy = "failed to render release for policy evaluation"
+	errFailedToEvaluatePolicy  = "failed to evaluate policies"
+	errPolicyViolated          = "rendered manifests violate %d policy rule(s): %s"
+)
+
+// A PolicyServer is an Open Policy Agent server that the rendered manifests
+// of all Releases of the provider are evaluated against before they are
+// installed or upgraded. Its policies are loaded by its operators, e.g. from
+// a bundle, so that the authors of Releases can't change them.
+type PolicyServer struct {
+	// URL of the server, e.g. http://opa.opa-system:8181.
+	URL string
+	// Query is the path of the rule reporting violations, e.g. helm/deny
+	// for the deny rule of package helm. The rule must produce a set of
+	// messages. Defaults to helm/deny.
+	Query string
+}
+
+// policyChecked returns a deployAction that evaluates the rendered manifests
+// against the policies of the policy server of the provider before running
+// the supplied action. Violations block the action and are reported in the
+// PolicyCompliant condition of the Release.
+func (e *helmExternal) policyChecked(ctx context.Context, cr *v1beta1.Release, template, action deployAction) deployAction {
+	return func(rel string, ch *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (*release.Release, error) {
+		r, err := template(rel, ch, vals, patches)
+		if err != nil {
+			return nil, errors.Wrap(err, errFailedToRenderForPolicy)
+		}
+		objs, err := parseManifest(r.Manifest)
+		if err != nil {
+			return nil, err
+		}
+
+		q := e.policyServer.Query
+		if q == "" {
+			q = defaultPolicyQuery
+		}
+		resources := make([]interface{}, len(objs))
+		for i := range objs {
+			resources[i] = objs[i].Object
+		}
+		input := map[string]interface{}{
+			"release": map[string]interface{}{
+				"name":      rel,
+				"namespace": cr.Spec.ForProvider.Namespace,
+				"chart": map[string]interface{}{
+					"name":    ch.Metadata.Name,
+					"version": ch.Metadata.Version,
+				},
+			},
+			"resources": resources,
+		}
+		v, err := opa.NewClient(e.policyServer.URL).Evaluate(ctx, strings.Trim(q, "/"), input)
+		if err != nil {
+			return nil, errors.Wrap(err, errFailedToEvaluatePolicy)
+		}
+		if len(v) > 0 {
+			err := errors.Errorf(errPolicyViolated, len(v), strings.Join(v, "; "))
+			cr.Status.SetConditions(v1beta1.PolicyViolated(err))
+			return nil, err
+		}
+		cr.Status.SetConditions(v1beta1.PolicyCompliant())
+		return action(rel, ch, vals, patches)
+	}
+}
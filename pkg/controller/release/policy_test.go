@@ -0,0 +1,109 @@
+package release
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/kustomize/api/types"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+func Test_policyChecked(t *testing.T) {
+	type want struct {
+		err    error
+		status corev1.ConditionStatus
+		called bool
+		query  string
+	}
+	cases := map[string]struct {
+		manifest string
+		query    string
+		want     want
+	}{
+		"Compliant": {
+			manifest: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n",
+			want: want{
+				status: corev1.ConditionTrue,
+				called: true,
+				query:  "/v1/data/helm/deny",
+			},
+		},
+		"Violated": {
+			manifest: "apiVersion: v1\nkind: Pod\nmetadata:\n  name: p\n",
+			want: want{
+				err:    errors.Errorf(errPolicyViolated, 1, "bare pods are not allowed"),
+				status: corev1.ConditionFalse,
+				query:  "/v1/data/helm/deny",
+			},
+		},
+		"CustomQuery": {
+			manifest: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n",
+			query:    "/tenants/deny/",
+			want: want{
+				status: corev1.ConditionTrue,
+				called: true,
+				query:  "/v1/data/tenants/deny",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var query string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				query = r.URL.Path
+				in := struct {
+					Input struct {
+						Resources []map[string]interface{} `json:"resources"`
+					} `json:"input"`
+				}{}
+				_ = json.NewDecoder(r.Body).Decode(&in)
+				for _, o := range in.Input.Resources {
+					if o["kind"] == "Pod" {
+						_, _ = w.Write([]byte(`{"result": ["bare pods are not allowed"]}`))
+						return
+					}
+				}
+				_, _ = w.Write([]byte(`{"result": []}`))
+			}))
+			defer srv.Close()
+
+			cr := helmRelease()
+			called := false
+			e := &helmExternal{
+				policyServer: &PolicyServer{URL: srv.URL, Query: tc.query},
+				helm: &MockHelmClient{
+					MockTemplate: func(string, *chart.Chart, map[string]interface{}, []types.Patch) (*release.Release, error) {
+						return &release.Release{Manifest: tc.manifest}, nil
+					},
+				},
+			}
+			action := e.policyChecked(context.Background(), cr, e.helm.Template, func(string, *chart.Chart, map[string]interface{}, []types.Patch) (*release.Release, error) {
+				called = true
+				return &release.Release{}, nil
+			})
+			_, err := action(testReleaseName, &chart.Chart{Metadata: &chart.Metadata{Name: testChart, Version: testVersion}}, nil, nil)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("policyChecked(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.called, called); diff != "" {
+				t.Errorf("policyChecked(...): -want called, +got called: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.status, cr.Status.GetCondition(v1beta1.TypePolicyCompliant).Status); diff != "" {
+				t.Errorf("policyChecked(...): -want status, +got status: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.query, query); diff != "" {
+				t.Errorf("policyChecked(...): -want query, +got query: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+// A pollIntervalReconciler requeues Releases that specify a poll interval at
+// their own interval rather than the default one, and Releases whose last
+// failure was tolerated at the failure retry interval. Poll intervals are
+// jittered by up to the supplied fraction.
+type pollIntervalReconciler struct {
+	kube    client.Client
+	poll    time.Duration
+	jitter  float64
+	wrapped reconcile.Reconciler
+}
+
+func (r *pollIntervalReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	// The spans of all phases of the reconcile are children of this span.
+	ctx, span := tracer.Start(ctx, "Reconcile", trace.WithAttributes(attrResourceName.String(req.Name)))
+	res, err := r.wrapped.Reconcile(ctx, req)
+	endSpan(span, err)
+	// Only the requeue of a successful reconcile is the poll interval, other
+	// requeues are left alone.
+	if err != nil || res.Requeue || res.RequeueAfter != r.poll {
+		return res, err
+	}
+
+	cr := &v1beta1.Release{}
+	if err := r.kube.Get(ctx, req.NamespacedName, cr); err != nil {
+		return res, nil
+	}
+	if p := cr.Spec.PollInterval; p != nil && p.Duration > 0 {
+		res.RequeueAfter = p.Duration
+	}
+	// Releases whose last failure was tolerated are observed again soon.
+	if cr.Status.ConsecutiveFailures > 0 && res.RequeueAfter > failureRetryInterval {
+		res.RequeueAfter = failureRetryInterval
+	}
+	if r.jitter > 0 {
+		res.RequeueAfter = wait.Jitter(res.RequeueAfter, r.jitter)
+	}
+	return res, nil
+}
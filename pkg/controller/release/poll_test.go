@@ -0,0 +1,104 @@
+package release
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+func TestPollIntervalReconcile(t *testing.T) {
+	poll := 10 * time.Minute
+
+	type want struct {
+		res reconcile.Result
+		err error
+	}
+	cases := map[string]struct {
+		res          reconcile.Result
+		err          error
+		pollInterval *metav1.Duration
+		failures     int32
+		want         want
+	}{
+		"DefaultPollInterval": {
+			res:  reconcile.Result{RequeueAfter: poll},
+			want: want{res: reconcile.Result{RequeueAfter: poll}},
+		},
+		"ReleasePollInterval": {
+			res:          reconcile.Result{RequeueAfter: poll},
+			pollInterval: &metav1.Duration{Duration: time.Minute},
+			want:         want{res: reconcile.Result{RequeueAfter: time.Minute}},
+		},
+		"ToleratedFailure": {
+			res:      reconcile.Result{RequeueAfter: poll},
+			failures: 1,
+			want:     want{res: reconcile.Result{RequeueAfter: failureRetryInterval}},
+		},
+		"OtherRequeue": {
+			res:          reconcile.Result{Requeue: true},
+			pollInterval: &metav1.Duration{Duration: time.Minute},
+			want:         want{res: reconcile.Result{Requeue: true}},
+		},
+		"Error": {
+			err:          errBoom,
+			pollInterval: &metav1.Duration{Duration: time.Minute},
+			want:         want{err: errBoom},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &pollIntervalReconciler{
+				kube: &test.MockClient{
+					MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+						obj.(*v1beta1.Release).Spec.PollInterval = tc.pollInterval
+						obj.(*v1beta1.Release).Status.ConsecutiveFailures = tc.failures
+						return nil
+					},
+				},
+				poll: poll,
+				wrapped: reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+					return tc.res, tc.err
+				}),
+			}
+			res, err := r.Reconcile(context.Background(), reconcile.Request{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("r.Reconcile(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.res, res); diff != "" {
+				t.Errorf("r.Reconcile(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func TestPollIntervalJitter(t *testing.T) {
+	poll := 10 * time.Minute
+	r := &pollIntervalReconciler{
+		kube: &test.MockClient{
+			MockGet: test.NewMockGetFn(nil),
+		},
+		poll:   poll,
+		jitter: 0.5,
+		wrapped: reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+			return reconcile.Result{RequeueAfter: poll}, nil
+		}),
+	}
+	for i := 0; i < 10; i++ {
+		res, err := r.Reconcile(context.Background(), reconcile.Request{})
+		if err != nil {
+			t.Fatalf("r.Reconcile(...): unexpected error: %s", err)
+		}
+		if res.RequeueAfter < poll || res.RequeueAfter > poll+poll/2 {
+			t.Errorf("r.Reconcile(...): want requeue after between %s and %s, got %s", poll, poll+poll/2, res.RequeueAfter)
+		}
+	}
+}
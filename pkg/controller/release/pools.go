@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+	helmv1beta1 "github.com/crossplane-contrib/provider-helm/apis/v1beta1"
+)
+
+// poolRetryInterval is how long a Release waits, plus up to the same again
+// as jitter, if the pool of its ProviderConfig has no worker to spare.
+const poolRetryInterval = 5 * time.Second
+
+// workerPools limit how many Releases of each ProviderConfig are reconciled
+// at once, so that the Releases of a slow or unreachable target cluster
+// can't occupy all workers and starve those of healthy clusters.
+type workerPools struct {
+	// max reconciles of a ProviderConfig that doesn't specify its own.
+	// Zero means unlimited.
+	max int
+
+	mu    sync.Mutex
+	inUse map[string]int
+}
+
+func newWorkerPools(max int) *workerPools {
+	return &workerPools{max: max, inUse: map[string]int{}}
+}
+
+// acquire a worker of the supplied pool, returning false if all of its max
+// workers are in use. Workers that are acquired must be released.
+func (p *workerPools) acquire(pool string, max int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if max > 0 && p.inUse[pool] >= max {
+		return false
+	}
+	p.inUse[pool]++
+	return true
+}
+
+// release a worker of the supplied pool.
+func (p *workerPools) release(pool string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.inUse[pool]--; p.inUse[pool] <= 0 {
+		delete(p.inUse, pool)
+	}
+}
+
+// A poolReconciler reconciles a Release only if the worker pool of its
+// ProviderConfig has a worker to spare, and requeues it otherwise.
+type poolReconciler struct {
+	kube    client.Client
+	pools   *workerPools
+	wrapped reconcile.Reconciler
+}
+
+func (r *poolReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	cr := &v1beta1.Release{}
+	if err := r.kube.Get(ctx, req.NamespacedName, cr); err != nil || cr.GetProviderConfigReference() == nil {
+		// Let the wrapped reconciler deal with Releases that are gone or
+		// can't be connected.
+		return r.wrapped.Reconcile(ctx, req)
+	}
+
+	pool := cr.GetProviderConfigReference().Name
+	if !r.pools.acquire(pool, r.max(ctx, pool)) {
+		return reconcile.Result{RequeueAfter: wait.Jitter(poolRetryInterval, 1)}, nil
+	}
+	defer r.pools.release(pool)
+	return r.wrapped.Reconcile(ctx, req)
+}
+
+// max returns the max workers of the pool of the supplied ProviderConfig.
+func (r *poolReconciler) max(ctx context.Context, name string) int {
+	pc := &helmv1beta1.ProviderConfig{}
+	if err := r.kube.Get(ctx, types.NamespacedName{Name: name}, pc); err != nil {
+		return r.pools.max
+	}
+	if m := pc.Spec.MaxConcurrentReconciles; m != nil {
+		return int(*m)
+	}
+	return r.pools.max
+}
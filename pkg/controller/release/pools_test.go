@@ -0,0 +1,99 @@
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+	helmv1beta1 "github.com/crossplane-contrib/provider-helm/apis/v1beta1"
+)
+
+func TestPoolReconcile(t *testing.T) {
+	two := int32(2)
+
+	type want struct {
+		reconciled bool
+		requeued   bool
+	}
+	cases := map[string]struct {
+		ref   *xpv1.Reference
+		max   int
+		pcMax *int32
+		inUse int
+		want  want
+	}{
+		"NoProviderConfig": {
+			max:   1,
+			inUse: 1,
+			want:  want{reconciled: true},
+		},
+		"WorkerAvailable": {
+			ref:  &xpv1.Reference{Name: providerName},
+			max:  1,
+			want: want{reconciled: true},
+		},
+		"PoolBusy": {
+			ref:   &xpv1.Reference{Name: providerName},
+			max:   1,
+			inUse: 1,
+			want:  want{requeued: true},
+		},
+		"ProviderConfigLimit": {
+			ref:   &xpv1.Reference{Name: providerName},
+			max:   1,
+			pcMax: &two,
+			inUse: 1,
+			want:  want{reconciled: true},
+		},
+		"Unlimited": {
+			ref:   &xpv1.Reference{Name: providerName},
+			inUse: 10,
+			want:  want{reconciled: true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pools := newWorkerPools(tc.max)
+			for i := 0; i < tc.inUse; i++ {
+				pools.acquire(providerName, 0)
+			}
+			got := want{}
+			r := &poolReconciler{
+				kube: &test.MockClient{
+					MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+						switch o := obj.(type) {
+						case *v1beta1.Release:
+							o.Spec.ProviderConfigReference = tc.ref
+						case *helmv1beta1.ProviderConfig:
+							o.Spec.MaxConcurrentReconciles = tc.pcMax
+						}
+						return nil
+					},
+				},
+				pools: pools,
+				wrapped: reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+					got.reconciled = true
+					return reconcile.Result{}, nil
+				}),
+			}
+			res, err := r.Reconcile(context.Background(), reconcile.Request{})
+			if err != nil {
+				t.Fatalf("r.Reconcile(...): unexpected error: %s", err)
+			}
+			got.requeued = res.RequeueAfter >= poolRetryInterval
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("r.Reconcile(...): -want, +got: %s", diff)
+			}
+			if diff := cmp.Diff(tc.inUse, pools.inUse[providerName]); diff != "" {
+				t.Errorf("r.Reconcile(...): -want workers in use, +got workers in use: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+	helmClient "github.com/crossplane-contrib/provider-helm/pkg/clients/helm"
+)
+
+const (
+	keyWebhookCA   = "ca.crt"
+	keyWebhookCert = "tls.crt"
+	keyWebhookKey  = "tls.key"
+)
+
+const (
+	errFailedToGetWebhookTLSSecret   = "failed to get post-render webhook TLS secret"
+	errFailedToGetWebhookTokenSecret = "failed to get post-render webhook bearer token secret"
+	errMissingWebhookToken           = "missing key \"%s\" in post-render webhook bearer token secret"
+)
+
+// webhookConfig resolves the post-render webhook configuration of a release,
+// returning nil if no webhook is configured.
+func webhookConfig(ctx context.Context, kube client.Client, pr *v1beta1.PostRender) (*helmClient.WebhookConfig, error) {
+	if pr == nil || pr.Webhook == nil {
+		return nil, nil
+	}
+	wh := pr.Webhook
+
+	cfg := &helmClient.WebhookConfig{URL: wh.URL}
+	if wh.Timeout != nil {
+		cfg.Timeout = wh.Timeout.Duration
+	}
+
+	if r := wh.TLSSecretRef; r != nil {
+		d, err := getSecretData(ctx, kube, types.NamespacedName{Name: r.Name, Namespace: r.Namespace})
+		if err != nil {
+			return nil, errors.Wrap(err, errFailedToGetWebhookTLSSecret)
+		}
+		cfg.CAData = d[keyWebhookCA]
+		cfg.CertData = d[keyWebhookCert]
+		cfg.KeyData = d[keyWebhookKey]
+	}
+
+	if r := wh.BearerTokenSecretRef; r != nil {
+		d, err := getSecretData(ctx, kube, types.NamespacedName{Name: r.Name, Namespace: r.Namespace})
+		if err != nil {
+			return nil, errors.Wrap(err, errFailedToGetWebhookTokenSecret)
+		}
+		t, ok := d[r.Key]
+		if !ok {
+			return nil, errors.New(fmt.Sprintf(errMissingWebhookToken, r.Key))
+		}
+		cfg.BearerToken = string(t)
+	}
+
+	return cfg, nil
+}
+
+func withPostRenderWebhook(cfg *helmClient.WebhookConfig) helmClient.ArgsApplier {
+	return func(config *helmClient.Args) {
+		config.PostRenderWebhook = cfg
+	}
+}
@@ -0,0 +1,123 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+	helmClient "github.com/crossplane-contrib/provider-helm/pkg/clients/helm"
+)
+
+const testWebhookURL = "https://post-render.example.org"
+
+func Test_webhookConfig(t *testing.T) {
+	secretData := map[string][]byte{
+		keyWebhookCA:   []byte("ca"),
+		keyWebhookCert: []byte("cert"),
+		keyWebhookKey:  []byte("key"),
+		"token":        []byte("t0k3n"),
+	}
+	kube := &test.MockClient{
+		MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+			if key.Name == testSecretName && key.Namespace == testNamespace {
+				*obj.(*corev1.Secret) = corev1.Secret{Data: secretData}
+				return nil
+			}
+			return errBoom
+		},
+	}
+	ref := xpv1.SecretReference{Name: testSecretName, Namespace: testNamespace}
+
+	type args struct {
+		pr *v1beta1.PostRender
+	}
+	type want struct {
+		out *helmClient.WebhookConfig
+		err error
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"NoPostRender": {
+			args: args{},
+			want: want{},
+		},
+		"NoWebhook": {
+			args: args{
+				pr: &v1beta1.PostRender{},
+			},
+			want: want{},
+		},
+		"Success": {
+			args: args{
+				pr: &v1beta1.PostRender{
+					Webhook: &v1beta1.PostRenderWebhook{
+						URL:                  testWebhookURL,
+						TLSSecretRef:         &ref,
+						BearerTokenSecretRef: &xpv1.SecretKeySelector{SecretReference: ref, Key: "token"},
+						Timeout:              &metav1.Duration{Duration: time.Minute},
+					},
+				},
+			},
+			want: want{
+				out: &helmClient.WebhookConfig{
+					URL:         testWebhookURL,
+					CAData:      []byte("ca"),
+					CertData:    []byte("cert"),
+					KeyData:     []byte("key"),
+					BearerToken: "t0k3n",
+					Timeout:     time.Minute,
+				},
+			},
+		},
+		"MissingToken": {
+			args: args{
+				pr: &v1beta1.PostRender{
+					Webhook: &v1beta1.PostRenderWebhook{
+						URL:                  testWebhookURL,
+						BearerTokenSecretRef: &xpv1.SecretKeySelector{SecretReference: ref, Key: "nope"},
+					},
+				},
+			},
+			want: want{
+				err: errors.New(fmt.Sprintf(errMissingWebhookToken, "nope")),
+			},
+		},
+		"FailedToGetTLSSecret": {
+			args: args{
+				pr: &v1beta1.PostRender{
+					Webhook: &v1beta1.PostRenderWebhook{
+						URL:          testWebhookURL,
+						TLSSecretRef: &xpv1.SecretReference{Name: "other", Namespace: testNamespace},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errBoom, fmt.Sprintf(errFailedToGetSecret, testNamespace)), errFailedToGetWebhookTLSSecret),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, gotErr := webhookConfig(context.Background(), kube, tc.args.pr)
+			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
+				t.Fatalf("webhookConfig(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.out, got); diff != "" {
+				t.Errorf("webhookConfig(...): -want result, +got result: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+	helmClient "github.com/crossplane-contrib/provider-helm/pkg/clients/helm"
+	"github.com/crossplane-contrib/provider-helm/pkg/clients/valuesource"
+)
+
+// A Preview of a Release.
+type Preview struct {
+	// Manifest the Release renders.
+	Manifest string
+
+	// FromRevision is the deployed revision of the release the manifest is
+	// compared to, or zero if it was not compared.
+	FromRevision int
+
+	// Added, Changed and Removed resources compared to the deployed revision.
+	Added   []string
+	Changed []string
+	Removed []string
+
+	// Diff of the changed resources, with the data of Secrets masked.
+	Diff string
+}
+
+// A Previewer renders Releases exactly like the provider does, without
+// deploying them.
+type Previewer struct {
+	kube            client.Client
+	logger          logging.Logger
+	newHelmClientFn func(log logging.Logger, config *rest.Config, helmArgs ...helmClient.ArgsApplier) (helmClient.Client, error)
+}
+
+// NewPreviewer returns a Previewer reading ReleaseClasses as well as the
+// values, patches and credentials of Releases using the supplied client.
+func NewPreviewer(kube client.Client, l logging.Logger) *Previewer {
+	return &Previewer{kube: kube, logger: l, newHelmClientFn: helmClient.NewClient}
+}
+
+// Preview renders the supplied Release. If a REST config of its target
+// cluster is supplied, the rendered manifest is compared to that of the
+// deployed revision of the release, if any. The Release is not modified.
+func (p *Previewer) Preview(ctx context.Context, cr *v1beta1.Release, target *rest.Config) (*Preview, error) {
+	cr = cr.DeepCopy()
+	// The Release may not exist yet, so the chart name and version must not
+	// be late initialized.
+	cr.Spec.ManagementPolicies = []v1beta1.ManagementAction{v1beta1.ManagementActionObserve}
+
+	class, err := releaseClass(ctx, p.kube, cr)
+	if err != nil {
+		return nil, err
+	}
+	if class != nil {
+		applyClassDefaults(&cr.Spec.ForProvider, class.Spec.Defaults)
+	}
+	wh, err := webhookConfig(ctx, p.kube, cr.Spec.ForProvider.PostRender)
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToConfigurePostRender)
+	}
+
+	// Rendering happens entirely on the client, so the target cluster is
+	// only needed to read the deployed revision.
+	rc := target
+	if rc == nil {
+		rc = &rest.Config{}
+	}
+	h, err := p.newHelmClientFn(p.logger, rc, withRelease(&cr.Spec.ForProvider), withPostRenderWebhook(wh))
+	if err != nil {
+		return nil, errors.Wrap(err, errNewKubernetesClient)
+	}
+
+	e := &helmExternal{
+		logger:    p.logger,
+		recorder:  event.NewNopRecorder(),
+		localKube: p.kube,
+		helm:      h,
+		patch:     newPatcher(),
+		// Value source plugins are only served to the provider.
+		plugins: valuesource.Plugins(nil),
+	}
+	rel, _, err := e.render(ctx, cr, h.Template)
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToRender)
+	}
+	pv := &Preview{Manifest: rel.Manifest}
+	if target == nil {
+		return pv, nil
+	}
+
+	deployed, err := h.GetLastRelease(meta.GetExternalName(cr))
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		return pv, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToGetLastRelease)
+	}
+	d, err := diffManifests(deployed.Manifest, rel.Manifest)
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToDiff)
+	}
+	pv.FromRevision = deployed.Version
+	pv.Added, pv.Changed, pv.Removed, pv.Diff = d.added, d.changed, d.removed, d.details
+	return pv, nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/kustomize/api/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+	helmClient "github.com/crossplane-contrib/provider-helm/pkg/clients/helm"
+)
+
+func TestPreview(t *testing.T) {
+	errBoom := errors.New("boom")
+	cr := helmRelease(func(r *v1beta1.Release) {
+		r.Spec.ForProvider.Values = runtime.RawExtension{Raw: []byte(`{"replicas":2}`)}
+	})
+
+	type args struct {
+		target *rest.Config
+		last   func(string) (*release.Release, error)
+	}
+	type want struct {
+		pv  *Preview
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoTarget": {
+			reason: "Without a target cluster the rendered manifest should be returned as is.",
+			want:   want{pv: &Preview{Manifest: testDiffTo}},
+		},
+		"Diff": {
+			reason: "The rendered manifest should be compared to that of the deployed revision.",
+			args: args{
+				target: &rest.Config{},
+				last: func(string) (*release.Release, error) {
+					return &release.Release{Version: 2, Manifest: testDiffFrom}, nil
+				},
+			},
+			want: want{pv: &Preview{
+				Manifest:     testDiffTo,
+				FromRevision: 2,
+				Added:        []string{"ConfigMap added"},
+				Changed:      []string{"Secret ns/creds"},
+				Removed:      []string{"ConfigMap removed"},
+			}},
+		},
+		"NotDeployed": {
+			reason: "The rendered manifest should not be compared if the release is not deployed.",
+			args: args{
+				target: &rest.Config{},
+				last: func(string) (*release.Release, error) {
+					return nil, driver.ErrReleaseNotFound
+				},
+			},
+			want: want{pv: &Preview{Manifest: testDiffTo}},
+		},
+		"GetLastReleaseError": {
+			reason: "Errors getting the deployed revision should be returned.",
+			args: args{
+				target: &rest.Config{},
+				last: func(string) (*release.Release, error) {
+					return nil, errBoom
+				},
+			},
+			want: want{err: errors.Wrap(errBoom, errFailedToGetLastRelease)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var vals map[string]interface{}
+			p := &Previewer{
+				kube:   &test.MockClient{},
+				logger: logging.NewNopLogger(),
+				newHelmClientFn: func(_ logging.Logger, _ *rest.Config, _ ...helmClient.ArgsApplier) (helmClient.Client, error) {
+					return &MockHelmClient{
+						MockPullAndLoadChart: func(*v1beta1.ChartSpec, *helmClient.RepoCreds) (*chart.Chart, error) {
+							return &chart.Chart{}, nil
+						},
+						MockTemplate: func(_ string, _ *chart.Chart, v map[string]interface{}, _ []types.Patch) (*release.Release, error) {
+							vals = v
+							return &release.Release{Manifest: testDiffTo}, nil
+						},
+						MockGetLastRelease: tc.args.last,
+					}, nil
+				},
+			}
+			pv, err := p.Preview(context.Background(), cr, tc.args.target)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPreview(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if pv != nil {
+				pv.Diff = ""
+			}
+			if diff := cmp.Diff(tc.want.pv, pv); diff != "" {
+				t.Errorf("\n%s\nPreview(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(map[string]interface{}{"replicas": float64(2)}, vals); diff != "" {
+				t.Errorf("\n%s\nPreview(...): -want values, +got values:\n%s", tc.reason, diff)
+			}
+		})
+	}
+	if len(cr.Spec.ManagementPolicies) > 0 {
+		t.Errorf("Preview(...): Release must not be modified")
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ktype "sigs.k8s.io/kustomize/api/types"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	errFailedToRenderForPreflight = "failed to render release for RBAC pre-flight check"
+	errFailedToReviewAccess       = "failed to review access"
+	errPermissionsMissing         = "missing %d permission(s): %s"
+)
+
+// preflightVerbs are the verbs Helm uses to install and upgrade resources.
+var preflightVerbs = []string{"get", "create", "patch"}
+
+// permitted returns a deployAction that checks the permissions of the
+// provider for the rendered manifests on the target cluster before running
+// the supplied action. The Permitted condition of the Release reflects the
+// outcome.
+func (e *helmExternal) permitted(ctx context.Context, cr *v1beta1.Release, template, action deployAction) deployAction {
+	return func(rel string, ch *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (*release.Release, error) {
+		// The chart, values and patches of the last successful install or
+		// upgrade don't need to be checked again, e.g. to correct drift.
+		if d, err := diffDigest(ch, vals, patches); err == nil && d == cr.Status.SyncedDigest {
+			return action(rel, ch, vals, patches)
+		}
+		r, err := template(rel, ch, vals, patches)
+		if err != nil {
+			return nil, errors.Wrap(err, errFailedToRenderForPreflight)
+		}
+		if err := checkPermissions(ctx, e.kube, e.kube.RESTMapper(), cr.Spec.ForProvider.Namespace, r.Manifest); err != nil {
+			cr.Status.SetConditions(v1beta1.PermissionsMissing(err))
+			return nil, err
+		}
+		cr.Status.SetConditions(v1beta1.PermissionsGranted())
+		return action(rel, ch, vals, patches)
+	}
+}
+
+// checkPermissions reviews whether the provider may get, create and patch
+// every resource of the manifest and returns an error listing all missing
+// permissions. Resources of kinds defined by a CRD in the same manifest are
+// skipped as long as the CRD does not exist yet.
+func checkPermissions(ctx context.Context, kube client.Client, mapper meta.RESTMapper, namespace, manifest string) error {
+	objs, err := parseManifest(manifest)
+	if err != nil {
+		return err
+	}
+	crds := manifestCRDs(objs)
+
+	reviewed := map[authorizationv1.ResourceAttributes]bool{}
+	var missing []string
+	for _, o := range objs {
+		gvk := o.GroupVersionKind()
+		m, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if meta.IsNoMatchError(err) && crds[gvk.GroupKind()] {
+			continue
+		}
+		if err != nil {
+			return errors.Wrap(err, errFailedToMapResource)
+		}
+		ns := ""
+		if m.Scope.Name() == meta.RESTScopeNameNamespace {
+			ns = o.GetNamespace()
+			if ns == "" {
+				ns = namespace
+			}
+		}
+		for _, v := range preflightVerbs {
+			ra := authorizationv1.ResourceAttributes{Namespace: ns, Verb: v, Group: m.Resource.Group, Resource: m.Resource.Resource}
+			if reviewed[ra] {
+				continue
+			}
+			reviewed[ra] = true
+
+			sar := &authorizationv1.SelfSubjectAccessReview{Spec: authorizationv1.SelfSubjectAccessReviewSpec{ResourceAttributes: &ra}}
+			if err := kube.Create(ctx, sar); err != nil {
+				return errors.Wrap(err, errFailedToReviewAccess)
+			}
+			if !sar.Status.Allowed {
+				missing = append(missing, describeAccess(ra))
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		return errors.Errorf(errPermissionsMissing, len(missing), strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// describeAccess returns e.g. "create deployments.apps in namespace default".
+func describeAccess(ra authorizationv1.ResourceAttributes) string {
+	r := ra.Resource
+	if ra.Group != "" {
+		r += "." + ra.Group
+	}
+	if ra.Namespace == "" {
+		return fmt.Sprintf("%s %s", ra.Verb, r)
+	}
+	return fmt.Sprintf("%s %s in namespace %s", ra.Verb, r, ra.Namespace)
+}
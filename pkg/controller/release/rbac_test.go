@@ -0,0 +1,70 @@
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func Test_checkPermissions(t *testing.T) {
+	// allow returns a Create mock that allows the supplied verbs.
+	allow := func(verbs ...string) func(context.Context, client.Object, ...client.CreateOption) error {
+		return func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+			sar := obj.(*authorizationv1.SelfSubjectAccessReview)
+			for _, v := range verbs {
+				if sar.Spec.ResourceAttributes.Verb == v {
+					sar.Status.Allowed = true
+				}
+			}
+			return nil
+		}
+	}
+
+	type args struct {
+		kube     client.Client
+		manifest string
+	}
+	cases := map[string]struct {
+		args
+		want error
+	}{
+		"Allowed": {
+			args: args{
+				kube:     &test.MockClient{MockCreate: allow("get", "create", "patch")},
+				manifest: testDryRunManifest,
+			},
+		},
+		"Missing": {
+			args: args{
+				kube: &test.MockClient{MockCreate: allow("get")},
+				manifest: "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: d\n---\n" +
+					"apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: e\n---\n" +
+					"apiVersion: apiextensions.k8s.io/v1\nkind: CustomResourceDefinition\nmetadata:\n  name: c\n",
+			},
+			want: errors.Errorf(errPermissionsMissing, 4,
+				"create deployments.apps in namespace "+testNamespace+", patch deployments.apps in namespace "+testNamespace+", "+
+					"create customresourcedefinitions.apiextensions.k8s.io, patch customresourcedefinitions.apiextensions.k8s.io"),
+		},
+		"ReviewError": {
+			args: args{
+				kube:     &test.MockClient{MockCreate: test.NewMockCreateFn(errBoom)},
+				manifest: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n",
+			},
+			want: errors.Wrap(errBoom, errFailedToReviewAccess),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := checkPermissions(context.Background(), tc.args.kube, testRESTMapper(), testNamespace, tc.args.manifest)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("checkPermissions(...): -want error, +got error: %s", diff)
+			}
+		})
+	}
+}
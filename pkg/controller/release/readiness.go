@@ -0,0 +1,100 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	errFailedToGetReadinessObject = "failed to get object of readiness check"
+)
+
+// observeReadiness evaluates the readiness checks of the Release and returns
+// whether the Release may report Ready. Failing checks are reported in the
+// ReadinessChecks condition.
+func (e *helmExternal) observeReadiness(ctx context.Context, cr *v1beta1.Release) (bool, error) {
+	if len(cr.Spec.ForProvider.ReadinessChecks) == 0 {
+		return true, nil
+	}
+
+	failed, err := failedReadinessChecks(ctx, e.kube, cr.Spec.ForProvider.Namespace, cr.Spec.ForProvider.ReadinessChecks)
+	if err != nil {
+		return false, err
+	}
+	if len(failed) > 0 {
+		cr.Status.SetConditions(v1beta1.ReadinessChecksFailed(failed))
+		return false, nil
+	}
+	cr.Status.SetConditions(v1beta1.ReadinessChecksPassed())
+	return true, nil
+}
+
+// failedReadinessChecks returns a description of every readiness check that
+// does not pass.
+func failedReadinessChecks(ctx context.Context, kube client.Client, namespace string, checks []v1beta1.ReadinessCheck) ([]string, error) {
+	var failed []string
+	for _, rc := range checks {
+		o := unstructuredFromObjectRef(rc.ObjectReference)
+		if o.GetNamespace() == "" {
+			o.SetNamespace(namespace)
+		}
+		key := resourceKey(&o)
+
+		err := kube.Get(ctx, types.NamespacedName{Name: o.GetName(), Namespace: o.GetNamespace()}, &o)
+		if kerrors.IsNotFound(err) {
+			failed = append(failed, key+": not found")
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errFailedToGetReadinessObject)
+		}
+
+		if rc.ConditionType != "" && !conditionIs(&o, rc.ConditionType, "True") {
+			failed = append(failed, fmt.Sprintf("%s: condition %s is not True", key, rc.ConditionType))
+			continue
+		}
+		if rc.FieldPath == "" {
+			continue
+		}
+		v, err := fieldpath.Pave(o.Object).GetValue(rc.FieldPath)
+		if err != nil && !fieldpath.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "failed to get value at fieldPath: %s", rc.FieldPath)
+		}
+		got := ""
+		if v != nil {
+			got = fmt.Sprintf("%v", v)
+		}
+		switch {
+		case rc.Value == "" && got == "":
+			failed = append(failed, fmt.Sprintf("%s: %s is not set", key, rc.FieldPath))
+		case rc.Value != "" && got != rc.Value:
+			failed = append(failed, fmt.Sprintf("%s: %s is %q, want %q", key, rc.FieldPath, got, rc.Value))
+		}
+	}
+	return failed, nil
+}
@@ -0,0 +1,80 @@
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+func Test_failedReadinessChecks(t *testing.T) {
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			if key.Name == "missing" {
+				return kerrors.NewNotFound(schema.GroupResource{Resource: "databases"}, key.Name)
+			}
+			u := obj.(*unstructured.Unstructured)
+			u.Object["status"] = map[string]interface{}{
+				"phase":      "Provisioning",
+				"conditions": testConditions("Synced", "True"),
+			}
+			return nil
+		},
+	}
+	ref := func(name string) corev1.ObjectReference {
+		return corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "Database", Name: name}
+	}
+
+	cases := map[string]struct {
+		checks []v1beta1.ReadinessCheck
+		want   []string
+	}{
+		"NoChecks": {},
+		"Passing": {
+			checks: []v1beta1.ReadinessCheck{
+				{ObjectReference: withFieldPath(ref("db"), "status.phase"), Value: "Provisioning"},
+				{ObjectReference: withFieldPath(ref("db"), "status.phase")},
+				{ObjectReference: ref("db"), ConditionType: "Synced"},
+			},
+		},
+		"Failing": {
+			checks: []v1beta1.ReadinessCheck{
+				{ObjectReference: ref("missing")},
+				{ObjectReference: withFieldPath(ref("db"), "status.phase"), Value: "Ready"},
+				{ObjectReference: withFieldPath(ref("db"), "status.endpoint")},
+				{ObjectReference: ref("db"), ConditionType: "Ready"},
+			},
+			want: []string{
+				"Database testns/missing: not found",
+				`Database testns/db: status.phase is "Provisioning", want "Ready"`,
+				"Database testns/db: status.endpoint is not set",
+				"Database testns/db: condition Ready is not True",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := failedReadinessChecks(context.Background(), kube, testNamespace, tc.checks)
+			if err != nil {
+				t.Fatalf("failedReadinessChecks(...): unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("failedReadinessChecks(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func withFieldPath(r corev1.ObjectReference, p string) corev1.ObjectReference {
+	r.FieldPath = p
+	return r
+}
@@ -18,6 +18,11 @@ package release
 
 import (
 	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -26,12 +31,19 @@ import (
 	"helm.sh/helm/v3/pkg/storage/driver"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
+	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	ctrlevent "sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 	ktype "sigs.k8s.io/kustomize/api/types"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -44,8 +56,12 @@ import (
 	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
 	helmv1beta1 "github.com/crossplane-contrib/provider-helm/apis/v1beta1"
 	"github.com/crossplane-contrib/provider-helm/pkg/clients"
+	"github.com/crossplane-contrib/provider-helm/pkg/clients/aks"
+	"github.com/crossplane-contrib/provider-helm/pkg/clients/eks"
 	"github.com/crossplane-contrib/provider-helm/pkg/clients/gke"
 	helmClient "github.com/crossplane-contrib/provider-helm/pkg/clients/helm"
+	"github.com/crossplane-contrib/provider-helm/pkg/clients/oidc"
+	"github.com/crossplane-contrib/provider-helm/pkg/clients/valuesource"
 )
 
 const (
@@ -60,6 +76,15 @@ const (
 	helmReleaseNamespaceAnnotation = "meta.helm.sh/release-namespace"
 	helmNamespaceLabel             = "app.kubernetes.io/managed-by"
 	helmProviderName               = "provider-helm"
+
+	renderedManifestKey = "manifest"
+
+	// Cluster API stores the kubeconfig of a Cluster in a Secret named
+	// after it.
+	clusterAPIKubeconfigSuffix = "-kubeconfig"
+	clusterAPIKubeconfigKey    = "value"
+
+	reasonResourcesKept event.Reason = "ResourcesKept"
 )
 
 const (
@@ -67,75 +92,279 @@ const (
 	errProviderConfigNotSet             = "provider config is not set"
 	errProviderNotRetrieved             = "provider could not be retrieved"
 	errNewKubernetesClient              = "cannot create new Kubernetes client"
+	errNewRESTMapper                    = "cannot create REST mapper"
+	errIndexReleases                    = "cannot index Releases"
+	errInvalidChartPolicy               = "invalid chart policy"
+	errServiceAccountImpersonated       = "cannot perform operations as ServiceAccount %s: ProviderConfig %s impersonates a user"
+	errFmtReleaseKubeconfigNotAllowed   = "ProviderConfig %s does not allow Releases to supply a kubeconfig"
 	errFailedToGetLastRelease           = "failed to get last helm release"
 	errLastReleaseIsNil                 = "last helm release is nil"
 	errFailedToCheckIfUpToDate          = "failed to check if release is up to date"
 	errFailedToInstall                  = "failed to install release"
 	errFailedToUpgrade                  = "failed to upgrade release"
 	errFailedToUninstall                = "failed to uninstall release"
+	errDeletionProtected                = "deletion protection is enabled"
+	errSuspended                        = "release is suspended"
+	errFailedToForget                   = "failed to delete release record"
 	errFailedToGetRepoCreds             = "failed to get user name and password from secret reference"
 	errFailedToComposeValues            = "failed to compose values"
 	errFailedToExtractKubeconfig        = "failed to extract kubeconfig"
+	errClusterAPIRefNotSet              = "clusterAPIRef must be set for credentials source " + string(helmv1beta1.CredentialsSourceClusterAPI)
 	errFailedToExtractGoogleCredentials = "failed to extract Google Application Credentials"
 	errFailedToInjectGoogleCredentials  = "failed to wrap REST client with Google Application Credentials"
+	errFailedToInjectWorkloadIdentity   = "failed to wrap REST client with Google workload identity"
+	errFailedToInjectAWSIdentity        = "failed to wrap REST client with AWS web identity"
+	errFailedToInjectAzureIdentity      = "failed to wrap REST client with Azure AD identity"
+	errFailedToInjectOIDCIdentity       = "failed to wrap REST client with OIDC token exchange"
+	errOIDCIdentityNotSet               = "oidc must be set for identity type " + helmv1beta1.IdentityTypeOIDCTokenExchange
+	errAWSIdentityNotSet                = "aws must be set for identity type " + helmv1beta1.IdentityTypeAWSWebIdentity
 	errFailedToCreateRESTConfig         = "cannot create new rest config using provider secret"
+	errFailedToInjectBoundToken         = "failed to wrap REST client with bound ServiceAccount token"
+	errInvalidProxyURL                  = "invalid proxy URL"
+	errProxyScheme                      = "unsupported proxy scheme %q, must be http, https or socks5"
+	errReadCAFile                       = "cannot read certificate authorities of the credentials"
 	errFailedToTrackUsage               = "cannot track provider config usage"
 	errFailedToLoadPatches              = "failed to load patches"
 	errFailedToUpdatePatchSha           = "failed to update patch sha"
 	errFailedToSetName                  = "failed to update chart spec with the name from URL"
 	errFailedToSetVersion               = "failed to update chart spec with the latest version"
 	errFailedToCreateNamespace          = "failed to create namespace for release"
+	errFailedToConfigurePostRender      = "failed to configure post-render webhook"
+	errFailedToRender                   = "failed to render release"
 )
 
+// Options of the Release controller.
+type Options struct {
+	// PollInterval at which Releases are observed unless they specify their
+	// own.
+	PollInterval time.Duration
+	// ExecPlugins that kubeconfigs may use, in addition to those that are
+	// supported natively.
+	ExecPlugins []string
+	// ClientCacheTTL is how long the clients of a target cluster are reused.
+	ClientCacheTTL time.Duration
+	// ClientCacheSize is the maximum number of target cluster clients that
+	// are cached.
+	ClientCacheSize int
+	// Connection tunes the clients of target clusters whose ProviderConfig
+	// doesn't.
+	Connection helmv1beta1.Connection
+	// HealthProbeInterval at which the target clusters of ProviderConfigs
+	// are probed.
+	HealthProbeInterval time.Duration
+	// HelmDebug logs the verbose output of the Helm actions of all
+	// Releases, rather than only of those annotated with
+	// helm.crossplane.io/debug.
+	HelmDebug bool
+	// HelmStorageCache reads the release records of target clusters from a
+	// cache that watches them, rather than from their API servers on every
+	// reconcile. The provider must be allowed to list and watch Secrets
+	// across the target clusters.
+	HelmStorageCache bool
+	// MaxReconcilesPerProviderConfig is the maximum number of Releases of a
+	// ProviderConfig that are reconciled at once, unless the ProviderConfig
+	// specifies its own. Zero means unlimited.
+	MaxReconcilesPerProviderConfig int
+	// Controller configures the workers and rate limiting of the controller.
+	// Ten Releases are reconciled at once unless it specifies otherwise.
+	Controller controller.Options
+	// RequeueJitter is the maximum fraction of the poll interval of a Release
+	// that is added to it at random, so that Releases created at once are not
+	// observed at once ever after.
+	RequeueJitter float64
+	// MaxParallelChartPulls is the maximum number of charts that are pulled
+	// at once. Concurrent pulls of the same chart are always deduplicated.
+	// Zero means unlimited.
+	MaxParallelChartPulls int
+	// ValueSourcePlugins that Releases read values from by name.
+	ValueSourcePlugins valuesource.Plugins
+	// ESSTLSCertDir is the directory of the TLS certificates that the
+	// provider authenticates to external secret store plugins with.
+	ESSTLSCertDir string
+	// ChartPolicy restricts the charts Releases may deploy. All charts are
+	// allowed if nil.
+	ChartPolicy *ChartPolicy
+	// PolicyServer evaluates the rendered manifests of all Releases before
+	// they are installed or upgraded. Manifests are not evaluated if nil.
+	PolicyServer *PolicyServer
+}
+
 // Setup adds a controller that reconciles Release managed resources.
-func Setup(mgr ctrl.Manager, l logging.Logger) error {
+func Setup(mgr ctrl.Manager, l logging.Logger, o Options) error {
 	name := managed.ControllerName(v1beta1.ReleaseGroupKind)
 	logger := l.WithValues("controller", name)
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
+	poll := o.PollInterval
+	if poll == 0 {
+		poll = resyncPeriod
+	}
+	cache := newClientCache(o.ClientCacheTTL, o.ClientCacheSize)
+	helmClient.SetMaxParallelChartPulls(o.MaxParallelChartPulls)
+	if err := o.ChartPolicy.Validate(); err != nil {
+		return errors.Wrap(err, errInvalidChartPolicy)
+	}
+	stats := newOperationStats()
+	drift := make(chan ctrlevent.GenericEvent, driftEventBuffer)
+
+	conn := &connector{
+		logger:          logger,
+		recorder:        recorder,
+		client:          mgr.GetClient(),
+		usage:           resource.NewProviderConfigUsageTracker(mgr.GetClient(), &helmv1beta1.ProviderConfigUsage{}),
+		kcfgExtractorFn: resource.CommonCredentialExtractor,
+		gcpExtractorFn:  resource.CommonCredentialExtractor,
+		gcpInjectorFn:   gke.WrapRESTConfig,
+		gcpWIInjectorFn: gke.WrapRESTConfigWithWorkloadIdentity,
+		awsInjectorFn:   eks.WrapRESTConfig,
+		azInjectorFn:    aks.WrapRESTConfig,
+		oidcInjectorFn:  oidc.WrapRESTConfig,
+		boundTokenFn:    clients.WrapRESTConfigWithBoundToken,
+		newRestConfigFn: func(kubeconfig []byte, translateExec bool) (*rest.Config, error) {
+			return clients.NewRESTConfig(kubeconfig, translateExec, o.ExecPlugins...)
+		},
+		newKubeClientFn: clients.NewKubeClient,
+		newHelmClientFn: helmClient.NewClient,
+		cache:           cache,
+		storageCache:    o.HelmStorageCache,
+		stats:           stats,
+		connection:      o.Connection,
+		helmDebug:       o.HelmDebug,
+		driftEvents:     drift,
+		chartPolicy:     o.ChartPolicy,
+		policyServer:    o.PolicyServer,
+		plugins:         o.ValueSourcePlugins,
+	}
+
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1beta1.ReleaseGroupVersionKind),
-		managed.WithExternalConnecter(&connector{
-			logger:          logger,
-			client:          mgr.GetClient(),
-			usage:           resource.NewProviderConfigUsageTracker(mgr.GetClient(), &helmv1beta1.ProviderConfigUsage{}),
-			kcfgExtractorFn: resource.CommonCredentialExtractor,
-			gcpExtractorFn:  resource.CommonCredentialExtractor,
-			gcpInjectorFn:   gke.WrapRESTConfig,
-			newRestConfigFn: clients.NewRESTConfig,
-			newKubeClientFn: clients.NewKubeClient,
-			newHelmClientFn: helmClient.NewClient,
-		}),
+		managed.WithExternalConnecter(conn),
 		managed.WithLogger(logger),
 		managed.WithTimeout(reconcileTimeout),
-		managed.WithPollInterval(resyncPeriod),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
+		managed.WithPollInterval(poll),
+		managed.WithConnectionPublishers(
+			managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme()),
+			newSecretsPublisher(mgr.GetClient()),
+			newStorePublisher(mgr.GetClient(), o.ESSTLSCertDir)),
+		managed.WithRecorder(recorder))
+
+	cm := &credentialsMapper{kube: mgr.GetClient(), log: logger, cache: cache}
+	if err := indexReleases(context.Background(), mgr.GetFieldIndexer()); err != nil {
+		return errors.Wrap(err, errIndexReleases)
+	}
+
+	co := o.Controller
+	if co.MaxConcurrentReconciles == 0 {
+		co.MaxConcurrentReconciles = maxConcurrency
+	}
+
+	if err := setupHealthProber(mgr, l, conn, o.HealthProbeInterval); err != nil {
+		return err
+	}
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		For(&v1beta1.Release{}).
-		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrency}).
-		Complete(r)
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(cm.releasesForSecret), builder.OnlyMetadata).
+		Watches(&source.Kind{Type: &helmv1beta1.ProviderConfig{}}, handler.EnqueueRequestsFromMapFunc(cm.releasesForProviderConfig), builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&source.Channel{Source: drift}, &handler.EnqueueRequestForObject{}).
+		WithOptions(co).
+		Complete(&poolReconciler{
+			kube:    mgr.GetClient(),
+			pools:   newWorkerPools(o.MaxReconcilesPerProviderConfig),
+			wrapped: &pollIntervalReconciler{kube: mgr.GetClient(), poll: poll, jitter: o.RequeueJitter, wrapped: r},
+		})
 }
 
 type connector struct {
-	logger logging.Logger
-	client client.Client
-	usage  resource.Tracker
+	logger   logging.Logger
+	recorder event.Recorder
+	client   client.Client
+	usage    resource.Tracker
 
 	kcfgExtractorFn func(ctx context.Context, src xpv1.CredentialsSource, c client.Client, ccs xpv1.CommonCredentialSelectors) ([]byte, error)
 	gcpExtractorFn  func(ctx context.Context, src xpv1.CredentialsSource, c client.Client, ccs xpv1.CommonCredentialSelectors) ([]byte, error)
 	gcpInjectorFn   func(ctx context.Context, rc *rest.Config, credentials []byte, scopes ...string) error
-	newRestConfigFn func(kubeconfig []byte) (*rest.Config, error)
-	newKubeClientFn func(config *rest.Config) (client.Client, error)
+	gcpWIInjectorFn func(rc *rest.Config, scopes ...string) error
+	awsInjectorFn   func(rc *rest.Config, cfg eks.Config) error
+	azInjectorFn    func(rc *rest.Config, cfg aks.Config) error
+	oidcInjectorFn  func(rc *rest.Config, cfg oidc.Config) error
+	boundTokenFn    func(rc *rest.Config, audiences []string, expirationSeconds *int64) error
+	newRestConfigFn func(kubeconfig []byte, translateExec bool) (*rest.Config, error)
+	newKubeClientFn func(config *rest.Config, mapper apimeta.RESTMapper) (client.Client, error)
 	newHelmClientFn func(log logging.Logger, config *rest.Config, helmArgs ...helmClient.ArgsApplier) (helmClient.Client, error)
+
+	// cache of target cluster clients. Clients are built on every connect
+	// if nil.
+	cache *clientCache
+
+	// stats of the operations of Releases, by ProviderConfig. Nothing is
+	// recorded if nil.
+	stats *operationStats
+
+	// connection defaults of target cluster clients.
+	connection helmv1beta1.Connection
+
+	// helmDebug logs the verbose output of the Helm actions of all Releases.
+	helmDebug bool
+
+	// storageCache caches the release records of cached target cluster
+	// clients.
+	storageCache bool
+
+	// chartPolicy restricts the charts of Releases. All charts are allowed
+	// if nil.
+	chartPolicy *ChartPolicy
+
+	// policyServer evaluates the rendered manifests of Releases. They are
+	// not evaluated if nil.
+	policyServer *PolicyServer
+
+	// plugins are the value source plugins of the provider.
+	plugins valuesource.Plugins
+
+	// driftEvents enqueue the Releases whose watched resources changed.
+	// Resources are not watched if nil.
+	driftEvents chan ctrlevent.GenericEvent
+}
+
+func withRelease(p *v1beta1.ReleaseParameters) helmClient.ArgsApplier {
+	return func(config *helmClient.Args) {
+		config.Namespace = p.Namespace
+		config.Wait = pointer.BoolDeref(p.Wait, false)
+		config.Timeout = waitTimeout(p)
+		config.SkipCRDs = pointer.BoolDeref(p.SkipCRDs, false)
+		if mh := p.MaxHistory; mh != nil {
+			config.MaxHistory = int(*mh)
+		}
+		if cm := commonMetadata(p); cm != nil {
+			config.CommonLabels = cm.Labels
+			config.CommonAnnotations = cm.Annotations
+		}
+	}
+}
+
+func withNamespace(namespace string) helmClient.ArgsApplier {
+	return func(config *helmClient.Args) {
+		config.Namespace = namespace
+	}
 }
 
-func withRelease(cr *v1beta1.Release) helmClient.ArgsApplier {
+func withDebug(debug bool) helmClient.ArgsApplier {
 	return func(config *helmClient.Args) {
-		config.Namespace = cr.Spec.ForProvider.Namespace
-		config.Wait = cr.Spec.ForProvider.Wait
-		config.Timeout = waitTimeout(cr)
-		config.SkipCRDs = cr.Spec.ForProvider.SkipCRDs
+		config.Debug = debug
+	}
+}
+
+// withClientGetter must be applied after the namespace is set.
+func withClientGetter(cc *clusterClients) helmClient.ArgsApplier {
+	return func(config *helmClient.Args) {
+		config.ClientGetter = cc.getter(config.Namespace)
+		config.Capabilities = cc.capabilities
+		if cc.storage != nil {
+			config.StorageSecrets = cc.storage.Secrets(config.Namespace)
+		}
 	}
 }
 
@@ -154,6 +383,12 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.New(errProviderConfigNotSet)
 	}
 
+	// The Release is updated if it is selected by a ProviderConfig, so this
+	// must happen before any defaults are applied to it.
+	if err := selectProviderConfig(ctx, c.client, cr); err != nil {
+		return nil, err
+	}
+
 	if err := c.usage.Track(ctx, cr); err != nil {
 		return nil, errors.Wrap(err, errFailedToTrackUsage)
 	}
@@ -163,66 +398,339 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errProviderNotRetrieved)
 	}
 
+	class, err := releaseClass(ctx, c.client, cr)
+	if err != nil {
+		return nil, err
+	}
+	// Releases that are deleted are still uninstalled, e.g. if the
+	// namespaces were restricted after they were installed.
+	if err := targetNamespaceAllowed(p, cr); err != nil && !meta.WasDeleted(cr) {
+		return nil, err
+	}
+
+	key := clientCacheKey{providerConfig: p.GetName(), generation: p.GetGeneration()}
+	var sa *types.NamespacedName
+	if name := cr.Spec.ForProvider.ServiceAccountName; name != "" {
+		sa = &types.NamespacedName{Namespace: cr.Spec.ForProvider.Namespace, Name: name}
+		key.serviceAccount = sa.String()
+	}
+	cache := c.cache
+	if ref := cr.Spec.ForProvider.KubeConfigSecretRef; ref != nil {
+		// Releases that are deleted are still uninstalled, as they use
+		// no credentials of the ProviderConfig.
+		if !p.Spec.AllowReleaseKubeconfigs && !meta.WasDeleted(cr) {
+			return nil, errors.Errorf(errFmtReleaseKubeconfigNotAllowed, p.GetName())
+		}
+		p = p.DeepCopy()
+		p.Spec.Credentials = helmv1beta1.ProviderCredentials{
+			Source:                    xpv1.CredentialsSourceSecret,
+			CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: ref},
+		}
+		// The kubeconfig may name any server, which must not receive
+		// tokens of the identity of the provider or the ProviderConfig.
+		p.Spec.TranslateExecPlugins = false
+		p.Spec.Identity = nil
+		// Clients of one-off kubeconfigs are not worth caching.
+		cache = nil
+	}
+	cc, ok := cache.Get(key)
+	if !ok {
+		rc, err := c.restConfig(ctx, p, sa)
+		if err != nil {
+			return nil, err
+		}
+		m, err := clients.NewLazyRESTMapper(rc)
+		if err != nil {
+			return nil, errors.Wrap(err, errNewRESTMapper)
+		}
+		k, err := c.newKubeClientFn(rc, m)
+		if err != nil {
+			return nil, errors.Wrap(err, errNewKubernetesClient)
+		}
+		cc = newClusterClients(rc, k, m)
+		// The release records of impersonated ServiceAccounts are not
+		// cached, as they are unlikely to be allowed to watch all Secrets.
+		if c.storageCache && cache != nil && sa == nil {
+			if cc.storage, err = helmClient.NewStorageCache(rc); err != nil {
+				l.Info("Cannot cache release records, reading them from the API server", "error", err)
+			}
+		}
+		// Only cached clients live long enough to watch resources.
+		if c.driftEvents != nil && cache != nil {
+			cc.watches = newResourceWatches(rc, c.driftEvents)
+		}
+		cache.Add(key, cc)
+	}
+
+	wh, err := webhookConfig(ctx, c.client, cr.Spec.ForProvider.PostRender)
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToConfigurePostRender)
+	}
+
+	sinks, err := notificationSinks(ctx, c.client, p, cr)
+	if err != nil {
+		return nil, err
+	}
+
+	hl := c.logger.WithValues("release", meta.GetExternalName(cr), "namespace", cr.Spec.ForProvider.Namespace)
+	debug := withDebug(c.helmDebug || cr.GetAnnotations()[v1beta1.AnnotationKeyDebug] == "true")
+	params := withClassDefaults(cr, class)
+	h, err := c.newHelmClientFn(hl, cc.rc, withRelease(params), withPostRenderWebhook(wh), withClientGetter(cc), debug)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewKubernetesClient)
+	}
+
+	var e managed.ExternalClient = &helmExternal{
+		logger:           l,
+		recorder:         withNotifications(c.recorder, sinks, l),
+		localKube:        c.client,
+		class:            class,
+		chartPolicy:      c.chartPolicy,
+		policyServer:     c.policyServer,
+		plugins:          c.plugins,
+		providerConfig:   p.GetName(),
+		targetNamespaces: p.Spec.TargetNamespaces,
+		kube:             cc.kube,
+		helm:             h,
+		patch:            newPatcher(),
+		watches:          cc.watches,
+		newHelm: func(namespace string) (helmClient.Client, error) {
+			return c.newHelmClientFn(hl, cc.rc, withRelease(params), withNamespace(namespace), withClientGetter(cc), debug)
+		},
+	}
+	e = &tracedExternal{ExternalClient: e}
+	if c.stats != nil {
+		e = &statsRecorder{ExternalClient: e, providerConfig: n.Name, stats: c.stats}
+	}
+	return e, nil
+}
+
+// restConfig returns the REST config of the target cluster of the supplied
+// ProviderConfig, authenticated as configured. The supplied ServiceAccount,
+// if any, is impersonated rather than the user of the ProviderConfig.
+func (c *connector) restConfig(ctx context.Context, p *helmv1beta1.ProviderConfig, sa *types.NamespacedName) (*rest.Config, error) {
 	var rc *rest.Config
 	var err error
 
-	switch pc := p.Spec.Credentials; pc.Source { //nolint:exhaustive
-	case xpv1.CredentialsSourceInjectedIdentity:
+	switch pc := p.Spec.Credentials; {
+	case pc.Source == xpv1.CredentialsSourceInjectedIdentity:
 		rc, err = rest.InClusterConfig()
 		if err != nil {
 			return nil, errors.Wrap(err, errFailedToCreateRESTConfig)
 		}
+		if bt := p.Spec.BoundToken; bt != nil {
+			if err := c.boundTokenFn(rc, bt.Audiences, bt.ExpirationSeconds); err != nil {
+				return nil, errors.Wrap(err, errFailedToInjectBoundToken)
+			}
+		}
+	case pc.Source == xpv1.CredentialsSourceNone && p.Spec.Cluster != nil:
+		// The identity supplies all credentials, e.g. EKS tokens.
+		rc = &rest.Config{
+			Host:            p.Spec.Cluster.Server,
+			TLSClientConfig: rest.TLSClientConfig{CAData: p.Spec.Cluster.CertificateAuthorityData},
+		}
 	default:
-		kc, err := c.kcfgExtractorFn(ctx, pc.Source, c.client, pc.CommonCredentialSelectors)
+		src, sel, err := kubeconfigSelectors(pc)
+		if err != nil {
+			return nil, err
+		}
+		kc, err := c.kcfgExtractorFn(ctx, src, c.client, sel)
 		if err != nil {
 			return nil, errors.Wrap(err, errFailedToExtractKubeconfig)
 		}
 
-		rc, err = c.newRestConfigFn(kc)
+		rc, err = c.newRestConfigFn(kc, p.Spec.TranslateExecPlugins)
 		if err != nil {
 			return nil, errors.Wrap(err, errFailedToCreateRESTConfig)
 		}
 	}
 
-	// NOTE(negz): We don't currently check the identity type because at the
-	// time of writing there's only one valid value (Google App Creds), and
-	// that value is required.
-	if id := p.Spec.Identity; id != nil {
-		creds, err := c.gcpExtractorFn(ctx, id.Source, c.client, id.CommonCredentialSelectors)
-		if err != nil {
-			return nil, errors.Wrap(err, errFailedToExtractGoogleCredentials)
+	if err := configureConnection(rc, &c.connection, p.Spec.Connection); err != nil {
+		return nil, err
+	}
+
+	if err := c.injectIdentity(ctx, rc, p.Spec.Identity); err != nil {
+		return nil, err
+	}
+
+	switch i := p.Spec.Impersonate; {
+	case sa != nil && i != nil:
+		// The ServiceAccount would replace the user the ProviderConfig
+		// restricts its Releases to.
+		return nil, errors.Errorf(errServiceAccountImpersonated, sa.String(), p.GetName())
+	case sa != nil:
+		clients.ImpersonateServiceAccount(rc, sa.Namespace, sa.Name)
+	case i != nil:
+		clients.Impersonate(rc, i.Username, i.UID, i.Groups)
+	}
+
+	return rc, nil
+}
+
+// configureConnection applies the supplied connection settings to the
+// supplied REST config. Later settings take precedence.
+func configureConnection(rc *rest.Config, cs ...*helmv1beta1.Connection) error {
+	for _, cn := range cs {
+		if cn == nil {
+			continue
+		}
+		if cn.QPS != nil {
+			rc.QPS = float32(*cn.QPS)
+		}
+		if cn.Burst != nil {
+			rc.Burst = int(*cn.Burst)
+		}
+		if cn.Timeout != nil {
+			rc.Timeout = cn.Timeout.Duration
+		}
+		if cn.ProxyURL != "" {
+			u, err := url.Parse(cn.ProxyURL)
+			if err != nil {
+				return errors.Wrap(err, errInvalidProxyURL)
+			}
+			switch u.Scheme {
+			case "http", "https", "socks5":
+			default:
+				return errors.Errorf(errProxyScheme, u.Scheme)
+			}
+			rc.Proxy = http.ProxyURL(u)
+		}
+		if err := overrideTLS(rc, cn.TLS); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		if err := c.gcpInjectorFn(ctx, rc, creds, gke.DefaultScopes...); err != nil {
-			return nil, errors.Wrap(err, errFailedToInjectGoogleCredentials)
+// overrideTLS applies the supplied TLS overrides to the supplied REST config.
+func overrideTLS(rc *rest.Config, t *helmv1beta1.TLSOverrides) error {
+	if t == nil {
+		return nil
+	}
+	if t.ServerName != "" {
+		rc.ServerName = t.ServerName
+	}
+	if t.InsecureSkipVerify {
+		// Certificate authorities may not be specified for insecure
+		// connections.
+		rc.Insecure = true
+		rc.CAData = nil
+		rc.CAFile = ""
+		return nil
+	}
+	if len(t.CABundle) == 0 {
+		return nil
+	}
+	ca := rc.CAData
+	if len(ca) == 0 && rc.CAFile != "" {
+		b, err := ioutil.ReadFile(rc.CAFile)
+		if err != nil {
+			return errors.Wrap(err, errReadCAFile)
 		}
+		ca = b
 	}
+	rc.CAData = append(append(append([]byte{}, ca...), '\n'), t.CABundle...)
+	rc.CAFile = ""
+	return nil
+}
 
-	k, err := c.newKubeClientFn(rc)
-	if err != nil {
-		return nil, errors.Wrap(err, errNewKubernetesClient)
+// kubeconfigSelectors returns the source and selectors of the kubeconfig of
+// the supplied credentials. The ClusterAPI source selects the kubeconfig
+// Secret that Cluster API maintains for the referenced Cluster.
+func kubeconfigSelectors(pc helmv1beta1.ProviderCredentials) (xpv1.CredentialsSource, xpv1.CommonCredentialSelectors, error) {
+	if pc.Source != helmv1beta1.CredentialsSourceClusterAPI {
+		return pc.Source, pc.CommonCredentialSelectors, nil
 	}
+	ref := pc.ClusterAPIRef
+	if ref == nil {
+		return "", xpv1.CommonCredentialSelectors{}, errors.New(errClusterAPIRefNotSet)
+	}
+	return xpv1.CredentialsSourceSecret, xpv1.CommonCredentialSelectors{
+		SecretRef: &xpv1.SecretKeySelector{
+			SecretReference: xpv1.SecretReference{Name: ref.Name + clusterAPIKubeconfigSuffix, Namespace: ref.Namespace},
+			Key:             clusterAPIKubeconfigKey,
+		},
+	}, nil
+}
 
-	h, err := c.newHelmClientFn(c.logger, rc, withRelease(cr))
-	if err != nil {
-		return nil, errors.Wrap(err, errNewKubernetesClient)
+// injectIdentity configures the supplied REST config to authenticate using
+// the supplied identity, if any.
+func (c *connector) injectIdentity(ctx context.Context, rc *rest.Config, id *helmv1beta1.Identity) error {
+	if id == nil {
+		return nil
 	}
 
-	return &helmExternal{
-		logger:    l,
-		localKube: c.client,
-		kube:      k,
-		helm:      h,
-		patch:     newPatcher(),
-	}, nil
+	// Google Application Credentials are the default for backward
+	// compatibility; the identity type used to be ignored.
+	switch id.Type {
+	case helmv1beta1.IdentityTypeGoogleWorkloadIdentity:
+		return errors.Wrap(c.gcpWIInjectorFn(rc, gke.DefaultScopes...), errFailedToInjectWorkloadIdentity)
+	case helmv1beta1.IdentityTypeAWSWebIdentity:
+		if id.AWS == nil {
+			return errors.New(errAWSIdentityNotSet)
+		}
+		cfg := eks.Config{ClusterName: id.AWS.ClusterName, Region: id.AWS.Region, RoleARN: id.AWS.RoleARN}
+		return errors.Wrap(c.awsInjectorFn(rc, cfg), errFailedToInjectAWSIdentity)
+	case helmv1beta1.IdentityTypeAzureADIdentity:
+		cfg := aks.Config{}
+		if az := id.Azure; az != nil {
+			cfg = aks.Config{ClientID: az.ClientID, TenantID: az.TenantID, ServerID: az.ServerID}
+		}
+		return errors.Wrap(c.azInjectorFn(rc, cfg), errFailedToInjectAzureIdentity)
+	case helmv1beta1.IdentityTypeOIDCTokenExchange:
+		o := id.OIDC
+		if o == nil {
+			return errors.New(errOIDCIdentityNotSet)
+		}
+		cfg := oidc.Config{
+			TokenURL:           o.TokenURL,
+			Audience:           o.Audience,
+			Scopes:             o.Scopes,
+			ClientID:           o.ClientID,
+			RequestedTokenType: o.RequestedTokenType,
+			TokenFile:          o.TokenFile,
+		}
+		return errors.Wrap(c.oidcInjectorFn(rc, cfg), errFailedToInjectOIDCIdentity)
+	default:
+		creds, err := c.gcpExtractorFn(ctx, id.Source, c.client, id.CommonCredentialSelectors)
+		if err != nil {
+			return errors.Wrap(err, errFailedToExtractGoogleCredentials)
+		}
+		return errors.Wrap(c.gcpInjectorFn(ctx, rc, creds, gke.DefaultScopes...), errFailedToInjectGoogleCredentials)
+	}
 }
 
 type helmExternal struct {
 	logger    logging.Logger
+	recorder  event.Recorder
 	localKube client.Client
 	kube      client.Client
 	helm      helmClient.Client
 	patch     Patcher
+	// class is the ReleaseClass of the Release, if any.
+	class *v1beta1.ReleaseClass
+	// chartPolicy of the provider. All charts are allowed if nil.
+	chartPolicy *ChartPolicy
+	// policyServer of the provider. Manifests are not evaluated if nil.
+	policyServer *PolicyServer
+	// plugins are the value source plugins of the provider.
+	plugins valuePlugins
+	// providerConfig is the name of the ProviderConfig of the Release.
+	providerConfig string
+	// targetNamespaces of the ProviderConfig. Resources may be installed
+	// into all namespaces if nil.
+	targetNamespaces *helmv1beta1.TargetNamespaces
+	// newHelm returns a Helm client for releases in another namespace.
+	newHelm func(namespace string) (helmClient.Client, error)
+	// watches of the resources of the target cluster. Resources are not
+	// watched if nil.
+	watches *resourceWatches
+}
+
+// params returns the parameters of the supplied Release with the defaults of
+// its class applied.
+func (e *helmExternal) params(cr *v1beta1.Release) *v1beta1.ReleaseParameters {
+	return withClassDefaults(cr, e.class)
 }
 
 func (e *helmExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -230,13 +738,65 @@ func (e *helmExternal) Observe(ctx context.Context, mg resource.Managed) (manage
 	if !ok {
 		return managed.ExternalObservation{}, errors.New(errNotRelease)
 	}
+	o, err := e.observe(ctx, cr)
+	// Only a successful observation reflects the current generation; a
+	// tolerated failure reports the release as up to date without having
+	// observed it.
+	if err == nil {
+		cr.Status.ObservedGeneration = cr.GetGeneration()
+	}
+	return e.tolerate(cr, o, err)
+}
+
+func (e *helmExternal) observe(ctx context.Context, cr *v1beta1.Release) (managed.ExternalObservation, error) {
 
 	e.logger.Debug("Observing")
 
+	expired, err := e.expire(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	if expired {
+		// The release is uninstalled once the deletion is observed.
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+	}
+
+	if cr.Spec.ForProvider.RenderOnly {
+		return e.observeRenderOnly(ctx, cr)
+	}
+
+	if cr.Spec.Suspend {
+		cr.Status.SetConditions(v1beta1.Suspended())
+	} else if cr.Status.GetCondition(v1beta1.TypeSuspended).Status == corev1.ConditionTrue {
+		cr.Status.SetConditions(v1beta1.Resumed())
+	}
+
+	if !meta.WasDeleted(cr) && !cr.Spec.Suspend && managementAllows(cr, v1beta1.ManagementActionUpdate) {
+		if err := e.migrate(cr); err != nil {
+			return managed.ExternalObservation{}, err
+		}
+	}
+
+	hib, err := hibernating(cr.Spec.ForProvider.Hibernation, time.Now())
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	if cr.Spec.ForProvider.Hibernation != nil {
+		if hib {
+			cr.Status.SetConditions(v1beta1.Hibernating())
+		} else {
+			cr.Status.SetConditions(v1beta1.Awake())
+		}
+	}
+
 	rel, err := e.helm.GetLastRelease(meta.GetExternalName(cr))
 	if errors.Is(err, driver.ErrReleaseNotFound) {
+		cr.Status.Hibernated = hib
+		// Suspended and hibernated Releases are reported as existing to not
+		// install them.
 		return managed.ExternalObservation{
-			ResourceExists: false,
+			ResourceExists:   (cr.Spec.Suspend || hib) && !meta.WasDeleted(cr),
+			ResourceUpToDate: cr.Spec.Suspend || hib,
 		}, nil
 	}
 
@@ -248,7 +808,16 @@ func (e *helmExternal) Observe(ctx context.Context, mg resource.Managed) (manage
 		return managed.ExternalObservation{}, errors.New(errLastReleaseIsNil)
 	}
 
+	prev := cr.Status.AtProvider.History
 	cr.Status.AtProvider = generateObservation(rel)
+	cr.Status.ReleaseName = meta.GetExternalName(cr)
+	cr.Status.ReleaseNamespace = cr.Spec.ForProvider.Namespace
+	tested := cr.Status.GetCondition(v1beta1.TypeTested)
+	setReleaseConditions(cr, rel)
+	e.recordTests(cr, tested)
+	if cr.Status.AtProvider.History, err = e.history(cr, rel, prev); err != nil {
+		return managed.ExternalObservation{}, err
+	}
 
 	// Determining whether the release is up to date may involve reading values
 	// from secrets, configmaps, etc. This will fail if said dependencies have
@@ -256,77 +825,272 @@ func (e *helmExternal) Observe(ctx context.Context, mg resource.Managed) (manage
 	// order to delete the release, so if we know we're about to be deleted we
 	// return early to avoid blocking unnecessarily on missing dependencies.
 	if meta.WasDeleted(cr) {
+		limitStatus(cr)
 		return managed.ExternalObservation{ResourceExists: true}, nil
 	}
 
-	s, err := isUpToDate(ctx, e.localKube, &cr.Spec.ForProvider, rel, cr.Status)
-	if err != nil {
-		return managed.ExternalObservation{}, errors.Wrap(err, errFailedToCheckIfUpToDate)
+	if rel.Manifest != "" {
+		if cr.Status.AtProvider.Resources, err = inventory(e.kube.RESTMapper(), cr.Spec.ForProvider.Namespace, rel.Manifest); err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		if cr.Status.AtProvider.Images, err = images(rel.Manifest); err != nil {
+			return managed.ExternalObservation{}, err
+		}
+	}
+	limitStatus(cr)
+
+	if err := e.publishManifest(ctx, cr, rel.Manifest); err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	li := false
+	if cr.GetAnnotations()[v1beta1.AnnotationKeyAdopt] == "true" && managementAllows(cr, v1beta1.ManagementActionLateInitialize) {
+		li = lateInitialize(&cr.Spec.ForProvider, rel)
+	}
+
+	// Observe only Releases don't describe the desired state of the release.
+	s := true
+	if !cr.Spec.ObserveOnly {
+		if s, err = e.unchangedSinceSync(ctx, cr, rel); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errFailedToCheckIfUpToDate)
+		}
+	}
+	if !s && !cr.Spec.ObserveOnly {
+		if s, err = isUpToDate(ctx, e.localKube, e.plugins, &cr.Spec.ForProvider, rel, cr.Status); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errFailedToCheckIfUpToDate)
+		}
+		if s {
+			e.markSynced(ctx, cr, rel)
+		}
+	}
+	if s && rel.Info.Status == release.StatusDeployed {
+		correct, err := e.observeDrift(ctx, cr, rel.Manifest)
+		if err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		s = !correct
+	}
+	if hib {
+		// The release is uninstalled on update.
+		s = false
 	}
 	cr.Status.Synced = s
+	cr.Status.Hibernated = false
+	if s && len(cr.Spec.ForProvider.UpgradeWindows) > 0 {
+		cr.Status.PendingUpgrade = false
+		cr.Status.SetConditions(v1beta1.NoUpgradePending())
+	}
 	cd := managed.ConnectionDetails{}
 	if cr.Status.AtProvider.State == release.StatusDeployed && s {
 		cr.Status.Failed = 0
 
-		cd, err = connectionDetails(ctx, e.kube, cr.Spec.ConnectionDetails, rel.Name, rel.Namespace)
+		var missing []string
+		cd, missing, err = connectionDetails(ctx, e.kube, cr.Spec.ConnectionDetails, rel.Name, rel.Namespace, rel.Info.Notes)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, "cannot get connection details")
+		}
+		pending, err := connectionDetailsPending(cr, rel, missing, time.Now())
 		if err != nil {
 			return managed.ExternalObservation{}, errors.Wrap(err, "cannot get connection details")
 		}
-		cr.Status.SetConditions(xpv1.Available())
+		if !pending {
+			if err := renderConnectionDetails(cd, cr.Spec.ConnectionDetailTemplates); err != nil {
+				return managed.ExternalObservation{}, errors.Wrap(err, "cannot get connection details")
+			}
+		}
+
+		h, err := e.observeHealth(ctx, cr, rel.Manifest)
+		if err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		r, err := e.observeReadiness(ctx, cr)
+		if err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		c, err := e.observeCRDs(ctx, cr, rel)
+		if err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		if h && r && c && !pending {
+			cr.Status.SetConditions(xpv1.Available())
+		} else {
+			cr.Status.SetConditions(xpv1.Unavailable())
+		}
 	} else {
 		cr.Status.SetConditions(xpv1.Unavailable())
 	}
 
 	return managed.ExternalObservation{
-		ResourceExists:    true,
-		ResourceUpToDate:  cr.Status.Synced && !(shouldRollBack(cr) && !rollBackLimitReached(cr)),
-		ConnectionDetails: cd,
+		ResourceExists:          true,
+		ResourceUpToDate:        cr.Spec.Suspend || cr.Status.Synced && !(shouldRollBack(cr) && !rollBackLimitReached(cr)),
+		ResourceLateInitialized: li,
+		ConnectionDetails:       cd,
+	}, nil
+}
+
+// observeRenderOnly renders the chart without installing it and publishes
+// the rendered manifests as connection details.
+func (e *helmExternal) observeRenderOnly(ctx context.Context, cr *v1beta1.Release) (managed.ExternalObservation, error) {
+	// Nothing was installed, so there is nothing to delete either.
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	rel, _, err := e.render(ctx, cr, e.helm.Template)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errFailedToRender)
+	}
+
+	if err := e.publishManifest(ctx, cr, rel.Manifest); err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	cr.Status.Synced = true
+	cr.Status.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: true,
+		ConnectionDetails: managed.ConnectionDetails{
+			renderedManifestKey: []byte(rel.Manifest),
+		},
 	}, nil
 }
 
 type deployAction func(release string, chart *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (*release.Release, error)
 
-func (e *helmExternal) deploy(ctx context.Context, cr *v1beta1.Release, action deployAction) error {
-	cv, err := composeValuesFromSpec(ctx, e.localKube, cr.Spec.ForProvider.ValuesSpec)
+// renderedOnce returns a deployAction that renders the manifest of a single
+// install or upgrade using the supplied template action, and returns the
+// same result to every call. The checks that wrap an install or upgrade share
+// it, so that the chart is rendered and post-rendered only once before it is
+// deployed.
+func renderedOnce(template deployAction) deployAction {
+	var (
+		done bool
+		rel  *release.Release
+		err  error
+	)
+	return func(name string, ch *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (*release.Release, error) {
+		if !done {
+			rel, err = template(name, ch, vals, patches)
+			done = true
+		}
+		return rel, err
+	}
+}
+
+// render composes the values, patches and chart of the supplied Release and
+// passes them to the supplied action. It returns the resulting release and the
+// patches that were applied.
+func (e *helmExternal) render(ctx context.Context, cr *v1beta1.Release, action deployAction) (*release.Release, []ktype.Patch, error) {
+	_, span := startSpan(ctx, "ComposeValues", cr)
+	cv, err := composeValuesFromSpec(ctx, e.localKube, e.plugins, cr.Spec.ForProvider.ValuesSpec)
+	endSpan(span, err)
 	if err != nil {
-		return errors.Wrap(err, errFailedToComposeValues)
+		err = errors.Wrap(err, errFailedToComposeValues)
+		cr.Status.SetConditions(v1beta1.ValuesCompositionFailed(err))
+		return nil, nil, err
 	}
+	cr.Status.SetConditions(v1beta1.ValuesComposed())
 
 	creds, err := repoCredsFromSecret(ctx, e.localKube, cr.Spec.ForProvider.Chart.PullSecretRef)
 	if err != nil {
-		return errors.Wrap(err, errFailedToGetRepoCreds)
+		return nil, nil, errors.Wrap(err, errFailedToGetRepoCreds)
 	}
 
-	p, err := e.patch.getFromSpec(ctx, e.localKube, cr.Spec.ForProvider.PatchesFrom)
+	p, err := e.patch.getFromSpec(ctx, e.localKube, e.plugins, &cr.Spec.ForProvider)
 	if err != nil {
-		return errors.Wrap(err, errFailedToLoadPatches)
+		return nil, nil, errors.Wrap(err, errFailedToLoadPatches)
 	}
 
+	// Charts are checked before they are pulled so that charts of denied
+	// repositories are never pulled, and again once pulled to check the
+	// versions they resolved to.
+	if err := e.chartAllowed(cr, cr.Spec.ForProvider.Chart.Name, ""); err != nil {
+		return nil, nil, err
+	}
+	_, span = startSpan(ctx, "PullChart", cr)
 	chart, err := e.helm.PullAndLoadChart(&cr.Spec.ForProvider.Chart, creds)
+	if chart != nil && chart.Metadata != nil {
+		span.SetAttributes(attrChartName.String(chart.Metadata.Name), attrChartVersion.String(chart.Metadata.Version))
+	}
+	endSpan(span, err)
 	if err != nil {
-		return err
+		cr.Status.SetConditions(v1beta1.ChartResolutionFailed(err))
+		return nil, nil, err
+	}
+	if chart != nil && chart.Metadata != nil {
+		cr.Status.SetConditions(v1beta1.ChartResolved(chart.Metadata.Name, chart.Metadata.Version))
+		e.recorder.Event(cr, event.Normal(reasonChartResolved, fmt.Sprintf("Resolved chart %s version %s", chart.Metadata.Name, chart.Metadata.Version)))
+		if err := e.chartAllowed(cr, chart.Metadata.Name, chart.Metadata.Version); err != nil {
+			return nil, nil, err
+		}
 	}
-	if cr.Spec.ForProvider.Chart.Name == "" {
+	if r := cr.Spec.ForProvider.Chart.Repository; r != "" && chart != nil && chart.Metadata != nil {
+		// Recorded so that the repository can be late initialized when the
+		// release is adopted.
+		if chart.Metadata.Annotations == nil {
+			chart.Metadata.Annotations = map[string]string{}
+		}
+		chart.Metadata.Annotations[v1beta1.AnnotationKeyChartRepository] = r
+	}
+	li := managementAllows(cr, v1beta1.ManagementActionLateInitialize)
+	if li && cr.Spec.ForProvider.Chart.Name == "" {
 		cr.Spec.ForProvider.Chart.Name = chart.Metadata.Name
 		if err := e.localKube.Update(ctx, cr); err != nil {
-			return errors.Wrap(err, errFailedToSetName)
+			return nil, nil, errors.Wrap(err, errFailedToSetName)
 		}
 	}
-	if cr.Spec.ForProvider.Chart.Version == "" {
+	if li && cr.Spec.ForProvider.Chart.Version == "" {
 		cr.Spec.ForProvider.Chart.Version = chart.Metadata.Version
 		if err := e.localKube.Update(ctx, cr); err != nil {
-			return errors.Wrap(err, errFailedToSetVersion)
+			return nil, nil, errors.Wrap(err, errFailedToSetVersion)
 		}
 	}
 
 	rel, err := action(meta.GetExternalName(cr), chart, cv, p)
-
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	if rel == nil {
-		return errors.New(errLastReleaseIsNil)
+		return nil, nil, errors.New(errLastReleaseIsNil)
+	}
+
+	return rel, p, nil
+}
+
+// deploy runs the supplied install or upgrade action, wrapped in the checks
+// configured for the supplied Release. The checks get the manifest from the
+// supplied template action, which should be renderedOnce.
+func (e *helmExternal) deploy(ctx context.Context, cr *v1beta1.Release, template, action deployAction) error {
+	if err := classAllows(e.class, cr.Spec.ForProvider); err != nil {
+		return err
+	}
+	params := e.params(cr)
+	action = e.digested(cr, action)
+	if params.ConflictPolicy != "" {
+		action = e.conflictsResolved(ctx, cr, template, action)
+	}
+	if pointer.BoolDeref(params.ServerSideDryRun, false) {
+		action = e.validated(ctx, cr, template, action)
+	}
+	if pointer.BoolDeref(params.RBACPreflight, false) {
+		action = e.permitted(ctx, cr, template, action)
+	}
+	if e.targetNamespaces != nil {
+		action = e.namespacesChecked(cr, template, action)
+	}
+	if e.policyServer != nil {
+		action = e.policyChecked(ctx, cr, template, action)
+	}
+	if pointer.BoolDeref(params.Lint, false) {
+		action = e.linted(cr, action)
+	}
+
+	rel, p, err := e.render(ctx, cr, action)
+	if err != nil {
+		return err
 	}
 
 	sha, err := e.patch.shaOf(p)
@@ -335,6 +1099,9 @@ func (e *helmExternal) deploy(ctx context.Context, cr *v1beta1.Release, action d
 	}
 	cr.Status.PatchesSha = sha
 	cr.Status.AtProvider = generateObservation(rel)
+	cr.Status.ReleaseName = meta.GetExternalName(cr)
+	cr.Status.ReleaseNamespace = cr.Spec.ForProvider.Namespace
+	setReleaseConditions(cr, rel)
 
 	return nil
 }
@@ -347,15 +1114,29 @@ func (e *helmExternal) Create(ctx context.Context, mg resource.Managed) (managed
 
 	e.logger.Debug("Creating")
 
-	if !cr.Spec.ForProvider.SkipCreateNamespace {
-		if err := e.createNamespace(ctx, cr.Spec.ForProvider.Namespace); err != nil {
+	if !managementAllows(cr, v1beta1.ManagementActionCreate) {
+		return managed.ExternalCreation{}, errors.New(errCreateNotAllowed)
+	}
+
+	if err := e.dependenciesReady(ctx, cr); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	if params := e.params(cr); !pointer.BoolDeref(params.SkipCreateNamespace, false) {
+		if err := e.createNamespace(ctx, params.Namespace, meta.GetExternalName(cr), params.NamespaceMetadata); err != nil {
 			if !kerrors.IsAlreadyExists(err) {
 				return managed.ExternalCreation{}, errors.Wrap(err, errFailedToCreateNamespace)
 			}
 		}
 	}
 
-	return managed.ExternalCreation{}, errors.Wrap(e.deploy(ctx, cr, e.helm.Install), errFailedToInstall)
+	e.recorder.Event(cr, event.Normal(reasonInstalling, "Installing release "+meta.GetExternalName(cr)))
+	if err := e.deploy(ctx, cr, renderedOnce(e.helm.Template), released(cr, v1beta1.InstallFailed, e.attempted(cr, v1beta1.AuditOperationInstall, traced(ctx, cr, "Install", e.helm.Install)))); err != nil {
+		e.recorder.Event(cr, event.Warning(reasonInstallFailed, err))
+		return managed.ExternalCreation{}, errors.Wrap(err, errFailedToInstall)
+	}
+	e.recorder.Event(cr, event.Normal(reasonInstalled, fmt.Sprintf("Installed revision %d", cr.Status.AtProvider.Revision)))
+	return managed.ExternalCreation{}, nil
 }
 
 func (e *helmExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
@@ -364,6 +1145,27 @@ func (e *helmExternal) Update(ctx context.Context, mg resource.Managed) (managed
 		return managed.ExternalUpdate{}, errors.New(errNotRelease)
 	}
 
+	if !managementAllows(cr, v1beta1.ManagementActionUpdate) {
+		e.logger.Debug("Management policies do not allow updating, skipping update")
+		return managed.ExternalUpdate{}, nil
+	}
+
+	hib, err := hibernating(cr.Spec.ForProvider.Hibernation, time.Now())
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	if hib {
+		e.logger.Debug("Hibernating")
+		err := e.helm.Uninstall(meta.GetExternalName(cr))
+		auditCurrent(cr, v1beta1.AuditOperationUninstall, err)
+		if err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errFailedToHibernate)
+		}
+		cr.Status.Hibernated = true
+		e.recorder.Event(cr, event.Normal(reasonHibernated, "Uninstalled release until it wakes up"))
+		return managed.ExternalUpdate{}, nil
+	}
+
 	if shouldRollBack(cr) {
 		e.logger.Debug("Last release failed")
 		if !rollBackLimitReached(cr) {
@@ -374,20 +1176,62 @@ func (e *helmExternal) Update(ctx context.Context, mg resource.Managed) (managed
 			// We need to uninstall to retry.
 			if cr.Status.AtProvider.Revision == 1 {
 				e.logger.Debug("Uninstalling")
-				return managed.ExternalUpdate{}, e.helm.Uninstall(meta.GetExternalName(cr))
+				err := e.helm.Uninstall(meta.GetExternalName(cr))
+				e.recordUninstall(cr, err, "Uninstalled failed release to retry the install")
+				return managed.ExternalUpdate{}, err
 			}
 			e.logger.Debug("Rolling back to previous release version")
-			return managed.ExternalUpdate{}, e.helm.Rollback(meta.GetExternalName(cr))
+			err := e.helm.Rollback(meta.GetExternalName(cr))
+			auditCurrent(cr, v1beta1.AuditOperationRollback, err)
+			if err != nil {
+				e.recorder.Event(cr, event.Warning(reasonRollbackFailed, err))
+				cr.Status.SetConditions(v1beta1.RollbackFailed(err))
+				return managed.ExternalUpdate{}, err
+			}
+			e.recorder.Event(cr, event.Normal(reasonRolledBack, fmt.Sprintf("Rolled back failed revision %d to the previous revision", cr.Status.AtProvider.Revision)))
+			return managed.ExternalUpdate{}, nil
 		}
 		e.logger.Debug("Reached max rollback retries, will not retry")
 		return managed.ExternalUpdate{}, nil
 	}
 
 	e.logger.Debug("Updating")
-	return managed.ExternalUpdate{}, errors.Wrap(e.deploy(ctx, cr, e.helm.Upgrade), errFailedToUpgrade)
+	open, next, err := upgradeWindowOpen(cr.Spec.ForProvider.UpgradeWindows, time.Now())
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	if !open {
+		e.logger.Debug("Deferring upgrade until the next upgrade window")
+		cr.Status.PendingUpgrade = true
+		cr.Status.SetConditions(v1beta1.UpgradeDeferred(next))
+		return managed.ExternalUpdate{}, nil
+	}
+	if err := e.dependenciesReady(ctx, cr); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	template := renderedOnce(e.helm.Template)
+	action := e.diffed(cr, template, released(cr, v1beta1.UpgradeFailed, e.attempted(cr, v1beta1.AuditOperationUpgrade, traced(ctx, cr, "Upgrade", e.helm.Upgrade))))
+	if us := cr.Spec.ForProvider.UpgradeStrategy; us != nil && us.Type == v1beta1.UpgradeStrategyCanary {
+		action = e.canaried(ctx, cr, action)
+	}
+	if cr.Spec.ForProvider.RequireApproval {
+		action = e.approved(cr, action)
+	}
+	e.recorder.Event(cr, event.Normal(reasonUpgrading, fmt.Sprintf("Upgrading revision %d", cr.Status.AtProvider.Revision)))
+	err = e.deploy(ctx, cr, template, action)
+	if errors.Is(err, errCanaryInProgress) {
+		e.logger.Debug("Waiting for canary release to be verified")
+		return managed.ExternalUpdate{}, nil
+	}
+	if err != nil {
+		e.recorder.Event(cr, event.Warning(reasonUpgradeFailed, err))
+		return managed.ExternalUpdate{}, errors.Wrap(err, errFailedToUpgrade)
+	}
+	e.recorder.Event(cr, event.Normal(reasonUpgraded, fmt.Sprintf("Upgraded to revision %d", cr.Status.AtProvider.Revision)))
+	return managed.ExternalUpdate{}, nil
 }
 
-func (e *helmExternal) Delete(_ context.Context, mg resource.Managed) error {
+func (e *helmExternal) Delete(ctx context.Context, mg resource.Managed) error {
 	cr, ok := mg.(*v1beta1.Release)
 	if !ok {
 		return errors.New(errNotRelease)
@@ -395,7 +1239,78 @@ func (e *helmExternal) Delete(_ context.Context, mg resource.Managed) error {
 
 	e.logger.Debug("Deleting")
 
-	return errors.Wrap(e.helm.Uninstall(meta.GetExternalName(cr)), errFailedToUninstall)
+	if e.watches != nil {
+		e.watches.forget(cr.GetName())
+	}
+
+	if cr.Spec.Suspend {
+		cr.Status.SetConditions(v1beta1.Suspended())
+		return errors.New(errSuspended)
+	}
+
+	if !managementAllows(cr, v1beta1.ManagementActionDelete) {
+		e.logger.Debug("Management policies do not allow deleting, leaving release in place")
+		return nil
+	}
+
+	if cr.Spec.ForProvider.DeletionProtection {
+		cr.Status.SetConditions(v1beta1.DeletionBlocked())
+		return errors.New(errDeletionProtected)
+	}
+
+	if cr.Spec.ForProvider.UninstallPolicy == v1beta1.UninstallPolicyKeepResources {
+		if err := e.helm.Forget(meta.GetExternalName(cr)); err != nil {
+			return errors.Wrap(err, errFailedToForget)
+		}
+		// The canary of an upgrade in progress was never promoted, so its
+		// resources are not kept.
+		if err := e.uninstallCanary(cr); err != nil {
+			return err
+		}
+		return e.unpublishManifest(ctx, cr)
+	}
+
+	rel, err := e.helm.GetLastRelease(meta.GetExternalName(cr))
+	if err != nil && !errors.Is(err, driver.ErrReleaseNotFound) {
+		return errors.Wrap(err, errFailedToGetLastRelease)
+	}
+
+	_, span := startSpan(ctx, "Uninstall", cr)
+	err = e.helm.Uninstall(meta.GetExternalName(cr))
+	endSpan(span, err)
+	e.recordUninstall(cr, err, "Uninstalled release")
+	if err != nil {
+		return errors.Wrap(err, errFailedToUninstall)
+	}
+	if err := e.uninstallCanary(cr); err != nil {
+		return err
+	}
+	if err := e.unpublishManifest(ctx, cr); err != nil {
+		return err
+	}
+
+	if rel != nil {
+		e.recordKeptResources(cr, rel.Manifest)
+	}
+	if cr.Spec.ForProvider.DeleteNamespaceOnUninstall {
+		e.deleteNamespace(ctx, cr)
+	}
+	return nil
+}
+
+// recordKeptResources reports the resources of the uninstalled release that
+// Helm left in place because of their resource policy.
+func (e *helmExternal) recordKeptResources(cr *v1beta1.Release, manifest string) {
+	kept, err := keptResources(manifest)
+	if err != nil {
+		// The release is uninstalled already, so we only log the failure.
+		e.logger.Debug("Cannot determine kept resources", "error", err)
+		return
+	}
+	cr.Status.AtProvider.KeptResources = kept
+	if len(kept) > 0 {
+		e.recorder.Event(cr, event.Normal(reasonResourcesKept, "Resources kept due to the helm.sh/resource-policy annotation: "+strings.Join(kept, ", ")))
+	}
 }
 
 func shouldRollBack(cr *v1beta1.Release) bool {
@@ -412,21 +1327,30 @@ func rollBackLimitReached(cr *v1beta1.Release) bool {
 	return cr.Status.Failed >= *cr.Spec.RollbackRetriesLimit
 }
 
-func (e *helmExternal) createNamespace(ctx context.Context, name string) error {
+func (e *helmExternal) createNamespace(ctx context.Context, name, rel string, md *v1beta1.NamespaceMetadata) error {
 	ns := &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: name,
-			Labels: map[string]string{
-				helmNamespaceLabel: helmProviderName,
-			},
+			Name:        name,
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
 		},
 	}
+	if md != nil {
+		for k, v := range md.Labels {
+			ns.Labels[k] = v
+		}
+		for k, v := range md.Annotations {
+			ns.Annotations[k] = v
+		}
+	}
+	ns.Labels[helmNamespaceLabel] = helmProviderName
+	ns.Annotations[namespaceReleaseAnnotation] = rel
 	return e.kube.Create(ctx, ns)
 }
 
-func waitTimeout(cr *v1beta1.Release) time.Duration {
-	if cr.Spec.ForProvider.WaitTimeout != nil {
-		return cr.Spec.ForProvider.WaitTimeout.Duration
+func waitTimeout(p *v1beta1.ReleaseParameters) time.Duration {
+	if p.WaitTimeout != nil {
+		return p.WaitTimeout.Duration
 	}
 	return defaultWaitTimeout
 }
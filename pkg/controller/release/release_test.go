@@ -3,6 +3,7 @@ package release
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
@@ -11,20 +12,28 @@ import (
 	"helm.sh/helm/v3/pkg/storage/driver"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/kustomize/api/types"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
 	helmv1beta1 "github.com/crossplane-contrib/provider-helm/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-helm/pkg/clients/aks"
+	"github.com/crossplane-contrib/provider-helm/pkg/clients/eks"
 	helmClient "github.com/crossplane-contrib/provider-helm/pkg/clients/helm"
+	"github.com/crossplane-contrib/provider-helm/pkg/clients/oidc"
 )
 
 const (
@@ -66,21 +75,32 @@ func helmRelease(rm ...helmReleaseModifier) *v1beta1.Release {
 type MockGetLastReleaseFn func(release string) (*release.Release, error)
 type MockInstallFn func(release string, chart *chart.Chart, vals map[string]interface{}, patches []types.Patch) (*release.Release, error)
 type MockUpgradeFn func(release string, chart *chart.Chart, vals map[string]interface{}, patches []types.Patch) (*release.Release, error)
+type MockTemplateFn func(release string, chart *chart.Chart, vals map[string]interface{}, patches []types.Patch) (*release.Release, error)
+type MockLintFn func(chart *chart.Chart, vals map[string]interface{}) (*helmClient.LintResult, error)
 type MockRollBackFn func(release string) error
 type MockUninstallFn func(release string) error
+type MockForgetFn func(release string) error
+type MockHistoryFn func(release string) ([]*release.Release, error)
 type MockPullAndLoadChartFn func(spec *v1beta1.ChartSpec, creds *helmClient.RepoCreds) (*chart.Chart, error)
 
 type MockHelmClient struct {
 	MockGetLastRelease   MockGetLastReleaseFn
 	MockInstall          MockInstallFn
 	MockUpgrade          MockUpgradeFn
+	MockTemplate         MockTemplateFn
+	MockLint             MockLintFn
 	MockRollBack         MockRollBackFn
 	MockUninstall        MockUninstallFn
+	MockForget           MockForgetFn
+	MockHistory          MockHistoryFn
 	MockPullAndLoadChart MockPullAndLoadChartFn
 }
 
 func (c *MockHelmClient) GetLastRelease(release string) (*release.Release, error) {
-	return c.MockGetLastRelease(release)
+	if c.MockGetLastRelease != nil {
+		return c.MockGetLastRelease(release)
+	}
+	return nil, nil
 }
 
 func (c *MockHelmClient) Install(release string, chart *chart.Chart, vals map[string]interface{}, patches []types.Patch) (*release.Release, error) {
@@ -91,6 +111,17 @@ func (c *MockHelmClient) Upgrade(release string, chart *chart.Chart, vals map[st
 	return c.MockUpgrade(release, chart, vals, patches)
 }
 
+func (c *MockHelmClient) Template(release string, chart *chart.Chart, vals map[string]interface{}, patches []types.Patch) (*release.Release, error) {
+	if c.MockTemplate != nil {
+		return c.MockTemplate(release, chart, vals, patches)
+	}
+	return nil, nil
+}
+
+func (c *MockHelmClient) Lint(chart *chart.Chart, vals map[string]interface{}) (*helmClient.LintResult, error) {
+	return c.MockLint(chart, vals)
+}
+
 func (c *MockHelmClient) Rollback(release string) error {
 	return c.MockRollBack(release)
 }
@@ -99,6 +130,17 @@ func (c *MockHelmClient) Uninstall(release string) error {
 	return c.MockUninstall(release)
 }
 
+func (c *MockHelmClient) Forget(release string) error {
+	return c.MockForget(release)
+}
+
+func (c *MockHelmClient) History(release string) ([]*release.Release, error) {
+	if c.MockHistory != nil {
+		return c.MockHistory(release)
+	}
+	return nil, nil
+}
+
 func (c *MockHelmClient) PullAndLoadChart(spec *v1beta1.ChartSpec, creds *helmClient.RepoCreds) (*chart.Chart, error) {
 	if c.MockPullAndLoadChart != nil {
 		return c.MockPullAndLoadChart(spec, creds)
@@ -126,13 +168,54 @@ func Test_connector_Connect(t *testing.T) {
 		},
 	}
 
+	gkeProviderConfig := helmv1beta1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: providerName},
+		Spec: helmv1beta1.ProviderConfigSpec{
+			Credentials: helmv1beta1.ProviderCredentials{
+				Source: xpv1.CredentialsSourceNone,
+			},
+			Cluster: &helmv1beta1.Cluster{
+				Server: "https://gke.example.org",
+			},
+			Identity: &helmv1beta1.Identity{
+				Type: helmv1beta1.IdentityTypeGoogleWorkloadIdentity,
+				ProviderCredentials: helmv1beta1.ProviderCredentials{
+					Source: xpv1.CredentialsSourceInjectedIdentity,
+				},
+			},
+		},
+	}
+
+	awsProviderConfig := helmv1beta1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: providerName},
+		Spec: helmv1beta1.ProviderConfigSpec{
+			Credentials: helmv1beta1.ProviderCredentials{
+				Source: xpv1.CredentialsSourceNone,
+			},
+			Cluster: &helmv1beta1.Cluster{
+				Server: "https://eks.example.org",
+			},
+			Identity: &helmv1beta1.Identity{
+				Type: helmv1beta1.IdentityTypeAWSWebIdentity,
+				ProviderCredentials: helmv1beta1.ProviderCredentials{
+					Source: xpv1.CredentialsSourceInjectedIdentity,
+				},
+				AWS: &helmv1beta1.AWSIdentity{ClusterName: "eks"},
+			},
+		},
+	}
+
 	type args struct {
 		client          client.Client
 		kcfgExtractorFn func(ctx context.Context, src xpv1.CredentialsSource, c client.Client, ccs xpv1.CommonCredentialSelectors) ([]byte, error)
 		gcpExtractorFn  func(ctx context.Context, src xpv1.CredentialsSource, c client.Client, ccs xpv1.CommonCredentialSelectors) ([]byte, error)
 		gcpInjectorFn   func(ctx context.Context, rc *rest.Config, credentials []byte, scopes ...string) error
-		newRestConfigFn func(kubeconfig []byte) (*rest.Config, error)
-		newKubeClientFn func(config *rest.Config) (client.Client, error)
+		gcpWIInjectorFn func(rc *rest.Config, scopes ...string) error
+		awsInjectorFn   func(rc *rest.Config, cfg eks.Config) error
+		azInjectorFn    func(rc *rest.Config, cfg aks.Config) error
+		oidcInjectorFn  func(rc *rest.Config, cfg oidc.Config) error
+		newRestConfigFn func(kubeconfig []byte, translateExec bool) (*rest.Config, error)
+		newKubeClientFn func(config *rest.Config, mapper apimeta.RESTMapper) (client.Client, error)
 		newHelmClientFn func(log logging.Logger, config *rest.Config, helmArgs ...helmClient.ArgsApplier) (helmClient.Client, error)
 		usage           resource.Tracker
 		mg              resource.Managed
@@ -179,6 +262,27 @@ func Test_connector_Connect(t *testing.T) {
 				err: errors.Wrap(errBoom, errProviderNotRetrieved),
 			},
 		},
+		"TargetNamespaceNotAllowed": {
+			args: args{
+				client: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						if key.Name == providerName {
+							pc := providerConfig.DeepCopy()
+							pc.Spec.TargetNamespaces = &helmv1beta1.TargetNamespaces{Allowed: []string{"team-a-*"}}
+							*obj.(*helmv1beta1.ProviderConfig) = *pc
+						}
+						return nil
+					},
+				},
+				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
+				mg: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.Namespace = "kube-system"
+				}),
+			},
+			want: want{
+				err: errors.Errorf(errFmtTargetNamespaceNotAllowed, "kube-system", providerName),
+			},
+		},
 		"FailedToExtractKubeconfig": {
 			args: args{
 				client: &test.MockClient{
@@ -200,6 +304,36 @@ func Test_connector_Connect(t *testing.T) {
 				err: errors.Wrap(errBoom, errFailedToExtractKubeconfig),
 			},
 		},
+		"ClusterAPIKubeconfigSecret": {
+			args: args{
+				client: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						if key.Name == providerName {
+							pc := providerConfig
+							pc.Spec.Credentials = helmv1beta1.ProviderCredentials{
+								Source:        helmv1beta1.CredentialsSourceClusterAPI,
+								ClusterAPIRef: &helmv1beta1.ClusterAPIReference{Name: "workload", Namespace: "fleet"},
+							}
+							*obj.(*helmv1beta1.ProviderConfig) = pc
+							return nil
+						}
+						return errBoom
+					},
+				},
+				kcfgExtractorFn: func(ctx context.Context, src xpv1.CredentialsSource, c client.Client, ccs xpv1.CommonCredentialSelectors) ([]byte, error) {
+					want := xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Name: "workload-kubeconfig", Namespace: "fleet"}, Key: "value"}
+					if src != xpv1.CredentialsSourceSecret || ccs.SecretRef == nil || *ccs.SecretRef != want {
+						return nil, errors.New("unexpected Cluster API kubeconfig selector")
+					}
+					return nil, errBoom
+				},
+				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
+				mg:    helmRelease(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFailedToExtractKubeconfig),
+			},
+		},
 		"FailedToCreateRestConfig": {
 			args: args{
 				client: &test.MockClient{
@@ -214,7 +348,7 @@ func Test_connector_Connect(t *testing.T) {
 				kcfgExtractorFn: func(ctx context.Context, src xpv1.CredentialsSource, c client.Client, ccs xpv1.CommonCredentialSelectors) ([]byte, error) {
 					return nil, nil
 				},
-				newRestConfigFn: func(kubeconfig []byte) (config *rest.Config, err error) {
+				newRestConfigFn: func(kubeconfig []byte, _ bool) (config *rest.Config, err error) {
 					return nil, errBoom
 				},
 				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
@@ -238,7 +372,7 @@ func Test_connector_Connect(t *testing.T) {
 				kcfgExtractorFn: func(ctx context.Context, src xpv1.CredentialsSource, c client.Client, ccs xpv1.CommonCredentialSelectors) ([]byte, error) {
 					return nil, nil
 				},
-				newRestConfigFn: func(kubeconfig []byte) (config *rest.Config, err error) {
+				newRestConfigFn: func(kubeconfig []byte, _ bool) (config *rest.Config, err error) {
 					return nil, nil
 				},
 				gcpExtractorFn: func(ctx context.Context, src xpv1.CredentialsSource, c client.Client, ccs xpv1.CommonCredentialSelectors) ([]byte, error) {
@@ -265,7 +399,7 @@ func Test_connector_Connect(t *testing.T) {
 				kcfgExtractorFn: func(ctx context.Context, src xpv1.CredentialsSource, c client.Client, ccs xpv1.CommonCredentialSelectors) ([]byte, error) {
 					return nil, nil
 				},
-				newRestConfigFn: func(kubeconfig []byte) (config *rest.Config, err error) {
+				newRestConfigFn: func(kubeconfig []byte, _ bool) (config *rest.Config, err error) {
 					return nil, nil
 				},
 				gcpExtractorFn: func(ctx context.Context, src xpv1.CredentialsSource, c client.Client, ccs xpv1.CommonCredentialSelectors) ([]byte, error) {
@@ -281,6 +415,109 @@ func Test_connector_Connect(t *testing.T) {
 				err: errors.Wrap(errBoom, errFailedToInjectGoogleCredentials),
 			},
 		},
+		"FailedToInjectWorkloadIdentity": {
+			args: args{
+				client: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						if key.Name == providerName {
+							*obj.(*helmv1beta1.ProviderConfig) = gkeProviderConfig
+							return nil
+						}
+						return errBoom
+					},
+				},
+				gcpWIInjectorFn: func(rc *rest.Config, scopes ...string) error {
+					return errBoom
+				},
+				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
+				mg:    helmRelease(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFailedToInjectWorkloadIdentity),
+			},
+		},
+		"FailedToInjectAWSIdentity": {
+			args: args{
+				client: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						if key.Name == providerName {
+							*obj.(*helmv1beta1.ProviderConfig) = awsProviderConfig
+							return nil
+						}
+						return errBoom
+					},
+				},
+				awsInjectorFn: func(rc *rest.Config, cfg eks.Config) error {
+					if rc.Host != awsProviderConfig.Spec.Cluster.Server || cfg.ClusterName != awsProviderConfig.Spec.Identity.AWS.ClusterName {
+						return errors.New("unexpected AWS identity configuration")
+					}
+					return errBoom
+				},
+				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
+				mg:    helmRelease(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFailedToInjectAWSIdentity),
+			},
+		},
+		"FailedToInjectAzureIdentity": {
+			args: args{
+				client: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						if key.Name == providerName {
+							pc := awsProviderConfig
+							pc.Spec.Identity = &helmv1beta1.Identity{
+								Type:  helmv1beta1.IdentityTypeAzureADIdentity,
+								Azure: &helmv1beta1.AzureIdentity{ClientID: "client"},
+							}
+							*obj.(*helmv1beta1.ProviderConfig) = pc
+							return nil
+						}
+						return errBoom
+					},
+				},
+				azInjectorFn: func(rc *rest.Config, cfg aks.Config) error {
+					if cfg.ClientID != "client" {
+						return errors.New("unexpected Azure identity configuration")
+					}
+					return errBoom
+				},
+				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
+				mg:    helmRelease(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFailedToInjectAzureIdentity),
+			},
+		},
+		"FailedToInjectOIDCIdentity": {
+			args: args{
+				client: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						if key.Name == providerName {
+							pc := awsProviderConfig
+							pc.Spec.Identity = &helmv1beta1.Identity{
+								Type: helmv1beta1.IdentityTypeOIDCTokenExchange,
+								OIDC: &helmv1beta1.OIDCIdentity{TokenURL: "https://sts.example.org/token", Audience: "kubernetes"},
+							}
+							*obj.(*helmv1beta1.ProviderConfig) = pc
+							return nil
+						}
+						return errBoom
+					},
+				},
+				oidcInjectorFn: func(rc *rest.Config, cfg oidc.Config) error {
+					if cfg.TokenURL != "https://sts.example.org/token" || cfg.Audience != "kubernetes" {
+						return errors.New("unexpected OIDC identity configuration")
+					}
+					return errBoom
+				},
+				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
+				mg:    helmRelease(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFailedToInjectOIDCIdentity),
+			},
+		},
 		"FailedToCreateNewKubernetesClient": {
 			args: args{
 				client: &test.MockClient{
@@ -304,10 +541,10 @@ func Test_connector_Connect(t *testing.T) {
 				gcpInjectorFn: func(ctx context.Context, rc *rest.Config, credentials []byte, scopes ...string) error {
 					return nil
 				},
-				newRestConfigFn: func(kubeconfig []byte) (config *rest.Config, err error) {
+				newRestConfigFn: func(kubeconfig []byte, _ bool) (config *rest.Config, err error) {
 					return &rest.Config{}, nil
 				},
-				newKubeClientFn: func(config *rest.Config) (c client.Client, err error) {
+				newKubeClientFn: func(config *rest.Config, mapper apimeta.RESTMapper) (c client.Client, err error) {
 					return nil, errBoom
 				},
 				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
@@ -340,10 +577,10 @@ func Test_connector_Connect(t *testing.T) {
 				gcpInjectorFn: func(ctx context.Context, rc *rest.Config, credentials []byte, scopes ...string) error {
 					return nil
 				},
-				newRestConfigFn: func(kubeconfig []byte) (config *rest.Config, err error) {
+				newRestConfigFn: func(kubeconfig []byte, _ bool) (config *rest.Config, err error) {
 					return &rest.Config{}, nil
 				},
-				newKubeClientFn: func(config *rest.Config) (c client.Client, err error) {
+				newKubeClientFn: func(config *rest.Config, mapper apimeta.RESTMapper) (c client.Client, err error) {
 					return nil, nil
 				},
 				newHelmClientFn: func(log logging.Logger, restConfig *rest.Config, helmArgs ...helmClient.ArgsApplier) (helmClient.Client, error) {
@@ -356,6 +593,83 @@ func Test_connector_Connect(t *testing.T) {
 				err: errors.Wrap(errBoom, errNewKubernetesClient),
 			},
 		},
+		"ReleaseKubeconfigNotAllowed": {
+			args: args{
+				client: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						switch t := obj.(type) {
+						case *helmv1beta1.ProviderConfig:
+							*t = providerConfig
+						default:
+							return errBoom
+						}
+						return nil
+					},
+				},
+				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
+				mg: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.KubeConfigSecretRef = &xpv1.SecretKeySelector{
+						SecretReference: xpv1.SecretReference{Name: "one-off", Namespace: testNamespace},
+						Key:             "kubeconfig",
+					}
+				}),
+			},
+			want: want{
+				err: errors.Errorf(errFmtReleaseKubeconfigNotAllowed, providerName),
+			},
+		},
+		"ReleaseKubeconfig": {
+			args: args{
+				client: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						switch t := obj.(type) {
+						case *helmv1beta1.ProviderConfig:
+							*t = providerConfig
+							t.Spec.AllowReleaseKubeconfigs = true
+							t.Spec.TranslateExecPlugins = true
+							t.Spec.Identity = &helmv1beta1.Identity{Type: helmv1beta1.IdentityTypeGoogleApplicationCredentials}
+						default:
+							return errBoom
+						}
+						return nil
+					},
+				},
+				kcfgExtractorFn: func(ctx context.Context, src xpv1.CredentialsSource, c client.Client, ccs xpv1.CommonCredentialSelectors) ([]byte, error) {
+					if src != xpv1.CredentialsSourceSecret || ccs.SecretRef == nil || ccs.SecretRef.Name != "one-off" {
+						return nil, errBoom
+					}
+					return nil, nil
+				},
+				gcpExtractorFn: func(ctx context.Context, src xpv1.CredentialsSource, c client.Client, ccs xpv1.CommonCredentialSelectors) ([]byte, error) {
+					return nil, nil
+				},
+				gcpInjectorFn: func(ctx context.Context, rc *rest.Config, credentials []byte, scopes ...string) error {
+					return errBoom
+				},
+				newRestConfigFn: func(kubeconfig []byte, translateExec bool) (config *rest.Config, err error) {
+					if translateExec {
+						return nil, errBoom
+					}
+					return &rest.Config{}, nil
+				},
+				newKubeClientFn: func(config *rest.Config, mapper apimeta.RESTMapper) (c client.Client, err error) {
+					return &test.MockClient{}, nil
+				},
+				newHelmClientFn: func(log logging.Logger, restConfig *rest.Config, helmArgs ...helmClient.ArgsApplier) (h helmClient.Client, err error) {
+					return &MockHelmClient{}, nil
+				},
+				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
+				mg: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.KubeConfigSecretRef = &xpv1.SecretKeySelector{
+						SecretReference: xpv1.SecretReference{Name: "one-off", Namespace: testNamespace},
+						Key:             "kubeconfig",
+					}
+				}),
+			},
+			want: want{
+				err: nil,
+			},
+		},
 		"Success": {
 			args: args{
 				client: &test.MockClient{
@@ -381,10 +695,10 @@ func Test_connector_Connect(t *testing.T) {
 				gcpInjectorFn: func(ctx context.Context, rc *rest.Config, credentials []byte, scopes ...string) error {
 					return nil
 				},
-				newRestConfigFn: func(kubeconfig []byte) (config *rest.Config, err error) {
+				newRestConfigFn: func(kubeconfig []byte, _ bool) (config *rest.Config, err error) {
 					return &rest.Config{}, nil
 				},
-				newKubeClientFn: func(config *rest.Config) (c client.Client, err error) {
+				newKubeClientFn: func(config *rest.Config, mapper apimeta.RESTMapper) (c client.Client, err error) {
 					return &test.MockClient{}, nil
 				},
 				newHelmClientFn: func(log logging.Logger, restConfig *rest.Config, helmArgs ...helmClient.ArgsApplier) (h helmClient.Client, err error) {
@@ -397,6 +711,56 @@ func Test_connector_Connect(t *testing.T) {
 				err: nil,
 			},
 		},
+		"DebugAnnotation": {
+			args: args{
+				client: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						switch t := obj.(type) {
+						case *helmv1beta1.ProviderConfig:
+							*t = providerConfig
+						default:
+							return errBoom
+						}
+						return nil
+					},
+					MockStatusUpdate: func(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+						return nil
+					},
+				},
+				kcfgExtractorFn: func(ctx context.Context, src xpv1.CredentialsSource, c client.Client, ccs xpv1.CommonCredentialSelectors) ([]byte, error) {
+					return nil, nil
+				},
+				gcpExtractorFn: func(ctx context.Context, src xpv1.CredentialsSource, c client.Client, ccs xpv1.CommonCredentialSelectors) ([]byte, error) {
+					return nil, nil
+				},
+				gcpInjectorFn: func(ctx context.Context, rc *rest.Config, credentials []byte, scopes ...string) error {
+					return nil
+				},
+				newRestConfigFn: func(kubeconfig []byte, _ bool) (config *rest.Config, err error) {
+					return &rest.Config{}, nil
+				},
+				newKubeClientFn: func(config *rest.Config, mapper apimeta.RESTMapper) (c client.Client, err error) {
+					return &test.MockClient{}, nil
+				},
+				newHelmClientFn: func(log logging.Logger, restConfig *rest.Config, helmArgs ...helmClient.ArgsApplier) (h helmClient.Client, err error) {
+					a := &helmClient.Args{}
+					for _, apply := range helmArgs {
+						apply(a)
+					}
+					if !a.Debug {
+						return nil, errBoom
+					}
+					return &MockHelmClient{}, nil
+				},
+				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
+				mg: helmRelease(func(r *v1beta1.Release) {
+					meta.AddAnnotations(r, map[string]string{v1beta1.AnnotationKeyDebug: "true"})
+				}),
+			},
+			want: want{
+				err: nil,
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -406,6 +770,10 @@ func Test_connector_Connect(t *testing.T) {
 				kcfgExtractorFn: tc.args.kcfgExtractorFn,
 				gcpExtractorFn:  tc.args.gcpExtractorFn,
 				gcpInjectorFn:   tc.args.gcpInjectorFn,
+				gcpWIInjectorFn: tc.args.gcpWIInjectorFn,
+				awsInjectorFn:   tc.args.awsInjectorFn,
+				azInjectorFn:    tc.args.azInjectorFn,
+				oidcInjectorFn:  tc.args.oidcInjectorFn,
 				newRestConfigFn: tc.args.newRestConfigFn,
 				newKubeClientFn: tc.args.newKubeClientFn,
 				newHelmClientFn: tc.args.newHelmClientFn,
@@ -581,14 +949,132 @@ func Test_helmExternal_Observe(t *testing.T) {
 				err: nil,
 			},
 		},
-	}
-	for name, tc := range cases {
-		t.Run(name, func(t *testing.T) {
-			e := &helmExternal{
-				logger:    logging.NewNopLogger(),
-				localKube: tc.args.localKube,
-				kube:      tc.args.kube,
+		"SuspendedNotInstalled": {
+			args: args{
+				helm: &MockHelmClient{
+					MockGetLastRelease: func(r string) (hr *release.Release, err error) {
+						return nil, driver.ErrReleaseNotFound
+					},
+				},
+				mg: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.Suspend = true
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"ObserveOnly": {
+			args: args{
+				helm: &MockHelmClient{
+					MockGetLastRelease: func(r string) (hr *release.Release, err error) {
+						return &release.Release{
+							Name: r,
+							Info: &release.Info{Status: release.StatusDeployed},
+							Chart: &chart.Chart{
+								Metadata: &chart.Metadata{
+									Name:    "other-chart",
+									Version: "9.9.9",
+								},
+							},
+							Config: map[string]interface{}{"foo": "bar"},
+						}, nil
+					},
+				},
+				mg: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ObserveOnly = true
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ConnectionDetails: managed.ConnectionDetails{}},
+			},
+		},
+		"AdoptExistingRelease": {
+			args: args{
+				helm: &MockHelmClient{
+					MockGetLastRelease: func(r string) (hr *release.Release, err error) {
+						return &release.Release{
+							Name: r,
+							Info: &release.Info{Status: release.StatusDeployed},
+							Chart: &chart.Chart{
+								Metadata: &chart.Metadata{
+									Name:    testChart,
+									Version: testVersion,
+								},
+							},
+						}, nil
+					},
+				},
+				mg: helmRelease(func(r *v1beta1.Release) {
+					r.SetAnnotations(map[string]string{v1beta1.AnnotationKeyAdopt: "true"})
+					r.Spec.ForProvider.Chart.Version = ""
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{
+					ResourceExists:          true,
+					ResourceUpToDate:        true,
+					ResourceLateInitialized: true,
+					ConnectionDetails:       managed.ConnectionDetails{},
+				},
+			},
+		},
+		"RenderOnly": {
+			args: args{
+				helm: &MockHelmClient{
+					MockTemplate: func(r string, chart *chart.Chart, vals map[string]interface{}, patches []types.Patch) (*release.Release, error) {
+						return &release.Release{Name: r, Manifest: "kind: ConfigMap"}, nil
+					},
+				},
+				mg: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.RenderOnly = true
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: managed.ConnectionDetails{renderedManifestKey: []byte("kind: ConfigMap")},
+				},
+			},
+		},
+		"RenderOnlyFailed": {
+			args: args{
+				helm: &MockHelmClient{
+					MockTemplate: func(r string, chart *chart.Chart, vals map[string]interface{}, patches []types.Patch) (*release.Release, error) {
+						return nil, errBoom
+					},
+				},
+				mg: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.RenderOnly = true
+				}),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFailedToRender),
+			},
+		},
+		"RenderOnlyBeingDeleted": {
+			args: args{
+				mg: helmRelease(func(r *v1beta1.Release) {
+					now := metav1.Now()
+					r.SetDeletionTimestamp(&now)
+					r.Spec.ForProvider.RenderOnly = true
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &helmExternal{
+				logger:    logging.NewNopLogger(),
+				recorder:  event.NewNopRecorder(),
+				localKube: tc.args.localKube,
+				kube:      tc.args.kube,
 				helm:      tc.args.helm,
+				patch:     newPatcher(),
 			}
 			got, gotErr := e.Observe(context.Background(), tc.args.mg)
 			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
@@ -609,6 +1095,7 @@ func Test_helmExternal_Create(t *testing.T) {
 		helm      helmClient.Client
 		mg        resource.Managed
 		updateFn  func(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error
+		policy    *ChartPolicy
 	}
 	type want struct {
 		err error
@@ -625,6 +1112,17 @@ func Test_helmExternal_Create(t *testing.T) {
 				err: errors.New(errNotRelease),
 			},
 		},
+		"CreateNotAllowed": {
+			args: args{
+				helm: &MockHelmClient{},
+				mg: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ManagementPolicies = []v1beta1.ManagementAction{v1beta1.ManagementActionObserve}
+				}),
+			},
+			want: want{
+				err: errors.New(errCreateNotAllowed),
+			},
+		},
 		"InstalledFailed": {
 			args: args{
 				helm: &MockHelmClient{
@@ -641,6 +1139,26 @@ func Test_helmExternal_Create(t *testing.T) {
 				err: errors.Wrap(errBoom, errFailedToInstall),
 			},
 		},
+		"ChartDenied": {
+			args: args{
+				helm: &MockHelmClient{
+					MockPullAndLoadChart: func(spec *v1beta1.ChartSpec, creds *helmClient.RepoCreds) (*chart.Chart, error) {
+						t.Fatalf("PullAndLoadChart(...): pulled chart of denied repository")
+						return nil, nil
+					},
+				},
+				kube: &test.MockClient{
+					MockCreate: test.NewMockCreateFn(nil),
+				},
+				mg: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.Chart.Repository = "https://evil.example.com"
+				}),
+				policy: &ChartPolicy{AllowedRepositories: []string{"https://charts.example.org/"}},
+			},
+			want: want{
+				err: errors.Wrap(errors.Errorf(errFmtRepositoryDenied, "https://evil.example.com"), errFailedToInstall),
+			},
+		},
 		"CreateNamespaceFailed": {
 			args: args{
 				kube: &test.MockClient{
@@ -700,6 +1218,47 @@ func Test_helmExternal_Create(t *testing.T) {
 				err: nil,
 			},
 		},
+		"SuccessNamespaceMetadata": {
+			args: args{
+				helm: &MockHelmClient{
+					MockInstall: func(r string, chart *chart.Chart, vals map[string]interface{}, patches []types.Patch) (hr *release.Release, err error) {
+						return &release.Release{}, nil
+					},
+				},
+				kube: &test.MockClient{
+					MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+						want := &corev1.Namespace{
+							ObjectMeta: metav1.ObjectMeta{
+								Name: testNamespace,
+								Labels: map[string]string{
+									"pod-security.kubernetes.io/enforce": "restricted",
+									helmNamespaceLabel:                   helmProviderName,
+								},
+								Annotations: map[string]string{
+									"owner":                    "team-a",
+									namespaceReleaseAnnotation: testReleaseName,
+								},
+							},
+						}
+						if diff := cmp.Diff(want, obj); diff != "" {
+							t.Errorf("Create(...): -want namespace, +got namespace: %s", diff)
+						}
+						return nil
+					},
+				},
+				mg: helmRelease(func(release *v1beta1.Release) {
+					meta.SetExternalName(release, testReleaseName)
+					release.Spec.ForProvider.Namespace = testNamespace
+					release.Spec.ForProvider.NamespaceMetadata = &v1beta1.NamespaceMetadata{
+						Labels:      map[string]string{"pod-security.kubernetes.io/enforce": "restricted"},
+						Annotations: map[string]string{"owner": "team-a"},
+					}
+				}),
+			},
+			want: want{
+				err: nil,
+			},
+		},
 		"SuccessSkipCreateNamespace": {
 			args: args{
 				helm: &MockHelmClient{
@@ -709,7 +1268,7 @@ func Test_helmExternal_Create(t *testing.T) {
 				},
 				kube: &test.MockClient{MockCreate: test.NewMockCreateFn(errBoom)},
 				mg: helmRelease(func(release *v1beta1.Release) {
-					release.Spec.ForProvider.SkipCreateNamespace = true
+					release.Spec.ForProvider.SkipCreateNamespace = pointer.Bool(true)
 				}),
 			},
 			want: want{
@@ -752,11 +1311,13 @@ func Test_helmExternal_Create(t *testing.T) {
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			e := &helmExternal{
-				logger:    logging.NewNopLogger(),
-				localKube: tc.args.localKube,
-				kube:      tc.args.kube,
-				helm:      tc.args.helm,
-				patch:     newPatcher(),
+				logger:      logging.NewNopLogger(),
+				recorder:    event.NewNopRecorder(),
+				localKube:   tc.args.localKube,
+				kube:        tc.args.kube,
+				helm:        tc.args.helm,
+				patch:       newPatcher(),
+				chartPolicy: tc.args.policy,
 			}
 			if tc.args.updateFn != nil {
 				e.localKube = &test.MockClient{
@@ -793,6 +1354,40 @@ func Test_helmExternal_Update(t *testing.T) {
 				err: errors.New(errNotRelease),
 			},
 		},
+		"UpdateNotAllowed": {
+			args: args{
+				helm: &MockHelmClient{},
+				mg: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ManagementPolicies = []v1beta1.ManagementAction{v1beta1.ManagementActionObserve, v1beta1.ManagementActionDelete}
+				}),
+			},
+			want: want{},
+		},
+		"Hibernate": {
+			args: args{
+				helm: &MockHelmClient{
+					MockUninstall: func(_ string) error {
+						return errBoom
+					},
+				},
+				mg: helmRelease(func(r *v1beta1.Release) {
+					// Hibernating every minute and never waking up.
+					r.Spec.ForProvider.Hibernation = &v1beta1.Hibernation{Hibernate: "* * * * *", WakeUp: "0 0 31 2 *"}
+				}),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFailedToHibernate),
+			},
+		},
+		"UpgradeDeferred": {
+			args: args{
+				helm: &MockHelmClient{},
+				mg: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.UpgradeWindows = []v1beta1.UpgradeWindow{{Schedule: "0 0 31 2 *"}}
+				}),
+			},
+			want: want{},
+		},
 		"RetryUninstallFails": {
 			args: args{
 				helm: &MockHelmClient{
@@ -910,6 +1505,7 @@ func Test_helmExternal_Update(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			e := &helmExternal{
 				logger:    logging.NewNopLogger(),
+				recorder:  event.NewNopRecorder(),
 				localKube: tc.args.localKube,
 				kube:      tc.args.kube,
 				helm:      tc.args.helm,
@@ -931,7 +1527,8 @@ func Test_helmExternal_Delete(t *testing.T) {
 		mg        resource.Managed
 	}
 	type want struct {
-		err error
+		err  error
+		kept []string
 	}
 	cases := map[string]struct {
 		args
@@ -945,6 +1542,26 @@ func Test_helmExternal_Delete(t *testing.T) {
 				err: errors.New(errNotRelease),
 			},
 		},
+		"Suspended": {
+			args: args{
+				helm: &MockHelmClient{},
+				mg: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.Suspend = true
+				}),
+			},
+			want: want{
+				err: errors.New(errSuspended),
+			},
+		},
+		"DeleteNotAllowed": {
+			args: args{
+				helm: &MockHelmClient{},
+				mg: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ManagementPolicies = []v1beta1.ManagementAction{v1beta1.ManagementActionObserve, v1beta1.ManagementActionCreate, v1beta1.ManagementActionUpdate}
+				}),
+			},
+			want: want{},
+		},
 		"FailedToUninstall": {
 			args: args{
 				helm: &MockHelmClient{
@@ -971,6 +1588,122 @@ func Test_helmExternal_Delete(t *testing.T) {
 				err: nil,
 			},
 		},
+		"KeepResources": {
+			args: args{
+				helm: &MockHelmClient{
+					MockForget: func(release string) error {
+						return nil
+					},
+				},
+				mg: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.UninstallPolicy = v1beta1.UninstallPolicyKeepResources
+				}),
+			},
+			want: want{
+				err: nil,
+			},
+		},
+		"KeepResourcesCanary": {
+			args: args{
+				helm: &MockHelmClient{
+					MockForget: func(release string) error {
+						return nil
+					},
+					MockUninstall: func(release string) error {
+						if release != "canary" {
+							return errBoom
+						}
+						return nil
+					},
+				},
+				mg: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.UninstallPolicy = v1beta1.UninstallPolicyKeepResources
+					r.Status.Canary = &v1beta1.CanaryStatus{ReleaseName: "canary"}
+				}),
+			},
+			want: want{
+				err: nil,
+			},
+		},
+		"KeepResourcesFailedToUninstallCanary": {
+			args: args{
+				helm: &MockHelmClient{
+					MockForget: func(release string) error {
+						return nil
+					},
+					MockUninstall: func(release string) error {
+						return errBoom
+					},
+				},
+				mg: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.UninstallPolicy = v1beta1.UninstallPolicyKeepResources
+					r.Status.Canary = &v1beta1.CanaryStatus{ReleaseName: "canary"}
+				}),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFailedToUninstallCanary),
+			},
+		},
+		"FailedToForget": {
+			args: args{
+				helm: &MockHelmClient{
+					MockForget: func(release string) error {
+						return errBoom
+					},
+				},
+				mg: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.UninstallPolicy = v1beta1.UninstallPolicyKeepResources
+				}),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFailedToForget),
+			},
+		},
+		"FailedToGetLastRelease": {
+			args: args{
+				helm: &MockHelmClient{
+					MockGetLastRelease: func(_ string) (*release.Release, error) {
+						return nil, errBoom
+					},
+				},
+				mg: helmRelease(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFailedToGetLastRelease),
+			},
+		},
+		"SuccessResourcesKept": {
+			args: args{
+				helm: &MockHelmClient{
+					MockGetLastRelease: func(_ string) (*release.Release, error) {
+						return &release.Release{Manifest: testManifest}, nil
+					},
+					MockUninstall: func(release string) error {
+						return nil
+					},
+				},
+				mg: helmRelease(),
+			},
+			want: want{
+				err:  nil,
+				kept: []string{"ConfigMap other/second"},
+			},
+		},
+		"DeletionProtected": {
+			args: args{
+				helm: &MockHelmClient{
+					MockUninstall: func(release string) error {
+						return errBoom
+					},
+				},
+				mg: helmRelease(func(r *v1beta1.Release) {
+					r.Spec.ForProvider.DeletionProtection = true
+				}),
+			},
+			want: want{
+				err: errors.New(errDeletionProtected),
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -979,11 +1712,187 @@ func Test_helmExternal_Delete(t *testing.T) {
 				localKube: tc.args.localKube,
 				kube:      tc.args.kube,
 				helm:      tc.args.helm,
+				recorder:  event.NewNopRecorder(),
 			}
 			gotErr := e.Delete(context.Background(), tc.args.mg)
 			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
 				t.Fatalf("e.Delete(...): -want error, +got error: %s", diff)
 			}
+			if cr, ok := tc.args.mg.(*v1beta1.Release); ok {
+				if diff := cmp.Diff(tc.want.kept, cr.Status.AtProvider.KeptResources); diff != "" {
+					t.Errorf("e.Delete(...): -want kept resources, +got kept resources: %s", diff)
+				}
+			}
 		})
 	}
 }
+
+func TestConfigureConnection(t *testing.T) {
+	qps, burst := int32(5), int32(10)
+	override := int32(50)
+
+	type want struct {
+		rc    *rest.Config
+		proxy string
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		rc     *rest.Config
+		cs     []*helmv1beta1.Connection
+		want   want
+	}{
+		"NoConnection": {
+			reason: "The REST config should be unchanged if no connection settings are supplied.",
+			cs:     []*helmv1beta1.Connection{nil},
+			want:   want{rc: &rest.Config{}},
+		},
+		"Defaults": {
+			reason: "The default connection settings should be applied.",
+			cs: []*helmv1beta1.Connection{
+				{QPS: &qps, Burst: &burst, Timeout: &metav1.Duration{Duration: time.Minute}},
+				nil,
+			},
+			want: want{rc: &rest.Config{QPS: 5, Burst: 10, Timeout: time.Minute}},
+		},
+		"Override": {
+			reason: "Later connection settings should take precedence over earlier ones.",
+			cs: []*helmv1beta1.Connection{
+				{QPS: &qps, Burst: &burst},
+				{QPS: &override},
+			},
+			want: want{rc: &rest.Config{QPS: 50, Burst: 10}},
+		},
+		"Proxy": {
+			reason: "Requests should be sent through the proxy.",
+			cs:     []*helmv1beta1.Connection{{ProxyURL: "socks5://proxy:1080"}},
+			want:   want{rc: &rest.Config{}, proxy: "socks5://proxy:1080"},
+		},
+		"ServerName": {
+			reason: "The server name should be overridden.",
+			cs:     []*helmv1beta1.Connection{{TLS: &helmv1beta1.TLSOverrides{ServerName: "api.internal"}}},
+			want:   want{rc: &rest.Config{TLSClientConfig: rest.TLSClientConfig{ServerName: "api.internal"}}},
+		},
+		"CABundle": {
+			reason: "The CA bundle should be trusted in addition to the certificate authorities of the credentials.",
+			rc:     &rest.Config{TLSClientConfig: rest.TLSClientConfig{CAData: []byte("ca")}},
+			cs:     []*helmv1beta1.Connection{{TLS: &helmv1beta1.TLSOverrides{CABundle: []byte("extra")}}},
+			want:   want{rc: &rest.Config{TLSClientConfig: rest.TLSClientConfig{CAData: []byte("ca\nextra")}}},
+		},
+		"InsecureSkipVerify": {
+			reason: "Certificate authorities should be dropped if verification is skipped.",
+			rc:     &rest.Config{TLSClientConfig: rest.TLSClientConfig{CAData: []byte("ca")}},
+			cs:     []*helmv1beta1.Connection{{TLS: &helmv1beta1.TLSOverrides{InsecureSkipVerify: true, CABundle: []byte("extra")}}},
+			want:   want{rc: &rest.Config{TLSClientConfig: rest.TLSClientConfig{Insecure: true}}},
+		},
+		"UnsupportedProxyScheme": {
+			reason: "Proxies of unsupported schemes should be rejected.",
+			cs:     []*helmv1beta1.Connection{{ProxyURL: "ftp://proxy"}},
+			want:   want{rc: &rest.Config{}, err: errors.Errorf(errProxyScheme, "ftp")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := &rest.Config{}
+			if tc.rc != nil {
+				got = tc.rc
+			}
+			err := configureConnection(got, tc.cs...)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nconfigureConnection(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			proxy := ""
+			if got.Proxy != nil {
+				u, _ := got.Proxy(nil)
+				proxy = u.String()
+			}
+			if diff := cmp.Diff(tc.want.proxy, proxy); diff != "" {
+				t.Errorf("\n%s\nconfigureConnection(...): -want proxy, +got proxy:\n%s", tc.reason, diff)
+			}
+			got.Proxy = nil
+			if diff := cmp.Diff(tc.want.rc, got); diff != "" {
+				t.Errorf("\n%s\nconfigureConnection(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRestConfigImpersonation(t *testing.T) {
+	pc := func(i *helmv1beta1.Impersonation) *helmv1beta1.ProviderConfig {
+		return &helmv1beta1.ProviderConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: providerName},
+			Spec: helmv1beta1.ProviderConfigSpec{
+				Credentials: helmv1beta1.ProviderCredentials{Source: xpv1.CredentialsSourceNone},
+				Cluster:     &helmv1beta1.Cluster{Server: "https://cluster.example.org"},
+				Impersonate: i,
+			},
+		}
+	}
+	sa := &k8stypes.NamespacedName{Namespace: testNamespace, Name: "deployer"}
+
+	type want struct {
+		user string
+		err  error
+	}
+	cases := map[string]struct {
+		reason string
+		pc     *helmv1beta1.ProviderConfig
+		sa     *k8stypes.NamespacedName
+		want   want
+	}{
+		"ProviderConfigImpersonation": {
+			reason: "The user of the ProviderConfig should be impersonated.",
+			pc:     pc(&helmv1beta1.Impersonation{Username: "tenant-a"}),
+			want:   want{user: "tenant-a"},
+		},
+		"ServiceAccount": {
+			reason: "The ServiceAccount of the Release should be impersonated if the ProviderConfig impersonates no user.",
+			pc:     pc(nil),
+			sa:     sa,
+			want:   want{user: "system:serviceaccount:" + testNamespace + ":deployer"},
+		},
+		"ServiceAccountOfImpersonatingProviderConfig": {
+			reason: "A Release should not escape the user its ProviderConfig impersonates by naming a ServiceAccount.",
+			pc:     pc(&helmv1beta1.Impersonation{Username: "tenant-a"}),
+			sa:     sa,
+			want:   want{err: errors.Errorf(errServiceAccountImpersonated, sa.String(), providerName)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &connector{logger: logging.NewNopLogger()}
+			rc, err := c.restConfig(context.Background(), tc.pc, tc.sa)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nrestConfig(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.user, rc.Impersonate.UserName); diff != "" {
+				t.Errorf("\n%s\nrestConfig(...): -want user, +got user:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func Test_renderedOnce(t *testing.T) {
+	calls := 0
+	template := renderedOnce(func(string, *chart.Chart, map[string]interface{}, []types.Patch) (*release.Release, error) {
+		calls++
+		return &release.Release{Manifest: "kind: ConfigMap"}, nil
+	})
+	for i := 0; i < 3; i++ {
+		r, err := template(testReleaseName, nil, nil, nil)
+		if err != nil {
+			t.Fatalf("renderedOnce(...): unexpected error: %s", err)
+		}
+		if diff := cmp.Diff("kind: ConfigMap", r.Manifest); diff != "" {
+			t.Errorf("renderedOnce(...): -want manifest, +got manifest: %s", diff)
+		}
+	}
+	if diff := cmp.Diff(1, calls); diff != "" {
+		t.Errorf("renderedOnce(...): -want renders, +got renders: %s", diff)
+	}
+}
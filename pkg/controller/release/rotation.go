@@ -0,0 +1,153 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+	helmv1beta1 "github.com/crossplane-contrib/provider-helm/apis/v1beta1"
+)
+
+const (
+	// releaseProviderConfigIndex indexes Releases by the name of their
+	// ProviderConfig.
+	releaseProviderConfigIndex = "spec.providerConfigRef.name"
+
+	// releaseKubeConfigSecretIndex indexes Releases by the namespace and
+	// name of the Secret their kubeconfig is read from, if any.
+	releaseKubeConfigSecretIndex = "spec.forProvider.kubeConfigSecretRef"
+
+	// providerConfigSecretIndex indexes ProviderConfigs by the namespace and
+	// name of the Secrets their credentials and identity are read from.
+	providerConfigSecretIndex = "spec.credentials.secretRef"
+)
+
+// indexReleases indexes Releases by the ProviderConfigs and Secrets that
+// their target cluster credentials are read from, and ProviderConfigs by the
+// Secrets that theirs are read from.
+func indexReleases(ctx context.Context, i client.FieldIndexer) error {
+	if err := i.IndexField(ctx, &v1beta1.Release{}, releaseProviderConfigIndex, releaseProviderConfig); err != nil {
+		return err
+	}
+	if err := i.IndexField(ctx, &v1beta1.Release{}, releaseKubeConfigSecretIndex, releaseKubeConfigSecret); err != nil {
+		return err
+	}
+	return i.IndexField(ctx, &helmv1beta1.ProviderConfig{}, providerConfigSecretIndex, providerConfigSecrets)
+}
+
+func releaseProviderConfig(o client.Object) []string {
+	r, ok := o.(*v1beta1.Release)
+	if !ok || r.GetProviderConfigReference() == nil {
+		return nil
+	}
+	return []string{r.GetProviderConfigReference().Name}
+}
+
+func releaseKubeConfigSecret(o client.Object) []string {
+	r, ok := o.(*v1beta1.Release)
+	if !ok || r.Spec.ForProvider.KubeConfigSecretRef == nil {
+		return nil
+	}
+	return []string{secretName(r.Spec.ForProvider.KubeConfigSecretRef.SecretReference)}
+}
+
+func providerConfigSecrets(o client.Object) []string {
+	pc, ok := o.(*helmv1beta1.ProviderConfig)
+	if !ok {
+		return nil
+	}
+	var secrets []string
+	if _, sel, err := kubeconfigSelectors(pc.Spec.Credentials); err == nil && sel.SecretRef != nil {
+		secrets = append(secrets, secretName(sel.SecretRef.SecretReference))
+	}
+	if id := pc.Spec.Identity; id != nil && id.SecretRef != nil {
+		secrets = append(secrets, secretName(id.SecretRef.SecretReference))
+	}
+	return secrets
+}
+
+func secretName(ref xpv1.SecretReference) string {
+	return types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}.String()
+}
+
+// A credentialsMapper maps Secrets and ProviderConfigs to the Releases whose
+// target cluster credentials are read from them, so that rotated kubeconfigs,
+// renewed certificates and reconfigured ProviderConfigs take effect
+// immediately rather than at the next poll. Cached clients of the
+// ProviderConfig are invalidated.
+type credentialsMapper struct {
+	kube  client.Client
+	log   logging.Logger
+	cache *clientCache
+}
+
+func (m *credentialsMapper) releasesForSecret(o client.Object) []reconcile.Request {
+	ctx := context.Background()
+	s := types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}
+
+	pcs := &helmv1beta1.ProviderConfigList{}
+	if err := m.kube.List(ctx, pcs, client.MatchingFields{providerConfigSecretIndex: s.String()}); err != nil {
+		m.log.Debug("Cannot list ProviderConfigs", "error", err)
+		return nil
+	}
+
+	reqs := m.releases(ctx, client.MatchingFields{releaseKubeConfigSecretIndex: s.String()}, nil)
+	for _, pc := range pcs.Items {
+		m.cache.Invalidate(pc.GetName())
+		reqs = m.releases(ctx, client.MatchingFields{releaseProviderConfigIndex: pc.GetName()}, reqs)
+	}
+	return reqs
+}
+
+func (m *credentialsMapper) releasesForProviderConfig(o client.Object) []reconcile.Request {
+	m.cache.Invalidate(o.GetName())
+	return m.releases(context.Background(), client.MatchingFields{releaseProviderConfigIndex: o.GetName()}, nil)
+}
+
+// releases appends requests for the Releases matching the supplied fields to
+// the supplied requests, unless they are requested already.
+func (m *credentialsMapper) releases(ctx context.Context, f client.MatchingFields, reqs []reconcile.Request) []reconcile.Request {
+	rs := &v1beta1.ReleaseList{}
+	if err := m.kube.List(ctx, rs, f); err != nil {
+		m.log.Debug("Cannot list Releases", "error", err)
+		return reqs
+	}
+	for _, r := range rs.Items {
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Name: r.GetName()}}
+		if !containsRequest(reqs, req) {
+			reqs = append(reqs, req)
+		}
+	}
+	return reqs
+}
+
+func containsRequest(reqs []reconcile.Request, req reconcile.Request) bool {
+	for _, r := range reqs {
+		if r == req {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+	helmv1beta1 "github.com/crossplane-contrib/provider-helm/apis/v1beta1"
+)
+
+func TestReleasesForSecret(t *testing.T) {
+	secretCreds := func(name, ns string) helmv1beta1.ProviderCredentials {
+		return helmv1beta1.ProviderCredentials{
+			Source: xpv1.CredentialsSourceSecret,
+			CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+				SecretRef: &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Name: name, Namespace: ns}, Key: "kubeconfig"},
+			},
+		}
+	}
+	pcs := []helmv1beta1.ProviderConfig{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubeconfig"},
+			Spec:       helmv1beta1.ProviderConfigSpec{Credentials: secretCreds("cluster-a", "crossplane-system")},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "identity"},
+			Spec: helmv1beta1.ProviderConfigSpec{
+				Credentials: secretCreds("cluster-b", "crossplane-system"),
+				Identity:    &helmv1beta1.Identity{ProviderCredentials: secretCreds("gcp", "crossplane-system")},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "capi"},
+			Spec: helmv1beta1.ProviderConfigSpec{Credentials: helmv1beta1.ProviderCredentials{
+				Source:        helmv1beta1.CredentialsSourceClusterAPI,
+				ClusterAPIRef: &helmv1beta1.ClusterAPIReference{Name: "workload", Namespace: "fleet"},
+			}},
+		},
+	}
+	release := func(name, pc string) v1beta1.Release {
+		r := v1beta1.Release{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		r.SetProviderConfigReference(&xpv1.Reference{Name: pc})
+		return r
+	}
+	override := release("e", "kubeconfig")
+	override.Spec.ForProvider.KubeConfigSecretRef = &xpv1.SecretKeySelector{
+		SecretReference: xpv1.SecretReference{Name: "one-off", Namespace: "default"},
+		Key:             "kubeconfig",
+	}
+	rs := []v1beta1.Release{release("a", "kubeconfig"), release("b", "identity"), release("c", "capi"), release("d", "kubeconfig"), override}
+
+	kube := &test.MockClient{
+		MockList: func(_ context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+			switch l := obj.(type) {
+			case *helmv1beta1.ProviderConfigList:
+				l.Items = indexedProviderConfigs(pcs, opts...)
+			case *v1beta1.ReleaseList:
+				l.Items = indexedReleases(rs, opts...)
+			}
+			return nil
+		},
+	}
+
+	req := func(name string) reconcile.Request {
+		return reconcile.Request{NamespacedName: types.NamespacedName{Name: name}}
+	}
+	cases := map[string]struct {
+		secret types.NamespacedName
+		want   []reconcile.Request
+	}{
+		"Kubeconfig": {
+			secret: types.NamespacedName{Namespace: "crossplane-system", Name: "cluster-a"},
+			want:   []reconcile.Request{req("a"), req("d"), req("e")},
+		},
+		"Identity": {
+			secret: types.NamespacedName{Namespace: "crossplane-system", Name: "gcp"},
+			want:   []reconcile.Request{req("b")},
+		},
+		"ClusterAPI": {
+			secret: types.NamespacedName{Namespace: "fleet", Name: "workload-kubeconfig"},
+			want:   []reconcile.Request{req("c")},
+		},
+		"ReleaseKubeconfig": {
+			secret: types.NamespacedName{Namespace: "default", Name: "one-off"},
+			want:   []reconcile.Request{req("e")},
+		},
+		"Unused": {
+			secret: types.NamespacedName{Namespace: "default", Name: "cluster-a"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := &credentialsMapper{kube: kube, log: logging.NewNopLogger()}
+			s := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: tc.secret.Namespace, Name: tc.secret.Name}}
+			if diff := cmp.Diff(tc.want, m.releasesForSecret(s)); diff != "" {
+				t.Errorf("releasesForSecret(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+// indexedReleases returns the supplied Releases that match the field
+// selector of the supplied options, as the indexes of indexReleases would.
+func indexedReleases(rs []v1beta1.Release, opts ...client.ListOption) []v1beta1.Release {
+	lo := &client.ListOptions{}
+	lo.ApplyOptions(opts)
+	if lo.FieldSelector == nil {
+		return rs
+	}
+	indexes := map[string]client.IndexerFunc{
+		releaseProviderConfigIndex:   releaseProviderConfig,
+		releaseKubeConfigSecretIndex: releaseKubeConfigSecret,
+	}
+	var res []v1beta1.Release
+	for i := range rs {
+		for field, index := range indexes {
+			v, ok := lo.FieldSelector.RequiresExactMatch(field)
+			if !ok {
+				continue
+			}
+			for _, iv := range index(&rs[i]) {
+				if iv == v {
+					res = append(res, rs[i])
+				}
+			}
+		}
+	}
+	return res
+}
+
+// indexedProviderConfigs returns the supplied ProviderConfigs that match the
+// field selector of the supplied options, as the index of indexReleases
+// would.
+func indexedProviderConfigs(pcs []helmv1beta1.ProviderConfig, opts ...client.ListOption) []helmv1beta1.ProviderConfig {
+	lo := &client.ListOptions{}
+	lo.ApplyOptions(opts)
+	if lo.FieldSelector == nil {
+		return pcs
+	}
+	v, ok := lo.FieldSelector.RequiresExactMatch(providerConfigSecretIndex)
+	if !ok {
+		return pcs
+	}
+	var res []helmv1beta1.ProviderConfig
+	for i := range pcs {
+		for _, iv := range providerConfigSecrets(&pcs[i]) {
+			if iv == v {
+				res = append(res, pcs[i])
+			}
+		}
+	}
+	return res
+}
+
+func TestReleasesForProviderConfig(t *testing.T) {
+	release := func(name, pc string) v1beta1.Release {
+		r := v1beta1.Release{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		r.SetProviderConfigReference(&xpv1.Reference{Name: pc})
+		return r
+	}
+	rs := []v1beta1.Release{release("a", "one"), release("b", "two"), release("c", "one"), {ObjectMeta: metav1.ObjectMeta{Name: "d"}}}
+
+	req := func(name string) reconcile.Request {
+		return reconcile.Request{NamespacedName: types.NamespacedName{Name: name}}
+	}
+	cases := map[string]struct {
+		kube client.Client
+		pc   string
+		want []reconcile.Request
+	}{
+		"Used": {
+			pc:   "one",
+			want: []reconcile.Request{req("a"), req("c")},
+		},
+		"Unused": {
+			pc: "three",
+		},
+		"ListError": {
+			kube: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			pc:   "one",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			kube := tc.kube
+			if kube == nil {
+				kube = &test.MockClient{
+					MockList: func(_ context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+						obj.(*v1beta1.ReleaseList).Items = indexedReleases(rs, opts...)
+						return nil
+					},
+				}
+			}
+			c := newClientCache(0, 0)
+			c.Add(clientCacheKey{providerConfig: tc.pc}, &clusterClients{})
+			m := &credentialsMapper{kube: kube, log: logging.NewNopLogger(), cache: c}
+			pc := &helmv1beta1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: tc.pc}}
+			if diff := cmp.Diff(tc.want, m.releasesForProviderConfig(pc)); diff != "" {
+				t.Errorf("releasesForProviderConfig(...): -want, +got:\n%s", diff)
+			}
+			if c.Len() != 0 {
+				t.Errorf("releasesForProviderConfig(...): want cached clients invalidated")
+			}
+		})
+	}
+}
@@ -0,0 +1,399 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+	helmv1beta1 "github.com/crossplane-contrib/provider-helm/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-helm/pkg/clients/ess"
+	"github.com/crossplane-contrib/provider-helm/pkg/clients/vault"
+)
+
+const (
+	errGetStoreConfig        = "cannot get StoreConfig"
+	errStoreConfigNoVault    = "StoreConfig of type Vault has no vault configuration"
+	errStoreConfigNoPlugin   = "StoreConfig of type Plugin has no plugin configuration"
+	errDialStorePlugin       = "cannot dial secret store plugin"
+	errFmtInvalidSecretName  = "invalid connection secret name %q: it must be a single path segment"
+	errFmtSecretNotOwned     = "secret %q in secret store is not owned by the Release"
+	errUnknownStoreType      = "unknown secret store type %q"
+	errGetVaultToken         = "cannot get vault token"
+	errGetVaultCABundle      = "cannot get vault CA bundle"
+	errNewVaultClient        = "cannot create vault client"
+	errPublishToSecretStore  = "cannot publish connection details to secret store"
+	errUnpublishSecretStore  = "cannot unpublish connection details from secret store"
+	errReadFromSecretStore   = "cannot read connection details from secret store"
+	errDeleteFromSecretStore = "cannot delete connection details from secret store"
+	errGetFromSecretStore    = "cannot get connection details from secret store"
+)
+
+// A secretStore stores the connection details of Releases.
+type secretStore interface {
+	write(ctx context.Context, cr *v1beta1.Release, to *v1beta1.PublishConnectionDetailsTo, cd managed.ConnectionDetails) error
+	delete(ctx context.Context, cr *v1beta1.Release, to *v1beta1.PublishConnectionDetailsTo) error
+}
+
+// secretOwnerKey is the metadata key of secrets of Vault and Plugin stores
+// that records the UID of the Release that owns them, as the secret stores of
+// Crossplane do.
+const secretOwnerKey = "secret.crossplane.io/owner-uid"
+
+// A vaultClient reads and writes secrets of a Vault KV secrets engine.
+type vaultClient interface {
+	Read(ctx context.Context, path string) (*vault.Secret, error)
+	Write(ctx context.Context, path string, s *vault.Secret) error
+	Delete(ctx context.Context, path string) error
+}
+
+// A storePublisher publishes the connection details of Releases to the
+// secret store configured by their PublishConnectionDetailsTo.
+type storePublisher struct {
+	kube client.Client
+
+	extractorFn  func(ctx context.Context, src xpv1.CredentialsSource, c client.Client, ccs xpv1.CommonCredentialSelectors) ([]byte, error)
+	newVaultFn   func(url, mountPath string, version vault.KVVersion, token string, caBundle []byte) (vaultClient, error)
+	dialPluginFn func(endpoint string) (ess.ExternalSecretStorePluginServiceClient, error)
+
+	mu      sync.Mutex
+	plugins map[string]ess.ExternalSecretStorePluginServiceClient
+}
+
+// newStorePublisher returns a storePublisher that authenticates to secret
+// store plugins with the TLS certificates in the supplied directory.
+func newStorePublisher(kube client.Client, essCertDir string) *storePublisher {
+	return &storePublisher{
+		kube:        kube,
+		extractorFn: resource.CommonCredentialExtractor,
+		newVaultFn: func(url, mountPath string, version vault.KVVersion, token string, caBundle []byte) (vaultClient, error) {
+			return vault.NewClient(url, mountPath, version, token, caBundle)
+		},
+		dialPluginFn: func(endpoint string) (ess.ExternalSecretStorePluginServiceClient, error) {
+			conn, err := ess.Dial(endpoint, essCertDir)
+			if err != nil {
+				return nil, err
+			}
+			return ess.NewExternalSecretStorePluginServiceClient(conn), nil
+		},
+		plugins: map[string]ess.ExternalSecretStorePluginServiceClient{},
+	}
+}
+
+// PublishConnection writes the supplied connection details to the secret
+// store of the supplied Release, if any.
+func (p *storePublisher) PublishConnection(ctx context.Context, mg resource.Managed, c managed.ConnectionDetails) error {
+	cr, ok := mg.(*v1beta1.Release)
+	if !ok {
+		return errors.New(errNotRelease)
+	}
+	to := cr.Spec.PublishConnectionDetailsTo
+	// Connection details are empty until the release is deployed; they
+	// should not replace the ones published before.
+	if to == nil || len(c) == 0 {
+		return nil
+	}
+	if err := validateSecretName(to.Name); err != nil {
+		return errors.Wrap(err, errPublishToSecretStore)
+	}
+	s, err := p.store(ctx, to)
+	if err != nil {
+		return errors.Wrap(err, errPublishToSecretStore)
+	}
+	return errors.Wrap(s.write(ctx, cr, to, c), errPublishToSecretStore)
+}
+
+// UnpublishConnection deletes the connection details of the supplied Release
+// from its secret store, if any.
+func (p *storePublisher) UnpublishConnection(ctx context.Context, mg resource.Managed, _ managed.ConnectionDetails) error {
+	cr, ok := mg.(*v1beta1.Release)
+	if !ok {
+		return errors.New(errNotRelease)
+	}
+	to := cr.Spec.PublishConnectionDetailsTo
+	if to == nil {
+		return nil
+	}
+	if err := validateSecretName(to.Name); err != nil {
+		return errors.Wrap(err, errUnpublishSecretStore)
+	}
+	s, err := p.store(ctx, to)
+	if err != nil {
+		return errors.Wrap(err, errUnpublishSecretStore)
+	}
+	return errors.Wrap(s.delete(ctx, cr, to), errUnpublishSecretStore)
+}
+
+// store returns the secret store configured by the supplied StoreConfig
+// reference.
+func (p *storePublisher) store(ctx context.Context, to *v1beta1.PublishConnectionDetailsTo) (secretStore, error) {
+	name := "default"
+	if ref := to.SecretStoreConfigRef; ref != nil {
+		name = ref.Name
+	}
+	sc := &helmv1beta1.StoreConfig{}
+	if err := p.kube.Get(ctx, types.NamespacedName{Name: name}, sc); err != nil {
+		return nil, errors.Wrap(err, errGetStoreConfig)
+	}
+
+	switch sc.Spec.Type {
+	case helmv1beta1.SecretStoreKubernetes, "":
+		return &kubernetesStore{kube: resource.NewAPIPatchingApplicator(p.kube), client: p.kube, namespace: sc.Spec.DefaultScope}, nil
+	case helmv1beta1.SecretStoreVault:
+		v := sc.Spec.Vault
+		if v == nil {
+			return nil, errors.New(errStoreConfigNoVault)
+		}
+		token, err := p.extractorFn(ctx, v.Token.Source, p.kube, v.Token.CommonCredentialSelectors)
+		if err != nil {
+			return nil, errors.Wrap(err, errGetVaultToken)
+		}
+		var ca []byte
+		if v.CABundle != nil {
+			if ca, err = p.extractorFn(ctx, v.CABundle.Source, p.kube, v.CABundle.CommonCredentialSelectors); err != nil {
+				return nil, errors.Wrap(err, errGetVaultCABundle)
+			}
+		}
+		vc, err := p.newVaultFn(v.Server, v.MountPath, vault.KVVersion(v.Version), strings.TrimSpace(string(token)), ca)
+		if err != nil {
+			return nil, errors.Wrap(err, errNewVaultClient)
+		}
+		return &vaultStore{client: vc, scope: sc.Spec.DefaultScope}, nil
+	case helmv1beta1.SecretStorePlugin:
+		pl := sc.Spec.Plugin
+		if pl == nil {
+			return nil, errors.New(errStoreConfigNoPlugin)
+		}
+		pc, err := p.plugin(pl.Endpoint)
+		if err != nil {
+			return nil, errors.Wrap(err, errDialStorePlugin)
+		}
+		return &pluginStore{
+			client: pc,
+			config: &ess.ConfigReference{ApiVersion: pl.ConfigRef.APIVersion, Kind: pl.ConfigRef.Kind, Name: pl.ConfigRef.Name},
+			scope:  sc.Spec.DefaultScope,
+		}, nil
+	}
+	return nil, errors.Errorf(errUnknownStoreType, sc.Spec.Type)
+}
+
+// plugin returns a client of the secret store plugin serving the supplied
+// endpoint. Clients are reused across reconciles.
+func (p *storePublisher) plugin(endpoint string) (ess.ExternalSecretStorePluginServiceClient, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if c, ok := p.plugins[endpoint]; ok {
+		return c, nil
+	}
+	c, err := p.dialPluginFn(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	p.plugins[endpoint] = c
+	return c, nil
+}
+
+// validateSecretName returns an error if the supplied name of a connection
+// secret is not a single path segment, which would let it escape the scope of
+// Vault and Plugin stores.
+func validateSecretName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.Contains(name, "/") {
+		return errors.Errorf(errFmtInvalidSecretName, name)
+	}
+	return nil
+}
+
+// A kubernetesStore stores connection details in Secrets of a namespace of
+// the cluster the provider runs in.
+type kubernetesStore struct {
+	kube      resource.Applicator
+	client    client.Client
+	namespace string
+}
+
+func (s *kubernetesStore) write(ctx context.Context, cr *v1beta1.Release, to *v1beta1.PublishConnectionDetailsTo, cd managed.ConnectionDetails) error {
+	sec := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            to.Name,
+			Namespace:       s.namespace,
+			OwnerReferences: []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(cr, v1beta1.ReleaseGroupVersionKind))},
+		},
+		Type: resource.SecretTypeConnection,
+		Data: cd,
+	}
+	if md := to.Metadata; md != nil {
+		sec.SetLabels(md.Labels)
+		sec.SetAnnotations(md.Annotations)
+		if md.Type != nil {
+			sec.Type = *md.Type
+		}
+	}
+	return s.kube.Apply(ctx, sec, resource.ConnectionSecretMustBeControllableBy(cr.GetUID()))
+}
+
+// delete deletes the Secret of the connection details, unless it is not
+// controlled by the supplied Release.
+func (s *kubernetesStore) delete(ctx context.Context, cr *v1beta1.Release, to *v1beta1.PublishConnectionDetailsTo) error {
+	sec := &corev1.Secret{}
+	err := s.client.Get(ctx, types.NamespacedName{Namespace: s.namespace, Name: to.Name}, sec)
+	if kerrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, errGetFromSecretStore)
+	}
+	if !metav1.IsControlledBy(sec, cr) {
+		return nil
+	}
+	return errors.Wrap(resource.IgnoreNotFound(s.client.Delete(ctx, sec)), errDeleteFromSecretStore)
+}
+
+// A vaultStore stores connection details in a Vault KV secrets engine, below
+// the path of its scope. Values are stored as strings. Secrets record the UID
+// of the Release that owns them in their metadata.
+type vaultStore struct {
+	client vaultClient
+	scope  string
+}
+
+// write writes the connection details, unless the secret is owned by
+// another Release.
+func (s *vaultStore) write(ctx context.Context, cr *v1beta1.Release, to *v1beta1.PublishConnectionDetailsTo, cd managed.ConnectionDetails) error {
+	data := make(map[string]string, len(cd))
+	for k, v := range cd {
+		data[k] = string(v)
+	}
+	p := path.Join(s.scope, to.Name)
+
+	current, err := s.client.Read(ctx, p)
+	if err != nil {
+		return errors.Wrap(err, errReadFromSecretStore)
+	}
+	if current != nil && current.Metadata[secretOwnerKey] != string(cr.GetUID()) {
+		return errors.Errorf(errFmtSecretNotOwned, p)
+	}
+	// Every write creates a new version of KV v2 secrets, so unchanged
+	// connection details are not written again.
+	if current != nil && equalStringMaps(current.Data, data) {
+		return nil
+	}
+	return s.client.Write(ctx, p, &vault.Secret{Data: data, Metadata: map[string]string{secretOwnerKey: string(cr.GetUID())}})
+}
+
+// delete deletes the secret of the connection details, unless it is not
+// owned by the supplied Release.
+func (s *vaultStore) delete(ctx context.Context, cr *v1beta1.Release, to *v1beta1.PublishConnectionDetailsTo) error {
+	p := path.Join(s.scope, to.Name)
+	current, err := s.client.Read(ctx, p)
+	if err != nil {
+		return errors.Wrap(err, errGetFromSecretStore)
+	}
+	if current == nil || current.Metadata[secretOwnerKey] != string(cr.GetUID()) {
+		return nil
+	}
+	return errors.Wrap(s.client.Delete(ctx, p), errDeleteFromSecretStore)
+}
+
+// A pluginStore stores connection details through an external secret store
+// plugin of Crossplane, below the path of its scope. Secrets record the UID
+// of the Release that owns them in their metadata.
+type pluginStore struct {
+	client ess.ExternalSecretStorePluginServiceClient
+	config *ess.ConfigReference
+	scope  string
+}
+
+// get returns the secret of the supplied scoped name, or nil if it does not
+// exist.
+func (s *pluginStore) get(ctx context.Context, name string) (*ess.Secret, error) {
+	resp, err := s.client.GetSecret(ctx, &ess.GetSecretRequest{Config: s.config, Secret: &ess.Secret{ScopedName: name}})
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Plugins return an empty secret rather than an error if it does not
+	// exist.
+	sec := resp.GetSecret()
+	if len(sec.GetData()) == 0 && len(sec.GetMetadata()) == 0 {
+		return nil, nil
+	}
+	return sec, nil
+}
+
+// write applies the connection details, unless the secret is owned by
+// another Release.
+func (s *pluginStore) write(ctx context.Context, cr *v1beta1.Release, to *v1beta1.PublishConnectionDetailsTo, cd managed.ConnectionDetails) error {
+	name := path.Join(s.scope, to.Name)
+	current, err := s.get(ctx, name)
+	if err != nil {
+		return errors.Wrap(err, errReadFromSecretStore)
+	}
+	if current != nil && current.GetMetadata()[secretOwnerKey] != string(cr.GetUID()) {
+		return errors.Errorf(errFmtSecretNotOwned, name)
+	}
+	_, err = s.client.ApplySecret(ctx, &ess.ApplySecretRequest{
+		Config: s.config,
+		Secret: &ess.Secret{ScopedName: name, Metadata: map[string]string{secretOwnerKey: string(cr.GetUID())}, Data: cd},
+	})
+	return err
+}
+
+// delete deletes the secret of the connection details, unless it is not
+// owned by the supplied Release.
+func (s *pluginStore) delete(ctx context.Context, cr *v1beta1.Release, to *v1beta1.PublishConnectionDetailsTo) error {
+	name := path.Join(s.scope, to.Name)
+	current, err := s.get(ctx, name)
+	if err != nil {
+		return errors.Wrap(err, errGetFromSecretStore)
+	}
+	if current == nil || current.GetMetadata()[secretOwnerKey] != string(cr.GetUID()) {
+		return nil
+	}
+	_, err = s.client.DeleteKeys(ctx, &ess.DeleteKeysRequest{Config: s.config, Secret: &ess.Secret{ScopedName: name}})
+	return errors.Wrap(err, errDeleteFromSecretStore)
+}
+
+func equalStringMaps(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,446 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+	helmv1beta1 "github.com/crossplane-contrib/provider-helm/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-helm/pkg/clients/ess"
+	"github.com/crossplane-contrib/provider-helm/pkg/clients/vault"
+)
+
+type mockVault struct {
+	data    map[string]*vault.Secret
+	writes  int
+	deleted []string
+}
+
+func (m *mockVault) Read(_ context.Context, path string) (*vault.Secret, error) {
+	return m.data[path], nil
+}
+
+func (m *mockVault) Write(_ context.Context, path string, s *vault.Secret) error {
+	m.writes++
+	m.data[path] = s
+	return nil
+}
+
+func (m *mockVault) Delete(_ context.Context, path string) error {
+	m.deleted = append(m.deleted, path)
+	delete(m.data, path)
+	return nil
+}
+
+func TestStorePublisher(t *testing.T) {
+	errBoom := errors.New("boom")
+	details := managed.ConnectionDetails{"password": []byte("12345")}
+	to := &v1beta1.PublishConnectionDetailsTo{Name: "db", SecretStoreConfigRef: &xpv1.Reference{Name: "store"}}
+	owned := &vault.Secret{Data: map[string]string{"password": "12345"}, Metadata: map[string]string{secretOwnerKey: "uid"}}
+	notOwned := &vault.Secret{Data: map[string]string{"password": "12345"}, Metadata: map[string]string{secretOwnerKey: "other"}}
+
+	storeConfig := func(sc helmv1beta1.StoreConfigSpec) func(obj client.Object) error {
+		return func(obj client.Object) error {
+			if s, ok := obj.(*helmv1beta1.StoreConfig); ok {
+				s.Spec = sc
+			}
+			return nil
+		}
+	}
+	vaultSpec := helmv1beta1.StoreConfigSpec{
+		Type:         helmv1beta1.SecretStoreVault,
+		DefaultScope: "crossplane",
+		Vault: &helmv1beta1.VaultConfig{
+			Server:    "https://vault",
+			MountPath: "secret",
+			Token:     helmv1beta1.VaultCredentials{Source: xpv1.CredentialsSourceSecret},
+		},
+	}
+
+	type want struct {
+		err       error
+		vault     map[string]*vault.Secret
+		writes    int
+		deleted   []string
+		published *corev1.Secret
+		removed   *corev1.Secret
+	}
+	cases := map[string]struct {
+		reason    string
+		to        *v1beta1.PublishConnectionDetailsTo
+		unpublish bool
+		details   managed.ConnectionDetails
+		existing  map[string]*vault.Secret
+		get       test.MockGetFn
+		want      want
+	}{
+		"NotConfigured": {
+			reason:  "Nothing should be published if no secret store is configured.",
+			details: details,
+			want:    want{vault: map[string]*vault.Secret{}},
+		},
+		"NoDetails": {
+			reason:  "Empty connection details should not replace published ones.",
+			to:      to,
+			details: managed.ConnectionDetails{},
+			want:    want{vault: map[string]*vault.Secret{}},
+		},
+		"StoreConfigNotFound": {
+			reason:  "Errors getting the StoreConfig should be returned.",
+			to:      to,
+			details: details,
+			get:     test.NewMockGetFn(errBoom),
+			want: want{
+				err:   errors.Wrap(errors.Wrap(errBoom, errGetStoreConfig), errPublishToSecretStore),
+				vault: map[string]*vault.Secret{},
+			},
+		},
+		"Kubernetes": {
+			reason:  "Connection details should be published to a Secret in the scope of a Kubernetes store.",
+			to:      &v1beta1.PublishConnectionDetailsTo{Name: "db", Metadata: &v1beta1.ConnectionSecretMetadata{Labels: map[string]string{"team": "a"}}},
+			details: details,
+			get: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+				if _, ok := obj.(*corev1.Secret); ok {
+					return kerrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, key.Name)
+				}
+				return storeConfig(helmv1beta1.StoreConfigSpec{DefaultScope: "crossplane-system"})(obj)
+			},
+			want: want{
+				vault: map[string]*vault.Secret{},
+				published: func() *corev1.Secret {
+					s := &corev1.Secret{Type: "connection.crossplane.io/v1alpha1", Data: details}
+					s.SetName("db")
+					s.SetNamespace("crossplane-system")
+					s.SetLabels(map[string]string{"team": "a"})
+					return s
+				}(),
+			},
+		},
+		"KubernetesUnpublish": {
+			reason:    "A Secret controlled by the Release should be deleted when unpublished.",
+			to:        &v1beta1.PublishConnectionDetailsTo{Name: "db"},
+			unpublish: true,
+			get: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+				if s, ok := obj.(*corev1.Secret); ok {
+					s.SetName("db")
+					s.SetNamespace("crossplane-system")
+					s.SetOwnerReferences([]metav1.OwnerReference{{UID: "uid", Controller: pointer.Bool(true)}})
+					return nil
+				}
+				return storeConfig(helmv1beta1.StoreConfigSpec{DefaultScope: "crossplane-system"})(obj)
+			},
+			want: want{
+				vault: map[string]*vault.Secret{},
+				removed: func() *corev1.Secret {
+					s := &corev1.Secret{}
+					s.SetName("db")
+					s.SetNamespace("crossplane-system")
+					s.SetOwnerReferences([]metav1.OwnerReference{{UID: "uid", Controller: pointer.Bool(true)}})
+					return s
+				}(),
+			},
+		},
+		"KubernetesUnpublishNotControlled": {
+			reason:    "A Secret not controlled by the Release should not be deleted when unpublished.",
+			to:        &v1beta1.PublishConnectionDetailsTo{Name: "db"},
+			unpublish: true,
+			get: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+				if s, ok := obj.(*corev1.Secret); ok {
+					s.SetOwnerReferences([]metav1.OwnerReference{{UID: "other", Controller: pointer.Bool(true)}})
+					return nil
+				}
+				return storeConfig(helmv1beta1.StoreConfigSpec{DefaultScope: "crossplane-system"})(obj)
+			},
+			want: want{vault: map[string]*vault.Secret{}},
+		},
+		"Vault": {
+			reason:  "Connection details should be written below the scope of a Vault store.",
+			to:      to,
+			details: details,
+			get:     test.NewMockGetFn(nil, storeConfig(vaultSpec)),
+			want: want{
+				vault:  map[string]*vault.Secret{"crossplane/db": owned},
+				writes: 1,
+			},
+		},
+		"VaultUnchanged": {
+			reason:   "Unchanged connection details should not be written to Vault again.",
+			to:       to,
+			details:  details,
+			existing: map[string]*vault.Secret{"crossplane/db": owned},
+			get:      test.NewMockGetFn(nil, storeConfig(vaultSpec)),
+			want: want{
+				vault: map[string]*vault.Secret{"crossplane/db": owned},
+			},
+		},
+		"VaultUnpublish": {
+			reason:    "Connection details should be deleted from Vault when unpublished.",
+			to:        to,
+			unpublish: true,
+			existing:  map[string]*vault.Secret{"crossplane/db": owned},
+			get:       test.NewMockGetFn(nil, storeConfig(vaultSpec)),
+			want: want{
+				vault:   map[string]*vault.Secret{},
+				deleted: []string{"crossplane/db"},
+			},
+		},
+		"VaultNotOwned": {
+			reason:   "A secret owned by another Release should not be overwritten.",
+			to:       to,
+			details:  details,
+			existing: map[string]*vault.Secret{"crossplane/db": notOwned},
+			get:      test.NewMockGetFn(nil, storeConfig(vaultSpec)),
+			want: want{
+				err:   errors.Wrap(errors.Errorf(errFmtSecretNotOwned, "crossplane/db"), errPublishToSecretStore),
+				vault: map[string]*vault.Secret{"crossplane/db": notOwned},
+			},
+		},
+		"VaultUnpublishNotOwned": {
+			reason:    "A secret owned by another Release should not be deleted when unpublished.",
+			to:        to,
+			unpublish: true,
+			existing:  map[string]*vault.Secret{"crossplane/db": notOwned},
+			get:       test.NewMockGetFn(nil, storeConfig(vaultSpec)),
+			want: want{
+				vault: map[string]*vault.Secret{"crossplane/db": notOwned},
+			},
+		},
+		"InvalidName": {
+			reason:  "Names that would escape the scope of the store should be rejected.",
+			to:      &v1beta1.PublishConnectionDetailsTo{Name: "../db"},
+			details: details,
+			get:     test.NewMockGetFn(nil, storeConfig(vaultSpec)),
+			want: want{
+				err:   errors.Wrap(errors.Errorf(errFmtInvalidSecretName, "../db"), errPublishToSecretStore),
+				vault: map[string]*vault.Secret{},
+			},
+		},
+		"InvalidNameUnpublish": {
+			reason:    "Names that would escape the scope of the store should be rejected when unpublished.",
+			to:        &v1beta1.PublishConnectionDetailsTo{Name: ".."},
+			unpublish: true,
+			get:       test.NewMockGetFn(nil, storeConfig(vaultSpec)),
+			want: want{
+				err:   errors.Wrap(errors.Errorf(errFmtInvalidSecretName, ".."), errUnpublishSecretStore),
+				vault: map[string]*vault.Secret{},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var published, removed *corev1.Secret
+			kube := &test.MockClient{
+				MockGet: tc.get,
+				MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+					published = obj.(*corev1.Secret)
+					return nil
+				},
+				MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+					removed = obj.(*corev1.Secret)
+					return nil
+				},
+			}
+			vc := &mockVault{data: map[string]*vault.Secret{}}
+			for k, v := range tc.existing {
+				vc.data[k] = v
+			}
+			p := &storePublisher{
+				kube: kube,
+				extractorFn: func(_ context.Context, _ xpv1.CredentialsSource, _ client.Client, _ xpv1.CommonCredentialSelectors) ([]byte, error) {
+					return []byte("s.token\n"), nil
+				},
+				newVaultFn: func(_, _ string, _ vault.KVVersion, token string, _ []byte) (vaultClient, error) {
+					if token != "s.token" {
+						return nil, errors.New("unexpected token")
+					}
+					return vc, nil
+				},
+			}
+			cr := &v1beta1.Release{ObjectMeta: metav1.ObjectMeta{UID: "uid"}, Spec: v1beta1.ReleaseSpec{PublishConnectionDetailsTo: tc.to}}
+
+			var err error
+			if tc.unpublish {
+				err = p.UnpublishConnection(context.Background(), cr, nil)
+			} else {
+				err = p.PublishConnection(context.Background(), cr, tc.details)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\n-want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.vault, vc.data); diff != "" {
+				t.Errorf("\n%s\n-want vault data, +got vault data:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.writes, vc.writes); diff != "" {
+				t.Errorf("\n%s\n-want vault writes, +got vault writes:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.deleted, vc.deleted); diff != "" {
+				t.Errorf("\n%s\n-want deleted, +got deleted:\n%s", tc.reason, diff)
+			}
+			if published != nil {
+				published.OwnerReferences = nil
+			}
+			if diff := cmp.Diff(tc.want.published, published); diff != "" {
+				t.Errorf("\n%s\n-want secret, +got secret:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.removed, removed); diff != "" {
+				t.Errorf("\n%s\n-want deleted secret, +got deleted secret:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+type mockPlugin struct {
+	secrets map[string]*ess.Secret
+	applied []string
+	deleted []string
+}
+
+func (m *mockPlugin) GetSecret(_ context.Context, in *ess.GetSecretRequest, _ ...grpc.CallOption) (*ess.GetSecretResponse, error) {
+	s, ok := m.secrets[in.GetSecret().GetScopedName()]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "secret not found")
+	}
+	return &ess.GetSecretResponse{Secret: s}, nil
+}
+
+func (m *mockPlugin) ApplySecret(_ context.Context, in *ess.ApplySecretRequest, _ ...grpc.CallOption) (*ess.ApplySecretResponse, error) {
+	m.applied = append(m.applied, in.GetSecret().GetScopedName())
+	m.secrets[in.GetSecret().GetScopedName()] = in.GetSecret()
+	return &ess.ApplySecretResponse{Changed: true}, nil
+}
+
+func (m *mockPlugin) DeleteKeys(_ context.Context, in *ess.DeleteKeysRequest, _ ...grpc.CallOption) (*ess.DeleteKeysResponse, error) {
+	m.deleted = append(m.deleted, in.GetSecret().GetScopedName())
+	delete(m.secrets, in.GetSecret().GetScopedName())
+	return &ess.DeleteKeysResponse{}, nil
+}
+
+func TestPluginStore(t *testing.T) {
+	details := managed.ConnectionDetails{"password": []byte("12345")}
+	to := &v1beta1.PublishConnectionDetailsTo{Name: "db"}
+	owned := &ess.Secret{ScopedName: "crossplane/db", Metadata: map[string]string{secretOwnerKey: "uid"}, Data: details}
+	notOwned := &ess.Secret{ScopedName: "crossplane/db", Metadata: map[string]string{secretOwnerKey: "other"}, Data: details}
+
+	type want struct {
+		err     error
+		applied []string
+		deleted []string
+	}
+	cases := map[string]struct {
+		reason    string
+		unpublish bool
+		existing  *ess.Secret
+		want      want
+	}{
+		"Apply": {
+			reason: "Connection details should be applied below the scope of a Plugin store.",
+			want:   want{applied: []string{"crossplane/db"}},
+		},
+		"ApplyOwned": {
+			reason:   "A secret owned by the Release should be updated.",
+			existing: owned,
+			want:     want{applied: []string{"crossplane/db"}},
+		},
+		"ApplyNotOwned": {
+			reason:   "A secret owned by another Release should not be overwritten.",
+			existing: notOwned,
+			want: want{
+				err: errors.Wrap(errors.Errorf(errFmtSecretNotOwned, "crossplane/db"), errPublishToSecretStore),
+			},
+		},
+		"Delete": {
+			reason:    "A secret owned by the Release should be deleted when unpublished.",
+			unpublish: true,
+			existing:  owned,
+			want:      want{deleted: []string{"crossplane/db"}},
+		},
+		"DeleteNotOwned": {
+			reason:    "A secret owned by another Release should not be deleted when unpublished.",
+			unpublish: true,
+			existing:  notOwned,
+		},
+		"DeleteMissing": {
+			reason:    "Unpublishing a secret that does not exist should be a no-op.",
+			unpublish: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pc := &mockPlugin{secrets: map[string]*ess.Secret{}}
+			if tc.existing != nil {
+				pc.secrets[tc.existing.ScopedName] = tc.existing
+			}
+			var dialed string
+			p := &storePublisher{
+				kube: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					obj.(*helmv1beta1.StoreConfig).Spec = helmv1beta1.StoreConfigSpec{
+						Type:         helmv1beta1.SecretStorePlugin,
+						DefaultScope: "crossplane",
+						Plugin: &helmv1beta1.PluginStoreConfig{
+							Endpoint:  "ess-plugin-vault:4040",
+							ConfigRef: helmv1beta1.PluginConfigReference{APIVersion: "secrets.crossplane.io/v1alpha1", Kind: "VaultConfig", Name: "vault"},
+						},
+					}
+					return nil
+				})},
+				dialPluginFn: func(endpoint string) (ess.ExternalSecretStorePluginServiceClient, error) {
+					dialed = endpoint
+					return pc, nil
+				},
+				plugins: map[string]ess.ExternalSecretStorePluginServiceClient{},
+			}
+			cr := &v1beta1.Release{ObjectMeta: metav1.ObjectMeta{UID: "uid"}, Spec: v1beta1.ReleaseSpec{PublishConnectionDetailsTo: to}}
+
+			var err error
+			if tc.unpublish {
+				err = p.UnpublishConnection(context.Background(), cr, nil)
+			} else {
+				err = p.PublishConnection(context.Background(), cr, details)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\n-want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff("ess-plugin-vault:4040", dialed); diff != "" {
+				t.Errorf("\n%s\n-want endpoint, +got endpoint:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.applied, pc.applied); diff != "" {
+				t.Errorf("\n%s\n-want applied, +got applied:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.deleted, pc.deleted); diff != "" {
+				t.Errorf("\n%s\n-want deleted, +got deleted:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"sort"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	ktype "sigs.k8s.io/kustomize/api/types"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+// setReleaseConditions sets the Released and, if the chart has test hooks,
+// the Tested conditions of the Release from the supplied release.
+func setReleaseConditions(cr *v1beta1.Release, rel *release.Release) {
+	if rel.Info == nil {
+		return
+	}
+	cr.Status.SetConditions(releasedCondition(rel))
+	if c, ok := testedCondition(rel); ok {
+		cr.Status.SetConditions(c)
+	}
+}
+
+// released returns a deploy action that sets the Released condition of the
+// Release to the condition returned by the supplied function if the supplied
+// action fails. Failures to resolve the chart or values, or of the checks
+// before deploying, are reported by their own conditions instead.
+func released(cr *v1beta1.Release, failed func(error) xpv1.Condition, action deployAction) deployAction {
+	return func(rel string, ch *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (*release.Release, error) {
+		r, err := action(rel, ch, vals, patches)
+		if err != nil {
+			cr.Status.SetConditions(failed(err))
+		}
+		return r, err
+	}
+}
+
+func releasedCondition(rel *release.Release) xpv1.Condition {
+	switch s := rel.Info.Status; s {
+	case release.StatusDeployed:
+		return v1beta1.ReleaseDeployed(rel.Version)
+	case release.StatusFailed:
+		return v1beta1.ReleaseFailed(rel.Version, rel.Info.Description)
+	default:
+		return v1beta1.ReleasePending(rel.Version, s.String())
+	}
+}
+
+// testedCondition returns the Tested condition of the supplied release, and
+// false if its chart has no test hooks. Test hooks are not run by the
+// provider; their outcome is reported once they were run, e.g. by helm test.
+func testedCondition(rel *release.Release) (xpv1.Condition, bool) {
+	tests, notRun := 0, false
+	var failed []string
+	for _, h := range rel.Hooks {
+		if !isTestHook(h) {
+			continue
+		}
+		tests++
+		switch h.LastRun.Phase {
+		case release.HookPhaseSucceeded:
+		case release.HookPhaseFailed:
+			failed = append(failed, h.Name)
+		default:
+			notRun = true
+		}
+	}
+	switch {
+	case tests == 0:
+		return xpv1.Condition{}, false
+	case len(failed) > 0:
+		sort.Strings(failed)
+		return v1beta1.TestsFailed(failed), true
+	case notRun:
+		return v1beta1.TestsNotRun(), true
+	default:
+		return v1beta1.TestsPassed(), true
+	}
+}
+
+func isTestHook(h *release.Hook) bool {
+	for _, e := range h.Events {
+		if e == release.HookTest {
+			return true
+		}
+	}
+	return false
+}
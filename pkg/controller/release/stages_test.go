@@ -0,0 +1,118 @@
+package release
+
+import (
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	ktype "sigs.k8s.io/kustomize/api/types"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+func testHook(name string, phase release.HookPhase, events ...release.HookEvent) *release.Hook {
+	return &release.Hook{Name: name, Events: events, LastRun: release.HookExecution{Phase: phase}}
+}
+
+func Test_releasedCondition(t *testing.T) {
+	cases := map[string]struct {
+		rel  *release.Release
+		want xpv1.Condition
+	}{
+		"Deployed": {
+			rel:  &release.Release{Version: 2, Info: &release.Info{Status: release.StatusDeployed}},
+			want: v1beta1.ReleaseDeployed(2),
+		},
+		"Failed": {
+			rel:  &release.Release{Version: 3, Info: &release.Info{Status: release.StatusFailed, Description: "timed out"}},
+			want: v1beta1.ReleaseFailed(3, "timed out"),
+		},
+		"Pending": {
+			rel:  &release.Release{Version: 4, Info: &release.Info{Status: release.StatusPendingUpgrade}},
+			want: v1beta1.ReleasePending(4, "pending-upgrade"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := releasedCondition(tc.rel)
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("releasedCondition(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_testedCondition(t *testing.T) {
+	type want struct {
+		c  xpv1.Condition
+		ok bool
+	}
+	cases := map[string]struct {
+		hooks []*release.Hook
+		want  want
+	}{
+		"NoTestHooks": {
+			hooks: []*release.Hook{testHook("migrate", release.HookPhaseSucceeded, release.HookPreUpgrade)},
+		},
+		"Passed": {
+			hooks: []*release.Hook{testHook("test-connection", release.HookPhaseSucceeded, release.HookTest)},
+			want:  want{c: v1beta1.TestsPassed(), ok: true},
+		},
+		"Failed": {
+			hooks: []*release.Hook{
+				testHook("test-b", release.HookPhaseFailed, release.HookTest),
+				testHook("test-a", release.HookPhaseFailed, release.HookTest),
+				testHook("test-c", release.HookPhaseUnknown, release.HookTest),
+			},
+			want: want{c: v1beta1.TestsFailed([]string{"test-a", "test-b"}), ok: true},
+		},
+		"NotRun": {
+			hooks: []*release.Hook{
+				testHook("test-a", release.HookPhaseSucceeded, release.HookTest),
+				testHook("test-b", release.HookPhaseUnknown, release.HookTest),
+			},
+			want: want{c: v1beta1.TestsNotRun(), ok: true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, ok := testedCondition(&release.Release{Hooks: tc.hooks})
+			if diff := cmp.Diff(tc.want.ok, ok); diff != "" {
+				t.Errorf("testedCondition(...): -want ok, +got ok: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.c, c, cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("testedCondition(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_released(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want []xpv1.Condition
+	}{
+		"Succeeded": {},
+		"Failed": {
+			err:  errBoom,
+			want: []xpv1.Condition{v1beta1.UpgradeFailed(errBoom)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1beta1.Release{}
+			action := func(_ string, _ *chart.Chart, _ map[string]interface{}, _ []ktype.Patch) (*release.Release, error) {
+				return nil, tc.err
+			}
+			if _, err := released(cr, v1beta1.UpgradeFailed, action)("", nil, nil, nil); err != tc.err {
+				t.Errorf("released(...): want error %v, got %v", tc.err, err)
+			}
+			if diff := cmp.Diff(tc.want, cr.Status.Conditions, cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("released(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
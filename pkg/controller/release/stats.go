@@ -0,0 +1,144 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	helmv1beta1 "github.com/crossplane-contrib/provider-helm/apis/v1beta1"
+)
+
+// The failure rate of a ProviderConfig is computed from at most this many of
+// its most recent operations.
+const operationStatsWindow = 100
+
+// operationStats track the outcome of the recent operations of the Releases
+// of each ProviderConfig. A nil operationStats tracks nothing.
+type operationStats struct {
+	window int
+	now    func() time.Time
+
+	mu      sync.Mutex
+	configs map[string]*configStats
+}
+
+type configStats struct {
+	lastSucceeded time.Time
+	lastFailed    time.Time
+	// failed is a ring buffer of the outcomes of recent operations.
+	failed []bool
+	next   int
+}
+
+func newOperationStats() *operationStats {
+	return &operationStats{window: operationStatsWindow, now: time.Now, configs: map[string]*configStats{}}
+}
+
+// record the outcome of an operation of a Release of the supplied
+// ProviderConfig.
+func (s *operationStats) record(providerConfig string, err error) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cs, ok := s.configs[providerConfig]
+	if !ok {
+		cs = &configStats{}
+		s.configs[providerConfig] = cs
+	}
+	if err != nil {
+		cs.lastFailed = s.now()
+	} else {
+		cs.lastSucceeded = s.now()
+	}
+	if len(cs.failed) < s.window {
+		cs.failed = append(cs.failed, err != nil)
+		return
+	}
+	cs.failed[cs.next] = err != nil
+	cs.next = (cs.next + 1) % s.window
+}
+
+// get the statistics of the supplied ProviderConfig, or nil if none of its
+// operations were recorded.
+func (s *operationStats) get(providerConfig string) *helmv1beta1.OperationStatistics {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cs, ok := s.configs[providerConfig]
+	if !ok {
+		return nil
+	}
+	st := &helmv1beta1.OperationStatistics{RecentOperations: int32(len(cs.failed))}
+	for _, f := range cs.failed {
+		if f {
+			st.RecentFailures++
+		}
+	}
+	if st.RecentOperations > 0 {
+		st.RecentFailurePercent = st.RecentFailures * 100 / st.RecentOperations
+	}
+	if !cs.lastSucceeded.IsZero() {
+		t := metav1.NewTime(cs.lastSucceeded)
+		st.LastSucceededTime = &t
+	}
+	if !cs.lastFailed.IsZero() {
+		t := metav1.NewTime(cs.lastFailed)
+		st.LastFailedTime = &t
+	}
+	return st
+}
+
+// A statsRecorder records the outcome of the install, upgrade and uninstall
+// operations of the wrapped client.
+type statsRecorder struct {
+	managed.ExternalClient
+	providerConfig string
+	stats          *operationStats
+}
+
+func (r *statsRecorder) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	c, err := r.ExternalClient.Create(ctx, mg)
+	r.stats.record(r.providerConfig, err)
+	return c, err
+}
+
+func (r *statsRecorder) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	u, err := r.ExternalClient.Update(ctx, mg)
+	r.stats.record(r.providerConfig, err)
+	return u, err
+}
+
+func (r *statsRecorder) Delete(ctx context.Context, mg resource.Managed) error {
+	err := r.ExternalClient.Delete(ctx, mg)
+	r.stats.record(r.providerConfig, err)
+	return err
+}
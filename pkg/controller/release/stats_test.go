@@ -0,0 +1,131 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	helmv1beta1 "github.com/crossplane-contrib/provider-helm/apis/v1beta1"
+)
+
+func TestOperationStats(t *testing.T) {
+	errBoom := errors.New("boom")
+	start := time.Unix(0, 0)
+	at := func(s int) *metav1.Time {
+		t := metav1.NewTime(start.Add(time.Duration(s) * time.Second))
+		return &t
+	}
+
+	cases := map[string]struct {
+		reason   string
+		outcomes []error
+		want     *helmv1beta1.OperationStatistics
+	}{
+		"NoOperations": {
+			reason: "No statistics should be returned if no operations were recorded.",
+		},
+		"Mixed": {
+			reason:   "Successes and failures should be counted separately.",
+			outcomes: []error{nil, errBoom, nil, errBoom},
+			want: &helmv1beta1.OperationStatistics{
+				LastSucceededTime:    at(2),
+				LastFailedTime:       at(3),
+				RecentOperations:     4,
+				RecentFailures:       2,
+				RecentFailurePercent: 50,
+			},
+		},
+		"Window": {
+			reason:   "Only the most recent operations should count towards the failure rate.",
+			outcomes: []error{errBoom, errBoom, nil, nil, errBoom, nil},
+			want: &helmv1beta1.OperationStatistics{
+				LastSucceededTime:    at(5),
+				LastFailedTime:       at(4),
+				RecentOperations:     4,
+				RecentFailures:       1,
+				RecentFailurePercent: 25,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			now := start
+			s := newOperationStats()
+			s.window = 4
+			s.now = func() time.Time { return now }
+			for i, err := range tc.outcomes {
+				now = start.Add(time.Duration(i) * time.Second)
+				s.record("a", err)
+			}
+			s.record("b", errBoom)
+
+			if diff := cmp.Diff(tc.want, s.get("a")); diff != "" {
+				t.Errorf("\n%s\ns.get(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNilOperationStats(t *testing.T) {
+	var s *operationStats
+	s.record("a", nil)
+	if got := s.get("a"); got != nil {
+		t.Errorf("s.get(...): a nil tracker should not return statistics, got %v", got)
+	}
+}
+
+type mockExternal struct {
+	managed.ExternalClient
+	err error
+}
+
+func (m *mockExternal) Create(_ context.Context, _ resource.Managed) (managed.ExternalCreation, error) {
+	return managed.ExternalCreation{}, m.err
+}
+
+func (m *mockExternal) Update(_ context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+	return managed.ExternalUpdate{}, m.err
+}
+
+func (m *mockExternal) Delete(_ context.Context, _ resource.Managed) error {
+	return m.err
+}
+
+func TestStatsRecorder(t *testing.T) {
+	s := newOperationStats()
+	ok := &statsRecorder{ExternalClient: &mockExternal{}, providerConfig: "a", stats: s}
+	failing := &statsRecorder{ExternalClient: &mockExternal{err: errors.New("boom")}, providerConfig: "a", stats: s}
+
+	_, _ = ok.Create(context.Background(), nil)
+	_, _ = ok.Update(context.Background(), nil)
+	_ = failing.Delete(context.Background(), nil)
+
+	got := s.get("a")
+	if diff := cmp.Diff([]int32{3, 1, 33}, []int32{got.RecentOperations, got.RecentFailures, got.RecentFailurePercent}); diff != "" {
+		t.Errorf("statsRecorder: -want, +got:\n%s", diff)
+	}
+}
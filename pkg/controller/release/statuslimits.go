@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"unicode/utf8"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	defaultMaxStatusResources   = 250
+	defaultMaxStatusNotesLength = 4096
+)
+
+// limitStatus omits the details of the manifest from the status of the
+// supplied Release that exceed its status limits, and reports what was
+// omitted.
+func limitStatus(cr *v1beta1.Release) {
+	maxResources, maxNotes := defaultMaxStatusResources, defaultMaxStatusNotesLength
+	if l := cr.Spec.ForProvider.StatusLimits; l != nil {
+		if l.MaxResources != nil {
+			maxResources = int(*l.MaxResources)
+		}
+		if l.MaxNotesLength != nil {
+			maxNotes = int(*l.MaxNotesLength)
+		}
+	}
+
+	o := &cr.Status.AtProvider
+	t := &v1beta1.StatusTruncation{}
+	if len(o.Resources) > maxResources {
+		t.OmittedResources = len(o.Resources) - maxResources
+		o.Resources = o.Resources[:maxResources]
+		if len(o.Resources) == 0 {
+			o.Resources = nil
+		}
+	}
+	if len(o.Notes) > maxNotes {
+		t.NotesTruncated = true
+		o.Notes = truncateUTF8(o.Notes, maxNotes)
+	}
+
+	o.Truncated = nil
+	if t.OmittedResources == 0 && !t.NotesTruncated {
+		return
+	}
+	if mo := cr.Spec.ForProvider.ManifestOutput; mo != nil {
+		t.ManifestRef = mo.DeepCopy()
+	}
+	o.Truncated = t
+}
+
+// truncateUTF8 truncates the supplied string to at most n bytes without
+// splitting a multi-byte character.
+func truncateUTF8(s string, n int) string {
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+func Test_limitStatus(t *testing.T) {
+	zero, one, four := int32(0), int32(1), int32(4)
+	resources := []v1beta1.ResourceRef{
+		{APIVersion: "v1", Kind: "ConfigMap", Name: "a"},
+		{APIVersion: "v1", Kind: "ConfigMap", Name: "b"},
+	}
+	out := &v1beta1.ManifestOutput{Kind: v1beta1.ManifestOutputKindConfigMap, Name: "manifest", Namespace: "default"}
+
+	cases := map[string]struct {
+		limits *v1beta1.StatusLimits
+		output *v1beta1.ManifestOutput
+		in     v1beta1.ReleaseObservation
+		want   v1beta1.ReleaseObservation
+	}{
+		"WithinDefaultLimits": {
+			in:   v1beta1.ReleaseObservation{Notes: "notes", Resources: resources},
+			want: v1beta1.ReleaseObservation{Notes: "notes", Resources: resources},
+		},
+		"DefaultNotesLimit": {
+			in: v1beta1.ReleaseObservation{Notes: strings.Repeat("n", defaultMaxStatusNotesLength+1)},
+			want: v1beta1.ReleaseObservation{
+				Notes:     strings.Repeat("n", defaultMaxStatusNotesLength),
+				Truncated: &v1beta1.StatusTruncation{NotesTruncated: true},
+			},
+		},
+		"ResourcesOmitted": {
+			limits: &v1beta1.StatusLimits{MaxResources: &one},
+			output: out,
+			in:     v1beta1.ReleaseObservation{Resources: resources},
+			want: v1beta1.ReleaseObservation{
+				Resources: resources[:1],
+				Truncated: &v1beta1.StatusTruncation{OmittedResources: 1, ManifestRef: out},
+			},
+		},
+		"Disabled": {
+			limits: &v1beta1.StatusLimits{MaxResources: &zero, MaxNotesLength: &zero},
+			in:     v1beta1.ReleaseObservation{Notes: "notes", Resources: resources},
+			want: v1beta1.ReleaseObservation{
+				Truncated: &v1beta1.StatusTruncation{OmittedResources: 2, NotesTruncated: true},
+			},
+		},
+		"MultiByteNotes": {
+			limits: &v1beta1.StatusLimits{MaxNotesLength: &four},
+			in:     v1beta1.ReleaseObservation{Notes: "abcé"},
+			want: v1beta1.ReleaseObservation{
+				Notes:     "abc",
+				Truncated: &v1beta1.StatusTruncation{NotesTruncated: true},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1beta1.Release{}
+			cr.Spec.ForProvider.StatusLimits = tc.limits
+			cr.Spec.ForProvider.ManifestOutput = tc.output
+			cr.Status.AtProvider = tc.in
+			limitStatus(cr)
+			if diff := cmp.Diff(tc.want, cr.Status.AtProvider); diff != "" {
+				t.Errorf("limitStatus(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_truncateUTF8(t *testing.T) {
+	cases := map[string]struct {
+		s    string
+		n    int
+		want string
+	}{
+		"ASCII": {
+			s:    "abcdef",
+			n:    3,
+			want: "abc",
+		},
+		"RuneBoundary": {
+			s:    "aäb",
+			n:    3,
+			want: "aä",
+		},
+		"WithinRune": {
+			s:    "aäb",
+			n:    2,
+			want: "a",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, truncateUTF8(tc.s, tc.n)); diff != "" {
+				t.Errorf("truncateUTF8(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
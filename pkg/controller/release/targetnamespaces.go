@@ -0,0 +1,130 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ktype "sigs.k8s.io/kustomize/api/types"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+	helmv1beta1 "github.com/crossplane-contrib/provider-helm/apis/v1beta1"
+)
+
+// labelKeyClaimNamespace is the label Crossplane records the namespace of
+// the claim a resource was composed for in.
+const labelKeyClaimNamespace = "crossplane.io/claim-namespace"
+
+const (
+	errFmtTargetNamespaceNotAllowed = "namespace %q is not allowed by ProviderConfig %q"
+	errFmtNotClaimNamespace         = "namespace %q is not the namespace of the claim of the Release, which ProviderConfig %q requires"
+	errFmtClusterScopedNotAllowed   = "cluster-scoped %s %q is not allowed by ProviderConfig %q"
+	errFailedToRenderForNamespaces  = "failed to render release for target namespace check"
+)
+
+// targetNamespaceAllowed returns an error if the supplied ProviderConfig
+// does not allow the supplied Release to install into its namespace.
+func targetNamespaceAllowed(pc *helmv1beta1.ProviderConfig, cr *v1beta1.Release) error {
+	return namespaceAllowed(pc.Spec.TargetNamespaces, pc.GetName(), cr, cr.Spec.ForProvider.Namespace)
+}
+
+// namespaceAllowed returns an error if the supplied target namespaces of the
+// named ProviderConfig don't allow the supplied Release to install resources
+// into the supplied namespace.
+func namespaceAllowed(t *helmv1beta1.TargetNamespaces, pc string, cr *v1beta1.Release, ns string) error {
+	if t == nil {
+		return nil
+	}
+	if len(t.Allowed) > 0 && !matchesAny(ns, t.Allowed) {
+		return errors.Errorf(errFmtTargetNamespaceNotAllowed, ns, pc)
+	}
+	if t.ClaimNamespaceOnly && cr.GetLabels()[labelKeyClaimNamespace] != ns {
+		return errors.Errorf(errFmtNotClaimNamespace, ns, pc)
+	}
+	return nil
+}
+
+// namespacesChecked returns a deployAction that checks the namespaces of all
+// rendered resources, including hooks, against the target namespaces of the
+// ProviderConfig before running the supplied action, as charts may install
+// resources into namespaces other than that of the release.
+func (e *helmExternal) namespacesChecked(cr *v1beta1.Release, template, action deployAction) deployAction {
+	return func(rel string, ch *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (*release.Release, error) {
+		r, err := template(rel, ch, vals, patches)
+		if err != nil {
+			return nil, errors.Wrap(err, errFailedToRenderForNamespaces)
+		}
+		objs, err := parseManifest(r.Manifest)
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range r.Hooks {
+			ho, err := parseManifest(h.Manifest)
+			if err != nil {
+				return nil, err
+			}
+			objs = append(objs, ho...)
+		}
+		if err := checkNamespaces(e.kube.RESTMapper(), e.targetNamespaces, e.providerConfig, cr, objs); err != nil {
+			return nil, err
+		}
+		return action(rel, ch, vals, patches)
+	}
+}
+
+// checkNamespaces returns an error if the supplied target namespaces of the
+// named ProviderConfig don't allow the namespace of any of the supplied
+// resources of the supplied Release. Cluster-scoped resources are only
+// allowed if the target namespaces allow them. Resources of kinds defined by
+// a CRD in the same manifest are assumed to be namespaced if they specify a
+// namespace, and cluster-scoped otherwise, as long as the CRD does not exist
+// yet.
+func checkNamespaces(mapper meta.RESTMapper, t *helmv1beta1.TargetNamespaces, pc string, cr *v1beta1.Release, objs []unstructured.Unstructured) error {
+	if t == nil {
+		return nil
+	}
+	crds := manifestCRDs(objs)
+	for _, o := range objs {
+		gvk := o.GroupVersionKind()
+		namespaced := o.GetNamespace() != ""
+		m, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		switch {
+		case meta.IsNoMatchError(err) && crds[gvk.GroupKind()]:
+		case err != nil:
+			return errors.Wrap(err, errFailedToMapResource)
+		default:
+			namespaced = m.Scope.Name() == meta.RESTScopeNameNamespace
+		}
+		if !namespaced {
+			if !t.AllowClusterScoped {
+				return errors.Errorf(errFmtClusterScopedNotAllowed, gvk.Kind, o.GetName(), pc)
+			}
+			continue
+		}
+		ns := o.GetNamespace()
+		if ns == "" {
+			ns = cr.Spec.ForProvider.Namespace
+		}
+		if err := namespaceAllowed(t, pc, cr, ns); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+	helmv1beta1 "github.com/crossplane-contrib/provider-helm/apis/v1beta1"
+)
+
+func Test_targetNamespaceAllowed(t *testing.T) {
+	release := func(ns string, labels map[string]string) *v1beta1.Release {
+		return &v1beta1.Release{
+			ObjectMeta: metav1.ObjectMeta{Name: "wordpress", Labels: labels},
+			Spec:       v1beta1.ReleaseSpec{ForProvider: v1beta1.ReleaseParameters{Namespace: ns}},
+		}
+	}
+	pc := func(t *helmv1beta1.TargetNamespaces) *helmv1beta1.ProviderConfig {
+		return &helmv1beta1.ProviderConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+			Spec:       helmv1beta1.ProviderConfigSpec{TargetNamespaces: t},
+		}
+	}
+
+	cases := map[string]struct {
+		pc   *helmv1beta1.ProviderConfig
+		cr   *v1beta1.Release
+		want error
+	}{
+		"Unrestricted": {
+			pc: pc(nil),
+			cr: release("kube-system", nil),
+		},
+		"Allowed": {
+			pc: pc(&helmv1beta1.TargetNamespaces{Allowed: []string{"team-a-*"}}),
+			cr: release("team-a-web", nil),
+		},
+		"NotAllowed": {
+			pc:   pc(&helmv1beta1.TargetNamespaces{Allowed: []string{"team-a-*"}}),
+			cr:   release("kube-system", nil),
+			want: errors.Errorf(errFmtTargetNamespaceNotAllowed, "kube-system", "team-a"),
+		},
+		"ClaimNamespace": {
+			pc: pc(&helmv1beta1.TargetNamespaces{ClaimNamespaceOnly: true}),
+			cr: release("team-a-web", map[string]string{labelKeyClaimNamespace: "team-a-web"}),
+		},
+		"NotClaimNamespace": {
+			pc:   pc(&helmv1beta1.TargetNamespaces{ClaimNamespaceOnly: true}),
+			cr:   release("team-b-web", map[string]string{labelKeyClaimNamespace: "team-a-web"}),
+			want: errors.Errorf(errFmtNotClaimNamespace, "team-b-web", "team-a"),
+		},
+		"NoClaim": {
+			pc:   pc(&helmv1beta1.TargetNamespaces{ClaimNamespaceOnly: true}),
+			cr:   release("team-a-web", nil),
+			want: errors.Errorf(errFmtNotClaimNamespace, "team-a-web", "team-a"),
+		},
+		"ClaimNamespaceNotAllowed": {
+			pc:   pc(&helmv1beta1.TargetNamespaces{Allowed: []string{"team-a-*"}, ClaimNamespaceOnly: true}),
+			cr:   release("kube-system", map[string]string{labelKeyClaimNamespace: "kube-system"}),
+			want: errors.Errorf(errFmtTargetNamespaceNotAllowed, "kube-system", "team-a"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := targetNamespaceAllowed(tc.pc, tc.cr)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("targetNamespaceAllowed(...): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_checkNamespaces(t *testing.T) {
+	obj := func(apiVersion, kind, ns, name string) unstructured.Unstructured {
+		u := unstructured.Unstructured{}
+		u.SetAPIVersion(apiVersion)
+		u.SetKind(kind)
+		u.SetNamespace(ns)
+		u.SetName(name)
+		return u
+	}
+	crd := obj("apiextensions.k8s.io/v1", "CustomResourceDefinition", "", "widgets.example.org")
+	_ = unstructured.SetNestedField(crd.Object, "example.org", "spec", "group")
+	_ = unstructured.SetNestedField(crd.Object, "Widget", "spec", "names", "kind")
+	cr := &v1beta1.Release{
+		ObjectMeta: metav1.ObjectMeta{Name: "wordpress", Labels: map[string]string{labelKeyClaimNamespace: "team-a-web"}},
+		Spec:       v1beta1.ReleaseSpec{ForProvider: v1beta1.ReleaseParameters{Namespace: "team-a-web"}},
+	}
+
+	type args struct {
+		t    *helmv1beta1.TargetNamespaces
+		objs []unstructured.Unstructured
+	}
+	cases := map[string]struct {
+		args
+		want error
+	}{
+		"Unrestricted": {
+			args: args{
+				objs: []unstructured.Unstructured{obj("v1", "ConfigMap", "kube-system", "cm"), crd},
+			},
+		},
+		"Allowed": {
+			args: args{
+				t:    &helmv1beta1.TargetNamespaces{Allowed: []string{"team-a-*"}},
+				objs: []unstructured.Unstructured{obj("v1", "ConfigMap", "", "cm"), obj("apps/v1", "Deployment", "team-a-db", "d")},
+			},
+		},
+		"OtherNamespaceNotAllowed": {
+			args: args{
+				t:    &helmv1beta1.TargetNamespaces{Allowed: []string{"team-a-*"}},
+				objs: []unstructured.Unstructured{obj("v1", "ConfigMap", "", "cm"), obj("apps/v1", "Deployment", "kube-system", "d")},
+			},
+			want: errors.Errorf(errFmtTargetNamespaceNotAllowed, "kube-system", "team-a"),
+		},
+		"OtherThanClaimNamespace": {
+			args: args{
+				t:    &helmv1beta1.TargetNamespaces{ClaimNamespaceOnly: true},
+				objs: []unstructured.Unstructured{obj("v1", "ConfigMap", "team-b-web", "cm")},
+			},
+			want: errors.Errorf(errFmtNotClaimNamespace, "team-b-web", "team-a"),
+		},
+		"ClusterScopedNotAllowed": {
+			args: args{
+				t:    &helmv1beta1.TargetNamespaces{Allowed: []string{"team-a-*"}},
+				objs: []unstructured.Unstructured{crd},
+			},
+			want: errors.Errorf(errFmtClusterScopedNotAllowed, "CustomResourceDefinition", "widgets.example.org", "team-a"),
+		},
+		"ClusterScopedAllowed": {
+			args: args{
+				t:    &helmv1beta1.TargetNamespaces{Allowed: []string{"team-a-*"}, AllowClusterScoped: true},
+				objs: []unstructured.Unstructured{crd, obj("example.org/v1", "Widget", "", "w")},
+			},
+		},
+		"KindOfCRDInOtherNamespace": {
+			args: args{
+				t:    &helmv1beta1.TargetNamespaces{Allowed: []string{"team-a-*"}, AllowClusterScoped: true},
+				objs: []unstructured.Unstructured{crd, obj("example.org/v1", "Widget", "kube-system", "w")},
+			},
+			want: errors.Errorf(errFmtTargetNamespaceNotAllowed, "kube-system", "team-a"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := checkNamespaces(testRESTMapper(), tc.args.t, "team-a", cr, tc.args.objs)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("checkNamespaces(...): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
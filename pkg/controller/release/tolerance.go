@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+// failureRetryInterval is the interval at which a Release is observed again
+// after a tolerated failure.
+const failureRetryInterval = 30 * time.Second
+
+// tolerate suppresses the supplied failure to observe the Release if its
+// failure tolerance permits. A tolerated failure is reported as an up to date
+// release, which leaves the conditions of the Release as they are. A
+// successful observation resets the failure count.
+func (e *helmExternal) tolerate(cr *v1beta1.Release, o managed.ExternalObservation, err error) (managed.ExternalObservation, error) {
+	if err == nil {
+		if cr.Status.ConsecutiveFailures > 0 {
+			e.logger.Debug("Recovered from tolerated failures", "failures", cr.Status.ConsecutiveFailures)
+		}
+		cr.Status.ConsecutiveFailures = 0
+		cr.Status.FailingSince = nil
+		return o, nil
+	}
+
+	ft := cr.Spec.FailureTolerance
+	if ft == nil || meta.WasDeleted(cr) {
+		return o, err
+	}
+
+	now := time.Now()
+	cr.Status.ConsecutiveFailures++
+	if cr.Status.FailingSince == nil {
+		t := metav1.NewTime(now)
+		cr.Status.FailingSince = &t
+	}
+	if cr.Status.ConsecutiveFailures > ft.Threshold {
+		return o, err
+	}
+	if ft.GracePeriod != nil && now.Sub(cr.Status.FailingSince.Time) > ft.GracePeriod.Duration {
+		return o, err
+	}
+
+	e.logger.Debug("Tolerating failure to observe release", "error", err, "failures", cr.Status.ConsecutiveFailures)
+	return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+}
@@ -0,0 +1,108 @@
+package release
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+func Test_tolerate(t *testing.T) {
+	observed := managed.ExternalObservation{ResourceExists: true, ConnectionDetails: managed.ConnectionDetails{}}
+	longAgo := metav1.NewTime(time.Now().Add(-time.Hour))
+
+	type want struct {
+		o        managed.ExternalObservation
+		err      error
+		failures int32
+	}
+	cases := map[string]struct {
+		tolerance    *v1beta1.FailureTolerance
+		failures     int32
+		failingSince *metav1.Time
+		err          error
+		want         want
+	}{
+		"Succeeded": {
+			tolerance: &v1beta1.FailureTolerance{Threshold: 3},
+			failures:  2,
+			want:      want{o: observed},
+		},
+		"NoTolerance": {
+			err:  errBoom,
+			want: want{err: errBoom},
+		},
+		"Tolerated": {
+			tolerance: &v1beta1.FailureTolerance{Threshold: 3},
+			failures:  2,
+			err:       errBoom,
+			want:      want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, failures: 3},
+		},
+		"ThresholdExceeded": {
+			tolerance: &v1beta1.FailureTolerance{Threshold: 3},
+			failures:  3,
+			err:       errBoom,
+			want:      want{err: errBoom, failures: 4},
+		},
+		"GracePeriodExceeded": {
+			tolerance:    &v1beta1.FailureTolerance{Threshold: 3, GracePeriod: &metav1.Duration{Duration: time.Minute}},
+			failures:     1,
+			failingSince: &longAgo,
+			err:          errBoom,
+			want:         want{err: errBoom, failures: 2},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := helmRelease(func(r *v1beta1.Release) {
+				r.Spec.FailureTolerance = tc.tolerance
+				r.Status.ConsecutiveFailures = tc.failures
+				r.Status.FailingSince = tc.failingSince
+			})
+			e := &helmExternal{logger: logging.NewNopLogger()}
+			o := observed
+			if tc.err != nil {
+				o = managed.ExternalObservation{}
+			}
+			got, err := e.tolerate(cr, o, tc.err)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("e.tolerate(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("e.tolerate(...): -want, +got: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.failures, cr.Status.ConsecutiveFailures); diff != "" {
+				t.Errorf("e.tolerate(...): -want failures, +got failures: %s", diff)
+			}
+		})
+	}
+}
+
+func TestObserveToleratedFailureGeneration(t *testing.T) {
+	cr := helmRelease(func(r *v1beta1.Release) {
+		r.SetGeneration(2)
+		r.Spec.Suspend = true
+		r.Spec.FailureTolerance = &v1beta1.FailureTolerance{Threshold: 3}
+		r.Spec.ForProvider.Hibernation = &v1beta1.Hibernation{Hibernate: "invalid", WakeUp: "0 7 * * *"}
+		r.Status.ObservedGeneration = 1
+	})
+	e := &helmExternal{logger: logging.NewNopLogger()}
+	got, err := e.Observe(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("e.Observe(...): %v", err)
+	}
+	if diff := cmp.Diff(managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, got); diff != "" {
+		t.Errorf("e.Observe(...): -want, +got: %s", diff)
+	}
+	if diff := cmp.Diff(int64(1), cr.Status.ObservedGeneration); diff != "" {
+		t.Errorf("e.Observe(...): -want observed generation, +got: %s", diff)
+	}
+}
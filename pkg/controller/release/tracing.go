@@ -0,0 +1,129 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	ktype "sigs.k8s.io/kustomize/api/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+// Attributes of the spans of Releases.
+const (
+	attrReleaseName    = attribute.Key("helm.release.name")
+	attrReleaseNS      = attribute.Key("helm.release.namespace")
+	attrProviderConfig = attribute.Key("crossplane.providerconfig.name")
+	attrChartName      = attribute.Key("helm.chart.name")
+	attrChartVersion   = attribute.Key("helm.chart.version")
+	attrResourceName   = attribute.Key("crossplane.resource.name")
+	attrExists         = attribute.Key("crossplane.resource.exists")
+	attrUpToDate       = attribute.Key("crossplane.resource.uptodate")
+)
+
+// tracer of the Release controller. Spans are dropped unless a tracer
+// provider was registered, e.g. by tracing.Setup.
+var tracer = otel.Tracer("github.com/crossplane-contrib/provider-helm/pkg/controller/release")
+
+// startSpan starts a span of the supplied phase of the reconcile of the
+// supplied Release.
+func startSpan(ctx context.Context, name string, cr *v1beta1.Release) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(releaseAttributes(cr)...))
+}
+
+// endSpan records the supplied error, if any, and ends the supplied span.
+func endSpan(s trace.Span, err error) {
+	if err != nil {
+		s.RecordError(err)
+		s.SetStatus(codes.Error, err.Error())
+	}
+	s.End()
+}
+
+func releaseAttributes(cr *v1beta1.Release) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attrReleaseName.String(meta.GetExternalName(cr)),
+		attrReleaseNS.String(cr.Spec.ForProvider.Namespace),
+	}
+	if ref := cr.GetProviderConfigReference(); ref != nil {
+		attrs = append(attrs, attrProviderConfig.String(ref.Name))
+	}
+	return attrs
+}
+
+// traced returns a deploy action that runs the supplied action in a span of
+// the supplied name.
+func traced(ctx context.Context, cr *v1beta1.Release, name string, action deployAction) deployAction {
+	return func(rel string, ch *chart.Chart, vals map[string]interface{}, patches []ktype.Patch) (*release.Release, error) {
+		_, span := startSpan(ctx, name, cr)
+		r, err := action(rel, ch, vals, patches)
+		endSpan(span, err)
+		return r, err
+	}
+}
+
+// A tracedExternal traces the operations of the wrapped client.
+type tracedExternal struct {
+	managed.ExternalClient
+}
+
+func (t *tracedExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	ctx, span := startManagedSpan(ctx, "Observe", mg)
+	o, err := t.ExternalClient.Observe(ctx, mg)
+	span.SetAttributes(attrExists.Bool(o.ResourceExists), attrUpToDate.Bool(o.ResourceUpToDate))
+	endSpan(span, err)
+	return o, err
+}
+
+func (t *tracedExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	ctx, span := startManagedSpan(ctx, "Create", mg)
+	c, err := t.ExternalClient.Create(ctx, mg)
+	endSpan(span, err)
+	return c, err
+}
+
+func (t *tracedExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	ctx, span := startManagedSpan(ctx, "Update", mg)
+	u, err := t.ExternalClient.Update(ctx, mg)
+	endSpan(span, err)
+	return u, err
+}
+
+func (t *tracedExternal) Delete(ctx context.Context, mg resource.Managed) error {
+	ctx, span := startManagedSpan(ctx, "Delete", mg)
+	err := t.ExternalClient.Delete(ctx, mg)
+	endSpan(span, err)
+	return err
+}
+
+func startManagedSpan(ctx context.Context, name string, mg resource.Managed) (context.Context, trace.Span) {
+	if cr, ok := mg.(*v1beta1.Release); ok {
+		return startSpan(ctx, name, cr)
+	}
+	return tracer.Start(ctx, name)
+}
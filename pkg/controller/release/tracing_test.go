@@ -0,0 +1,110 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	ktype "sigs.k8s.io/kustomize/api/types"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+type fnExternal struct {
+	managed.ExternalClient
+	observe func(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error)
+	update  func(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error)
+}
+
+func (f *fnExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	return f.observe(ctx, mg)
+}
+
+func (f *fnExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	return f.update(ctx, mg)
+}
+
+func TestTracedExternal(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	cr := &v1beta1.Release{}
+	meta.SetExternalName(cr, "wordpress")
+	cr.Spec.ForProvider.Namespace = "wordpress"
+	cr.SetProviderConfigReference(&xpv1.Reference{Name: "cluster-a"})
+
+	type span struct {
+		Name   string
+		Status codes.Code
+		Attrs  []attribute.KeyValue
+	}
+	attrs := []attribute.KeyValue{
+		attrReleaseName.String("wordpress"),
+		attrReleaseNS.String("wordpress"),
+		attrProviderConfig.String("cluster-a"),
+	}
+
+	te := &tracedExternal{ExternalClient: &fnExternal{
+		observe: func(_ context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+			return managed.ExternalObservation{ResourceExists: true}, nil
+		},
+		update: func(ctx context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+			upgrade := func(_ string, _ *chart.Chart, _ map[string]interface{}, _ []ktype.Patch) (*release.Release, error) {
+				return nil, errBoom
+			}
+			_, err := traced(ctx, cr, "Upgrade", upgrade)("wordpress", nil, nil, nil)
+			return managed.ExternalUpdate{}, err
+		},
+	}}
+	_, _ = te.Observe(context.Background(), cr)
+	_, err := te.Update(context.Background(), cr)
+
+	var got []span
+	for _, s := range sr.Ended() {
+		got = append(got, span{Name: s.Name(), Status: s.Status().Code, Attrs: s.Attributes()})
+	}
+	want := []span{
+		{Name: "Observe", Status: codes.Unset, Attrs: append(append([]attribute.KeyValue{}, attrs...), attrExists.Bool(true), attrUpToDate.Bool(false))},
+		{Name: "Upgrade", Status: codes.Error, Attrs: attrs},
+		{Name: "Update", Status: codes.Error, Attrs: attrs},
+	}
+	if err == nil {
+		t.Fatal("Update(...): expected an error")
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(attribute.Value{})); diff != "" {
+		t.Errorf("spans: -want, +got:\n%s", diff)
+	}
+	if parent, child := sr.Ended()[2], sr.Ended()[1]; child.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("Upgrade span is not a child of the Update span")
+	}
+}
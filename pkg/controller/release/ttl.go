@@ -0,0 +1,73 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	errFailedToDeleteExpired = "failed to delete expired release"
+
+	reasonExpired event.Reason = "Expired"
+)
+
+// expire deletes the Release once its TTL has passed and returns whether it
+// did. The deletion uninstalls the release like any other deletion. Releases
+// that could not be uninstalled, because they are suspended, protected from
+// deletion or not allowed to be deleted by their management policies, are
+// not deleted; the Expired condition reports why.
+func (e *helmExternal) expire(ctx context.Context, cr *v1beta1.Release) (bool, error) {
+	if cr.Spec.TTL == nil || meta.WasDeleted(cr) {
+		cr.Status.ExpiresAt = nil
+		return false, nil
+	}
+
+	at := metav1.NewTime(cr.GetCreationTimestamp().Add(cr.Spec.TTL.Duration))
+	cr.Status.ExpiresAt = &at
+	if time.Now().Before(at.Time) {
+		return false, nil
+	}
+
+	switch {
+	case cr.Spec.Suspend:
+		cr.Status.SetConditions(v1beta1.ExpiryBlocked("it is suspended"))
+		return false, nil
+	case cr.Spec.ForProvider.DeletionProtection:
+		cr.Status.SetConditions(v1beta1.ExpiryBlocked("deletion protection is enabled"))
+		return false, nil
+	case !managementAllows(cr, v1beta1.ManagementActionDelete):
+		cr.Status.SetConditions(v1beta1.ExpiryBlocked("its management policies do not allow deleting it"))
+		return false, nil
+	}
+
+	if err := e.localKube.Delete(ctx, cr); resource.IgnoreNotFound(err) != nil {
+		return false, errors.Wrap(err, errFailedToDeleteExpired)
+	}
+	e.recorder.Event(cr, event.Normal(reasonExpired, "TTL of release has passed, deleting release"))
+	return true, nil
+}
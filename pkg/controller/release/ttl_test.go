@@ -0,0 +1,108 @@
+package release
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+func Test_expire(t *testing.T) {
+	type want struct {
+		expired bool
+		deleted bool
+		blocked bool
+		err     error
+	}
+
+	cases := map[string]struct {
+		ttl       *metav1.Duration
+		created   time.Duration
+		deleteErr error
+		modify    helmReleaseModifier
+		want      want
+	}{
+		"NoTTL": {},
+		"NotExpired": {
+			ttl:     &metav1.Duration{Duration: time.Hour},
+			created: -time.Minute,
+		},
+		"Expired": {
+			ttl:     &metav1.Duration{Duration: time.Hour},
+			created: -2 * time.Hour,
+			want:    want{expired: true, deleted: true},
+		},
+		"Suspended": {
+			ttl:     &metav1.Duration{Duration: time.Hour},
+			created: -2 * time.Hour,
+			modify:  func(r *v1beta1.Release) { r.Spec.Suspend = true },
+			want:    want{blocked: true},
+		},
+		"DeletionProtection": {
+			ttl:     &metav1.Duration{Duration: time.Hour},
+			created: -2 * time.Hour,
+			modify:  func(r *v1beta1.Release) { r.Spec.ForProvider.DeletionProtection = true },
+			want:    want{blocked: true},
+		},
+		"DeleteNotManaged": {
+			ttl:     &metav1.Duration{Duration: time.Hour},
+			created: -2 * time.Hour,
+			modify: func(r *v1beta1.Release) {
+				r.Spec.ManagementPolicies = []v1beta1.ManagementAction{v1beta1.ManagementActionObserve, v1beta1.ManagementActionUpdate}
+			},
+			want: want{blocked: true},
+		},
+		"FailedToDelete": {
+			ttl:       &metav1.Duration{Duration: time.Hour},
+			created:   -2 * time.Hour,
+			deleteErr: errBoom,
+			want:      want{deleted: true, err: errors.Wrap(errBoom, errFailedToDeleteExpired)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			deleted := false
+			e := &helmExternal{
+				localKube: &test.MockClient{
+					MockDelete: func(_ context.Context, _ client.Object, _ ...client.DeleteOption) error {
+						deleted = true
+						return tc.deleteErr
+					},
+				},
+				recorder: event.NewNopRecorder(),
+			}
+			cr := helmRelease(func(r *v1beta1.Release) {
+				r.Spec.TTL = tc.ttl
+				r.SetCreationTimestamp(metav1.NewTime(time.Now().Add(tc.created)))
+				if tc.modify != nil {
+					tc.modify(r)
+				}
+			})
+			expired, err := e.expire(context.Background(), cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("e.expire(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.expired, expired); diff != "" {
+				t.Errorf("e.expire(...): -want expired, +got expired: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.deleted, deleted); diff != "" {
+				t.Errorf("e.expire(...): -want deleted, +got deleted: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.blocked, cr.Status.GetCondition(v1beta1.TypeExpired).Reason == v1beta1.ReasonExpiryBlocked); diff != "" {
+				t.Errorf("e.expire(...): -want blocked, +got blocked: %s", diff)
+			}
+			if got := cr.Status.ExpiresAt != nil; got != (tc.ttl != nil) {
+				t.Errorf("e.expire(...): expiresAt set: %v", got)
+			}
+		})
+	}
+}
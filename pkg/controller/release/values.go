@@ -21,11 +21,13 @@ import (
 	"fmt"
 
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
 	"helm.sh/helm/v3/pkg/strvals"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
 
 	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+	"github.com/crossplane-contrib/provider-helm/pkg/clients/valuesource"
 )
 
 const (
@@ -40,12 +42,22 @@ const (
 	errMissingValueForSet             = "missing value for --set"
 )
 
-func composeValuesFromSpec(ctx context.Context, kube client.Client, spec v1beta1.ValuesSpec) (map[string]interface{}, error) {
+// ComposeValues composes the values of the supplied spec like those of a
+// Release. Values from ConfigMaps and Secrets are read using the supplied
+// client, which may be nil if the spec reads none. Value source plugins are
+// not available.
+func ComposeValues(ctx context.Context, kube client.Client, spec v1beta1.ValuesSpec) (map[string]interface{}, error) {
+	return composeValuesFromSpec(ctx, kube, valuesource.Plugins(nil), spec)
+}
+
+func composeValuesFromSpec(ctx context.Context, kube client.Client, plugins valuePlugins, spec v1beta1.ValuesSpec) (map[string]interface{}, error) {
+	defer prometheus.NewTimer(valuesCompositionDuration).ObserveDuration()
 	base := map[string]interface{}{}
 
 	for _, vf := range spec.ValuesFrom {
-		s, err := getDataValueFromSource(ctx, kube, vf, keyDefaultValuesFrom)
+		s, err := getDataValueFromSource(ctx, kube, plugins, vf, keyDefaultValuesFrom)
 		if err != nil {
+			valueSourceFailures.WithLabelValues(valueSourceType(vf)).Inc()
 			return nil, errors.Wrap(err, errFailedToGetValueFromSource)
 		}
 
@@ -70,8 +82,9 @@ func composeValuesFromSpec(ctx context.Context, kube client.Client, spec v1beta1
 			v = s.Value
 		}
 		if s.ValueFrom != nil {
-			v, err = getDataValueFromSource(ctx, kube, *s.ValueFrom, keyDefaultSet)
+			v, err = getDataValueFromSource(ctx, kube, plugins, *s.ValueFrom, keyDefaultSet)
 			if err != nil {
+				valueSourceFailures.WithLabelValues(valueSourceType(*s.ValueFrom)).Inc()
 				return nil, errors.Wrap(err, errFailedToGetValueFromSource)
 			}
 		}
@@ -320,7 +320,7 @@ keyB:
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			got, gotErr := composeValuesFromSpec(context.Background(), tc.args.kube, tc.args.spec)
+			got, gotErr := composeValuesFromSpec(context.Background(), tc.args.kube, nil, tc.args.spec)
 			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
 				t.Fatalf("composeValuesFromSpec(...): -want error, +got error: %s", diff)
 			}
@@ -0,0 +1,223 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	errInvalidSchedule = "invalid upgrade window schedule %q"
+	errInvalidTimeZone = "invalid time zone %q"
+	errCronFields      = "expected 5 fields, got %d"
+	errCronValue       = "invalid value %q"
+	errCronRange       = "value %d out of range [%d, %d]"
+
+	// nextWindowHorizon is how far ahead the next upgrade window is looked
+	// up.
+	nextWindowHorizon = 366 * 24 * time.Hour
+)
+
+// upgradeWindowOpen returns whether any of the supplied windows is open at
+// the supplied time. If none is, it also returns the time the next window
+// opens, or the zero time if none opens within a year.
+func upgradeWindowOpen(windows []v1beta1.UpgradeWindow, now time.Time) (bool, time.Time, error) {
+	if len(windows) == 0 {
+		return true, time.Time{}, nil
+	}
+
+	var next time.Time
+	for _, w := range windows {
+		c, err := parseCron(w.Schedule)
+		if err != nil {
+			return false, time.Time{}, errors.Wrapf(err, errInvalidSchedule, w.Schedule)
+		}
+		loc, err := location(w.TimeZone)
+		if err != nil {
+			return false, time.Time{}, err
+		}
+
+		t := now.In(loc)
+		// The window is open if it opened within its duration.
+		if s, ok := c.next(t.Add(-w.Duration.Duration), t); ok && !s.After(t) {
+			return true, time.Time{}, nil
+		}
+		if s, ok := c.next(t, t.Add(nextWindowHorizon)); ok && (next.IsZero() || s.Before(next)) {
+			next = s
+		}
+	}
+	return false, next, nil
+}
+
+// location returns the supplied time zone, or UTC if none is supplied.
+func location(tz string) (*time.Location, error) {
+	if tz == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(tz)
+	return loc, errors.Wrapf(err, errInvalidTimeZone, tz)
+}
+
+// A cronSchedule is a parsed five field cron expression. Every field is a
+// bit set of the values it matches.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// domAny and dowAny are true if the day of month or the day of week is
+	// "*". If neither is, a day matches if either of them matches.
+	domAny, dowAny bool
+}
+
+func parseCron(s string) (*cronSchedule, error) {
+	f := strings.Fields(s)
+	if len(f) != 5 {
+		return nil, errors.Errorf(errCronFields, len(f))
+	}
+
+	c := &cronSchedule{domAny: f[2] == "*", dowAny: f[4] == "*"}
+	bounds := []struct {
+		field    *uint64
+		min, max int
+	}{
+		{&c.minute, 0, 59},
+		{&c.hour, 0, 23},
+		{&c.dom, 1, 31},
+		{&c.month, 1, 12},
+		{&c.dow, 0, 7},
+	}
+	for i, b := range bounds {
+		v, err := parseCronField(f[i], b.min, b.max)
+		if err != nil {
+			return nil, err
+		}
+		*b.field = v
+	}
+	// Both 0 and 7 are Sunday.
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+	return c, nil
+}
+
+func parseCronField(f string, min, max int) (uint64, error) {
+	var res uint64
+	for _, part := range strings.Split(f, ",") {
+		rng, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			s, err := strconv.Atoi(part[i+1:])
+			if err != nil || s < 1 {
+				return 0, errors.Errorf(errCronValue, part)
+			}
+			rng, step = part[:i], s
+		}
+
+		lo, hi := min, max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			i := strings.Index(rng, "-")
+			var err error
+			if lo, err = cronValue(rng[:i], min, max); err != nil {
+				return 0, err
+			}
+			if hi, err = cronValue(rng[i+1:], min, max); err != nil {
+				return 0, err
+			}
+		default:
+			v, err := cronValue(rng, min, max)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			if step == 1 {
+				hi = v
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			res |= 1 << uint(v)
+		}
+	}
+	return res, nil
+}
+
+func cronValue(s string, min, max int) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, errors.Errorf(errCronValue, s)
+	}
+	if v < min || v > max {
+		return 0, errors.Errorf(errCronRange, v, min, max)
+	}
+	return v, nil
+}
+
+func (c *cronSchedule) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+	if !c.domAny && !c.dowAny {
+		return dom || dow
+	}
+	return dom && dow
+}
+
+// next returns the first time after from and not after until that matches
+// the schedule.
+func (c *cronSchedule) next(from, until time.Time) (time.Time, bool) {
+	loc := from.Location()
+	t := time.Date(from.Year(), from.Month(), from.Day(), from.Hour(), from.Minute(), 0, 0, loc).Add(time.Minute)
+	for !t.After(until) {
+		switch {
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// prev returns the last time not after from and not before since that
+// matches the schedule.
+func (c *cronSchedule) prev(from, since time.Time) (time.Time, bool) {
+	loc := from.Location()
+	t := time.Date(from.Year(), from.Month(), from.Day(), from.Hour(), from.Minute(), 0, 0, loc)
+	for !t.Before(since) {
+		switch {
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc).Add(-time.Minute)
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc).Add(-time.Minute)
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(-time.Minute)
+		default:
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,81 @@
+package release
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+func Test_upgradeWindowOpen(t *testing.T) {
+	// A Wednesday.
+	now := time.Date(2021, time.July, 14, 10, 30, 0, 0, time.UTC)
+	twoHours := metav1.Duration{Duration: 2 * time.Hour}
+
+	type want struct {
+		open bool
+		next time.Time
+		err  error
+	}
+
+	cases := map[string]struct {
+		windows []v1beta1.UpgradeWindow
+		want    want
+	}{
+		"NoWindows": {
+			want: want{open: true},
+		},
+		"Open": {
+			windows: []v1beta1.UpgradeWindow{{Schedule: "0 9 * * 1-5", Duration: twoHours}},
+			want:    want{open: true},
+		},
+		"Closed": {
+			windows: []v1beta1.UpgradeWindow{{Schedule: "0 2 * * 6,0", Duration: twoHours}},
+			want:    want{next: time.Date(2021, time.July, 17, 2, 0, 0, 0, time.UTC)},
+		},
+		"EarliestNextWindow": {
+			windows: []v1beta1.UpgradeWindow{
+				{Schedule: "0 2 * * 6,0", Duration: twoHours},
+				{Schedule: "*/15 22 * * *", Duration: twoHours},
+			},
+			want: want{next: time.Date(2021, time.July, 14, 22, 0, 0, 0, time.UTC)},
+		},
+		"TimeZone": {
+			// 10:30 UTC is 12:30 in Berlin.
+			windows: []v1beta1.UpgradeWindow{{Schedule: "0 12 * * *", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "Europe/Berlin"}},
+			want:    want{open: true},
+		},
+		"DayOfMonthOrWeek": {
+			windows: []v1beta1.UpgradeWindow{{Schedule: "0 0 1 * 0", Duration: twoHours}},
+			want:    want{next: time.Date(2021, time.July, 18, 0, 0, 0, 0, time.UTC)},
+		},
+		"InvalidSchedule": {
+			windows: []v1beta1.UpgradeWindow{{Schedule: "0 25 * * *"}},
+			want:    want{err: errors.Wrapf(errors.Errorf(errCronRange, 25, 0, 23), errInvalidSchedule, "0 25 * * *")},
+		},
+		"InvalidFields": {
+			windows: []v1beta1.UpgradeWindow{{Schedule: "@daily"}},
+			want:    want{err: errors.Wrapf(errors.Errorf(errCronFields, 1), errInvalidSchedule, "@daily")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			open, next, err := upgradeWindowOpen(tc.windows, now)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("upgradeWindowOpen(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.open, open); diff != "" {
+				t.Errorf("upgradeWindowOpen(...): -want open, +got open: %s", diff)
+			}
+			if !tc.want.next.Equal(next) {
+				t.Errorf("upgradeWindowOpen(...): want next %s, got %s", tc.want.next, next)
+			}
+		})
+	}
+}
@@ -0,0 +1,346 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package releaseset
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chartutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sigs.k8s.io/yaml"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+	helmv1beta1 "github.com/crossplane-contrib/provider-helm/apis/v1beta1"
+)
+
+const (
+	reconcileTimeout = 1 * time.Minute
+	shortWait        = 30 * time.Second
+)
+
+const (
+	errGetReleaseSet         = "cannot get ReleaseSet"
+	errUpdateStatus          = "cannot update ReleaseSet status"
+	errSelectProviderConfigs = "cannot select ProviderConfigs"
+	errListReleases          = "cannot list Releases of ReleaseSet"
+	errApplyRelease          = "cannot apply Release"
+	errDeleteRelease         = "cannot delete Release that is no longer selected"
+	errMergeValues           = "cannot merge override values"
+	errNotControlled         = "Release %s exists and is not controlled by the ReleaseSet"
+)
+
+const (
+	reasonApplyRelease  event.Reason = "ApplyRelease"
+	reasonDeleteRelease event.Reason = "DeleteRelease"
+)
+
+// Setup adds a controller that reconciles ReleaseSets by creating a Release
+// for every selected ProviderConfig. The supplied options configure its
+// workers and rate limiting.
+func Setup(mgr ctrl.Manager, l logging.Logger, o controller.Options) error {
+	name := "releaseset/" + strings.ToLower(v1beta1.ReleaseSetGroupKind)
+
+	r := NewReconciler(mgr,
+		WithLogger(l.WithValues("controller", name)),
+		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1beta1.ReleaseSet{}).
+		Owns(&v1beta1.Release{}).
+		Watches(&source.Kind{Type: &helmv1beta1.ProviderConfig{}}, handler.EnqueueRequestsFromMapFunc(r.allReleaseSets)).
+		WithOptions(o).
+		Complete(r)
+}
+
+// A ReconcilerOption configures a Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(l logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = l
+	}
+}
+
+// WithRecorder specifies how the Reconciler should record events.
+func WithRecorder(er event.Recorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.record = er
+	}
+}
+
+// A Reconciler reconciles ReleaseSets.
+type Reconciler struct {
+	client client.Client
+	log    logging.Logger
+	record event.Recorder
+}
+
+// NewReconciler returns a Reconciler of ReleaseSets.
+func NewReconciler(mgr ctrl.Manager, o ...ReconcilerOption) *Reconciler {
+	r := &Reconciler{
+		client: mgr.GetClient(),
+		log:    logging.NewNopLogger(),
+		record: event.NewNopRecorder(),
+	}
+	for _, ro := range o {
+		ro(r)
+	}
+	return r
+}
+
+// Reconcile a ReleaseSet.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(ctx, reconcileTimeout)
+	defer cancel()
+
+	rs := &v1beta1.ReleaseSet{}
+	if err := r.client.Get(ctx, req.NamespacedName, rs); err != nil {
+		log.Debug(errGetReleaseSet, "error", err)
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetReleaseSet)
+	}
+
+	// Releases are garbage collected through their owner references.
+	if meta.WasDeleted(rs) {
+		return reconcile.Result{}, nil
+	}
+
+	if err := r.reconcile(ctx, rs); err != nil {
+		log.Debug("Cannot reconcile ReleaseSet", "error", err)
+		rs.Status.SetConditions(xpv1.ReconcileError(err))
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, rs), errUpdateStatus)
+	}
+
+	rs.Status.SetConditions(xpv1.ReconcileSuccess())
+	return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, rs), errUpdateStatus)
+}
+
+func (r *Reconciler) reconcile(ctx context.Context, rs *v1beta1.ReleaseSet) error {
+	sel, err := metav1.LabelSelectorAsSelector(&rs.Spec.ProviderConfigSelector)
+	if err != nil {
+		return errors.Wrap(err, errSelectProviderConfigs)
+	}
+	pcs := &helmv1beta1.ProviderConfigList{}
+	if err := r.client.List(ctx, pcs, client.MatchingLabelsSelector{Selector: sel}); err != nil {
+		return errors.Wrap(err, errSelectProviderConfigs)
+	}
+	names := make([]string, 0, len(pcs.Items))
+	for _, pc := range pcs.Items {
+		names = append(names, pc.GetName())
+	}
+	sort.Strings(names)
+
+	desired := map[string]bool{}
+	members := make([]v1beta1.ReleaseSetMember, 0, len(names))
+	for _, pc := range names {
+		rel, err := r.apply(ctx, rs, pc)
+		if err != nil {
+			return err
+		}
+		desired[rel.GetName()] = true
+		members = append(members, member(pc, rel))
+	}
+
+	rels := &v1beta1.ReleaseList{}
+	if err := r.client.List(ctx, rels, client.MatchingLabels{v1beta1.LabelKeyReleaseSet: rs.GetName()}); err != nil {
+		return errors.Wrap(err, errListReleases)
+	}
+	for i := range rels.Items {
+		rel := &rels.Items[i]
+		if desired[rel.GetName()] || !metav1.IsControlledBy(rel, rs) {
+			continue
+		}
+		if err := r.client.Delete(ctx, rel); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errDeleteRelease)
+		}
+		r.record.Event(rs, event.Normal(reasonDeleteRelease, "Deleted Release "+rel.GetName()))
+	}
+
+	setStatus(rs, members)
+	return nil
+}
+
+// apply creates or updates the Release of the ReleaseSet for the supplied
+// ProviderConfig.
+func (r *Reconciler) apply(ctx context.Context, rs *v1beta1.ReleaseSet, pc string) (*v1beta1.Release, error) {
+	want, err := release(rs, pc)
+	if err != nil {
+		return nil, err
+	}
+
+	rel := &v1beta1.Release{ObjectMeta: metav1.ObjectMeta{Name: want.GetName()}}
+	res, err := controllerutil.CreateOrUpdate(ctx, r.client, rel, func() error {
+		if c := metav1.GetControllerOf(rel); c != nil && c.UID != rs.GetUID() {
+			return errors.Errorf(errNotControlled, rel.GetName())
+		}
+		meta.AddLabels(rel, want.GetLabels())
+		meta.AddAnnotations(rel, want.GetAnnotations())
+		meta.AddOwnerReference(rel, want.GetOwnerReferences()[0])
+		syncSpec(rel, want.Spec)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errApplyRelease)
+	}
+	if res != controllerutil.OperationResultNone {
+		r.record.Event(rs, event.Normal(reasonApplyRelease, "Applied Release "+rel.GetName()))
+	}
+	return rel, nil
+}
+
+// syncSpec sets the spec of the supplied Release to the supplied spec of its
+// template. Fields the Release controller late initializes are kept if the
+// template does not set them, so that the ReleaseSet and Release controllers
+// don't undo each other's updates.
+func syncSpec(rel *v1beta1.Release, want v1beta1.ReleaseSpec) {
+	have := rel.Spec.ForProvider
+	rel.Spec = want
+	p := &rel.Spec.ForProvider
+	if p.Chart.Name == "" && p.Chart.URL == "" {
+		p.Chart.Name = have.Chart.Name
+	}
+	if p.Chart.Version == "" {
+		p.Chart.Version = have.Chart.Version
+	}
+	if len(p.Values.Raw) == 0 && len(p.ValuesFrom) == 0 && len(p.Set) == 0 {
+		p.Values = have.Values
+	}
+}
+
+// release returns the desired Release of the supplied ReleaseSet for the
+// supplied ProviderConfig. The Releases of a ReleaseSet share the release
+// name, which defaults to the name of the ReleaseSet.
+func release(rs *v1beta1.ReleaseSet, pc string) (*v1beta1.Release, error) {
+	t := rs.Spec.Template
+	rel := &v1beta1.Release{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        rs.GetName() + "-" + pc,
+			Labels:      map[string]string{},
+			Annotations: map[string]string{meta.AnnotationKeyExternalName: rs.GetName()},
+		},
+		Spec: *t.Spec.DeepCopy(),
+	}
+	for k, v := range t.Metadata.Labels {
+		rel.Labels[k] = v
+	}
+	for k, v := range t.Metadata.Annotations {
+		rel.Annotations[k] = v
+	}
+	rel.Labels[v1beta1.LabelKeyReleaseSet] = rs.GetName()
+	rel.Labels[v1beta1.LabelKeyProviderConfig] = pc
+	meta.AddOwnerReference(rel, meta.AsController(meta.TypedReferenceTo(rs, v1beta1.ReleaseSetGroupVersionKind)))
+	rel.Spec.ProviderConfigReference = &xpv1.Reference{Name: pc}
+
+	for _, o := range rs.Spec.Overrides {
+		if o.ProviderConfigName != pc {
+			continue
+		}
+		v, err := mergeValues(rel.Spec.ForProvider.Values.Raw, o.Values.Raw)
+		if err != nil {
+			return nil, errors.Wrap(err, errMergeValues)
+		}
+		rel.Spec.ForProvider.Values.Raw = v
+		rel.Spec.ForProvider.Set = append(rel.Spec.ForProvider.Set, o.Set...)
+	}
+	return rel, nil
+}
+
+// mergeValues deep merges the override values into the base values. Values of
+// the override take precedence.
+func mergeValues(base, override []byte) ([]byte, error) {
+	if len(override) == 0 {
+		return base, nil
+	}
+	b, o := map[string]interface{}{}, map[string]interface{}{}
+	if err := yaml.Unmarshal(base, &b); err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(override, &o); err != nil {
+		return nil, err
+	}
+	return json.Marshal(chartutil.CoalesceTables(o, b))
+}
+
+func member(pc string, rel *v1beta1.Release) v1beta1.ReleaseSetMember {
+	c := rel.Status.GetCondition(xpv1.TypeReady)
+	m := v1beta1.ReleaseSetMember{
+		ProviderConfigName: pc,
+		ReleaseName:        rel.GetName(),
+		Ready:              c.Status == corev1.ConditionTrue,
+	}
+	if !m.Ready {
+		m.Message = c.Message
+	}
+	return m
+}
+
+// setStatus aggregates the status of the supplied Releases into the status
+// of the ReleaseSet. A ReleaseSet is ready once all its Releases are ready.
+func setStatus(rs *v1beta1.ReleaseSet, members []v1beta1.ReleaseSetMember) {
+	rs.Status.Releases = members
+	rs.Status.Desired = int32(len(members))
+	rs.Status.Ready = 0
+	for _, m := range members {
+		if m.Ready {
+			rs.Status.Ready++
+		}
+	}
+	if rs.Status.Ready == rs.Status.Desired {
+		rs.Status.SetConditions(xpv1.Available())
+		return
+	}
+	rs.Status.SetConditions(xpv1.Unavailable())
+}
+
+// allReleaseSets maps a ProviderConfig to all ReleaseSets, since any of them
+// may select it.
+func (r *Reconciler) allReleaseSets(_ client.Object) []reconcile.Request {
+	l := &v1beta1.ReleaseSetList{}
+	if err := r.client.List(context.Background(), l); err != nil {
+		r.log.Debug("Cannot list ReleaseSets", "error", err)
+		return nil
+	}
+	reqs := make([]reconcile.Request, 0, len(l.Items))
+	for _, rs := range l.Items {
+		reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: rs.GetName()}})
+	}
+	return reqs
+}
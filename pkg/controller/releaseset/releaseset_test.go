@@ -0,0 +1,179 @@
+package releaseset
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+func testReleaseSet() *v1beta1.ReleaseSet {
+	return &v1beta1.ReleaseSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", UID: "uid"},
+		Spec: v1beta1.ReleaseSetSpec{
+			Template: v1beta1.ReleaseTemplate{
+				Metadata: v1beta1.ReleaseTemplateMetadata{
+					Labels: map[string]string{"team": "platform"},
+				},
+				Spec: v1beta1.ReleaseSpec{
+					ForProvider: v1beta1.ReleaseParameters{
+						Chart:     v1beta1.ChartSpec{Name: "agent", Version: "1.0.0"},
+						Namespace: "agent",
+						ValuesSpec: v1beta1.ValuesSpec{
+							Values: runtime.RawExtension{Raw: []byte(`{"replicas":1,"config":{"region":"eu","debug":false}}`)},
+							Set:    []v1beta1.SetVal{{Name: "a", Value: "b"}},
+						},
+					},
+				},
+			},
+			Overrides: []v1beta1.ReleaseSetOverride{
+				{
+					ProviderConfigName: "prod",
+					Values:             runtime.RawExtension{Raw: []byte(`{"replicas":3,"config":{"region":"us"}}`)},
+					Set:                []v1beta1.SetVal{{Name: "c", Value: "d"}},
+				},
+			},
+		},
+	}
+}
+
+func Test_release(t *testing.T) {
+	cases := map[string]struct {
+		pc   string
+		want func(r *v1beta1.Release)
+	}{
+		"NoOverride": {
+			pc: "dev",
+			want: func(r *v1beta1.Release) {
+				r.Spec.ForProvider.Values.Raw = []byte(`{"replicas":1,"config":{"region":"eu","debug":false}}`)
+				r.Spec.ForProvider.Set = []v1beta1.SetVal{{Name: "a", Value: "b"}}
+			},
+		},
+		"Override": {
+			pc: "prod",
+			want: func(r *v1beta1.Release) {
+				r.Spec.ForProvider.Values.Raw = []byte(`{"config":{"debug":false,"region":"us"},"replicas":3}`)
+				r.Spec.ForProvider.Set = []v1beta1.SetVal{{Name: "a", Value: "b"}, {Name: "c", Value: "d"}}
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rs := testReleaseSet()
+			got, err := release(rs, tc.pc)
+			if err != nil {
+				t.Fatalf("release(...): unexpected error: %s", err)
+			}
+
+			want := &v1beta1.Release{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "agent-" + tc.pc,
+					Labels: map[string]string{
+						"team":                         "platform",
+						v1beta1.LabelKeyReleaseSet:     "agent",
+						v1beta1.LabelKeyProviderConfig: tc.pc,
+					},
+					Annotations: map[string]string{meta.AnnotationKeyExternalName: "agent"},
+				},
+				Spec: v1beta1.ReleaseSpec{
+					ResourceSpec: xpv1.ResourceSpec{ProviderConfigReference: &xpv1.Reference{Name: tc.pc}},
+					ForProvider: v1beta1.ReleaseParameters{
+						Chart:     v1beta1.ChartSpec{Name: "agent", Version: "1.0.0"},
+						Namespace: "agent",
+					},
+				},
+			}
+			meta.AddOwnerReference(want, meta.AsController(meta.TypedReferenceTo(rs, v1beta1.ReleaseSetGroupVersionKind)))
+			tc.want(want)
+
+			if diff := cmp.Diff(want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("release(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_syncSpec(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		rel      v1beta1.ReleaseSpec
+		template v1beta1.ReleaseSpec
+		want     v1beta1.ReleaseSpec
+	}{
+		"LateInitializedKept": {
+			reason: "The chart version and values late initialized by the Release controller should be kept if the template does not set them.",
+			rel: v1beta1.ReleaseSpec{ForProvider: v1beta1.ReleaseParameters{
+				Chart:      v1beta1.ChartSpec{Name: "agent", Version: "1.2.0"},
+				Namespace:  "old",
+				ValuesSpec: v1beta1.ValuesSpec{Values: runtime.RawExtension{Raw: []byte(`{"replicas":2}`)}},
+			}},
+			template: v1beta1.ReleaseSpec{ForProvider: v1beta1.ReleaseParameters{
+				Chart:     v1beta1.ChartSpec{Name: "agent"},
+				Namespace: "agent",
+			}},
+			want: v1beta1.ReleaseSpec{ForProvider: v1beta1.ReleaseParameters{
+				Chart:      v1beta1.ChartSpec{Name: "agent", Version: "1.2.0"},
+				Namespace:  "agent",
+				ValuesSpec: v1beta1.ValuesSpec{Values: runtime.RawExtension{Raw: []byte(`{"replicas":2}`)}},
+			}},
+		},
+		"TemplateTakesPrecedence": {
+			reason: "Fields the template sets should be synced.",
+			rel: v1beta1.ReleaseSpec{ForProvider: v1beta1.ReleaseParameters{
+				Chart:      v1beta1.ChartSpec{Name: "agent", Version: "1.2.0"},
+				ValuesSpec: v1beta1.ValuesSpec{Values: runtime.RawExtension{Raw: []byte(`{"replicas":2}`)}},
+			}},
+			template: v1beta1.ReleaseSpec{ForProvider: v1beta1.ReleaseParameters{
+				Chart:      v1beta1.ChartSpec{Name: "agent", Version: "2.0.0"},
+				Namespace:  "agent",
+				ValuesSpec: v1beta1.ValuesSpec{Set: []v1beta1.SetVal{{Name: "a", Value: "b"}}},
+			}},
+			want: v1beta1.ReleaseSpec{ForProvider: v1beta1.ReleaseParameters{
+				Chart:      v1beta1.ChartSpec{Name: "agent", Version: "2.0.0"},
+				Namespace:  "agent",
+				ValuesSpec: v1beta1.ValuesSpec{Set: []v1beta1.SetVal{{Name: "a", Value: "b"}}},
+			}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rel := &v1beta1.Release{Spec: tc.rel}
+			syncSpec(rel, *tc.template.DeepCopy())
+			if diff := cmp.Diff(tc.want, rel.Spec); diff != "" {
+				t.Errorf("\n%s\nsyncSpec(...): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			// Syncing the late initialized Release again must not change
+			// it, or the controllers would update it in turns.
+			synced := rel.DeepCopy()
+			syncSpec(rel, *tc.template.DeepCopy())
+			if diff := cmp.Diff(synced.Spec, rel.Spec); diff != "" {
+				t.Errorf("\n%s\nsyncSpec(...): round trip: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func Test_setStatus(t *testing.T) {
+	rs := testReleaseSet()
+	setStatus(rs, []v1beta1.ReleaseSetMember{
+		{ProviderConfigName: "dev", ReleaseName: "agent-dev", Ready: true},
+		{ProviderConfigName: "prod", ReleaseName: "agent-prod", Message: "installing"},
+	})
+	if diff := cmp.Diff(int32(2), rs.Status.Desired); diff != "" {
+		t.Errorf("setStatus(...): -want desired, +got desired: %s", diff)
+	}
+	if diff := cmp.Diff(int32(1), rs.Status.Ready); diff != "" {
+		t.Errorf("setStatus(...): -want ready, +got ready: %s", diff)
+	}
+	if diff := cmp.Diff(xpv1.Unavailable(), rs.Status.GetCondition(xpv1.TypeReady), cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+		t.Errorf("setStatus(...): -want condition, +got condition: %s", diff)
+	}
+}
@@ -0,0 +1,434 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package flux converts Flux HelmReleases and the HelmRepositories serving
+// their charts into Releases, to ease migrations from Flux.
+package flux
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/utils/pointer"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	errDecode              = "cannot decode objects"
+	errDecodeHelmRelease   = "cannot decode HelmRelease"
+	errDecodeHelmRepo      = "cannot decode HelmRepository"
+	errEncodePatch         = "cannot encode patch"
+	errFmtUnsupportedSrc   = "chart source %s %s is not supported, only HelmRepositories are"
+	errFmtHelmRepoNotFound = "HelmRepository %s not found"
+	errFmtConvert          = "cannot convert HelmRelease %s"
+	errOCINotSupported     = "OCI HelmRepositories are not supported"
+)
+
+const (
+	helmReleaseGroup    = "helm.toolkit.fluxcd.io"
+	helmRepositoryGroup = "source.toolkit.fluxcd.io"
+
+	kindHelmRelease    = "HelmRelease"
+	kindHelmRepository = "HelmRepository"
+
+	// Defaults of the Flux APIs.
+	defaultValuesKey     = "values.yaml"
+	defaultKubeConfigKey = "value"
+)
+
+// Options of the conversion.
+type Options struct {
+	// ProviderConfigName is the ProviderConfig of the converted Releases.
+	// Defaults to the default ProviderConfig.
+	ProviderConfigName string
+}
+
+// Decode decodes the Kubernetes objects of the supplied YAML or JSON stream.
+func Decode(r io.Reader) ([]unstructured.Unstructured, error) {
+	d := kyaml.NewYAMLOrJSONDecoder(r, 4096)
+	var objs []unstructured.Unstructured
+	for {
+		u := unstructured.Unstructured{}
+		err := d.Decode(&u.Object)
+		if errors.Is(err, io.EOF) {
+			return objs, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errDecode)
+		}
+		if len(u.Object) == 0 {
+			continue
+		}
+		objs = append(objs, u)
+	}
+}
+
+// Convert converts the HelmReleases among the supplied objects into Releases,
+// resolving their charts from the HelmRepositories among them. Other objects
+// are ignored. It returns warnings about the settings of the HelmReleases
+// that could not be converted, or that behave differently.
+func Convert(objs []unstructured.Unstructured, o Options) ([]v1beta1.Release, []string, error) {
+	repos := map[types.NamespacedName]*unstructured.Unstructured{}
+	for i := range objs {
+		if isKind(&objs[i], helmRepositoryGroup, kindHelmRepository) {
+			repos[types.NamespacedName{Namespace: objs[i].GetNamespace(), Name: objs[i].GetName()}] = &objs[i]
+		}
+	}
+
+	var rels []v1beta1.Release
+	var warnings []string
+	for i := range objs {
+		if !isKind(&objs[i], helmReleaseGroup, kindHelmRelease) {
+			continue
+		}
+		hr := &helmRelease{}
+		if err := decode(&objs[i], hr); err != nil {
+			return nil, nil, errors.Wrap(err, errDecodeHelmRelease)
+		}
+		if k := hr.Spec.Chart.Spec.SourceRef.Kind; k != kindHelmRepository {
+			return nil, nil, errors.Wrapf(errors.Errorf(errFmtUnsupportedSrc, k, hr.Spec.Chart.Spec.SourceRef.Name), errFmtConvert, key(hr))
+		}
+		ref := sourceRef(hr)
+		repo, ok := repos[ref]
+		if !ok {
+			return nil, nil, errors.Wrapf(errors.Errorf(errFmtHelmRepoNotFound, ref), errFmtConvert, key(hr))
+		}
+		r, w, err := ConvertHelmRelease(&objs[i], repo, o)
+		if err != nil {
+			return nil, nil, err
+		}
+		rels = append(rels, *r)
+		warnings = append(warnings, w...)
+	}
+	return rels, warnings, nil
+}
+
+// ConvertHelmRelease converts the supplied HelmRelease into a Release, whose
+// chart is served by the supplied HelmRepository.
+func ConvertHelmRelease(hru, repou *unstructured.Unstructured, o Options) (*v1beta1.Release, []string, error) {
+	hr := &helmRelease{}
+	if err := decode(hru, hr); err != nil {
+		return nil, nil, errors.Wrap(err, errDecodeHelmRelease)
+	}
+	repo := &helmRepository{}
+	if err := decode(repou, repo); err != nil {
+		return nil, nil, errors.Wrap(err, errDecodeHelmRepo)
+	}
+	c := &converter{hr: hr}
+	r, err := c.convert(repo, o)
+	return r, c.warnings, errors.Wrapf(err, errFmtConvert, key(hr))
+}
+
+type converter struct {
+	hr       *helmRelease
+	warnings []string
+}
+
+func (c *converter) warn(format string, args ...interface{}) {
+	c.warnings = append(c.warnings, fmt.Sprintf("HelmRelease %s: ", key(c.hr))+fmt.Sprintf(format, args...))
+}
+
+func (c *converter) convert(repo *helmRepository, o Options) (*v1beta1.Release, error) {
+	hr := c.hr
+	s := hr.Spec
+
+	if k := s.Chart.Spec.SourceRef.Kind; k != kindHelmRepository {
+		return nil, errors.Errorf(errFmtUnsupportedSrc, k, s.Chart.Spec.SourceRef.Name)
+	}
+	if repo.Spec.Type == "oci" || strings.HasPrefix(repo.Spec.URL, "oci://") {
+		return nil, errors.New(errOCINotSupported)
+	}
+
+	r := &v1beta1.Release{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1beta1.ReleaseGroupVersionKind.GroupVersion().String(),
+			Kind:       v1beta1.ReleaseKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   ReleaseName(hr.Namespace, hr.Name),
+			Labels: hr.Labels,
+		},
+	}
+	meta.SetExternalName(r, helmReleaseName(hr))
+	if o.ProviderConfigName != "" {
+		r.SetProviderConfigReference(&xpv1.Reference{Name: o.ProviderConfigName})
+	}
+
+	p := &r.Spec.ForProvider
+	p.Namespace = targetNamespace(hr)
+	p.Chart = v1beta1.ChartSpec{
+		Repository: repo.Spec.URL,
+		Name:       s.Chart.Spec.Chart,
+		Version:    c.chartVersion(s.Chart.Spec.Version),
+	}
+	if ref := repo.Spec.SecretRef; ref != nil {
+		p.Chart.PullSecretRef = xpv1.SecretReference{Namespace: repo.Namespace, Name: ref.Name}
+	}
+	if len(s.Chart.Spec.ValuesFiles) > 0 {
+		c.warn("values files of the chart are not supported, only its default values are used")
+	}
+
+	c.convertValues(p)
+	c.convertInstall(p)
+	if err := c.convertPostRenderers(p); err != nil {
+		return nil, err
+	}
+
+	// Flux stores releases in the namespace of the HelmRelease by default,
+	// whereas Releases store them in their target namespace.
+	st := s.StorageNamespace
+	if st == "" {
+		st = hr.Namespace
+	}
+	if st != p.Namespace {
+		c.warn("release is stored in namespace %q rather than the target namespace %q, so it is installed anew rather than adopted", st, p.Namespace)
+	}
+	if ref := s.KubeConfig; ref != nil {
+		k := ref.SecretRef.Key
+		if k == "" {
+			k = defaultKubeConfigKey
+		}
+		p.KubeConfigSecretRef = &xpv1.SecretKeySelector{
+			SecretReference: xpv1.SecretReference{Namespace: hr.Namespace, Name: ref.SecretRef.Name},
+			Key:             k,
+		}
+	}
+	if sa := s.ServiceAccountName; sa != "" {
+		p.ServiceAccountName = sa
+		if hr.Namespace != p.Namespace {
+			c.warn("ServiceAccount %q is impersonated in the target namespace %q rather than in namespace %q of the HelmRelease", sa, p.Namespace, hr.Namespace)
+		}
+	}
+	for _, d := range s.DependsOn {
+		ns := d.Namespace
+		if ns == "" {
+			ns = hr.Namespace
+		}
+		p.DependsOn = append(p.DependsOn, v1beta1.Dependency{Name: ReleaseName(ns, d.Name)})
+	}
+	p.MaxHistory = s.MaxHistory
+	if dd := s.DriftDetection; dd != nil {
+		switch dd.Mode {
+		case "enabled":
+			p.DriftPolicy = v1beta1.DriftPolicyCorrect
+		case "warn":
+			p.DriftPolicy = v1beta1.DriftPolicyDetect
+		}
+	}
+
+	r.Spec.Suspend = s.Suspend
+	r.Spec.PollInterval = s.Interval
+	if u := s.Upgrade; u != nil && u.Remediation != nil && u.Remediation.Retries > 0 {
+		retries := u.Remediation.Retries
+		r.Spec.RollbackRetriesLimit = &retries
+	}
+
+	if s.Test != nil {
+		c.warn("test settings are not supported")
+	}
+	if s.Rollback != nil {
+		c.warn("rollback settings are not supported")
+	}
+	if s.Uninstall != nil {
+		c.warn("uninstall settings are not supported")
+	}
+	return r, nil
+}
+
+// chartVersion returns the supplied version if it is exact. Version ranges
+// are not supported, in which case the latest version is used.
+func (c *converter) chartVersion(v string) string {
+	if v == "" || v == "*" {
+		return ""
+	}
+	if _, err := semver.StrictNewVersion(strings.TrimPrefix(v, "v")); err != nil {
+		c.warn("chart version range %q is not supported, the latest version is used", v)
+		return ""
+	}
+	return v
+}
+
+func (c *converter) convertValues(p *v1beta1.ReleaseParameters) {
+	s := c.hr.Spec
+	if len(s.Values) > 0 {
+		p.Values = runtime.RawExtension{Raw: s.Values}
+	}
+	for _, vf := range s.ValuesFrom {
+		k := vf.ValuesKey
+		if k == "" {
+			k = defaultValuesKey
+		}
+		sel := &v1beta1.DataKeySelector{
+			NamespacedName: v1beta1.NamespacedName{Namespace: c.hr.Namespace, Name: vf.Name},
+			Key:            k,
+			Optional:       vf.Optional,
+		}
+		src := v1beta1.ValueFromSource{}
+		switch vf.Kind {
+		case "ConfigMap":
+			src.ConfigMapKeyRef = sel
+		case "Secret":
+			src.SecretKeyRef = sel
+		default:
+			c.warn("values from %s %q are not supported", vf.Kind, vf.Name)
+			continue
+		}
+		if vf.TargetPath != "" {
+			p.Set = append(p.Set, v1beta1.SetVal{Name: vf.TargetPath, ValueFrom: &src})
+			continue
+		}
+		p.ValuesFrom = append(p.ValuesFrom, src)
+	}
+	if len(p.Set) > 0 && (len(p.ValuesFrom) > 0 || len(s.Values) > 0) {
+		// Flux merges values in order, whereas set values always take
+		// precedence.
+		c.warn("values with a target path take precedence over all other values")
+	}
+}
+
+func (c *converter) convertInstall(p *v1beta1.ReleaseParameters) {
+	s := c.hr.Spec
+	// Flux waits for releases and doesn't create their namespace by
+	// default.
+	p.Wait = pointer.Bool(true)
+	p.SkipCreateNamespace = pointer.Bool(true)
+	p.WaitTimeout = s.Timeout
+	if i := s.Install; i != nil {
+		p.Wait = pointer.Bool(!i.DisableWait)
+		p.SkipCreateNamespace = pointer.Bool(!i.CreateNamespace)
+		p.SkipCRDs = pointer.Bool(i.SkipCRDs || i.CRDs == "Skip")
+		if i.Timeout != nil {
+			p.WaitTimeout = i.Timeout
+		}
+		if i.CRDs == "CreateReplace" {
+			c.warn("CRDs are created but not replaced on upgrades")
+		}
+	}
+	if u := s.Upgrade; u != nil && u.DisableWait == *p.Wait {
+		c.warn("waiting for upgrades is configured like waiting for installs")
+	}
+}
+
+func (c *converter) convertPostRenderers(p *v1beta1.ReleaseParameters) error {
+	var patches []v1beta1.KustomizePatch
+	for _, pr := range c.hr.Spec.PostRenderers {
+		k := pr.Kustomize
+		if k == nil {
+			continue
+		}
+		for _, pt := range k.Patches {
+			patches = append(patches, v1beta1.KustomizePatch{Patch: pt.Patch, Target: target(pt.Target)})
+		}
+		for _, pt := range k.PatchesStrategicMerge {
+			patches = append(patches, v1beta1.KustomizePatch{Patch: string(pt)})
+		}
+		for _, pt := range k.PatchesJSON6902 {
+			b, err := json.Marshal(pt.Patch)
+			if err != nil {
+				return errors.Wrap(err, errEncodePatch)
+			}
+			t := pt.Target
+			patches = append(patches, v1beta1.KustomizePatch{Patch: string(b), Target: target(&t)})
+		}
+		if len(k.Images) > 0 {
+			c.warn("kustomize image overrides are not supported")
+		}
+	}
+	if len(patches) > 0 {
+		p.PostRender = &v1beta1.PostRender{Kustomize: &v1beta1.KustomizePostRender{Patches: patches}}
+	}
+	return nil
+}
+
+func target(s *selector) *v1beta1.KustomizePatchTarget {
+	if s == nil {
+		return nil
+	}
+	return &v1beta1.KustomizePatchTarget{
+		Group:              s.Group,
+		Version:            s.Version,
+		Kind:               s.Kind,
+		Name:               s.Name,
+		Namespace:          s.Namespace,
+		LabelSelector:      s.LabelSelector,
+		AnnotationSelector: s.AnnotationSelector,
+	}
+}
+
+// ReleaseName returns the name of the Release converted from the supplied
+// HelmRelease. Releases are cluster scoped, so it is prefixed with the
+// namespace of the HelmRelease.
+func ReleaseName(namespace, name string) string {
+	return namespace + "-" + name
+}
+
+// helmReleaseName returns the name of the Helm release of the supplied
+// HelmRelease, which defaults to [TargetNamespace-]Name.
+func helmReleaseName(hr *helmRelease) string {
+	if n := hr.Spec.ReleaseName; n != "" {
+		return n
+	}
+	if ns := hr.Spec.TargetNamespace; ns != "" {
+		return ns + "-" + hr.Name
+	}
+	return hr.Name
+}
+
+func targetNamespace(hr *helmRelease) string {
+	if ns := hr.Spec.TargetNamespace; ns != "" {
+		return ns
+	}
+	return hr.Namespace
+}
+
+func sourceRef(hr *helmRelease) types.NamespacedName {
+	ref := hr.Spec.Chart.Spec.SourceRef
+	ns := ref.Namespace
+	if ns == "" {
+		ns = hr.Namespace
+	}
+	return types.NamespacedName{Namespace: ns, Name: ref.Name}
+}
+
+func key(hr *helmRelease) string {
+	return types.NamespacedName{Namespace: hr.Namespace, Name: hr.Name}.String()
+}
+
+func isKind(u *unstructured.Unstructured, group, kind string) bool {
+	gvk := u.GroupVersionKind()
+	return gvk.Group == group && gvk.Kind == kind
+}
+
+func decode(u *unstructured.Unstructured, into interface{}) error {
+	b, err := u.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, into)
+}
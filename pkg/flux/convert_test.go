@@ -0,0 +1,312 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flux
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const testRepository = `
+apiVersion: source.toolkit.fluxcd.io/v1beta2
+kind: HelmRepository
+metadata:
+  name: charts
+  namespace: flux-system
+spec:
+  url: https://charts.example.org
+  secretRef:
+    name: creds
+`
+
+func TestConvert(t *testing.T) {
+	minute := &metav1.Duration{Duration: time.Minute}
+	retries, history := int32(2), int32(3)
+	release := func(name, external string, m ...func(r *v1beta1.Release)) v1beta1.Release {
+		r := v1beta1.Release{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "helm.crossplane.io/v1beta1", Kind: "Release"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{"crossplane.io/external-name": external}},
+		}
+		r.SetProviderConfigReference(&xpv1.Reference{Name: "cluster"})
+		r.Spec.ForProvider.Chart = v1beta1.ChartSpec{
+			Repository:    "https://charts.example.org",
+			Name:          "app",
+			PullSecretRef: xpv1.SecretReference{Namespace: "flux-system", Name: "creds"},
+		}
+		r.Spec.ForProvider.Namespace = "apps"
+		r.Spec.ForProvider.Wait = pointer.Bool(true)
+		r.Spec.ForProvider.SkipCreateNamespace = pointer.Bool(true)
+		for _, f := range m {
+			f(&r)
+		}
+		return r
+	}
+
+	type want struct {
+		rels     []v1beta1.Release
+		warnings []string
+		err      error
+	}
+	cases := map[string]struct {
+		reason string
+		yaml   string
+		want   want
+	}{
+		"Minimal": {
+			reason: "A HelmRelease with defaults should be converted into a Release of the same release.",
+			yaml: testRepository + `---
+apiVersion: helm.toolkit.fluxcd.io/v2beta1
+kind: HelmRelease
+metadata:
+  name: app
+  namespace: apps
+spec:
+  chart:
+    spec:
+      chart: app
+      sourceRef:
+        kind: HelmRepository
+        name: charts
+        namespace: flux-system
+`,
+			want: want{rels: []v1beta1.Release{release("apps-app", "app")}},
+		},
+		"Full": {
+			reason: "Settings of a HelmRelease should be converted into their Release counterparts.",
+			yaml: testRepository + `---
+apiVersion: helm.toolkit.fluxcd.io/v2beta1
+kind: HelmRelease
+metadata:
+  name: app
+  namespace: apps
+spec:
+  interval: 1m
+  suspend: true
+  releaseName: my-app
+  maxHistory: 3
+  timeout: 1m
+  serviceAccountName: deployer
+  kubeConfig:
+    secretRef:
+      name: remote
+  chart:
+    spec:
+      chart: app
+      version: 1.2.3
+      sourceRef:
+        kind: HelmRepository
+        name: charts
+        namespace: flux-system
+  dependsOn:
+  - name: db
+    namespace: data
+  install:
+    createNamespace: true
+    disableWait: true
+    crds: Skip
+  upgrade:
+    disableWait: true
+    remediation:
+      retries: 2
+  driftDetection:
+    mode: warn
+  values:
+    replicas: 2
+  valuesFrom:
+  - kind: ConfigMap
+    name: values
+  - kind: Secret
+    name: password
+    valuesKey: pw
+    optional: true
+  postRenderers:
+  - kustomize:
+      patches:
+      - patch: |
+          - op: remove
+            path: /spec/replicas
+        target:
+          kind: Deployment
+`,
+			want: want{rels: []v1beta1.Release{release("apps-app", "my-app", func(r *v1beta1.Release) {
+				r.Spec.Suspend = true
+				r.Spec.PollInterval = minute
+				r.Spec.RollbackRetriesLimit = &retries
+				p := &r.Spec.ForProvider
+				p.Chart.Version = "1.2.3"
+				p.MaxHistory = &history
+				p.WaitTimeout = minute
+				p.Wait = pointer.Bool(false)
+				p.SkipCreateNamespace = pointer.Bool(false)
+				p.SkipCRDs = pointer.Bool(true)
+				p.ServiceAccountName = "deployer"
+				p.KubeConfigSecretRef = &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Namespace: "apps", Name: "remote"}, Key: "value"}
+				p.DependsOn = []v1beta1.Dependency{{Name: "data-db"}}
+				p.DriftPolicy = v1beta1.DriftPolicyDetect
+				p.Values = runtime.RawExtension{Raw: []byte(`{"replicas":2}`)}
+				p.ValuesFrom = []v1beta1.ValueFromSource{
+					{ConfigMapKeyRef: &v1beta1.DataKeySelector{NamespacedName: v1beta1.NamespacedName{Namespace: "apps", Name: "values"}, Key: "values.yaml"}},
+					{SecretKeyRef: &v1beta1.DataKeySelector{NamespacedName: v1beta1.NamespacedName{Namespace: "apps", Name: "password"}, Key: "pw", Optional: true}},
+				}
+				p.PostRender = &v1beta1.PostRender{Kustomize: &v1beta1.KustomizePostRender{Patches: []v1beta1.KustomizePatch{{
+					Patch:  "- op: remove\n  path: /spec/replicas\n",
+					Target: &v1beta1.KustomizePatchTarget{Kind: "Deployment"},
+				}}}}
+			})}},
+		},
+		"Unsupported": {
+			reason: "Settings that can't be converted should be reported as warnings.",
+			yaml: testRepository + `---
+apiVersion: helm.toolkit.fluxcd.io/v2beta1
+kind: HelmRelease
+metadata:
+  name: app
+  namespace: apps
+spec:
+  targetNamespace: prod
+  chart:
+    spec:
+      chart: app
+      version: 1.x
+      valuesFiles:
+      - values-prod.yaml
+      sourceRef:
+        kind: HelmRepository
+        name: charts
+        namespace: flux-system
+  test:
+    enable: true
+  valuesFrom:
+  - kind: Secret
+    name: password
+    targetPath: auth.password
+  values:
+    replicas: 2
+`,
+			want: want{
+				rels: []v1beta1.Release{release("apps-app", "prod-app", func(r *v1beta1.Release) {
+					p := &r.Spec.ForProvider
+					p.Namespace = "prod"
+					p.Values = runtime.RawExtension{Raw: []byte(`{"replicas":2}`)}
+					p.Set = []v1beta1.SetVal{{Name: "auth.password", ValueFrom: &v1beta1.ValueFromSource{
+						SecretKeyRef: &v1beta1.DataKeySelector{NamespacedName: v1beta1.NamespacedName{Namespace: "apps", Name: "password"}, Key: "values.yaml"},
+					}}}
+				})},
+				warnings: []string{
+					`HelmRelease apps/app: chart version range "1.x" is not supported, the latest version is used`,
+					`HelmRelease apps/app: values files of the chart are not supported, only its default values are used`,
+					`HelmRelease apps/app: values with a target path take precedence over all other values`,
+					`HelmRelease apps/app: release is stored in namespace "apps" rather than the target namespace "prod", so it is installed anew rather than adopted`,
+					`HelmRelease apps/app: test settings are not supported`,
+				},
+			},
+		},
+		"GitSource": {
+			reason: "Charts of sources other than HelmRepositories can't be converted.",
+			yaml: `
+apiVersion: helm.toolkit.fluxcd.io/v2beta1
+kind: HelmRelease
+metadata:
+  name: app
+  namespace: apps
+spec:
+  chart:
+    spec:
+      chart: ./charts/app
+      sourceRef:
+        kind: GitRepository
+        name: repo
+`,
+			want: want{err: errors.Wrapf(errors.Errorf(errFmtUnsupportedSrc, "GitRepository", "repo"), errFmtConvert, "apps/app")},
+		},
+		"RepositoryNotFound": {
+			reason: "HelmReleases whose HelmRepository is missing can't be converted.",
+			yaml: `
+apiVersion: helm.toolkit.fluxcd.io/v2beta1
+kind: HelmRelease
+metadata:
+  name: app
+  namespace: apps
+spec:
+  chart:
+    spec:
+      chart: app
+      sourceRef:
+        kind: HelmRepository
+        name: charts
+`,
+			want: want{err: errors.Wrapf(errors.Errorf(errFmtHelmRepoNotFound, "apps/charts"), errFmtConvert, "apps/app")},
+		},
+		"OCIRepository": {
+			reason: "Charts of OCI repositories can't be converted.",
+			yaml: `
+apiVersion: source.toolkit.fluxcd.io/v1beta2
+kind: HelmRepository
+metadata:
+  name: charts
+  namespace: apps
+spec:
+  type: oci
+  url: oci://registry.example.org/charts
+---
+apiVersion: helm.toolkit.fluxcd.io/v2beta1
+kind: HelmRelease
+metadata:
+  name: app
+  namespace: apps
+spec:
+  chart:
+    spec:
+      chart: app
+      sourceRef:
+        kind: HelmRepository
+        name: charts
+`,
+			want: want{err: errors.Wrapf(errors.New(errOCINotSupported), errFmtConvert, "apps/app")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			objs, err := Decode(strings.NewReader(tc.yaml))
+			if err != nil {
+				t.Fatalf("Decode(...): %v", err)
+			}
+			rels, warnings, err := Convert(objs, Options{ProviderConfigName: "cluster"})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nConvert(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.rels, rels); diff != "" {
+				t.Errorf("\n%s\nConvert(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.warnings, warnings); diff != "" {
+				t.Errorf("\n%s\nConvert(...): -want warnings, +got warnings:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
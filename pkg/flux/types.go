@@ -0,0 +1,158 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flux
+
+import (
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The subset of the Flux HelmRelease and HelmRepository APIs that is
+// converted. Both the v2beta1 and v2beta2 HelmRelease and the v1beta1 and
+// v1beta2 HelmRepository APIs decode into these types.
+
+type helmRelease struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              helmReleaseSpec `json:"spec"`
+}
+
+type helmReleaseSpec struct {
+	Chart              helmChartTemplate         `json:"chart"`
+	Interval           *metav1.Duration          `json:"interval,omitempty"`
+	KubeConfig         *kubeConfig               `json:"kubeConfig,omitempty"`
+	Suspend            bool                      `json:"suspend,omitempty"`
+	ReleaseName        string                    `json:"releaseName,omitempty"`
+	TargetNamespace    string                    `json:"targetNamespace,omitempty"`
+	StorageNamespace   string                    `json:"storageNamespace,omitempty"`
+	DependsOn          []crossNamespaceReference `json:"dependsOn,omitempty"`
+	Timeout            *metav1.Duration          `json:"timeout,omitempty"`
+	MaxHistory         *int32                    `json:"maxHistory,omitempty"`
+	ServiceAccountName string                    `json:"serviceAccountName,omitempty"`
+	Install            *install                  `json:"install,omitempty"`
+	Upgrade            *upgrade                  `json:"upgrade,omitempty"`
+	Test               json.RawMessage           `json:"test,omitempty"`
+	Rollback           json.RawMessage           `json:"rollback,omitempty"`
+	Uninstall          json.RawMessage           `json:"uninstall,omitempty"`
+	DriftDetection     *driftDetection           `json:"driftDetection,omitempty"`
+	ValuesFrom         []valuesReference         `json:"valuesFrom,omitempty"`
+	Values             json.RawMessage           `json:"values,omitempty"`
+	PostRenderers      []postRenderer            `json:"postRenderers,omitempty"`
+}
+
+type helmChartTemplate struct {
+	Spec helmChartTemplateSpec `json:"spec"`
+}
+
+type helmChartTemplateSpec struct {
+	Chart       string                  `json:"chart"`
+	Version     string                  `json:"version,omitempty"`
+	SourceRef   crossNamespaceReference `json:"sourceRef"`
+	ValuesFiles []string                `json:"valuesFiles,omitempty"`
+}
+
+type crossNamespaceReference struct {
+	Kind      string `json:"kind,omitempty"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+type kubeConfig struct {
+	SecretRef secretKeyReference `json:"secretRef"`
+}
+
+type secretKeyReference struct {
+	Name string `json:"name"`
+	Key  string `json:"key,omitempty"`
+}
+
+type remediation struct {
+	Retries int32 `json:"retries,omitempty"`
+}
+
+type install struct {
+	Timeout         *metav1.Duration `json:"timeout,omitempty"`
+	Remediation     *remediation     `json:"remediation,omitempty"`
+	DisableWait     bool             `json:"disableWait,omitempty"`
+	SkipCRDs        bool             `json:"skipCRDs,omitempty"`
+	CRDs            string           `json:"crds,omitempty"`
+	CreateNamespace bool             `json:"createNamespace,omitempty"`
+}
+
+type upgrade struct {
+	Timeout     *metav1.Duration `json:"timeout,omitempty"`
+	Remediation *remediation     `json:"remediation,omitempty"`
+	DisableWait bool             `json:"disableWait,omitempty"`
+}
+
+type driftDetection struct {
+	Mode string `json:"mode,omitempty"`
+}
+
+type valuesReference struct {
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	ValuesKey  string `json:"valuesKey,omitempty"`
+	TargetPath string `json:"targetPath,omitempty"`
+	Optional   bool   `json:"optional,omitempty"`
+}
+
+type postRenderer struct {
+	Kustomize *kustomize `json:"kustomize,omitempty"`
+}
+
+type kustomize struct {
+	Patches               []patch           `json:"patches,omitempty"`
+	PatchesStrategicMerge []json.RawMessage `json:"patchesStrategicMerge,omitempty"`
+	PatchesJSON6902       []jsonPatch       `json:"patchesJson6902,omitempty"`
+	Images                []json.RawMessage `json:"images,omitempty"`
+}
+
+type patch struct {
+	Patch  string    `json:"patch"`
+	Target *selector `json:"target,omitempty"`
+}
+
+type jsonPatch struct {
+	Target selector          `json:"target"`
+	Patch  []json.RawMessage `json:"patch"`
+}
+
+type selector struct {
+	Group              string `json:"group,omitempty"`
+	Version            string `json:"version,omitempty"`
+	Kind               string `json:"kind,omitempty"`
+	Name               string `json:"name,omitempty"`
+	Namespace          string `json:"namespace,omitempty"`
+	AnnotationSelector string `json:"annotationSelector,omitempty"`
+	LabelSelector      string `json:"labelSelector,omitempty"`
+}
+
+type helmRepository struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              helmRepositorySpec `json:"spec"`
+}
+
+type helmRepositorySpec struct {
+	URL       string                `json:"url"`
+	SecretRef *localObjectReference `json:"secretRef,omitempty"`
+	Type      string                `json:"type,omitempty"`
+}
+
+type localObjectReference struct {
+	Name string `json:"name"`
+}
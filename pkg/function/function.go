@@ -0,0 +1,300 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package function implements a Crossplane composition function that renders
+// a Helm chart like a Release does, and returns the rendered manifests as
+// desired composed resources rather than installing them as a release.
+package function
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+	helmClient "github.com/crossplane-contrib/provider-helm/pkg/clients/helm"
+	"github.com/crossplane-contrib/provider-helm/pkg/controller/release"
+)
+
+const (
+	errDecodeFunctionIO   = "cannot decode FunctionIO"
+	errEncodeFunctionIO   = "cannot encode FunctionIO"
+	errGetConfig          = "cannot get function config"
+	errGetComposite       = "cannot get observed composite resource"
+	errGetDesired         = "cannot get desired resources"
+	errSetDesired         = "cannot set desired resources"
+	errSetResults         = "cannot set results"
+	errNoChart            = "chart name or URL is required"
+	errValuesFrom         = "values from ConfigMaps and Secrets are not supported"
+	errPullSecret         = "charts are pulled anonymously, pull secrets are not supported"
+	errFmtFromFieldPath   = "cannot get value %q from the composite resource"
+	errComposeValues      = "cannot compose values"
+	errRender             = "cannot render chart"
+	errParseManifest      = "cannot parse rendered manifest"
+	errNewHelmClient      = "cannot create Helm client"
+	errPullChart          = "cannot pull chart"
+	errFmtDuplicateObject = "chart renders %s more than once"
+)
+
+const (
+	defaultNamespace = "default"
+
+	objectAPIVersion = "kubernetes.crossplane.io/v1alpha1"
+	objectKind       = "Object"
+
+	severityFatal = "Fatal"
+)
+
+// Config of the function, supplied by the Composition.
+type Config struct {
+	// Chart to render. Charts are pulled anonymously.
+	Chart v1beta1.ChartSpec `json:"chart"`
+
+	// ReleaseName the chart is rendered as. Defaults to the name of the
+	// composite resource.
+	// +optional
+	ReleaseName string `json:"releaseName,omitempty"`
+
+	// Namespace the chart is rendered for. Defaults to "default". Charts
+	// that don't set the namespace of their resources render them without
+	// one.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Values of the chart, composed like those of a Release. Values from
+	// ConfigMaps and Secrets are not supported.
+	v1beta1.ValuesSpec `json:",inline"`
+
+	// SetFromComposite sets values from fields of the observed composite
+	// resource. They take precedence over all other values.
+	// +optional
+	SetFromComposite []FieldValue `json:"setFromComposite,omitempty"`
+
+	// ObjectProviderConfigName wraps every rendered resource in a
+	// provider-kubernetes Object using the named ProviderConfig, so that
+	// the resources are deployed to the cluster of the ProviderConfig
+	// rather than composed directly.
+	// +optional
+	ObjectProviderConfigName string `json:"objectProviderConfigName,omitempty"`
+}
+
+// A FieldValue sets a value from a field of the composite resource.
+type FieldValue struct {
+	// Name of the value, like that of Set.
+	Name string `json:"name"`
+	// FromFieldPath is the path of the field of the composite resource.
+	FromFieldPath string `json:"fromFieldPath"`
+}
+
+// A Renderer renders the supplied chart with the supplied values as the
+// supplied release, returning its manifest.
+type Renderer func(cfg *Config, releaseName string, vals map[string]interface{}) (string, error)
+
+// Function renders charts of FunctionIOs.
+type Function struct {
+	render Renderer
+}
+
+// New returns a Function that renders charts using the supplied Renderer.
+func New(r Renderer) *Function {
+	return &Function{render: r}
+}
+
+// NewHelmRenderer returns a Renderer that pulls and renders charts entirely
+// on the client, like a Release in render only mode does.
+func NewHelmRenderer(log logging.Logger) Renderer {
+	return func(cfg *Config, releaseName string, vals map[string]interface{}) (string, error) {
+		ns := cfg.Namespace
+		h, err := helmClient.NewClient(log, &rest.Config{}, func(a *helmClient.Args) { a.Namespace = ns })
+		if err != nil {
+			return "", errors.Wrap(err, errNewHelmClient)
+		}
+		ch, err := h.PullAndLoadChart(&cfg.Chart, &helmClient.RepoCreds{})
+		if err != nil {
+			return "", errors.Wrap(err, errPullChart)
+		}
+		rel, err := h.Template(releaseName, ch, vals, nil)
+		if err != nil {
+			return "", err
+		}
+		return rel.Manifest, nil
+	}
+}
+
+// Run renders the chart configured by the supplied FunctionIO and returns
+// it with the rendered resources appended to its desired resources. Failures
+// to render the chart are returned as a fatal result of the FunctionIO.
+func (f *Function) Run(ctx context.Context, in []byte) ([]byte, error) {
+	io := map[string]interface{}{}
+	if err := yaml.Unmarshal(in, &io); err != nil {
+		return nil, errors.Wrap(err, errDecodeFunctionIO)
+	}
+	p := fieldpath.Pave(io)
+
+	if err := f.run(ctx, p); err != nil {
+		results, _ := p.GetValue("results")
+		r, _ := results.([]interface{})
+		r = append(r, map[string]interface{}{"severity": severityFatal, "message": err.Error()})
+		if err := p.SetValue("results", r); err != nil {
+			return nil, errors.Wrap(err, errSetResults)
+		}
+	}
+
+	out, err := yaml.Marshal(io)
+	return out, errors.Wrap(err, errEncodeFunctionIO)
+}
+
+func (f *Function) run(ctx context.Context, p *fieldpath.Paved) error {
+	cfg := &Config{}
+	if err := p.GetValueInto("config", cfg); err != nil {
+		return errors.Wrap(err, errGetConfig)
+	}
+	if cfg.Chart.Name == "" && cfg.Chart.URL == "" {
+		return errors.New(errNoChart)
+	}
+	if cfg.Chart.PullSecretRef.Name != "" {
+		return errors.New(errPullSecret)
+	}
+	if cfg.Namespace == "" {
+		cfg.Namespace = defaultNamespace
+	}
+
+	xr := &unstructured.Unstructured{}
+	if err := p.GetValueInto("observed.composite.resource", &xr.Object); err != nil {
+		return errors.Wrap(err, errGetComposite)
+	}
+	name := cfg.ReleaseName
+	if name == "" {
+		name = xr.GetName()
+	}
+
+	vals, err := composeValues(ctx, cfg, xr)
+	if err != nil {
+		return err
+	}
+	manifest, err := f.render(cfg, name, vals)
+	if err != nil {
+		return errors.Wrap(err, errRender)
+	}
+	rendered, err := desiredResources(cfg, manifest)
+	if err != nil {
+		return err
+	}
+
+	var desired []interface{}
+	if v, err := p.GetValue("desired.resources"); err == nil {
+		desired, _ = v.([]interface{})
+	} else if !fieldpath.IsNotFound(err) {
+		return errors.Wrap(err, errGetDesired)
+	}
+	return errors.Wrap(p.SetValue("desired.resources", append(desired, rendered...)), errSetDesired)
+}
+
+// composeValues composes the values of the supplied config like those of a
+// Release, with values set from the supplied composite resource taking
+// precedence.
+func composeValues(ctx context.Context, cfg *Config, xr *unstructured.Unstructured) (map[string]interface{}, error) {
+	spec := *cfg.ValuesSpec.DeepCopy()
+	if len(spec.ValuesFrom) > 0 {
+		return nil, errors.New(errValuesFrom)
+	}
+	for _, s := range spec.Set {
+		if s.ValueFrom != nil {
+			return nil, errors.New(errValuesFrom)
+		}
+	}
+	for _, fv := range cfg.SetFromComposite {
+		v, err := fieldpath.Pave(xr.Object).GetValue(fv.FromFieldPath)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtFromFieldPath, fv.FromFieldPath)
+		}
+		spec.Set = append(spec.Set, v1beta1.SetVal{Name: fv.Name, Value: fmt.Sprint(v)})
+	}
+	if len(spec.Values.Raw) == 0 {
+		spec.Values.Raw = []byte("{}")
+	}
+	vals, err := release.ComposeValues(ctx, nil, spec)
+	return vals, errors.Wrap(err, errComposeValues)
+}
+
+// desiredResources returns the desired composed resources of the resources
+// of the supplied manifest, named after their kind, namespace and name.
+func desiredResources(cfg *Config, manifest string) ([]interface{}, error) {
+	docs := releaseutil.SplitManifests(manifest)
+	keys := make([]string, 0, len(docs))
+	for k := range docs {
+		keys = append(keys, k)
+	}
+	sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+
+	seen := map[string]bool{}
+	res := make([]interface{}, 0, len(keys))
+	for _, k := range keys {
+		u := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(docs[k]), &u.Object); err != nil {
+			return nil, errors.Wrap(err, errParseManifest)
+		}
+		if len(u.Object) == 0 {
+			continue
+		}
+		name := resourceName(u)
+		if seen[name] {
+			return nil, errors.Errorf(errFmtDuplicateObject, name)
+		}
+		seen[name] = true
+
+		obj := u.Object
+		if cfg.ObjectProviderConfigName != "" {
+			obj = wrap(u, name, cfg.ObjectProviderConfigName)
+		}
+		res = append(res, map[string]interface{}{"name": name, "resource": obj})
+	}
+	return res, nil
+}
+
+// resourceName returns the name of the desired composed resource of the
+// supplied rendered resource.
+func resourceName(u *unstructured.Unstructured) string {
+	parts := []string{strings.ToLower(u.GetKind())}
+	if ns := u.GetNamespace(); ns != "" {
+		parts = append(parts, ns)
+	}
+	return strings.Join(append(parts, u.GetName()), "-")
+}
+
+// wrap returns a provider-kubernetes Object that deploys the supplied
+// resource using the supplied ProviderConfig.
+func wrap(u *unstructured.Unstructured, name, providerConfig string) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": objectAPIVersion,
+		"kind":       objectKind,
+		"metadata":   map[string]interface{}{"name": name},
+		"spec": map[string]interface{}{
+			"forProvider":       map[string]interface{}{"manifest": u.Object},
+			"providerConfigRef": map[string]interface{}{"name": providerConfig},
+		},
+	}
+}
@@ -0,0 +1,323 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package function
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+const testManifest = `---
+# Source: app/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+---
+# Source: app/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: apps
+spec:
+  replicas: 2
+`
+
+func TestRun(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		render Renderer
+		in     string
+	}
+	type want struct {
+		out  string
+		vals map[string]interface{}
+		name string
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Rendered": {
+			reason: "Rendered resources should be appended to the desired resources, with composite values taking precedence.",
+			args: args{
+				in: `
+apiVersion: apiextensions.crossplane.io/v1alpha1
+kind: FunctionIO
+config:
+  chart:
+    repository: https://charts.example.org
+    name: app
+  values:
+    replicas: 1
+    image: app
+  set:
+  - name: image
+    value: app:v1
+  setFromComposite:
+  - name: replicas
+    fromFieldPath: spec.replicas
+observed:
+  composite:
+    resource:
+      apiVersion: example.org/v1
+      kind: XApp
+      metadata:
+        name: my-app
+      spec:
+        replicas: 2
+desired:
+  resources:
+  - name: existing
+    resource:
+      apiVersion: v1
+      kind: ConfigMap
+`,
+			},
+			want: want{
+				name: "my-app",
+				vals: map[string]interface{}{"replicas": int64(2), "image": "app:v1"},
+				out: `
+apiVersion: apiextensions.crossplane.io/v1alpha1
+kind: FunctionIO
+config:
+  chart:
+    repository: https://charts.example.org
+    name: app
+  values:
+    replicas: 1
+    image: app
+  set:
+  - name: image
+    value: app:v1
+  setFromComposite:
+  - name: replicas
+    fromFieldPath: spec.replicas
+observed:
+  composite:
+    resource:
+      apiVersion: example.org/v1
+      kind: XApp
+      metadata:
+        name: my-app
+      spec:
+        replicas: 2
+desired:
+  resources:
+  - name: existing
+    resource:
+      apiVersion: v1
+      kind: ConfigMap
+  - name: service-app
+    resource:
+      apiVersion: v1
+      kind: Service
+      metadata:
+        name: app
+  - name: deployment-apps-app
+    resource:
+      apiVersion: apps/v1
+      kind: Deployment
+      metadata:
+        name: app
+        namespace: apps
+      spec:
+        replicas: 2
+`,
+			},
+		},
+		"WrappedInObjects": {
+			reason: "Rendered resources should be wrapped in Objects if a ProviderConfig of them is configured.",
+			args: args{
+				in: `
+config:
+  chart:
+    name: app
+  releaseName: app
+  objectProviderConfigName: remote
+observed:
+  composite:
+    resource:
+      metadata:
+        name: my-app
+`,
+			},
+			want: want{
+				name: "app",
+				vals: map[string]interface{}{},
+				out: `
+config:
+  chart:
+    name: app
+  releaseName: app
+  objectProviderConfigName: remote
+observed:
+  composite:
+    resource:
+      metadata:
+        name: my-app
+desired:
+  resources:
+  - name: service-app
+    resource:
+      apiVersion: kubernetes.crossplane.io/v1alpha1
+      kind: Object
+      metadata:
+        name: service-app
+      spec:
+        forProvider:
+          manifest:
+            apiVersion: v1
+            kind: Service
+            metadata:
+              name: app
+        providerConfigRef:
+          name: remote
+  - name: deployment-apps-app
+    resource:
+      apiVersion: kubernetes.crossplane.io/v1alpha1
+      kind: Object
+      metadata:
+        name: deployment-apps-app
+      spec:
+        forProvider:
+          manifest:
+            apiVersion: apps/v1
+            kind: Deployment
+            metadata:
+              name: app
+              namespace: apps
+            spec:
+              replicas: 2
+        providerConfigRef:
+          name: remote
+`,
+			},
+		},
+		"ValuesFromNotSupported": {
+			reason: "Values from ConfigMaps and Secrets should be reported as a fatal result.",
+			args: args{
+				in: `
+config:
+  chart:
+    name: app
+  valuesFrom:
+  - configMapKeyRef:
+      name: values
+      namespace: default
+      key: values.yaml
+observed:
+  composite:
+    resource:
+      metadata:
+        name: my-app
+`,
+			},
+			want: want{
+				out: `
+config:
+  chart:
+    name: app
+  valuesFrom:
+  - configMapKeyRef:
+      name: values
+      namespace: default
+      key: values.yaml
+observed:
+  composite:
+    resource:
+      metadata:
+        name: my-app
+results:
+- severity: Fatal
+  message: ` + errValuesFrom + `
+`,
+			},
+		},
+		"RenderError": {
+			reason: "Errors rendering the chart should be reported as a fatal result.",
+			args: args{
+				render: func(_ *Config, _ string, _ map[string]interface{}) (string, error) { return "", errBoom },
+				in: `
+config:
+  chart:
+    name: app
+observed:
+  composite:
+    resource:
+      metadata:
+        name: my-app
+`,
+			},
+			want: want{
+				out: `
+config:
+  chart:
+    name: app
+observed:
+  composite:
+    resource:
+      metadata:
+        name: my-app
+results:
+- severity: Fatal
+  message: "` + errors.Wrap(errBoom, errRender).Error() + `"
+`,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var gotName string
+			var gotVals map[string]interface{}
+			r := tc.args.render
+			if r == nil {
+				r = func(cfg *Config, releaseName string, vals map[string]interface{}) (string, error) {
+					gotName, gotVals = releaseName, vals
+					return testManifest, nil
+				}
+			}
+			out, err := New(r).Run(context.Background(), []byte(tc.args.in))
+			if err != nil {
+				t.Fatalf("\n%s\nRun(...): %v", tc.reason, err)
+			}
+			got, want := map[string]interface{}{}, map[string]interface{}{}
+			if err := yaml.Unmarshal(out, &got); err != nil {
+				t.Fatal(err)
+			}
+			if err := yaml.Unmarshal([]byte(tc.want.out), &want); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("\n%s\nRun(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.name, gotName); diff != "" {
+				t.Errorf("\n%s\nRun(...): -want release name, +got release name:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.vals, gotVals); diff != "" {
+				t.Errorf("\n%s\nRun(...): -want values, +got values:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package importer generates Releases adopting the Helm releases of a
+// cluster, to ease onboarding clusters whose releases were installed by other
+// tools.
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/release"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+const (
+	errFmtEncodeValues = "cannot encode values of release %s"
+)
+
+// Options of the import.
+type Options struct {
+	// ProviderConfigName is the ProviderConfig of the imported Releases.
+	// Defaults to the default ProviderConfig.
+	ProviderConfigName string
+
+	// Repositories maps chart names to the URLs of the repositories serving
+	// them. Helm doesn't record the repository a chart was pulled from.
+	Repositories map[string]string
+}
+
+// Import returns Releases adopting the supplied Helm releases. Only the
+// latest revision of every release is imported. It returns warnings about
+// the releases whose Releases need to be completed or reviewed before they
+// are applied.
+func Import(rels []*release.Release, o Options) ([]v1beta1.Release, []string, error) {
+	latest := map[string]*release.Release{}
+	for _, r := range rels {
+		k := key(r)
+		if l, ok := latest[k]; !ok || r.Version > l.Version {
+			latest[k] = r
+		}
+	}
+	keys := make([]string, 0, len(latest))
+	for k := range latest {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := make([]v1beta1.Release, 0, len(keys))
+	var warnings []string
+	for _, k := range keys {
+		r, w, err := ImportRelease(latest[k], o)
+		if err != nil {
+			return nil, nil, err
+		}
+		out = append(out, *r)
+		warnings = append(warnings, w...)
+	}
+	return out, warnings, nil
+}
+
+// ImportRelease returns a Release adopting the supplied Helm release, and
+// warnings about what needs to be completed or reviewed before it is
+// applied.
+func ImportRelease(rel *release.Release, o Options) (*v1beta1.Release, []string, error) {
+	var warnings []string
+	warn := func(format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf("release %s: ", key(rel))+fmt.Sprintf(format, args...))
+	}
+
+	r := &v1beta1.Release{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1beta1.ReleaseGroupVersionKind.GroupVersion().String(),
+			Kind:       v1beta1.ReleaseKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        ReleaseName(rel.Namespace, rel.Name),
+			Annotations: map[string]string{v1beta1.AnnotationKeyAdopt: "true"},
+		},
+	}
+	meta.SetExternalName(r, rel.Name)
+	if o.ProviderConfigName != "" {
+		r.SetProviderConfigReference(&xpv1.Reference{Name: o.ProviderConfigName})
+	}
+
+	p := &r.Spec.ForProvider
+	p.Namespace = rel.Namespace
+	if c := rel.Chart; c != nil && c.Metadata != nil {
+		p.Chart.Name = c.Metadata.Name
+		p.Chart.Version = c.Metadata.Version
+		p.Chart.Repository = o.Repositories[c.Metadata.Name]
+	}
+	if p.Chart.Repository == "" {
+		warn("repository of chart %q is unknown and must be set", p.Chart.Name)
+	}
+	if len(rel.Config) > 0 {
+		b, err := json.Marshal(rel.Config)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, errFmtEncodeValues, key(rel))
+		}
+		p.Values.Raw = b
+	}
+	if rel.Info != nil && rel.Info.Status != release.StatusDeployed {
+		warn("latest revision %d is %s rather than deployed", rel.Version, rel.Info.Status)
+	}
+	return r, warnings, nil
+}
+
+// ReleaseName returns the name of the Release of the Helm release of the
+// supplied namespace and name.
+func ReleaseName(namespace, name string) string {
+	return namespace + "-" + name
+}
+
+func key(rel *release.Release) string {
+	return rel.Namespace + "/" + rel.Name
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	"github.com/crossplane-contrib/provider-helm/apis/release/v1beta1"
+)
+
+func TestImport(t *testing.T) {
+	rel := func(ns, name string, version int, status release.Status, vals map[string]interface{}) *release.Release {
+		return &release.Release{
+			Namespace: ns,
+			Name:      name,
+			Version:   version,
+			Info:      &release.Info{Status: status},
+			Chart:     &chart.Chart{Metadata: &chart.Metadata{Name: "app", Version: fmt.Sprintf("1.0.%d", version)}},
+			Config:    vals,
+		}
+	}
+	cr := func(ns, name, version string, m ...func(r *v1beta1.Release)) v1beta1.Release {
+		r := v1beta1.Release{
+			TypeMeta: metav1.TypeMeta{APIVersion: "helm.crossplane.io/v1beta1", Kind: "Release"},
+			ObjectMeta: metav1.ObjectMeta{Name: ns + "-" + name, Annotations: map[string]string{
+				"helm.crossplane.io/adopt":    "true",
+				"crossplane.io/external-name": name,
+			}},
+		}
+		r.SetProviderConfigReference(&xpv1.Reference{Name: "cluster"})
+		r.Spec.ForProvider.Namespace = ns
+		r.Spec.ForProvider.Chart = v1beta1.ChartSpec{Repository: "https://charts.example.org", Name: "app", Version: version}
+		for _, f := range m {
+			f(&r)
+		}
+		return r
+	}
+
+	type want struct {
+		rels     []v1beta1.Release
+		warnings []string
+		err      error
+	}
+	cases := map[string]struct {
+		reason string
+		rels   []*release.Release
+		o      Options
+		want   want
+	}{
+		"LatestRevisions": {
+			reason: "The latest revision of every release should be imported, including its values.",
+			rels: []*release.Release{
+				rel("apps", "web", 2, release.StatusDeployed, map[string]interface{}{"replicas": 2}),
+				rel("apps", "web", 1, release.StatusSuperseded, nil),
+				rel("apps", "api", 1, release.StatusDeployed, nil),
+			},
+			o: Options{ProviderConfigName: "cluster", Repositories: map[string]string{"app": "https://charts.example.org"}},
+			want: want{rels: []v1beta1.Release{
+				cr("apps", "api", "1.0.1"),
+				cr("apps", "web", "1.0.2", func(r *v1beta1.Release) {
+					r.Spec.ForProvider.Values.Raw = []byte(`{"replicas":2}`)
+				}),
+			}},
+		},
+		"NeedsReview": {
+			reason: "Releases of unknown repositories or that are not deployed should be reported as warnings.",
+			rels: []*release.Release{
+				rel("apps", "web", 3, release.StatusFailed, nil),
+			},
+			o: Options{ProviderConfigName: "cluster"},
+			want: want{
+				rels: []v1beta1.Release{cr("apps", "web", "1.0.3", func(r *v1beta1.Release) {
+					r.Spec.ForProvider.Chart.Repository = ""
+				})},
+				warnings: []string{
+					`release apps/web: repository of chart "app" is unknown and must be set`,
+					`release apps/web: latest revision 3 is failed rather than deployed`,
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rels, warnings, err := Import(tc.rels, tc.o)
+			if diff := cmp.Diff(tc.want.err, err); diff != "" {
+				t.Errorf("\n%s\nImport(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.rels, rels); diff != "" {
+				t.Errorf("\n%s\nImport(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.warnings, warnings); diff != "" {
+				t.Errorf("\n%s\nImport(...): -want warnings, +got warnings:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}